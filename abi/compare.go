@@ -0,0 +1,367 @@
+package abi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Impact classifies a change by what it breaks. Impacts are ordered by severity.
+type Impact uint32
+
+const (
+	// Impact_Compatible is a change which breaks neither binaries nor source code, like an
+	// added function.
+	Impact_Compatible Impact = iota + 1
+	// Impact_API is a change which breaks source code using the old headers, but not binaries
+	// built against them, like a removed enumeration constant.
+	Impact_API
+	// Impact_ABI is a change which breaks binaries built against the old headers, like a
+	// record of different size or a removed function.
+	Impact_ABI
+)
+
+func (i Impact) Spelling() string {
+	switch i {
+	case Impact_Compatible:
+		return "Impact=Compatible"
+	case Impact_API:
+		return "Impact=API"
+	case Impact_ABI:
+		return "Impact=ABI"
+	}
+
+	return fmt.Sprintf("Impact unknown %d", int(i))
+}
+
+func (i Impact) String() string {
+	return i.Spelling()
+}
+
+// Description returns a short description of the impact.
+func (i Impact) Description() string {
+	switch i {
+	case Impact_Compatible:
+		return "compatible"
+	case Impact_API:
+		return "API-breaking"
+	case Impact_ABI:
+		return "ABI-breaking"
+	}
+
+	return i.Spelling()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (i Impact) MarshalText() ([]byte, error) {
+	return []byte(strings.TrimPrefix(i.Spelling(), "Impact=")), nil
+}
+
+// Change is a difference between two surfaces.
+type Change struct {
+	Impact Impact
+	// Kind is the kind of the declaration: "function", "record", "enum", "constant" or
+	// "typedef".
+	Kind string
+	Name string
+	// Description explains the change.
+	Description string
+	// Location is the location of the declaration in the new surface, or in the old surface if
+	// it was removed.
+	Location clang.Position
+}
+
+// Compare returns the changes from the surface before to the surface after, the most severe
+// first and then ordered by kind and name.
+func Compare(before, after *Surface) []Change {
+	c := &comparison{}
+
+	c.functions(before.Functions, after.Functions)
+	c.records(before.Records, after.Records)
+	c.enums(before.Enums, after.Enums)
+	c.constants(before.Constants, after.Constants)
+	c.typedefs(before.Typedefs, after.Typedefs)
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		a, b := c.changes[i], c.changes[j]
+		if a.Impact != b.Impact {
+			return a.Impact > b.Impact
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return a.Name < b.Name
+	})
+
+	return c.changes
+}
+
+// Worst returns the most severe impact of the changes, or 0 if there are none.
+func Worst(changes []Change) Impact {
+	var worst Impact
+	for _, c := range changes {
+		if c.Impact > worst {
+			worst = c.Impact
+		}
+	}
+
+	return worst
+}
+
+type comparison struct {
+	changes []Change
+}
+
+func (c *comparison) add(impact Impact, kind, name string, loc clang.Position, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Impact:      impact,
+		Kind:        kind,
+		Name:        name,
+		Description: fmt.Sprintf(format, args...),
+		Location:    loc,
+	})
+}
+
+func (c *comparison) functions(before, after map[string]*Function) {
+	for _, name := range union(before, after) {
+		o, n := before[name], after[name]
+
+		switch {
+		case o == nil:
+			if n.Exported() {
+				c.add(Impact_Compatible, "function", name, n.Location, "added")
+			}
+
+			continue
+		case n == nil:
+			if o.Exported() {
+				c.add(Impact_ABI, "function", name, o.Location, "removed")
+			}
+
+			continue
+		case !o.Exported() && !n.Exported():
+			continue
+		case o.Exported() && !n.Exported():
+			c.add(Impact_ABI, "function", name, n.Location, "no longer exported, visibility changed from %s to %s",
+				text(o.Visibility), text(n.Visibility))
+
+			continue
+		case o.Visibility != n.Visibility:
+			c.add(Impact_Compatible, "function", name, n.Location, "visibility changed from %s to %s",
+				text(o.Visibility), text(n.Visibility))
+		}
+
+		if o.Result != n.Result {
+			c.add(Impact_ABI, "function", name, n.Location, "result type changed from %s to %s", o.Result, n.Result)
+		}
+		if len(o.Params) != len(n.Params) {
+			c.add(Impact_ABI, "function", name, n.Location, "number of parameters changed from %d to %d",
+				len(o.Params), len(n.Params))
+		} else {
+			for i := range o.Params {
+				if o.Params[i].Type != n.Params[i].Type {
+					c.add(Impact_ABI, "function", name, n.Location, "type of parameter %s changed from %s to %s",
+						param(i, n.Params[i].Name), o.Params[i].Type, n.Params[i].Type)
+				}
+			}
+		}
+		if o.Variadic != n.Variadic {
+			c.add(Impact_ABI, "function", name, n.Location, "variadic changed from %t to %t", o.Variadic, n.Variadic)
+		}
+		if o.CallingConv != n.CallingConv {
+			c.add(Impact_ABI, "function", name, n.Location, "calling convention changed from %s to %s",
+				text(o.CallingConv), text(n.CallingConv))
+		}
+	}
+}
+
+func (c *comparison) records(before, after map[string]*Record) {
+	for _, name := range union(before, after) {
+		o, n := before[name], after[name]
+
+		switch {
+		case o == nil:
+			c.add(Impact_Compatible, "record", name, n.Location, "added")
+
+			continue
+		case n == nil:
+			c.add(Impact_API, "record", name, o.Location, "removed")
+
+			continue
+		case o.Kind != n.Kind:
+			c.add(Impact_ABI, "record", name, n.Location, "changed from %s to %s", recordKind(o.Kind), recordKind(n.Kind))
+
+			continue
+		case o.Opaque && n.Opaque:
+			continue
+		case o.Opaque:
+			c.add(Impact_Compatible, "record", name, n.Location, "defined")
+
+			continue
+		case n.Opaque:
+			c.add(Impact_API, "record", name, n.Location, "definition removed")
+
+			continue
+		}
+
+		if o.Size != n.Size {
+			c.add(Impact_ABI, "record", name, n.Location, "size changed from %d to %d bytes", o.Size, n.Size)
+		}
+		if o.Align != n.Align {
+			c.add(Impact_ABI, "record", name, n.Location, "alignment changed from %d to %d bytes", o.Align, n.Align)
+		}
+		c.fields(name, n.Location, o.Fields, n.Fields)
+	}
+}
+
+// fields compares the named fields of a record. Unnamed fields are covered by the size of the
+// record and the offsets of the fields following them.
+func (c *comparison) fields(record string, loc clang.Position, before, after []Field) {
+	byName := map[string]Field{}
+	for _, f := range after {
+		if f.Name != "" {
+			byName[f.Name] = f
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, o := range before {
+		if o.Name == "" {
+			continue
+		}
+		seen[o.Name] = true
+
+		n, ok := byName[o.Name]
+		if !ok {
+			c.add(Impact_API, "record", record, loc, "field %s removed", o.Name)
+
+			continue
+		}
+		if o.Offset != n.Offset {
+			c.add(Impact_ABI, "record", record, loc, "offset of field %s changed from %s to %s", o.Name,
+				offset(o.Offset), offset(n.Offset))
+		}
+		if o.Type != n.Type {
+			c.add(Impact_ABI, "record", record, loc, "type of field %s changed from %s to %s", o.Name, o.Type, n.Type)
+		}
+		if o.BitWidth != n.BitWidth {
+			c.add(Impact_ABI, "record", record, loc, "bit width of field %s changed from %d to %d", o.Name,
+				o.BitWidth, n.BitWidth)
+		}
+	}
+
+	for _, n := range after {
+		if n.Name != "" && !seen[n.Name] {
+			c.add(Impact_Compatible, "record", record, loc, "field %s added", n.Name)
+		}
+	}
+}
+
+func (c *comparison) enums(before, after map[string]*Enum) {
+	for _, name := range union(before, after) {
+		o, n := before[name], after[name]
+
+		switch {
+		case o == nil:
+			c.add(Impact_Compatible, "enum", name, n.Location, "added")
+		case n == nil:
+			c.add(Impact_API, "enum", name, o.Location, "removed")
+		case o.Type != n.Type:
+			c.add(Impact_ABI, "enum", name, n.Location, "integer type changed from %s to %s", o.Type, n.Type)
+		}
+	}
+}
+
+func (c *comparison) constants(before, after map[string]*Constant) {
+	for _, name := range union(before, after) {
+		o, n := before[name], after[name]
+
+		switch {
+		case o == nil:
+			c.add(Impact_Compatible, "constant", name, n.Location, "added")
+		case n == nil:
+			c.add(Impact_API, "constant", name, o.Location, "removed")
+		case o.Value != n.Value:
+			c.add(Impact_ABI, "constant", name, n.Location, "value changed from %d to %d", o.Value, n.Value)
+		case o.Enum != n.Enum:
+			c.add(Impact_Compatible, "constant", name, n.Location, "moved from %s to %s", enumName(o.Enum),
+				enumName(n.Enum))
+		}
+	}
+}
+
+func (c *comparison) typedefs(before, after map[string]*Typedef) {
+	for _, name := range union(before, after) {
+		o, n := before[name], after[name]
+
+		switch {
+		case o == nil:
+			c.add(Impact_Compatible, "typedef", name, n.Location, "added")
+		case n == nil:
+			c.add(Impact_API, "typedef", name, o.Location, "removed")
+		case o.Type != n.Type:
+			c.add(Impact_ABI, "typedef", name, n.Location, "underlying type changed from %s to %s", o.Type, n.Type)
+		}
+	}
+}
+
+// union returns the names declared before or after in order.
+func union[T any](before, after map[string]T) []string {
+	names := make([]string, 0, len(after))
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range after {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// text returns the name of an enumeration value of the clang package.
+func text(v interface{ MarshalText() ([]byte, error) }) string {
+	b, err := v.MarshalText()
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func param(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("%d", i+1)
+	}
+
+	return fmt.Sprintf("%d (%s)", i+1, name)
+}
+
+func offset(bits int64) string {
+	if bits%8 != 0 {
+		return fmt.Sprintf("%d bits", bits)
+	}
+
+	return fmt.Sprintf("%d bytes", bits/8)
+}
+
+func recordKind(kind clang.CursorKind) string {
+	if kind == clang.Cursor_UnionDecl {
+		return "union"
+	}
+
+	return "struct"
+}
+
+func enumName(name string) string {
+	if name == "" {
+		return "an unnamed enum"
+	}
+
+	return name
+}
@@ -0,0 +1,205 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func surfaces() (*Surface, *Surface) {
+	before, after := NewSurface(), NewSurface()
+
+	fn := func(name, result string, params ...string) *Function {
+		f := &Function{
+			Name:        name,
+			Result:      result,
+			Params:      []Param{},
+			CallingConv: clang.CallingConv_C,
+			Visibility:  clang.Visibility_Default,
+		}
+		for _, p := range params {
+			f.Params = append(f.Params, Param{Type: p})
+		}
+
+		return f
+	}
+	before.Functions["open"] = fn("open", "int", "const char *")
+	after.Functions["open"] = fn("open", "int", "const char *")
+	before.Functions["read"] = fn("read", "long", "int", "void *", "unsigned long")
+	after.Functions["read"] = fn("read", "long", "int", "void *", "unsigned int")
+	before.Functions["close"] = fn("close", "int", "int")
+	before.Functions["flush"] = fn("flush", "int", "int")
+	after.Functions["flush"] = fn("flush", "int", "int")
+	after.Functions["flush"].Visibility = clang.Visibility_Hidden
+	before.Functions["seek"] = fn("seek", "int", "int", "long")
+	after.Functions["seek"] = fn("seek", "int", "int", "long")
+	after.Functions["seek"].CallingConv = clang.CallingConv_X86StdCall
+	after.Functions["stat"] = fn("stat", "int", "const char *")
+
+	before.Records["struct file"] = &Record{
+		Name: "struct file", Kind: clang.Cursor_StructDecl, Size: 16, Align: 8,
+		Fields: []Field{{Name: "fd", Type: "int"}, {Name: "flags", Type: "int", Offset: 32}, {Name: "buf", Type: "char *", Offset: 64}},
+	}
+	after.Records["struct file"] = &Record{
+		Name: "struct file", Kind: clang.Cursor_StructDecl, Size: 24, Align: 8,
+		Fields: []Field{{Name: "fd", Type: "int"}, {Name: "buf", Type: "char *", Offset: 64}, {Name: "pos", Type: "long", Offset: 128}},
+	}
+	before.Records["struct handle"] = &Record{Name: "struct handle", Kind: clang.Cursor_StructDecl, Size: 8, Align: 8}
+	after.Records["struct handle"] = &Record{Name: "struct handle", Kind: clang.Cursor_StructDecl, Opaque: true}
+
+	before.Enums["enum mode"] = &Enum{Name: "enum mode", Type: "unsigned int"}
+	after.Enums["enum mode"] = &Enum{Name: "enum mode", Type: "unsigned int"}
+	before.Constants["MODE_READ"] = &Constant{Name: "MODE_READ", Enum: "enum mode", Value: 1}
+	after.Constants["MODE_READ"] = &Constant{Name: "MODE_READ", Enum: "enum mode", Value: 1}
+	before.Constants["MODE_WRITE"] = &Constant{Name: "MODE_WRITE", Enum: "enum mode", Value: 2}
+	after.Constants["MODE_WRITE"] = &Constant{Name: "MODE_WRITE", Enum: "enum mode", Value: 4}
+	before.Constants["MODE_APPEND"] = &Constant{Name: "MODE_APPEND", Enum: "enum mode", Value: 8}
+
+	before.Typedefs["off_t"] = &Typedef{Name: "off_t", Type: "long"}
+	after.Typedefs["off_t"] = &Typedef{Name: "off_t", Type: "long long"}
+	before.Typedefs["file_t"] = &Typedef{Name: "file_t", Type: "struct file"}
+
+	return before, after
+}
+
+func TestCompare(t *testing.T) {
+	changes := Compare(surfaces())
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, fmt.Sprintf("%s %s %s: %s", c.Impact.Description(), c.Kind, c.Name, c.Description))
+	}
+
+	want := []string{
+		"ABI-breaking constant MODE_WRITE: value changed from 2 to 4",
+		"ABI-breaking function close: removed",
+		"ABI-breaking function flush: no longer exported, visibility changed from Default to Hidden",
+		"ABI-breaking function read: type of parameter 3 changed from unsigned long to unsigned int",
+		"ABI-breaking function seek: calling convention changed from C to X86StdCall",
+		"ABI-breaking record struct file: size changed from 16 to 24 bytes",
+		"ABI-breaking typedef off_t: underlying type changed from long to long long",
+		"API-breaking constant MODE_APPEND: removed",
+		"API-breaking record struct file: field flags removed",
+		"API-breaking record struct handle: definition removed",
+		"API-breaking typedef file_t: removed",
+		"compatible function stat: added",
+		"compatible record struct file: field pos added",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if w := Worst(changes); w != Impact_ABI {
+		t.Errorf("Worst = %s, want %s", w, Impact_ABI)
+	}
+	if w := Worst(Compare(NewSurface(), NewSurface())); w != 0 {
+		t.Errorf("Worst without changes = %d, want 0", w)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	changes := []Change{
+		{Impact: Impact_ABI, Kind: "record", Name: "struct file", Description: "size changed from 16 to 24 bytes",
+			Location: clang.Position{File: "include/file.h", Line: 3, Column: 8}},
+		{Impact: Impact_Compatible, Kind: "function", Name: "stat", Description: "added"},
+	}
+
+	var b bytes.Buffer
+	if err := WriteText(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "include/file.h:3:8: ABI-breaking: record struct file: size changed from 16 to 24 bytes\n" +
+		"compatible: function stat: added\n" +
+		"1 ABI-breaking, 0 API-breaking, 1 compatible changes\n"
+	if b.String() != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteJSON(&b, changes[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"Impact": "ABI"`) {
+		t.Errorf("WriteJSON = %s, want the impact as text", b.String())
+	}
+}
+
+func TestCollect(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("testdata", "api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit(filepath.Join(root, "api.h"), []string{"-xc-header"}, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	s := Collector{Root: root}.Collect(tu)
+
+	line := func(kind, name string, pos clang.Position, want uint32) {
+		if pos.File != "api.h" || pos.Line != want {
+			t.Errorf("expected %s %s at api.h:%d. got=%v", kind, name, want, pos)
+		}
+	}
+
+	if _, ok := s.Functions["helper"]; ok || len(s.Functions) != 2 {
+		t.Errorf("expected the functions area and log_message with external linkage. got=%v", s.Functions)
+	}
+	if f := s.Functions["area"]; f != nil {
+		want := []Param{{Name: "p", Type: "const struct point *"}, {Name: "scale", Type: "long"}}
+		if f.Result != "int" || !reflect.DeepEqual(f.Params, want) || f.Variadic {
+			t.Errorf("unexpected function area %+v", f)
+		}
+		line("function", "area", f.Location, 14)
+	}
+	if f := s.Functions["log_message"]; f != nil && !f.Variadic {
+		t.Errorf("expected log_message to be variadic. got=%+v", f)
+	}
+
+	if r := s.Records["struct point"]; r == nil {
+		t.Errorf("expected struct point. got=%v", s.Records)
+	} else {
+		want := []Field{{Name: "x", Type: "int"}, {Name: "y", Type: "int", Offset: 32}}
+		if r.Opaque || r.Size != 8 || r.Align != 4 || !reflect.DeepEqual(r.Fields, want) {
+			t.Errorf("unexpected record struct point %+v", r)
+		}
+		line("record", "struct point", r.Location, 3)
+	}
+	if r := s.Records["struct opaque"]; r == nil || !r.Opaque {
+		t.Errorf("expected opaque struct opaque. got=%+v", r)
+	}
+
+	if e := s.Enums["enum mode"]; e == nil || e.Type != "unsigned int" {
+		t.Errorf("unexpected enum mode %+v", e)
+	}
+	if e := s.Enums["level"]; e == nil {
+		t.Errorf("expected the unnamed enum to be named by its typedef. got=%v", s.Enums)
+	}
+	if c := s.Constants["MODE_WRITE"]; c == nil || c.Enum != "enum mode" || c.Value != 2 {
+		t.Errorf("unexpected constant MODE_WRITE %+v", c)
+	}
+	if c := s.Constants["LEVEL_HIGH"]; c == nil || c.Enum != "level" || c.Value != 1 {
+		t.Errorf("unexpected constant LEVEL_HIGH %+v", c)
+	}
+
+	if td := s.Typedefs["point"]; td == nil || td.Type != "struct point" {
+		t.Errorf("unexpected typedef point %+v", td)
+	}
+	if td := s.Typedefs["level"]; td == nil || td.Type != "enum" {
+		t.Errorf("unexpected typedef level %+v", td)
+	}
+	if _, ok := s.Typedefs["dep_t"]; ok {
+		t.Error("expected no typedef of a header outside of the root")
+	}
+}
@@ -0,0 +1,41 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON writes the changes as JSON.
+func WriteJSON(w io.Writer, changes []Change) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	return enc.Encode(changes)
+}
+
+// WriteText writes a line for every change, prefixed with its location, followed by a summary.
+func WriteText(w io.Writer, changes []Change) error {
+	var b strings.Builder
+
+	counts := map[Impact]int{}
+	for _, c := range changes {
+		if c.Location.IsValid() {
+			fmt.Fprintf(&b, "%s: ", c.Location)
+		}
+		fmt.Fprintf(&b, "%s: %s %s: %s\n", c.Impact.Description(), c.Kind, c.Name, c.Description)
+		counts[c.Impact]++
+	}
+
+	fmt.Fprintf(&b, "%d ABI-breaking, %d API-breaking, %d compatible changes\n", counts[Impact_ABI], counts[Impact_API],
+		counts[Impact_Compatible])
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
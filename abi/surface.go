@@ -0,0 +1,388 @@
+// Package abi compares the exported surface of two versions of a set of C headers and
+// classifies every difference by whether it breaks binaries or source code built against the
+// old version.
+//
+// A Collector extracts the Surface of translation units: functions with their signatures,
+// calling conventions and visibility, the layout of records, enumerations with the values of
+// their constants, and typedefs. Compare returns the Changes between two surfaces.
+package abi
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Surface is the exported surface of a set of headers. The maps are keyed by name.
+type Surface struct {
+	Functions map[string]*Function
+	Records   map[string]*Record
+	Enums     map[string]*Enum
+	// Constants holds the constants of all enumerations, including unnamed ones.
+	Constants map[string]*Constant
+	Typedefs  map[string]*Typedef
+}
+
+// NewSurface returns an empty surface.
+func NewSurface() *Surface {
+	return &Surface{
+		Functions: map[string]*Function{},
+		Records:   map[string]*Record{},
+		Enums:     map[string]*Enum{},
+		Constants: map[string]*Constant{},
+		Typedefs:  map[string]*Typedef{},
+	}
+}
+
+// Merge adds the declarations of o which are not part of s.
+func (s *Surface) Merge(o *Surface) {
+	for name, f := range o.Functions {
+		if _, ok := s.Functions[name]; !ok {
+			s.Functions[name] = f
+		}
+	}
+	for name, r := range o.Records {
+		if old, ok := s.Records[name]; !ok || old.Opaque && !r.Opaque {
+			s.Records[name] = r
+		}
+	}
+	for name, e := range o.Enums {
+		if _, ok := s.Enums[name]; !ok {
+			s.Enums[name] = e
+		}
+	}
+	for name, c := range o.Constants {
+		if _, ok := s.Constants[name]; !ok {
+			s.Constants[name] = c
+		}
+	}
+	for name, t := range o.Typedefs {
+		if _, ok := s.Typedefs[name]; !ok {
+			s.Typedefs[name] = t
+		}
+	}
+}
+
+// Param is a parameter of a function.
+type Param struct {
+	Name string
+	// Type is the canonical spelling of the type.
+	Type string
+}
+
+// Function is a function with external linkage.
+type Function struct {
+	Name string
+	// Result is the canonical spelling of the result type.
+	Result      string
+	Params      []Param
+	Variadic    bool
+	CallingConv clang.CallingConv
+	Visibility  clang.VisibilityKind
+	Location    clang.Position
+}
+
+// Exported reports whether the function is visible outside of its shared library.
+func (f *Function) Exported() bool {
+	return f.Visibility != clang.Visibility_Hidden
+}
+
+// Field is a field of a record. Offset is in bits.
+type Field struct {
+	Name     string
+	Type     string
+	Offset   int64
+	BitWidth int64
+}
+
+// Record is a struct or union. Unnamed records are named by their typedef. Size and Align are in
+// bytes.
+type Record struct {
+	Name string
+	Kind clang.CursorKind
+	// Opaque is set for records which are declared but not defined.
+	Opaque   bool
+	Size     int64
+	Align    int64
+	Fields   []Field
+	Location clang.Position
+}
+
+// Enum is an enumeration. Unnamed enumerations are named by their typedef.
+type Enum struct {
+	Name string
+	// Type is the canonical spelling of the integer type.
+	Type     string
+	Location clang.Position
+}
+
+// Constant is an enumeration constant.
+type Constant struct {
+	Name string
+	// Enum is the name of the enumeration, it is empty for unnamed enumerations.
+	Enum     string
+	Value    int64
+	Location clang.Position
+}
+
+// Typedef is a typedef. The underlying type of a typedef of an unnamed record or enumeration is
+// only the keyword, the record or enumeration is compared under the name of the typedef.
+type Typedef struct {
+	Name string
+	// Type is the canonical spelling of the underlying type.
+	Type     string
+	Location clang.Position
+}
+
+// Collector collects the surface of translation units.
+type Collector struct {
+	// Root is the directory of the header set. Only declarations in files below it are
+	// collected, and their file names are made relative to it. Without a root, the
+	// declarations of all files except system headers are collected.
+	Root string
+}
+
+// Collect returns the surface declared by the translation unit.
+func (col Collector) Collect(tu clang.TranslationUnit) *Surface {
+	w := walker{
+		Collector: col,
+		surface:   NewSurface(),
+		paths:     map[string]string{},
+	}
+	w.children(tu.TranslationUnitCursor())
+
+	return w.surface
+}
+
+type walker struct {
+	Collector
+
+	surface *Surface
+	// paths maps file names to their names relative to the root, or to "" for files outside of
+	// it.
+	paths map[string]string
+}
+
+func (w *walker) children(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		w.visit(c)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) visit(c clang.Cursor) {
+	if c.Kind() == clang.Cursor_LinkageSpec {
+		w.children(c)
+
+		return
+	}
+
+	pos, ok := w.position(c)
+	if !ok {
+		return
+	}
+
+	switch c.Kind() {
+	case clang.Cursor_FunctionDecl:
+		w.function(c, pos)
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl:
+		if !c.IsUnnamed() {
+			w.record(c, c.Type().Spelling(), pos)
+		}
+	case clang.Cursor_EnumDecl:
+		name := ""
+		if !c.IsUnnamed() {
+			name = c.Type().Spelling()
+		}
+		w.enum(c, name, pos)
+	case clang.Cursor_TypedefDecl:
+		w.typedef(c, pos)
+	}
+}
+
+// position returns the position of c relative to the root, and whether c is declared in the
+// header set.
+func (w *walker) position(c clang.Cursor) (clang.Position, bool) {
+	loc := c.Location()
+	if w.Root == "" && loc.IsInSystemHeader() {
+		return clang.Position{}, false
+	}
+
+	pos := loc.Position()
+	if !pos.IsValid() {
+		return pos, false
+	}
+
+	rel, ok := w.paths[pos.File]
+	if !ok {
+		rel = filepath.Clean(pos.File)
+		if w.Root != "" {
+			r, err := filepath.Rel(w.Root, rel)
+			if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+				r = ""
+			}
+			rel = r
+		}
+		w.paths[pos.File] = rel
+	}
+	pos.File = rel
+
+	return pos, rel != ""
+}
+
+func (w *walker) function(c clang.Cursor, pos clang.Position) {
+	name := c.Spelling()
+	if c.Linkage() != clang.Linkage_External {
+		return
+	}
+	if _, ok := w.surface.Functions[name]; ok {
+		return
+	}
+
+	t := c.Type()
+	f := &Function{
+		Name:        name,
+		Result:      spell(t.ResultType()),
+		Params:      []Param{},
+		Variadic:    t.Kind() == clang.Type_FunctionProto && t.IsFunctionTypeVariadic(),
+		CallingConv: t.FunctionTypeCallingConv(),
+		Visibility:  c.Visibility(),
+		Location:    pos,
+	}
+	for i := 0; i < int(t.NumArgTypes()); i++ {
+		f.Params = append(f.Params, Param{
+			Name: c.Argument(uint32(i)).Spelling(),
+			Type: spell(t.ArgType(uint32(i))),
+		})
+	}
+	w.surface.Functions[name] = f
+}
+
+// record adds the record c with the name. Definitions replace declarations.
+func (w *walker) record(c clang.Cursor, name string, pos clang.Position) {
+	if r, ok := w.surface.Records[name]; ok && !r.Opaque {
+		return
+	}
+
+	r := &Record{
+		Name:     name,
+		Kind:     c.Kind(),
+		Opaque:   true,
+		Location: pos,
+	}
+	w.surface.Records[name] = r
+
+	def := c.Definition()
+	if def.IsNull() {
+		return
+	}
+	t := def.Type()
+	if r.Size = t.SizeOf(); r.Size < 0 {
+		r.Size = 0
+
+		return
+	}
+	r.Opaque = false
+	r.Align = t.AlignOf()
+	if p, ok := w.position(def); ok {
+		r.Location = p
+	}
+
+	def.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() != clang.Cursor_FieldDecl {
+			return clang.ChildVisit_Continue
+		}
+
+		f := Field{
+			Name:   child.Spelling(),
+			Type:   spell(child.Type()),
+			Offset: child.OffsetOfField(),
+		}
+		if child.IsBitField() {
+			f.BitWidth = int64(child.FieldDeclBitWidth())
+		}
+		r.Fields = append(r.Fields, f)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// enum adds the enumeration c with the name, and its constants. Unnamed enumerations only add
+// their constants.
+func (w *walker) enum(c clang.Cursor, name string, pos clang.Position) {
+	if name != "" {
+		if _, ok := w.surface.Enums[name]; ok {
+			return
+		}
+		w.surface.Enums[name] = &Enum{
+			Name:     name,
+			Type:     spell(c.EnumDeclIntegerType()),
+			Location: pos,
+		}
+	}
+
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() != clang.Cursor_EnumConstantDecl {
+			return clang.ChildVisit_Continue
+		}
+
+		cname := child.Spelling()
+		if k, ok := w.surface.Constants[cname]; ok && k.Enum != "" {
+			return clang.ChildVisit_Continue
+		}
+		p, _ := w.position(child)
+		w.surface.Constants[cname] = &Constant{
+			Name:     cname,
+			Enum:     name,
+			Value:    child.EnumConstantDeclValue(),
+			Location: p,
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// typedef adds the typedef c. Unnamed records and enumerations are added under its name.
+func (w *walker) typedef(c clang.Cursor, pos clang.Position) {
+	name := c.Spelling()
+	if _, ok := w.surface.Typedefs[name]; ok {
+		return
+	}
+
+	u := c.TypedefDeclUnderlyingType()
+	td := &Typedef{
+		Name:     name,
+		Type:     spell(u),
+		Location: pos,
+	}
+
+	switch canonical := u.CanonicalType(); canonical.Kind() {
+	case clang.Type_Record, clang.Type_Enum:
+		decl := canonical.Declaration()
+		if !decl.IsUnnamed() {
+			break
+		}
+
+		td.Type = decl.TagKeyword()
+		if canonical.Kind() == clang.Type_Enum {
+			w.enum(decl, name, pos)
+		} else {
+			w.record(decl, name, pos)
+		}
+	}
+	w.surface.Typedefs[name] = td
+}
+
+// spell returns the canonical spelling of t, which does not depend on the typedefs it is
+// written with. Unnamed records and enumerations are spelled by their location, so the
+// spelling of t is used for them.
+func spell(t clang.Type) string {
+	s := t.CanonicalType().Spelling()
+	if strings.Contains(s, "(unnamed") || strings.Contains(s, "(anonymous") {
+		return t.Spelling()
+	}
+
+	return s
+}
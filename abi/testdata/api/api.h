@@ -0,0 +1,16 @@
+#include "../dep.h"
+
+typedef struct point {
+  int x;
+  int y;
+} point;
+
+enum mode { MODE_READ = 1, MODE_WRITE = 2 };
+
+typedef enum { LEVEL_LOW, LEVEL_HIGH } level;
+
+struct opaque;
+
+int area(const point *p, dep_t scale);
+void log_message(const char *fmt, ...);
+static int helper(void) { return 0; }
@@ -0,0 +1,2 @@
+typedef long dep_t;
+int dep(void);
@@ -0,0 +1,180 @@
+// Package bindgen generates cgo bindings for C headers: Go structs whose layout is checked
+// against the C structs, enums as typed constants, integer macros as constants, and wrapper
+// functions converting between Go and C values, including Go functions passed as callbacks.
+//
+// Bind extracts a Module from a header, Generate writes the Go source of a Module.
+package bindgen
+
+import "fmt"
+
+// Conv is how a value is converted between its Go and its C type.
+type Conv uint32
+
+const (
+	// Conv_Value converts numbers and booleans with a type conversion.
+	Conv_Value Conv = iota + 1
+	// Conv_Pointer converts pointers through unsafe.Pointer.
+	Conv_Pointer
+	// Conv_String converts Go strings to and from char *. Strings passed to C are copied and
+	// freed after the call, strings returned by C are copied and not freed.
+	Conv_String
+	// Conv_Struct reinterprets the memory of structs passed by value, their layouts are
+	// identical.
+	Conv_Struct
+	// Conv_Callback passes a Go function to C through a handle in the user data parameter of
+	// the callback.
+	Conv_Callback
+)
+
+func (c Conv) Spelling() string {
+	switch c {
+	case Conv_Value:
+		return "Conv=Value"
+	case Conv_Pointer:
+		return "Conv=Pointer"
+	case Conv_String:
+		return "Conv=String"
+	case Conv_Struct:
+		return "Conv=Struct"
+	case Conv_Callback:
+		return "Conv=Callback"
+	}
+
+	return fmt.Sprintf("Conv unknown %d", int(c))
+}
+
+func (c Conv) String() string {
+	return c.Spelling()
+}
+
+// Type is a C type together with the Go type it is bound to.
+type Type struct {
+	// Go is the Go type, e.g. "int32" or "*Point".
+	Go string
+	// C is the cgo type, e.g. "C.int" or "*C.struct_point".
+	C    string
+	Conv Conv
+}
+
+// Field is a field of a struct. Offset, Size and Align are in bytes.
+type Field struct {
+	// Name is the C name, GoName is empty for fields which are only represented by padding,
+	// like bit-fields and anonymous records.
+	Name   string
+	GoName string
+	Type   Type
+	Offset int64
+	Size   int64
+	Align  int64
+}
+
+// Struct is a struct or union. Size and Align are in bytes.
+type Struct struct {
+	// Name is the C spelling, e.g. "struct point".
+	Name   string
+	GoName string
+	// C is the cgo type, e.g. "C.struct_point".
+	C      string
+	Size   int64
+	Align  int64
+	Union  bool
+	Opaque bool
+	// Fields are ordered by offset.
+	Fields []Field
+}
+
+// Typedef is a named integer or floating point type.
+type Typedef struct {
+	Name   string
+	GoName string
+	// Go is the underlying Go type.
+	Go string
+}
+
+// Constant is an enumerator or an integer macro.
+type Constant struct {
+	Name   string
+	GoName string
+	// Value is the Go literal of the value.
+	Value string
+	// Type is the Go type of the constant, it is empty for untyped constants.
+	Type string
+}
+
+// Enum is an enumeration with its constants.
+type Enum struct {
+	Name   string
+	GoName string
+	// C is the cgo type, e.g. "C.enum_color".
+	C string
+	// Go is the underlying Go type.
+	Go        string
+	Constants []Constant
+}
+
+// Param is a parameter of a function or a callback.
+type Param struct {
+	Name   string
+	GoName string
+	Type   Type
+	// Callback is the callback of a parameter converted with Conv_Callback.
+	Callback *Callback
+	// UserData is set for the parameter passing the handle of a callback, it is hidden from
+	// the Go signature. The n-th user data parameter belongs to the n-th callback.
+	UserData bool
+}
+
+// Function is a C function bound by a wrapper function.
+type Function struct {
+	Name   string
+	GoName string
+	Params []Param
+	// Result is nil for functions returning void.
+	Result *Type
+	// Sync is set if callbacks are only invoked during the call, so their handles are released
+	// when the wrapper returns.
+	Sync bool
+}
+
+// HasCallbacks reports whether a parameter of the function is a callback.
+func (f *Function) HasCallbacks() bool {
+	for _, p := range f.Params {
+		if p.Type.Conv == Conv_Callback {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Callback is a typedef of a function pointer with a void * user data parameter, which is
+// bound to a Go function type.
+type Callback struct {
+	Name   string
+	GoName string
+	// Params include the user data parameter.
+	Params []Param
+	Result *Type
+	// Export is the name of the exported Go function the callback is implemented with, and
+	// Decl its C declaration.
+	Export string
+	Decl   string
+}
+
+// Module holds the declarations of a header which are bound.
+type Module struct {
+	Package string
+	// Header is the file name of the header.
+	Header    string
+	Include   string
+	CFlags    string
+	LDFlags   string
+	Typedefs  []*Typedef
+	Enums     []*Enum
+	Constants []Constant
+	Structs   []*Struct
+	Callbacks []*Callback
+	Functions []*Function
+	// Skipped explains why declarations are not bound.
+	Skipped []string
+}
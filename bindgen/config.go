@@ -0,0 +1,83 @@
+package bindgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+// Config controls which declarations are bound and how their values are converted.
+type Config struct {
+	// Package is the name of the generated Go package.
+	Package string `json:"package"`
+	// Include is the directive including the header in the cgo preamble. It defaults to
+	// including the base name of the header with quotes.
+	Include string `json:"include,omitempty"`
+	// CFlags and LDFlags are written to #cgo directives.
+	CFlags  string `json:"cflags,omitempty"`
+	LDFlags string `json:"ldflags,omitempty"`
+	// Prefixes are removed from C names to form Go names, e.g. "foo_" and "FOO_".
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Renames maps C names to Go names.
+	Renames map[string]string `json:"renames,omitempty"`
+	// Ignore holds glob patterns of the C names of functions, types and constants which are not
+	// bound.
+	Ignore []string `json:"ignore,omitempty"`
+	// Strings holds patterns of char * parameters converted from Go strings, and of char *
+	// results converted to Go strings. A pattern has the form "function.parameter", where both
+	// parts are glob patterns, and "function.return" selects the result. Parameters and results
+	// of type const char * are strings unless they match NoStrings.
+	Strings   []string `json:"strings,omitempty"`
+	NoStrings []string `json:"no_strings,omitempty"`
+	// SyncCallbacks holds glob patterns of functions which invoke the callbacks passed to them
+	// only during the call. The wrappers of other functions return a function releasing the
+	// callbacks.
+	SyncCallbacks []string `json:"sync_callbacks,omitempty"`
+}
+
+// LoadConfig reads a configuration in JSON.
+func LoadConfig(file string) (*Config, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+
+	return &cfg, nil
+}
+
+// ignored reports whether the declaration with the C name is not bound.
+func (cfg *Config) ignored(name string) bool {
+	return matchAny(cfg.Ignore, name)
+}
+
+// isString reports whether the char * parameter or result of the function is a string.
+// param is "return" for the result.
+func (cfg *Config) isString(function, param string, isConst bool) bool {
+	name := function + "." + param
+	if matchAny(cfg.NoStrings, name) {
+		return false
+	}
+
+	return isConst || matchAny(cfg.Strings, name)
+}
+
+// isSync reports whether the function invokes its callbacks only during the call.
+func (cfg *Config) isSync(function string) bool {
+	return matchAny(cfg.SyncCallbacks, function)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
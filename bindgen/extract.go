@@ -0,0 +1,774 @@
+package bindgen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/layout"
+)
+
+// Options are the options headers are parsed with. Macros are only seen with a detailed
+// preprocessing record.
+const Options = uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_SkipFunctionBodies |
+	clang.TranslationUnit_KeepGoing)
+
+// macroPrefix prefixes the variables the values of macros are evaluated with.
+const macroPrefix = "__bindgen_"
+
+// Bind parses the header with the clang arguments and returns the declarations of the header
+// which can be bound. Declarations of included headers are not bound, but their types are used
+// through pointers and typedefs. Declarations which can not be bound are listed in
+// Module.Skipped.
+func Bind(idx clang.Index, header string, args []string, cfg *Config) (*Module, error) {
+	header, err := filepath.Abs(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var tu clang.TranslationUnit
+	if ec := idx.ParseTranslationUnit2(header, args, nil, Options, &tu); ec != clang.Error_Success {
+		return nil, fmt.Errorf("parse %s: %s", header, ec)
+	}
+	defer tu.Dispose()
+
+	b := &binder{
+		cfg:   cfg,
+		names: newNamer(cfg),
+		module: &Module{
+			Package: cfg.Package,
+			Header:  header,
+			Include: cfg.Include,
+			CFlags:  cfg.CFlags,
+			LDFlags: cfg.LDFlags,
+		},
+		structs:       map[string]*Struct{},
+		enums:         map[string]*Enum{},
+		typedefs:      map[string]*Typedef{},
+		callbacks:     map[string]*Callback{},
+		structDecls:   map[*Struct]clang.Cursor{},
+		enumDecls:     map[*Enum]clang.Cursor{},
+		callbackDecls: map[*Callback]clang.Cursor{},
+		seen:          map[string]bool{},
+	}
+
+	// The first pass names the types, so that the second pass can map the types of fields and
+	// parameters in any order of declaration.
+	tu.TranslationUnitCursor().Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if c.Location().IsFromMainFile() {
+			b.declare(tu, c)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	for _, s := range b.module.Structs {
+		b.fields(s, b.structDecls[s])
+	}
+	for _, e := range b.enumOrder {
+		b.constants(e, b.enumDecls[e])
+	}
+	for _, c := range b.anonymous {
+		if _, ok := b.enums[c.USR()]; !ok {
+			b.module.Constants = append(b.module.Constants, b.enumerators(c, "")...)
+		}
+	}
+	for _, cb := range b.callbackOrder {
+		b.callback(cb, b.callbackDecls[cb])
+	}
+	for _, c := range b.functions {
+		b.function(c)
+	}
+
+	if err := b.evaluate(idx, header, args); err != nil {
+		return nil, err
+	}
+
+	return b.module, nil
+}
+
+type binder struct {
+	cfg    *Config
+	names  *namer
+	module *Module
+
+	// structs, enums, typedefs and callbacks map the USRs of declarations to their bindings.
+	// Typedefs of records and enumerations map to the bindings of the records and enumerations.
+	structs   map[string]*Struct
+	enums     map[string]*Enum
+	typedefs  map[string]*Typedef
+	callbacks map[string]*Callback
+
+	structDecls   map[*Struct]clang.Cursor
+	enumDecls     map[*Enum]clang.Cursor
+	callbackDecls map[*Callback]clang.Cursor
+
+	enumOrder     []*Enum
+	callbackOrder []*Callback
+	// anonymous holds the unnamed enumerations, whose constants are untyped unless they are
+	// named by a typedef.
+	anonymous []clang.Cursor
+	functions []clang.Cursor
+	macros    []string
+	seen      map[string]bool
+}
+
+func (b *binder) skip(name string, err error) {
+	b.module.Skipped = append(b.module.Skipped, fmt.Sprintf("%s: %v", name, err))
+}
+
+// declare registers the declaration c of the header.
+func (b *binder) declare(tu clang.TranslationUnit, c clang.Cursor) {
+	switch c.Kind() {
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl:
+		if !c.IsUnnamed() {
+			b.record(c, c.Spelling(), "C."+c.TagKeyword()+"_"+c.Spelling())
+		}
+	case clang.Cursor_EnumDecl:
+		switch {
+		case !c.IsUnnamed():
+			b.enum(c, c.Spelling(), "C.enum_"+c.Spelling())
+		case c.IsCursorDefinition():
+			b.anonymous = append(b.anonymous, c)
+		}
+	case clang.Cursor_TypedefDecl:
+		b.typedef(c)
+	case clang.Cursor_FunctionDecl:
+		b.functions = append(b.functions, c)
+	case clang.Cursor_MacroDefinition:
+		name := c.Spelling()
+		if !c.IsMacroFunctionLike() && !c.IsMacroBuiltin() && !b.cfg.ignored(name) && c.HasMacroBody() {
+			b.macros = append(b.macros, name)
+		}
+	}
+}
+
+// record registers the struct or union c with the C name and the cgo type, unless it is
+// already registered.
+func (b *binder) record(c clang.Cursor, cname, ctype string) *Struct {
+	usr := c.USR()
+	if s, ok := b.structs[usr]; ok {
+		return s
+	}
+	if b.cfg.ignored(cname) {
+		return nil
+	}
+
+	name := cname
+	if !c.IsUnnamed() {
+		name = c.TagKeyword() + " " + c.Spelling()
+	}
+	s := &Struct{
+		Name:   name,
+		GoName: b.names.exported(cname),
+		C:      ctype,
+		Union:  c.Kind() == clang.Cursor_UnionDecl,
+	}
+	b.structs[usr] = s
+	b.structDecls[s] = c
+	b.module.Structs = append(b.module.Structs, s)
+
+	return s
+}
+
+// enum registers the enumeration c with the C name and the cgo type, unless it is already
+// registered.
+func (b *binder) enum(c clang.Cursor, cname, ctype string) *Enum {
+	usr := c.USR()
+	if e, ok := b.enums[usr]; ok {
+		return e
+	}
+	if b.cfg.ignored(cname) {
+		return nil
+	}
+
+	name := cname
+	if !c.IsUnnamed() {
+		name = "enum " + c.Spelling()
+	}
+	e := &Enum{
+		Name:   name,
+		GoName: b.names.exported(cname),
+		C:      ctype,
+	}
+	b.enums[usr] = e
+	b.enumDecls[e] = c
+	b.enumOrder = append(b.enumOrder, e)
+
+	return e
+}
+
+// typedef registers the typedef c. Typedefs of records and enumerations name them if they are
+// unnamed, typedefs of function pointers with a user data parameter are callbacks.
+func (b *binder) typedef(c clang.Cursor) {
+	name := c.Spelling()
+	if b.cfg.ignored(name) {
+		return
+	}
+	usr := c.USR()
+
+	t := c.TypedefDeclUnderlyingType().CanonicalType()
+	switch t.Kind() {
+	case clang.Type_Record:
+		decl := t.Declaration()
+		var s *Struct
+		if decl.IsUnnamed() {
+			s = b.record(decl, name, "C."+name)
+		} else {
+			s = b.record(decl, decl.Spelling(), "C."+decl.TagKeyword()+"_"+decl.Spelling())
+		}
+		if s != nil {
+			b.structs[usr] = s
+		}
+	case clang.Type_Enum:
+		decl := t.Declaration()
+		var e *Enum
+		if decl.IsUnnamed() {
+			e = b.enum(decl, name, "C."+name)
+		} else {
+			e = b.enum(decl, decl.Spelling(), "C.enum_"+decl.Spelling())
+		}
+		if e != nil {
+			b.enums[usr] = e
+		}
+	case clang.Type_Pointer:
+		if t.PointeeType().Kind() != clang.Type_FunctionProto {
+			return
+		}
+
+		cb := &Callback{
+			Name:   name,
+			GoName: b.names.exported(name),
+			Export: "bindgen_" + name,
+		}
+		b.callbacks[usr] = cb
+		b.callbackDecls[cb] = c
+		b.callbackOrder = append(b.callbackOrder, cb)
+	default:
+		if p, ok := primitive(t); ok {
+			td := &Typedef{
+				Name:   name,
+				GoName: b.names.exported(name),
+				Go:     p.Go,
+			}
+			b.typedefs[usr] = td
+			b.module.Typedefs = append(b.module.Typedefs, td)
+		}
+	}
+}
+
+// fields sets the layout of the struct declared by c. Structs without a definition are opaque.
+func (b *binder) fields(s *Struct, c clang.Cursor) {
+	def := c.Definition()
+	if def.IsNull() {
+		s.Opaque = true
+
+		return
+	}
+
+	r, err := layout.Inspect(def)
+	if err != nil {
+		s.Opaque = true
+
+		return
+	}
+	s.Size, s.Align = r.Size, r.Align
+	if s.Union {
+		return
+	}
+
+	types := map[string]clang.Type{}
+	def.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() == clang.Cursor_FieldDecl {
+			types[child.Spelling()] = child.Type()
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	names := newNamer(&Config{})
+	for _, f := range r.Fields {
+		// Bit-fields are left to the padding between the fields.
+		if f.BitField {
+			continue
+		}
+
+		field := Field{
+			Name:   f.Name,
+			Offset: f.Offset / 8,
+			Size:   f.Size,
+			Align:  f.Align,
+		}
+		// Fields of anonymous records and misaligned fields of packed structs are only
+		// represented by their bytes.
+		if t, ok := types[f.Name]; ok && f.Name != "" && f.Offset%(f.Align*8) == 0 {
+			if ft, err := b.typ(t, "", ""); err == nil {
+				field.GoName = names.exported(f.Name)
+				field.Type = ft
+			}
+		}
+		s.Fields = append(s.Fields, field)
+	}
+}
+
+// constants sets the underlying type and the constants of the enumeration declared by c.
+func (b *binder) constants(e *Enum, c clang.Cursor) {
+	if def := c.Definition(); !def.IsNull() {
+		c = def
+	}
+
+	p, ok := primitive(c.EnumDeclIntegerType().CanonicalType())
+	if !ok {
+		b.skip(e.Name, fmt.Errorf("unsupported integer type %s", c.EnumDeclIntegerType().Spelling()))
+		delete(b.enums, c.USR())
+
+		return
+	}
+	e.Go = p.Go
+	e.Constants = b.enumerators(c, e.GoName)
+
+	b.module.Enums = append(b.module.Enums, e)
+}
+
+// enumerators returns the constants of the enumeration c with the Go type typ.
+func (b *binder) enumerators(c clang.Cursor, typ string) []Constant {
+	unsigned := strings.HasPrefix(primitiveGo(c.EnumDeclIntegerType().CanonicalType()), "u")
+
+	var consts []Constant
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() != clang.Cursor_EnumConstantDecl || b.cfg.ignored(child.Spelling()) {
+			return clang.ChildVisit_Continue
+		}
+
+		value := strconv.FormatInt(child.EnumConstantDeclValue(), 10)
+		if unsigned {
+			value = strconv.FormatUint(child.EnumConstantDeclUnsignedValue(), 10)
+		}
+		consts = append(consts, Constant{
+			Name:   child.Spelling(),
+			GoName: b.names.exported(child.Spelling()),
+			Value:  value,
+			Type:   typ,
+		})
+
+		return clang.ChildVisit_Continue
+	})
+
+	return consts
+}
+
+// callback binds the parameters and the result of the callback declared by the typedef c. It
+// is not bound without a void * parameter passing the user data.
+func (b *binder) callback(cb *Callback, c clang.Cursor) {
+	fn := c.TypedefDeclUnderlyingType().CanonicalType().PointeeType()
+
+	var names []string
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() == clang.Cursor_ParmDecl {
+			names = append(names, child.Spelling())
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	err := func() error {
+		var decls []string
+		user := false
+		for i := 0; i < int(fn.NumArgTypes()); i++ {
+			t := fn.ArgType(uint32(i))
+
+			name := ""
+			if i < len(names) {
+				name = names[i]
+			}
+			p := Param{Name: name, GoName: localName(name, i)}
+			if isVoidPointer(t) && !user {
+				p.Type, p.UserData, user = voidPointer, true, true
+			} else {
+				pt, err := b.typ(t, cb.Name, name)
+				if err != nil {
+					return err
+				}
+				p.Type = pt
+			}
+			if p.Type.Conv == Conv_Callback {
+				return fmt.Errorf("callback parameter %s", name)
+			}
+			cb.Params = append(cb.Params, p)
+			decls = append(decls, unqualified(t.Spelling()))
+		}
+		if !user {
+			return fmt.Errorf("no user data parameter")
+		}
+
+		rt := fn.ResultType()
+		result := "void"
+		if rt.Kind() != clang.Type_Void {
+			t, err := b.typ(rt, "", "")
+			if err != nil {
+				return err
+			}
+			cb.Result = &t
+			result = unqualified(rt.Spelling())
+		}
+		cb.Decl = fmt.Sprintf("%s %s(%s)", result, cb.Export, strings.Join(decls, ", "))
+
+		return nil
+	}()
+	if err != nil {
+		b.skip(cb.Name, err)
+		delete(b.callbacks, c.USR())
+
+		return
+	}
+
+	b.module.Callbacks = append(b.module.Callbacks, cb)
+}
+
+// function binds the function declared by c. Every callback parameter has to be followed by a
+// void * parameter passing its user data.
+func (b *binder) function(c clang.Cursor) {
+	name := c.Spelling()
+	usr := c.USR()
+	if b.seen[usr] || b.cfg.ignored(name) || c.Linkage() != clang.Linkage_External {
+		return
+	}
+	b.seen[usr] = true
+
+	t := c.Type()
+	switch {
+	case t.Kind() != clang.Type_FunctionProto:
+		b.skip(name, fmt.Errorf("no prototype"))
+
+		return
+	case t.IsFunctionTypeVariadic():
+		b.skip(name, fmt.Errorf("variadic"))
+
+		return
+	}
+
+	f := &Function{
+		Name:   name,
+		GoName: b.names.exported(name),
+		Sync:   b.cfg.isSync(name),
+	}
+
+	pending := 0
+	for i := 0; i < int(t.NumArgTypes()); i++ {
+		at := t.ArgType(uint32(i))
+		pname := c.Argument(uint32(i)).Spelling()
+
+		p := Param{Name: pname, GoName: localName(pname, i)}
+		if pending > 0 && isVoidPointer(at) {
+			p.Type, p.UserData = voidPointer, true
+			pending--
+		} else {
+			pt, err := b.typ(at, name, pname)
+			if err != nil {
+				b.skip(name, err)
+
+				return
+			}
+			p.Type = pt
+			if pt.Conv == Conv_Callback {
+				p.Callback = b.callbackOf(at)
+				pending++
+			}
+		}
+		f.Params = append(f.Params, p)
+	}
+	if pending > 0 {
+		b.skip(name, fmt.Errorf("callback without user data parameter"))
+
+		return
+	}
+
+	if rt := t.ResultType(); rt.Kind() != clang.Type_Void {
+		r, err := b.typ(rt, name, "return")
+		if err != nil {
+			b.skip(name, err)
+
+			return
+		}
+		if r.Conv == Conv_Callback {
+			b.skip(name, fmt.Errorf("callback result"))
+
+			return
+		}
+		f.Result = &r
+	}
+
+	b.module.Functions = append(b.module.Functions, f)
+}
+
+// evaluate evaluates the integer macros of the header by parsing a file which includes the
+// header and initializes a variable with every macro.
+func (b *binder) evaluate(idx clang.Index, header string, args []string) error {
+	if len(b.macros) == 0 {
+		return nil
+	}
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "#include %q\n", header)
+	for _, m := range b.macros {
+		fmt.Fprintf(&src, "static const __typeof__((%s)) %s%s = (%s);\n", m, macroPrefix, m, m)
+	}
+
+	file := filepath.Join(filepath.Dir(header), macroPrefix+"macros.c")
+	args = append(append([]string(nil), args...), "-ferror-limit=0")
+	unsaved := []clang.UnsavedFile{clang.NewUnsavedFile(file, src.String())}
+	defer unsaved[0].Dispose()
+	options := uint32(clang.TranslationUnit_SkipFunctionBodies | clang.TranslationUnit_KeepGoing)
+
+	var tu clang.TranslationUnit
+	if ec := idx.ParseTranslationUnit2(file, args, unsaved, options, &tu); ec != clang.Error_Success {
+		return fmt.Errorf("parse macros of %s: %s", header, ec)
+	}
+	defer tu.Dispose()
+
+	values := map[string]string{}
+	tu.TranslationUnitCursor().Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		name := c.Spelling()
+		if c.Kind() != clang.Cursor_VarDecl || !c.Location().IsFromMainFile() || !strings.HasPrefix(name, macroPrefix) {
+			return clang.ChildVisit_Continue
+		}
+
+		r := c.Evaluate()
+		if r.Kind() == clang.Eval_Int {
+			if r.IsUnsignedInt() {
+				values[name] = strconv.FormatUint(r.AsUnsigned(), 10)
+			} else {
+				values[name] = strconv.FormatInt(r.AsLongLong(), 10)
+			}
+		}
+		r.Dispose()
+
+		return clang.ChildVisit_Continue
+	})
+
+	for _, m := range b.macros {
+		if v, ok := values[macroPrefix+m]; ok {
+			b.module.Constants = append(b.module.Constants, Constant{
+				Name:   m,
+				GoName: b.names.exported(m),
+				Value:  v,
+			})
+		}
+	}
+
+	return nil
+}
+
+var voidPointer = Type{Go: "unsafe.Pointer", C: "unsafe.Pointer", Conv: Conv_Pointer}
+
+// typ returns the binding of the C type t. Strings and callbacks are only bound for the
+// parameters and results of functions, param is the name of the parameter or "return".
+func (b *binder) typ(t clang.Type, function, param string) (Type, error) {
+	switch t.Kind() {
+	case clang.Type_Elaborated:
+		return b.typ(t.NamedType(), function, param)
+	case clang.Type_Typedef:
+		decl := t.Declaration()
+		usr := decl.USR()
+		ctype := "C." + decl.Spelling()
+
+		if s := b.structs[usr]; s != nil && !s.Opaque {
+			return Type{Go: s.GoName, C: ctype, Conv: Conv_Struct}, nil
+		}
+		if e := b.enums[usr]; e != nil {
+			return Type{Go: e.GoName, C: ctype, Conv: Conv_Value}, nil
+		}
+		if td := b.typedefs[usr]; td != nil {
+			return Type{Go: td.GoName, C: ctype, Conv: Conv_Value}, nil
+		}
+		if cb := b.callbacks[usr]; cb != nil && function != "" {
+			return Type{Go: cb.GoName, C: ctype, Conv: Conv_Callback}, nil
+		}
+
+		u, err := b.typ(decl.TypedefDeclUnderlyingType(), "", "")
+		if err != nil {
+			return Type{}, err
+		}
+		u.C = ctype
+
+		return u, nil
+	case clang.Type_Record:
+		if s := b.structs[t.Declaration().USR()]; s != nil && !s.Opaque {
+			return Type{Go: s.GoName, C: s.C, Conv: Conv_Struct}, nil
+		}
+	case clang.Type_Enum:
+		if e := b.enums[t.Declaration().USR()]; e != nil {
+			return Type{Go: e.GoName, C: e.C, Conv: Conv_Value}, nil
+		}
+	case clang.Type_Pointer:
+		return b.pointer(t, function, param)
+	case clang.Type_ConstantArray:
+		elem, err := b.typ(t.ArrayElementType(), "", "")
+		if err != nil {
+			return Type{}, err
+		}
+		n := strconv.FormatInt(t.ArraySize(), 10)
+
+		return Type{Go: "[" + n + "]" + elem.Go, C: "[" + n + "]" + elem.C, Conv: Conv_Value}, nil
+	default:
+		if p, ok := primitive(t); ok {
+			return p, nil
+		}
+	}
+
+	return Type{}, fmt.Errorf("unsupported type %s", t.Spelling())
+}
+
+// pointer returns the binding of the pointer type t. Pointers to types which are not bound are
+// bound to unsafe.Pointer if the type has a cgo name.
+func (b *binder) pointer(t clang.Type, function, param string) (Type, error) {
+	pointee := t.PointeeType()
+
+	switch pointee.CanonicalType().Kind() {
+	case clang.Type_Void:
+		return voidPointer, nil
+	case clang.Type_FunctionProto, clang.Type_FunctionNoProto:
+		return Type{Go: "*[0]byte", C: "*[0]byte", Conv: Conv_Pointer}, nil
+	}
+
+	switch pointee.Kind() {
+	case clang.Type_Char_S, clang.Type_Char_U:
+		if function != "" && b.cfg.isString(function, param, pointee.IsConstQualifiedType()) {
+			return Type{Go: "string", C: "*C.char", Conv: Conv_String}, nil
+		}
+	}
+
+	// Opaque structs can only be used through pointers.
+	if s, ctype := b.structOf(pointee); s != nil {
+		return Type{Go: "*" + s.GoName, C: "*" + ctype, Conv: Conv_Pointer}, nil
+	}
+
+	elem, err := b.typ(pointee, "", "")
+	if err == nil {
+		return Type{Go: "*" + elem.Go, C: "*" + elem.C, Conv: Conv_Pointer}, nil
+	}
+	if name, ok := cgoName(pointee); ok {
+		return Type{Go: "unsafe.Pointer", C: "*" + name, Conv: Conv_Pointer}, nil
+	}
+
+	return Type{}, err
+}
+
+// structOf returns the struct bound to the record type t and its cgo type.
+func (b *binder) structOf(t clang.Type) (*Struct, string) {
+	switch t.Kind() {
+	case clang.Type_Elaborated:
+		return b.structOf(t.NamedType())
+	case clang.Type_Typedef:
+		decl := t.Declaration()
+		if s := b.structs[decl.USR()]; s != nil {
+			return s, "C." + decl.Spelling()
+		}
+	case clang.Type_Record:
+		if s := b.structs[t.Declaration().USR()]; s != nil {
+			return s, s.C
+		}
+	}
+
+	return nil, ""
+}
+
+// callbackOf returns the callback of the typedef t.
+func (b *binder) callbackOf(t clang.Type) *Callback {
+	if t.Kind() == clang.Type_Elaborated {
+		t = t.NamedType()
+	}
+
+	return b.callbacks[t.Declaration().USR()]
+}
+
+// primitive returns the binding of the numeric or boolean type t.
+func primitive(t clang.Type) (Type, bool) {
+	var ctype string
+	switch t.Kind() {
+	case clang.Type_Bool:
+		return Type{Go: "bool", C: "C._Bool", Conv: Conv_Value}, true
+	case clang.Type_Char_S, clang.Type_Char_U:
+		return Type{Go: "byte", C: "C.char", Conv: Conv_Value}, true
+	case clang.Type_SChar:
+		ctype = "C.schar"
+	case clang.Type_UChar:
+		ctype = "C.uchar"
+	case clang.Type_Short:
+		ctype = "C.short"
+	case clang.Type_UShort:
+		ctype = "C.ushort"
+	case clang.Type_Int:
+		ctype = "C.int"
+	case clang.Type_UInt:
+		ctype = "C.uint"
+	case clang.Type_Long:
+		ctype = "C.long"
+	case clang.Type_ULong:
+		ctype = "C.ulong"
+	case clang.Type_LongLong:
+		ctype = "C.longlong"
+	case clang.Type_ULongLong:
+		ctype = "C.ulonglong"
+	case clang.Type_Float:
+		return Type{Go: "float32", C: "C.float", Conv: Conv_Value}, true
+	case clang.Type_Double:
+		return Type{Go: "float64", C: "C.double", Conv: Conv_Value}, true
+	default:
+		return Type{}, false
+	}
+
+	return Type{Go: primitiveGo(t), C: ctype, Conv: Conv_Value}, true
+}
+
+// primitiveGo returns the Go integer type with the size and signedness of the integer type t.
+func primitiveGo(t clang.Type) string {
+	bits := strconv.FormatInt(t.SizeOf()*8, 10)
+
+	switch t.Kind() {
+	case clang.Type_UChar, clang.Type_UShort, clang.Type_UInt, clang.Type_ULong, clang.Type_ULongLong, clang.Type_Char_U:
+		return "uint" + bits
+	}
+
+	return "int" + bits
+}
+
+// cgoName returns the cgo name of the named type t, e.g. "C.struct_point" or "C.size_t".
+func cgoName(t clang.Type) (string, bool) {
+	switch t.Kind() {
+	case clang.Type_Elaborated:
+		return cgoName(t.NamedType())
+	case clang.Type_Typedef:
+		return "C." + t.Declaration().Spelling(), true
+	case clang.Type_Record, clang.Type_Enum:
+		decl := t.Declaration()
+		if !decl.IsUnnamed() {
+			return "C." + decl.TagKeyword() + "_" + decl.Spelling(), true
+		}
+	}
+
+	return "", false
+}
+
+func isVoidPointer(t clang.Type) bool {
+	t = t.CanonicalType()
+
+	return t.Kind() == clang.Type_Pointer && t.PointeeType().CanonicalType().Kind() == clang.Type_Void
+}
+
+// unqualified removes the qualifiers from the spelling of a type, since the C declarations of
+// exported Go functions do not have them.
+func unqualified(spelling string) string {
+	var words []string
+	for _, w := range strings.Fields(spelling) {
+		switch w {
+		case "const", "volatile", "restrict":
+		default:
+			words = append(words, w)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,77 @@
+package bindgen
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func TestBind(t *testing.T) {
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	cfg := &Config{
+		Package:       "geo",
+		Prefixes:      []string{"geo_", "GEO_"},
+		SyncCallbacks: []string{"geo_visit"},
+	}
+	m, err := Bind(idx, filepath.Join("testdata", "geo.h"), []string{"-xc"}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header, _ := filepath.Abs(filepath.Join("testdata", "geo.h")); m.Header != header {
+		t.Errorf("expected header %s. got=%s", header, m.Header)
+	}
+
+	if want := []*Typedef{{Name: "geo_real", GoName: "Real", Go: "float64"}}; !reflect.DeepEqual(m.Typedefs, want) {
+		t.Errorf("unexpected typedefs %+v", m.Typedefs)
+	}
+
+	wantEnum := &Enum{Name: "enum geo_color", GoName: "Color", C: "C.enum_geo_color", Go: "uint32", Constants: []Constant{
+		{Name: "GEO_RED", GoName: "Red", Value: "0", Type: "Color"},
+		{Name: "GEO_GREEN", GoName: "Green", Value: "4", Type: "Color"},
+	}}
+	if len(m.Enums) != 1 || !reflect.DeepEqual(m.Enums[0], wantEnum) {
+		t.Errorf("expected enum %+v. got=%+v", wantEnum, m.Enums)
+	}
+
+	wantMacros := []Constant{{Name: "GEO_MAX", GoName: "Max", Value: "16"}, {Name: "GEO_SCALE", GoName: "Scale", Value: "32"}}
+	if !reflect.DeepEqual(m.Constants, wantMacros) {
+		t.Errorf("expected macros %+v. got=%+v", wantMacros, m.Constants)
+	}
+
+	wantStruct := &Struct{Name: "struct geo_point", GoName: "Point", C: "C.struct_geo_point", Size: 16, Align: 8, Fields: []Field{
+		{Name: "x", GoName: "X", Type: Type{Go: "int32", C: "C.int", Conv: Conv_Value}, Offset: 0, Size: 4, Align: 4},
+		{Name: "y", GoName: "Y", Type: Type{Go: "Real", C: "C.geo_real", Conv: Conv_Value}, Offset: 8, Size: 8, Align: 8},
+	}}
+	if len(m.Structs) != 1 || !reflect.DeepEqual(m.Structs[0], wantStruct) {
+		t.Errorf("expected struct %+v. got=%+v", wantStruct, m.Structs)
+	}
+
+	if len(m.Callbacks) != 1 {
+		t.Fatalf("expected one callback. got=%+v", m.Callbacks)
+	}
+	cb := m.Callbacks[0]
+	if cb.GoName != "VisitFn" || cb.Decl != "int bindgen_geo_visit_fn(struct geo_point *, void *)" ||
+		len(cb.Params) != 2 || cb.Params[0].Type.Go != "*Point" || !cb.Params[1].UserData {
+		t.Errorf("unexpected callback %+v", cb)
+	}
+
+	functions := map[string]*Function{}
+	for _, f := range m.Functions {
+		functions[f.Name] = f
+	}
+	if f := functions["geo_name"]; f == nil || f.GoName != "Name" || f.Params[0].Type.Conv != Conv_String ||
+		f.Result == nil || f.Result.Conv != Conv_String {
+		t.Errorf("expected geo_name to convert strings. got=%+v", f)
+	}
+	if f := functions["geo_visit"]; f == nil || !f.Sync || f.Params[0].Callback != cb || !f.Params[1].UserData {
+		t.Errorf("expected geo_visit to take the callback with its user data. got=%+v", f)
+	}
+	if _, ok := functions["geo_log"]; ok || !reflect.DeepEqual(m.Skipped, []string{"geo_log: variadic"}) {
+		t.Errorf("expected the variadic geo_log to be skipped. got=%v", m.Skipped)
+	}
+}
@@ -0,0 +1,471 @@
+package bindgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strings"
+)
+
+// Generate returns the Go source of the bindings of the module.
+func Generate(m *Module) ([]byte, error) {
+	g := &generator{module: m}
+
+	g.typedefs()
+	g.enums()
+	g.constants()
+	g.structs()
+	g.layoutChecks()
+	if len(m.Callbacks) > 0 {
+		g.registry()
+		g.callbackTypes()
+	}
+	for _, f := range m.Functions {
+		g.function(f)
+	}
+
+	var b bytes.Buffer
+	g.header(&b, true)
+	b.Write(g.body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+// GenerateCallbacks returns the Go source of the exported functions implementing the callbacks
+// of the module, or nil if there are no callbacks. The source has to be a separate file, since
+// the preamble of a file with exported functions must not contain C definitions.
+func GenerateCallbacks(m *Module) ([]byte, error) {
+	if len(m.Callbacks) == 0 {
+		return nil, nil
+	}
+
+	g := &generator{module: m}
+	for _, cb := range m.Callbacks {
+		g.export(cb)
+	}
+
+	var b bytes.Buffer
+	g.header(&b, false)
+	b.Write(g.body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+type generator struct {
+	module *Module
+	body   bytes.Buffer
+	unsafe bool
+	sync   bool
+	stdlib bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+// header writes the package clause, the preamble and the imports.
+func (g *generator) header(b *bytes.Buffer, main bool) {
+	m := g.module
+
+	fmt.Fprintf(b, "// Code generated by cgo-bindgen from %s. DO NOT EDIT.\n\n", filepath.Base(m.Header))
+	fmt.Fprintf(b, "package %s\n\n", m.Package)
+
+	b.WriteString("/*\n")
+	if main && m.CFlags != "" {
+		fmt.Fprintf(b, "#cgo CFLAGS: %s\n", m.CFlags)
+	}
+	if main && m.LDFlags != "" {
+		fmt.Fprintf(b, "#cgo LDFLAGS: %s\n", m.LDFlags)
+	}
+	if g.stdlib {
+		b.WriteString("#include <stdlib.h>\n")
+	}
+	include := m.Include
+	if include == "" {
+		include = fmt.Sprintf("#include %q", filepath.Base(m.Header))
+	}
+	fmt.Fprintf(b, "%s\n", include)
+	if main && len(m.Callbacks) > 0 {
+		b.WriteString("\n")
+		for _, cb := range m.Callbacks {
+			fmt.Fprintf(b, "extern %s;\n", cb.Decl)
+		}
+	}
+	b.WriteString("*/\nimport \"C\"\n\n")
+
+	var imports []string
+	if g.sync {
+		imports = append(imports, `"sync"`)
+	}
+	if g.unsafe {
+		imports = append(imports, `"unsafe"`)
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(b, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	}
+}
+
+func (g *generator) typedefs() {
+	for _, t := range g.module.Typedefs {
+		g.printf("// %s is %s.\ntype %s %s\n\n", t.GoName, t.Name, t.GoName, t.Go)
+	}
+}
+
+func (g *generator) enums() {
+	for _, e := range g.module.Enums {
+		g.printf("// %s is %s.\ntype %s %s\n\n", e.GoName, e.Name, e.GoName, e.Go)
+		if len(e.Constants) == 0 {
+			continue
+		}
+
+		g.printf("const (\n")
+		for _, c := range e.Constants {
+			g.printf("\t%s %s = %s // %s\n", c.GoName, c.Type, c.Value, c.Name)
+		}
+		g.printf(")\n\n")
+	}
+}
+
+func (g *generator) constants() {
+	if len(g.module.Constants) == 0 {
+		return
+	}
+
+	g.printf("const (\n")
+	for _, c := range g.module.Constants {
+		if c.Type != "" {
+			g.printf("\t%s %s = %s // %s\n", c.GoName, c.Type, c.Value, c.Name)
+		} else {
+			g.printf("\t%s = %s // %s\n", c.GoName, c.Value, c.Name)
+		}
+	}
+	g.printf(")\n\n")
+}
+
+func (g *generator) structs() {
+	for _, s := range g.module.Structs {
+		g.printf("// %s is %s.\n", s.GoName, s.Name)
+
+		switch {
+		case s.Opaque:
+			g.printf("type %s %s\n\n", s.GoName, s.C)
+		case s.Union:
+			g.printf("type %s struct {\n\t_ [0]%s\n\tData [%d]byte\n}\n\n", s.GoName, alignType(s.Align), s.Size)
+		default:
+			g.printf("type %s struct {\n", s.GoName)
+			offset := int64(0)
+			for _, f := range s.Fields {
+				if f.Offset > offset {
+					g.printf("\t_ [%d]byte\n", f.Offset-offset)
+				}
+				switch {
+				case f.GoName == "" && f.Name != "":
+					g.printf("\t_ [%d]byte // %s\n", f.Size, f.Name)
+				case f.GoName == "":
+					g.printf("\t_ [%d]byte\n", f.Size)
+				default:
+					g.printf("\t%s %s\n", f.GoName, f.Type.Go)
+				}
+				offset = f.Offset + f.Size
+			}
+			if s.Size > offset {
+				g.printf("\t_ [%d]byte\n", s.Size-offset)
+			}
+			g.printf("}\n\n")
+		}
+	}
+}
+
+// alignType returns an unsigned integer type with the alignment in bytes.
+func alignType(align int64) string {
+	switch {
+	case align >= 8:
+		return "uint64"
+	case align >= 4:
+		return "uint32"
+	case align >= 2:
+		return "uint16"
+	}
+
+	return "uint8"
+}
+
+// layoutChecks writes expressions which only compile if the sizes and field offsets of the Go
+// structs match the C structs.
+func (g *generator) layoutChecks() {
+	var checks []string
+	for _, s := range g.module.Structs {
+		if s.Opaque {
+			continue
+		}
+
+		checks = append(checks, fmt.Sprintf("unsafe.Sizeof(%s{}) - unsafe.Sizeof(%s{})", s.GoName, s.C))
+		if s.Union {
+			continue
+		}
+		for _, f := range s.Fields {
+			if f.GoName != "" {
+				checks = append(checks, fmt.Sprintf("unsafe.Offsetof(%s{}.%s) - unsafe.Offsetof(%s{}.%s)",
+					s.GoName, f.GoName, s.C, cgoField(f.Name)))
+			}
+		}
+	}
+	if len(checks) == 0 {
+		return
+	}
+
+	g.unsafe = true
+	g.printf("// The layouts of the structs are checked at compile time. An index out of range or an\n")
+	g.printf("// overflowing constant below means that the Go and C layouts differ.\n")
+	g.printf("var (\n")
+	for _, c := range checks {
+		g.printf("\t_ = [1]struct{}{}[%s]\n", c)
+	}
+	g.printf(")\n\n")
+}
+
+// registry writes the table of the Go functions passed to C as callbacks.
+func (g *generator) registry() {
+	g.unsafe, g.sync, g.stdlib = true, true, true
+
+	g.printf(`// callbacks holds the Go functions passed to C. They are looked up by the handles passed as
+// user data, which are unique pointers allocated by C.
+var callbacks = struct {
+	sync.Mutex
+	funcs map[unsafe.Pointer]interface{}
+}{funcs: map[unsafe.Pointer]interface{}{}}
+
+func registerCallback(f interface{}) unsafe.Pointer {
+	h := C.malloc(1)
+
+	callbacks.Lock()
+	callbacks.funcs[h] = f
+	callbacks.Unlock()
+
+	return h
+}
+
+func releaseCallback(h unsafe.Pointer) {
+	callbacks.Lock()
+	delete(callbacks.funcs, h)
+	callbacks.Unlock()
+
+	C.free(h)
+}
+
+func lookupCallback(h unsafe.Pointer) interface{} {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+
+	return callbacks.funcs[h]
+}
+
+`)
+}
+
+func (g *generator) callbackTypes() {
+	for _, cb := range g.module.Callbacks {
+		var params []string
+		for _, p := range cb.Params {
+			if !p.UserData {
+				params = append(params, p.GoName+" "+p.Type.Go)
+			}
+		}
+
+		result := ""
+		if cb.Result != nil {
+			result = " " + cb.Result.Go
+		}
+
+		g.printf("// %s is the callback %s.\ntype %s func(%s)%s\n\n", cb.GoName, cb.Name, cb.GoName,
+			strings.Join(params, ", "), result)
+	}
+}
+
+// function writes the wrapper of a C function.
+func (g *generator) function(f *Function) {
+	callbacks := f.HasCallbacks()
+	release := callbacks && !f.Sync
+
+	var params, results, args []string
+	var userData []string
+	for _, p := range f.Params {
+		if p.UserData {
+			v := "c_" + p.GoName
+			userData = append(userData, v)
+			args = append(args, v)
+
+			continue
+		}
+
+		params = append(params, p.GoName+" "+p.Type.Go)
+		args = append(args, g.toC(p))
+	}
+	if f.Result != nil {
+		results = append(results, f.Result.Go)
+	}
+	if release {
+		results = append(results, "func()")
+	}
+
+	signature := fmt.Sprintf("func %s(%s)", f.GoName, strings.Join(params, ", "))
+	switch len(results) {
+	case 0:
+	case 1:
+		signature += " " + results[0]
+	default:
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	g.printf("// %s wraps %s.\n", f.GoName, f.Name)
+	if release {
+		g.printf("//\n// The returned function releases the callbacks passed to %s.\n", f.Name)
+	}
+	g.printf("%s {\n", signature)
+
+	for _, v := range userData {
+		g.printf("\tvar %s unsafe.Pointer\n", v)
+	}
+	if release {
+		g.printf("\tvar c_handles []unsafe.Pointer\n")
+	}
+
+	n := 0
+	for _, p := range f.Params {
+		switch p.Type.Conv {
+		case Conv_String:
+			g.stdlib, g.unsafe = true, true
+			g.printf("\tc_%s := C.CString(%s)\n\tdefer C.free(unsafe.Pointer(c_%s))\n", p.GoName, p.GoName, p.GoName)
+		case Conv_Callback:
+			g.printf("\tvar c_%s %s\n", p.GoName, p.Type.C)
+			if n >= len(userData) {
+				break
+			}
+
+			g.printf("\tif %s != nil {\n\t\tc_h := registerCallback(%s)\n", p.GoName, p.GoName)
+			if release {
+				g.printf("\t\tc_handles = append(c_handles, c_h)\n")
+			} else {
+				g.printf("\t\tdefer releaseCallback(c_h)\n")
+			}
+			g.printf("\t\tc_%s, %s = %s(C.%s), c_h\n\t}\n", p.GoName, userData[n], p.Type.C, p.Callback.Export)
+			n++
+		}
+	}
+
+	call := fmt.Sprintf("C.%s(%s)", f.Name, strings.Join(args, ", "))
+	var ret []string
+	if f.Result != nil {
+		g.printf("\tc_r := %s\n", call)
+		ret = append(ret, g.fromC(*f.Result, "c_r"))
+	} else {
+		g.printf("\t%s\n", call)
+	}
+	if release {
+		ret = append(ret, "func() {\n\t\tfor _, c_h := range c_handles {\n\t\t\treleaseCallback(c_h)\n\t\t}\n\t}")
+	}
+	if len(ret) > 0 {
+		g.printf("\n\treturn %s\n", strings.Join(ret, ", "))
+	}
+	g.printf("}\n\n")
+}
+
+// toC returns the expression converting the Go parameter to its C type.
+func (g *generator) toC(p Param) string {
+	v := p.GoName
+	t := p.Type
+
+	switch t.Conv {
+	case Conv_Value:
+		return fmt.Sprintf("%s(%s)", t.C, v)
+	case Conv_Pointer:
+		if t.C == "unsafe.Pointer" {
+			if t.Go == "unsafe.Pointer" {
+				return v
+			}
+			g.unsafe = true
+
+			return fmt.Sprintf("unsafe.Pointer(%s)", v)
+		}
+		g.unsafe = true
+		if t.Go == "unsafe.Pointer" {
+			return fmt.Sprintf("(%s)(%s)", t.C, v)
+		}
+
+		return fmt.Sprintf("(%s)(unsafe.Pointer(%s))", t.C, v)
+	case Conv_Struct:
+		g.unsafe = true
+
+		return fmt.Sprintf("*(*%s)(unsafe.Pointer(&%s))", t.C, v)
+	case Conv_String, Conv_Callback:
+		return "c_" + v
+	}
+
+	return v
+}
+
+// fromC returns the expression converting the C value v to its Go type. Values converted with
+// Conv_Struct must be addressable.
+func (g *generator) fromC(t Type, v string) string {
+	switch t.Conv {
+	case Conv_Value:
+		return fmt.Sprintf("%s(%s)", t.Go, v)
+	case Conv_Pointer:
+		if t.Go == "unsafe.Pointer" {
+			if t.C == "unsafe.Pointer" {
+				return v
+			}
+			g.unsafe = true
+
+			return fmt.Sprintf("unsafe.Pointer(%s)", v)
+		}
+		g.unsafe = true
+
+		return fmt.Sprintf("(%s)(unsafe.Pointer(%s))", t.Go, v)
+	case Conv_String:
+		return fmt.Sprintf("C.GoString(%s)", v)
+	case Conv_Struct:
+		g.unsafe = true
+
+		return fmt.Sprintf("*(*%s)(unsafe.Pointer(&%s))", t.Go, v)
+	}
+
+	return v
+}
+
+// export writes the exported Go function implementing the callback.
+func (g *generator) export(cb *Callback) {
+	g.unsafe = true
+
+	var params, args []string
+	user := ""
+	for _, p := range cb.Params {
+		params = append(params, p.GoName+" "+p.Type.C)
+		if p.UserData {
+			if user == "" {
+				user = p.GoName
+			}
+
+			continue
+		}
+		args = append(args, g.fromC(p.Type, p.GoName))
+	}
+
+	result := ""
+	if cb.Result != nil {
+		result = " " + cb.Result.C
+	}
+
+	g.printf("//export %s\nfunc %s(%s)%s {\n", cb.Export, cb.Export, strings.Join(params, ", "), result)
+	g.printf("\tc_f := lookupCallback(%s).(%s)\n", user, cb.GoName)
+
+	call := fmt.Sprintf("c_f(%s)", strings.Join(args, ", "))
+	if cb.Result == nil {
+		g.printf("\t%s\n}\n\n", call)
+
+		return
+	}
+
+	g.printf("\tc_v := %s\n\n\treturn %s\n}\n\n", call, g.toC(Param{GoName: "c_v", Type: *cb.Result}))
+}
@@ -0,0 +1,149 @@
+package bindgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func module() *Module {
+	int32Type := Type{Go: "int32", C: "C.int", Conv: Conv_Value}
+	point := Type{Go: "*Point", C: "*C.struct_point", Conv: Conv_Pointer}
+	user := Param{Name: "user", GoName: "user", Type: voidPointer, UserData: true}
+
+	cb := &Callback{
+		Name:   "visit_fn",
+		GoName: "VisitFn",
+		Params: []Param{{Name: "p", GoName: "p", Type: point}, user},
+		Result: &int32Type,
+		Export: "bindgen_visit_fn",
+		Decl:   "int bindgen_visit_fn(struct point *, void *)",
+	}
+
+	return &Module{
+		Package: "geo",
+		Header:  "/src/geo.h",
+		Structs: []*Struct{{
+			Name:   "struct point",
+			GoName: "Point",
+			C:      "C.struct_point",
+			Size:   16,
+			Align:  8,
+			Fields: []Field{
+				{Name: "x", GoName: "X", Type: int32Type, Offset: 0, Size: 4, Align: 4},
+				{Name: "type", GoName: "Type", Type: Type{Go: "float64"}, Offset: 8, Size: 8, Align: 8},
+			},
+		}},
+		Callbacks: []*Callback{cb},
+		Functions: []*Function{
+			{
+				Name:   "geo_name",
+				GoName: "Name",
+				Params: []Param{{Name: "s", GoName: "s", Type: Type{Go: "string", C: "*C.char", Conv: Conv_String}}},
+				Result: &Type{Go: "string", C: "*C.char", Conv: Conv_String},
+			},
+			{
+				Name:   "geo_watch",
+				GoName: "Watch",
+				Params: []Param{
+					{Name: "fn", GoName: "fn", Type: Type{Go: "VisitFn", C: "C.visit_fn", Conv: Conv_Callback}, Callback: cb},
+					user,
+				},
+			},
+		},
+	}
+}
+
+// parse parses the generated source and returns the names of its top-level functions and
+// types, so that syntactically invalid output fails the tests.
+func parse(t *testing.T, src []byte) map[string]bool {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "geo.go", src, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		t.Fatalf("invalid source: %v\n%s", err, src)
+	}
+	if f.Name.Name != "geo" {
+		t.Errorf("expected package geo. got=%s", f.Name.Name)
+	}
+
+	names := map[string]bool{}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			names[d.Name.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					names[ts.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(module())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := parse(t, src)
+	for _, want := range []string{"Point", "VisitFn", "Name", "Watch"} {
+		if !names[want] {
+			t.Errorf("expected a declaration of %s", want)
+		}
+	}
+
+	for _, want := range []string{
+		"// Code generated by cgo-bindgen from geo.h. DO NOT EDIT.",
+		"#include <stdlib.h>\n#include \"geo.h\"\n",
+		"extern int bindgen_visit_fn(struct point *, void *);",
+		"import (\n\t\"sync\"\n\t\"unsafe\"\n)",
+		"type Point struct {\n\tX    int32\n\t_    [4]byte\n\tType float64\n}",
+		"unsafe.Offsetof(Point{}.Type)-unsafe.Offsetof(C.struct_point{}._type)",
+		"type VisitFn func(p *Point) int32",
+		"func Name(s string) string {\n\tc_s := C.CString(s)\n\tdefer C.free(unsafe.Pointer(c_s))\n",
+		"return C.GoString(c_r)",
+		"func Watch(fn VisitFn) func() {",
+		"c_fn, c_user = C.visit_fn(C.bindgen_visit_fn), c_h",
+		"C.geo_watch(c_fn, c_user)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %q in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateCallbacks(t *testing.T) {
+	src, err := GenerateCallbacks(module())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parse(t, src)["bindgen_visit_fn"] {
+		t.Error("expected a declaration of bindgen_visit_fn")
+	}
+
+	for _, want := range []string{
+		"//export bindgen_visit_fn\nfunc bindgen_visit_fn(p *C.struct_point, user unsafe.Pointer) C.int {",
+		"c_f := lookupCallback(user).(VisitFn)",
+		"c_v := c_f((*Point)(unsafe.Pointer(p)))",
+		"return C.int(c_v)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %q in\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "extern") {
+		t.Errorf("preamble of exported functions declares callbacks:\n%s", src)
+	}
+
+	if src, err := GenerateCallbacks(&Module{Package: "geo"}); src != nil || err != nil {
+		t.Errorf("GenerateCallbacks without callbacks = %q, %v, want nil", src, err)
+	}
+}
@@ -0,0 +1,104 @@
+package bindgen
+
+import (
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// namer assigns unique Go names to C declarations.
+type namer struct {
+	cfg  *Config
+	used map[string]bool
+}
+
+func newNamer(cfg *Config) *namer {
+	return &namer{cfg: cfg, used: map[string]bool{}}
+}
+
+// exported returns a unique exported Go name for the C name.
+func (n *namer) exported(cname string) string {
+	name, ok := n.cfg.Renames[cname]
+	if !ok {
+		name = goName(trimPrefixes(cname, n.cfg.Prefixes))
+	}
+
+	unique := name
+	for i := 2; n.used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	n.used[unique] = true
+
+	return unique
+}
+
+// trimPrefixes removes the first matching prefix from name, unless nothing would be left.
+func trimPrefixes(name string, prefixes []string) string {
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p) && len(name) > len(p) {
+			return name[len(p):]
+		}
+	}
+
+	return name
+}
+
+// goName converts a C name to an exported Go name in camel case, e.g. "point_t" becomes
+// "Point" and "COLOR_RED" becomes "ColorRed". Words in mixed case keep their case.
+func goName(cname string) string {
+	cname = strings.TrimSuffix(cname, "_t")
+
+	var b strings.Builder
+	for _, word := range strings.Split(cname, "_") {
+		if word == "" {
+			continue
+		}
+
+		r := []rune(word)
+		if word == strings.ToUpper(word) || word == strings.ToLower(word) {
+			for i := range r {
+				r[i] = unicode.ToLower(r[i])
+			}
+		}
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// localName converts a C parameter name to a Go identifier which does not clash with keywords
+// or the identifiers used by the generated code.
+func localName(cname string, i int) string {
+	if cname == "" {
+		return "arg" + strconv.Itoa(i)
+	}
+
+	name := []rune(goName(cname))
+	name[0] = unicode.ToLower(name[0])
+	s := string(name)
+
+	// The local variables of the generated functions start with "c_", so they can not clash.
+	if token.IsKeyword(s) || types.Universe.Lookup(s) != nil || s == "unsafe" || s == "sync" {
+		s += "_"
+	}
+
+	return s
+}
+
+// cgoField returns the name cgo gives the C field name, which is prefixed with an underscore
+// if it is a Go keyword.
+func cgoField(name string) string {
+	if token.IsKeyword(name) {
+		return "_" + name
+	}
+
+	return name
+}
@@ -0,0 +1,80 @@
+package bindgen
+
+import "testing"
+
+func TestGoName(t *testing.T) {
+	for _, tt := range []struct {
+		cname, want string
+	}{
+		{"point_t", "Point"},
+		{"COLOR_RED", "ColorRed"},
+		{"parseURL", "ParseURL"},
+		{"__reserved", "Reserved"},
+		{"_2d", "X2d"},
+	} {
+		if got := goName(tt.cname); got != tt.want {
+			t.Errorf("goName(%q) = %q, want %q", tt.cname, got, tt.want)
+		}
+	}
+}
+
+func TestNamer(t *testing.T) {
+	n := newNamer(&Config{
+		Prefixes: []string{"foo_", "FOO_"},
+		Renames:  map[string]string{"foo_ctx": "Context"},
+	})
+
+	for _, tt := range []struct {
+		cname, want string
+	}{
+		{"foo_open", "Open"},
+		{"FOO_OPEN", "Open2"},
+		{"foo_ctx", "Context"},
+		{"foo_", "Foo"},
+	} {
+		if got := n.exported(tt.cname); got != tt.want {
+			t.Errorf("exported(%q) = %q, want %q", tt.cname, got, tt.want)
+		}
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	for _, tt := range []struct {
+		cname string
+		i     int
+		want  string
+	}{
+		{"", 2, "arg2"},
+		{"user_data", 0, "userData"},
+		{"type", 0, "type_"},
+		{"len", 0, "len_"},
+		{"unsafe", 0, "unsafe_"},
+	} {
+		if got := localName(tt.cname, tt.i); got != tt.want {
+			t.Errorf("localName(%q, %d) = %q, want %q", tt.cname, tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestIsString(t *testing.T) {
+	cfg := &Config{
+		Strings:   []string{"set_*.name", "get_name.return"},
+		NoStrings: []string{"write.buf"},
+	}
+
+	for _, tt := range []struct {
+		function, param string
+		isConst         bool
+		want            bool
+	}{
+		{"set_title", "name", false, true},
+		{"set_title", "buf", false, false},
+		{"get_name", "return", false, true},
+		{"puts", "s", true, true},
+		{"write", "buf", true, false},
+	} {
+		if got := cfg.isString(tt.function, tt.param, tt.isConst); got != tt.want {
+			t.Errorf("isString(%q, %q, %v) = %v, want %v", tt.function, tt.param, tt.isConst, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+#define GEO_MAX 16
+#define GEO_SCALE (GEO_MAX * 2)
+
+typedef double geo_real;
+
+enum geo_color { GEO_RED, GEO_GREEN = 4 };
+
+struct geo_point {
+  int x;
+  geo_real y;
+};
+
+typedef int (*geo_visit_fn)(struct geo_point *p, void *user);
+
+const char *geo_name(const char *s);
+int geo_visit(geo_visit_fn fn, void *user);
+void geo_log(const char *fmt, ...);
@@ -0,0 +1,157 @@
+package callgraph
+
+import (
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Builder builds the call graphs of translation units.
+type Builder struct {
+	// SkipSystemHeaders skips the functions defined in system headers. Calls of them are
+	// still recorded.
+	SkipSystemHeaders bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Build returns the call graph of the translation unit.
+func (b Builder) Build(tu clang.TranslationUnit) *Graph {
+	w := walker{
+		Builder: b,
+		graph:   NewGraph(),
+		paths:   compdb.Paths{Directory: b.Directory},
+	}
+	w.children(tu.TranslationUnitCursor())
+
+	return w.graph
+}
+
+type walker struct {
+	Builder
+
+	graph *Graph
+	paths compdb.Paths
+	// callers is the stack of function definitions enclosing the current cursor.
+	callers []string
+}
+
+func (w *walker) children(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		w.visit(c)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) visit(c clang.Cursor) {
+	kind := c.Kind()
+	if kind.IsDeclaration() && w.SkipSystemHeaders && c.Location().IsInSystemHeader() {
+		return
+	}
+
+	switch {
+	case isFunction(kind):
+		n := w.node(c)
+		if n == nil || !c.IsCursorDefinition() {
+			break
+		}
+
+		w.callers = append(w.callers, n.USR)
+		w.children(c)
+		w.callers = w.callers[:len(w.callers)-1]
+
+		return
+	case kind == clang.Cursor_CallExpr && len(w.callers) > 0:
+		w.call(c)
+	}
+
+	w.children(c)
+}
+
+// node returns the node of the function declaration c and adds it to the graph if it is new.
+func (w *walker) node(c clang.Cursor) *Node {
+	usr := c.USR()
+	if usr == "" {
+		return nil
+	}
+
+	n, ok := w.graph.Nodes[usr]
+	if !ok {
+		n = &Node{
+			USR:       usr,
+			Name:      c.QualifiedName(),
+			Signature: c.DisplayName(),
+			Kind:      c.Kind(),
+		}
+		w.graph.Nodes[usr] = n
+
+		overridden := c.OverriddenCursors()
+		for _, o := range overridden {
+			if w.node(o) != nil {
+				n.Overrides = append(n.Overrides, o.USR())
+			}
+		}
+		if len(overridden) > 0 {
+			clang.Dispose(overridden)
+		}
+	}
+
+	if !n.IsDefined() && c.IsCursorDefinition() {
+		n.Definition = w.paths.Position(c.Location().Position())
+	}
+
+	return n
+}
+
+func (w *walker) call(c clang.Cursor) {
+	e := Edge{
+		Caller: w.callers[len(w.callers)-1],
+		Kind:   Edge_Unresolved,
+		Site:   w.site(c),
+	}
+
+	ref := c.Referenced()
+	switch {
+	case ref.IsNull():
+	case isFunction(ref.Kind()):
+		if n := w.node(ref); n != nil {
+			e.Callee = n.USR
+			e.Kind = Edge_Direct
+			if c.IsDynamicCall() {
+				e.Kind = Edge_Virtual
+			}
+		}
+	default:
+		// A variable, parameter or field holding a function pointer.
+		e.Expression = ref.Spelling()
+	}
+
+	w.graph.addEdge(e)
+}
+
+// site returns the span of the name called by c.
+func (w *walker) site(c clang.Cursor) clang.Span {
+	start := w.paths.Position(c.Location().Position())
+	if !start.IsValid() {
+		return clang.Span{}
+	}
+
+	end := start
+	n := uint32(len(c.Spelling()))
+	end.Column += n
+	end.Offset += n
+
+	return clang.Span{Start: start, End: end}
+}
+
+// isFunction reports whether kind is a function declaration which can be called. Lambdas are
+// part of the enclosing function.
+func isFunction(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_Constructor, clang.Cursor_Destructor,
+		clang.Cursor_ConversionFunction, clang.Cursor_FunctionTemplate:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,325 @@
+// Package callgraph builds the call graphs of translation units. Functions are identified by
+// their USR, so the graphs of all translation units of a project can be merged into one graph.
+//
+// Calls of virtual methods are recorded with the statically called method. The methods
+// overriding it are possible targets as well, Graph.Targets expands them. Calls through function
+// pointers can not be resolved and are recorded without callee.
+package callgraph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// EdgeKind is the kind of a call.
+type EdgeKind uint32
+
+const (
+	// Edge_Direct is a call of a known function.
+	Edge_Direct EdgeKind = iota + 1
+	// Edge_Virtual is a dynamically dispatched call of a virtual method. The callee may be any
+	// method overriding the called method.
+	Edge_Virtual
+	// Edge_Unresolved is a call through a function pointer or of a dependent expression. The
+	// callee is unknown.
+	Edge_Unresolved
+)
+
+func (ek EdgeKind) Spelling() string {
+	switch ek {
+	case Edge_Direct:
+		return "Edge=Direct"
+	case Edge_Virtual:
+		return "Edge=Virtual"
+	case Edge_Unresolved:
+		return "Edge=Unresolved"
+	}
+
+	return fmt.Sprintf("EdgeKind unknown %d", int(ek))
+}
+
+func (ek EdgeKind) String() string {
+	return ek.Spelling()
+}
+
+// Node is a function or method.
+type Node struct {
+	USR string
+	// Name is the qualified name, e.g. "ns::Cls::method".
+	Name string
+	// Signature is the name together with the parameter types, e.g. "method(int)".
+	Signature string
+	Kind      clang.CursorKind
+	// Definition is the position of the name in the definition. It is invalid if the function
+	// is not defined in any of the translation units.
+	Definition clang.Position
+	// Overrides holds the USRs of the methods directly overridden by the method.
+	Overrides []string
+}
+
+// IsDefined reports whether the definition of the function was seen.
+func (n *Node) IsDefined() bool {
+	return n.Definition.IsValid()
+}
+
+// Edge is a call site.
+type Edge struct {
+	// Caller is the USR of the function containing the call.
+	Caller string
+	// Callee is the USR of the called function. It is empty for unresolved calls.
+	Callee string
+	Kind   EdgeKind
+	// Site is the span of the called name.
+	Site clang.Span
+	// Expression is the name of the called function pointer of an unresolved call, if known.
+	Expression string
+}
+
+// Graph is a call graph.
+type Graph struct {
+	Nodes map[string]*Node
+	// Edges are in the order they were found.
+	Edges []Edge
+
+	seen       map[Edge]bool
+	overriders map[string][]string
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		Nodes: map[string]*Node{},
+	}
+}
+
+// Merge adds the nodes and edges of other to g. Node information already present in g is
+// completed but not replaced, duplicate call sites, e.g. in inline functions of headers, are
+// skipped.
+func (g *Graph) Merge(other *Graph) {
+	for usr, n := range other.Nodes {
+		mine, ok := g.Nodes[usr]
+		if !ok {
+			cp := *n
+			cp.Overrides = append([]string(nil), n.Overrides...)
+			g.Nodes[usr] = &cp
+
+			continue
+		}
+
+		if !mine.IsDefined() {
+			mine.Definition = n.Definition
+		}
+	overrides:
+		for _, o := range n.Overrides {
+			for _, m := range mine.Overrides {
+				if m == o {
+					continue overrides
+				}
+			}
+			mine.Overrides = append(mine.Overrides, o)
+		}
+	}
+
+	for _, e := range other.Edges {
+		g.addEdge(e)
+	}
+
+	g.overriders = nil
+}
+
+func (g *Graph) addEdge(e Edge) {
+	if g.seen == nil {
+		g.seen = make(map[Edge]bool, len(g.Edges))
+		for _, e := range g.Edges {
+			g.seen[e] = true
+		}
+	}
+
+	if !g.seen[e] {
+		g.seen[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// USRs returns the USRs of all nodes in sorted order.
+func (g *Graph) USRs() []string {
+	usrs := make([]string, 0, len(g.Nodes))
+	for usr := range g.Nodes {
+		usrs = append(usrs, usr)
+	}
+	sort.Strings(usrs)
+
+	return usrs
+}
+
+// Lookup returns the USRs of the nodes with the given qualified name or USR. A name without
+// qualification matches functions of any scope, so "f" matches "ns::A::f".
+func (g *Graph) Lookup(name string) []string {
+	if _, ok := g.Nodes[name]; ok {
+		return []string{name}
+	}
+
+	var usrs []string
+	for _, usr := range g.USRs() {
+		n := g.Nodes[usr]
+		if n.Name == name || len(n.Name) > len(name)+2 && n.Name[len(n.Name)-len(name)-2:] == "::"+name {
+			usrs = append(usrs, usr)
+		}
+	}
+
+	return usrs
+}
+
+// Overriders returns the USRs of all methods directly or indirectly overriding the method usr.
+func (g *Graph) Overriders(usr string) []string {
+	if g.overriders == nil {
+		g.overriders = map[string][]string{}
+		for _, u := range g.USRs() {
+			for _, o := range g.Nodes[u].Overrides {
+				g.overriders[o] = append(g.overriders[o], u)
+			}
+		}
+	}
+
+	seen := map[string]bool{usr: true}
+	var result []string
+
+	queue := []string{usr}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, o := range g.overriders[u] {
+			if !seen[o] {
+				seen[o] = true
+				result = append(result, o)
+				queue = append(queue, o)
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Targets returns the USRs of the functions the call may reach: the callee of a direct call,
+// the callee and all of its overriders for a virtual call and nothing for an unresolved call.
+func (g *Graph) Targets(e Edge) []string {
+	switch e.Kind {
+	case Edge_Direct:
+		return []string{e.Callee}
+	case Edge_Virtual:
+		return append([]string{e.Callee}, g.Overriders(e.Callee)...)
+	}
+
+	return nil
+}
+
+// Callees returns the calls made by the function usr.
+func (g *Graph) Callees(usr string) []Edge {
+	var edges []Edge
+	for _, e := range g.Edges {
+		if e.Caller == usr {
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// Callers returns the calls which may reach the function usr, including virtual calls of
+// methods it overrides.
+func (g *Graph) Callers(usr string) []Edge {
+	var edges []Edge
+	for _, e := range g.Edges {
+		for _, t := range g.Targets(e) {
+			if t == usr {
+				edges = append(edges, e)
+
+				break
+			}
+		}
+	}
+
+	return edges
+}
+
+// Impact returns the USRs of all functions which directly or indirectly may call one of the
+// given functions, i.e. the functions affected by changing them. The given functions are
+// included.
+func (g *Graph) Impact(usrs ...string) []string {
+	callers := map[string][]string{}
+	for _, e := range g.Edges {
+		for _, t := range g.Targets(e) {
+			callers[t] = append(callers[t], e.Caller)
+		}
+	}
+
+	seen := map[string]bool{}
+	queue := append([]string(nil), usrs...)
+	for _, usr := range usrs {
+		seen[usr] = true
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, c := range callers[u] {
+			if !seen[c] {
+				seen[c] = true
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for usr := range seen {
+		result = append(result, usr)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Subgraph returns the graph of the given functions and the calls between them. Unresolved
+// calls of the functions are kept, as are virtual calls which may reach one of them. The
+// statically called methods of these calls are added to the graph.
+func (g *Graph) Subgraph(usrs []string) *Graph {
+	keep := map[string]bool{}
+	for _, usr := range usrs {
+		keep[usr] = true
+	}
+
+	sub := NewGraph()
+	add := func(usr string) {
+		if n, ok := g.Nodes[usr]; ok {
+			sub.Nodes[usr] = n
+		}
+	}
+	for usr := range keep {
+		add(usr)
+	}
+
+	for _, e := range g.Edges {
+		if !keep[e.Caller] {
+			continue
+		}
+		if e.Kind == Edge_Unresolved {
+			sub.addEdge(e)
+
+			continue
+		}
+		for _, t := range g.Targets(e) {
+			if keep[t] {
+				add(e.Callee)
+				sub.addEdge(e)
+
+				break
+			}
+		}
+	}
+
+	return sub
+}
@@ -0,0 +1,222 @@
+package callgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func site(file string, line uint32) clang.Span {
+	start := clang.Position{File: file, Line: line, Column: 3, Offset: line * 10}
+	end := start
+	end.Column++
+	end.Offset++
+
+	return clang.Span{Start: start, End: end}
+}
+
+// testGraphs returns the graphs of two translation units:
+//
+//	struct Base { virtual void f(); };
+//	struct Derived : Base { void f() override; };
+//	struct Leaf : Derived { void f() override; };
+//	void run(Base *b) { b->f(); }            // a.cc
+//	void main() { run(0); cb(); }            // a.cc, cb is a function pointer
+//	void test() { run(0); }                  // b.cc
+func testGraphs() (*Graph, *Graph) {
+	def := func(file string, line uint32) clang.Position {
+		return clang.Position{File: file, Line: line, Column: 6, Offset: line * 10}
+	}
+
+	a := NewGraph()
+	a.Nodes["base.f"] = &Node{USR: "base.f", Name: "Base::f", Kind: clang.Cursor_CXXMethod, Definition: def("/p/s.h", 1)}
+	a.Nodes["derived.f"] = &Node{USR: "derived.f", Name: "Derived::f", Kind: clang.Cursor_CXXMethod, Overrides: []string{"base.f"}}
+	a.Nodes["run"] = &Node{USR: "run", Name: "run", Kind: clang.Cursor_FunctionDecl, Definition: def("/p/a.cc", 4)}
+	a.Nodes["main"] = &Node{USR: "main", Name: "main", Kind: clang.Cursor_FunctionDecl, Definition: def("/p/a.cc", 5)}
+	a.Edges = []Edge{
+		{Caller: "run", Callee: "base.f", Kind: Edge_Virtual, Site: site("/p/a.cc", 4)},
+		{Caller: "main", Callee: "run", Kind: Edge_Direct, Site: site("/p/a.cc", 5)},
+		{Caller: "main", Kind: Edge_Unresolved, Site: site("/p/a.cc", 5), Expression: "cb"},
+	}
+
+	b := NewGraph()
+	b.Nodes["derived.f"] = &Node{USR: "derived.f", Name: "Derived::f", Kind: clang.Cursor_CXXMethod, Overrides: []string{"base.f"}, Definition: def("/p/b.cc", 2)}
+	b.Nodes["leaf.f"] = &Node{USR: "leaf.f", Name: "Leaf::f", Kind: clang.Cursor_CXXMethod, Overrides: []string{"derived.f"}}
+	b.Nodes["run"] = &Node{USR: "run", Name: "run", Kind: clang.Cursor_FunctionDecl}
+	b.Nodes["test"] = &Node{USR: "test", Name: "test", Kind: clang.Cursor_FunctionDecl, Definition: def("/p/b.cc", 6)}
+	b.Edges = []Edge{
+		{Caller: "test", Callee: "run", Kind: Edge_Direct, Site: site("/p/b.cc", 6)},
+	}
+
+	return a, b
+}
+
+func TestMerge(t *testing.T) {
+	a, b := testGraphs()
+
+	g := NewGraph()
+	g.Merge(a)
+	g.Merge(b)
+	g.Merge(a)
+
+	if n := len(g.Nodes); n != 6 {
+		t.Errorf("expected 6 nodes. got=%d", n)
+	}
+	if n := len(g.Edges); n != 4 {
+		t.Errorf("expected 4 edges. got=%d: %+v", n, g.Edges)
+	}
+	if d := g.Nodes["derived.f"]; !d.IsDefined() || len(d.Overrides) != 1 {
+		t.Errorf("expected the definition to be completed. got=%+v", d)
+	}
+	if a.Nodes["derived.f"].IsDefined() {
+		t.Error("expected the merged graph to be unchanged")
+	}
+
+	if got := g.Overriders("base.f"); !reflect.DeepEqual(got, []string{"derived.f", "leaf.f"}) {
+		t.Errorf("unexpected overriders %v", got)
+	}
+	if got := g.Targets(g.Edges[0]); !reflect.DeepEqual(got, []string{"base.f", "derived.f", "leaf.f"}) {
+		t.Errorf("unexpected targets of the virtual call %v", got)
+	}
+	if got := g.Targets(g.Edges[2]); got != nil {
+		t.Errorf("expected no targets of the unresolved call. got=%v", got)
+	}
+
+	if got := g.Lookup("f"); !reflect.DeepEqual(got, []string{"base.f", "derived.f", "leaf.f"}) {
+		t.Errorf("unexpected lookup of f %v", got)
+	}
+	if got := g.Lookup("Derived::f"); !reflect.DeepEqual(got, []string{"derived.f"}) {
+		t.Errorf("unexpected lookup of Derived::f %v", got)
+	}
+
+	if callers := g.Callers("leaf.f"); len(callers) != 1 || callers[0].Caller != "run" {
+		t.Errorf("expected run to call leaf.f virtually. got=%+v", callers)
+	}
+	if callees := g.Callees("main"); len(callees) != 2 {
+		t.Errorf("expected 2 calls in main. got=%+v", callees)
+	}
+
+	if got := g.Impact("leaf.f"); !reflect.DeepEqual(got, []string{"leaf.f", "main", "run", "test"}) {
+		t.Errorf("unexpected impact of leaf.f %v", got)
+	}
+
+	sub := g.Subgraph(g.Impact("leaf.f"))
+	if _, ok := sub.Nodes["base.f"]; !ok {
+		t.Error("expected the statically called method in the subgraph")
+	}
+	if n := len(sub.Edges); n != 4 {
+		t.Errorf("expected 4 edges in the subgraph. got=%d: %+v", n, sub.Edges)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	a, b := testGraphs()
+	g := NewGraph()
+	g.Merge(a)
+	g.Merge(b)
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Nodes []struct {
+			USR  string `json:"usr"`
+			Kind string `json:"kind"`
+		} `json:"nodes"`
+		Edges []struct {
+			Kind    string   `json:"kind"`
+			Targets []string `json:"targets"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Nodes) != 6 || out.Nodes[0].USR != "base.f" || out.Nodes[0].Kind != "CXXMethod" {
+		t.Errorf("unexpected nodes %+v", out.Nodes)
+	}
+	if len(out.Edges) != 4 || out.Edges[0].Kind != "virtual" || len(out.Edges[0].Targets) != 3 {
+		t.Errorf("unexpected edges %+v", out.Edges)
+	}
+
+	buf.Reset()
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{
+		"digraph callgraph {",
+		`n5 [label="test", tooltip=`,
+		`n2 [label="Leaf::f", style=dashed];`,
+		"n4 -> n0 [style=dashed];",
+		"n4 -> n2 [style=dotted];",
+		`u0 [label="cb ?", shape=plaintext];`,
+		"n3 -> u0 [style=dashed];",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected DOT output to contain %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/calls.cc", []string{"-std=c++11"}, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	g := Builder{Directory: dir}.Build(tu)
+
+	lookup := func(name string) *Node {
+		usrs := g.Lookup(name)
+		if len(usrs) != 1 {
+			t.Fatalf("expected one node named %s. got=%v", name, usrs)
+		}
+
+		return g.Nodes[usrs[0]]
+	}
+	base, derived, run, main := lookup("ns::Base::f"), lookup("ns::Derived::f"), lookup("ns::run"), lookup("main")
+
+	source := filepath.Join(dir, "testdata", "calls.cc")
+	if run.Definition.File != source || run.Definition.Line != 8 {
+		t.Errorf("expected ns::run to be defined at %s:8. got=%v", source, run.Definition)
+	}
+	if !reflect.DeepEqual(derived.Overrides, []string{base.USR}) {
+		t.Errorf("expected ns::Derived::f to override ns::Base::f. got=%v", derived.Overrides)
+	}
+
+	type edge struct {
+		caller, callee string
+		kind           EdgeKind
+		line           uint32
+		expression     string
+	}
+	var got []edge
+	for _, e := range g.Edges {
+		got = append(got, edge{e.Caller, e.Callee, e.Kind, e.Site.Start.Line, e.Expression})
+	}
+	want := []edge{
+		{run.USR, base.USR, Edge_Virtual, 8, ""},
+		{main.USR, run.USR, Edge_Direct, 13, ""},
+		{main.USR, "", Edge_Unresolved, 14, "cb"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected edges %v. got=%v", want, got)
+	}
+}
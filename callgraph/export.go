@@ -0,0 +1,200 @@
+package callgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	USR        string           `json:"usr"`
+	Name       string           `json:"name"`
+	Signature  string           `json:"signature"`
+	Kind       clang.CursorKind `json:"kind"`
+	Definition *jsonPosition    `json:"definition,omitempty"`
+	Overrides  []string         `json:"overrides,omitempty"`
+}
+
+type jsonEdge struct {
+	Caller     string       `json:"caller"`
+	Callee     string       `json:"callee,omitempty"`
+	Kind       string       `json:"kind"`
+	Site       jsonPosition `json:"site"`
+	Expression string       `json:"expression,omitempty"`
+	// Targets are the possible callees of a virtual call.
+	Targets []string `json:"targets,omitempty"`
+}
+
+type jsonPosition struct {
+	File   string `json:"file"`
+	Line   uint32 `json:"line"`
+	Column uint32 `json:"column"`
+}
+
+// edgeKinds are the names of the edge kinds in JSON.
+var edgeKinds = map[EdgeKind]string{
+	Edge_Direct:     "direct",
+	Edge_Virtual:    "virtual",
+	Edge_Unresolved: "unresolved",
+}
+
+// WriteJSON writes the graph as JSON object with a list of nodes and a list of edges. Virtual
+// calls list all of their possible targets.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out := jsonGraph{
+		Nodes: []jsonNode{},
+		Edges: []jsonEdge{},
+	}
+
+	for _, usr := range g.USRs() {
+		n := g.Nodes[usr]
+
+		jn := jsonNode{
+			USR:       n.USR,
+			Name:      n.Name,
+			Signature: n.Signature,
+			Kind:      n.Kind,
+			Overrides: n.Overrides,
+		}
+		if n.IsDefined() {
+			jn.Definition = &jsonPosition{n.Definition.File, n.Definition.Line, n.Definition.Column}
+		}
+		out.Nodes = append(out.Nodes, jn)
+	}
+
+	for _, e := range g.Edges {
+		je := jsonEdge{
+			Caller:     e.Caller,
+			Callee:     e.Callee,
+			Kind:       edgeKinds[e.Kind],
+			Site:       jsonPosition{e.Site.Start.File, e.Site.Start.Line, e.Site.Start.Column},
+			Expression: e.Expression,
+		}
+		if e.Kind == Edge_Virtual {
+			je.Targets = g.Targets(e)
+		}
+		out.Edges = append(out.Edges, je)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+// dotEdge is an edge of the DOT graph. Calls of the same kind between two functions are
+// written as a single edge.
+type dotEdge struct {
+	from, to string
+	kind     EdgeKind
+	// possible is set for the edges to the overriders of a virtual callee.
+	possible bool
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz. Functions without definition are
+// drawn dashed. Virtual calls are drawn dashed as well, with dotted edges to the overriders of
+// the callee. Unresolved calls point to a node per caller and called expression.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	ids := map[string]string{}
+	id := func(usr string) string {
+		if s, ok := ids[usr]; ok {
+			return s
+		}
+		s := "n" + strconv.Itoa(len(ids))
+		ids[usr] = s
+
+		return s
+	}
+
+	fmt.Fprintln(bw, "digraph callgraph {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+
+	for _, usr := range g.USRs() {
+		n := g.Nodes[usr]
+
+		attrs := "label=" + strconv.Quote(n.Name)
+		if n.IsDefined() {
+			tooltip := n.Definition.String()
+			if n.Signature != "" {
+				tooltip = n.Signature + "\n" + tooltip
+			}
+			attrs += ", tooltip=" + strconv.Quote(tooltip)
+		} else {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(bw, "\t%s [%s];\n", id(usr), attrs)
+	}
+
+	counts := map[dotEdge]int{}
+	var order []dotEdge
+	add := func(e dotEdge) {
+		if counts[e] == 0 {
+			order = append(order, e)
+		}
+		counts[e]++
+	}
+
+	unresolved := map[[2]string]string{}
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case Edge_Unresolved:
+			key := [2]string{e.Caller, e.Expression}
+			to, ok := unresolved[key]
+			if !ok {
+				to = fmt.Sprintf("u%d", len(unresolved))
+				unresolved[key] = to
+
+				label := "?"
+				if e.Expression != "" {
+					label = e.Expression + " ?"
+				}
+				fmt.Fprintf(bw, "\t%s [label=%s, shape=plaintext];\n", to, strconv.Quote(label))
+			}
+			add(dotEdge{from: id(e.Caller), to: to, kind: e.Kind})
+		case Edge_Virtual:
+			add(dotEdge{from: id(e.Caller), to: id(e.Callee), kind: e.Kind})
+			for _, t := range g.Overriders(e.Callee) {
+				if _, ok := g.Nodes[t]; ok {
+					add(dotEdge{from: id(e.Caller), to: id(t), kind: e.Kind, possible: true})
+				}
+			}
+		default:
+			add(dotEdge{from: id(e.Caller), to: id(e.Callee), kind: e.Kind})
+		}
+	}
+
+	for _, e := range order {
+		var attrs []string
+		switch {
+		case e.possible:
+			attrs = append(attrs, "style=dotted")
+		case e.kind == Edge_Virtual, e.kind == Edge_Unresolved:
+			attrs = append(attrs, "style=dashed")
+		}
+		if n := counts[e]; n > 1 {
+			attrs = append(attrs, "label="+strconv.Quote(strconv.Itoa(n)))
+		}
+
+		fmt.Fprintf(bw, "\t%s -> %s", e.from, e.to)
+		if len(attrs) > 0 {
+			fmt.Fprintf(bw, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprintln(bw, ";")
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
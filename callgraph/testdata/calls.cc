@@ -0,0 +1,16 @@
+namespace ns {
+struct Base {
+  virtual void f() {}
+};
+struct Derived : Base {
+  void f() override {}
+};
+void run(Base *b) { b->f(); }
+} // namespace ns
+
+int main() {
+  void (*cb)() = nullptr;
+  ns::run(nullptr);
+  cb();
+  return 0;
+}
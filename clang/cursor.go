@@ -62,10 +62,10 @@ type funcRegistry struct {
 	sync.RWMutex
 
 	index int
-	funcs map[int]*CursorVisitor
+	funcs map[int]interface{}
 }
 
-func (fm *funcRegistry) register(f *CursorVisitor) int {
+func (fm *funcRegistry) register(f interface{}) int {
 	fm.Lock()
 	defer fm.Unlock()
 
@@ -79,7 +79,7 @@ func (fm *funcRegistry) register(f *CursorVisitor) int {
 	return fm.index
 }
 
-func (fm *funcRegistry) lookup(index int) *CursorVisitor {
+func (fm *funcRegistry) lookup(index int) interface{} {
 	fm.RLock()
 	defer fm.RUnlock()
 
@@ -95,14 +95,14 @@ func (fm *funcRegistry) unregister(index int) {
 }
 
 var visitors = &funcRegistry{
-	funcs: map[int]*CursorVisitor{},
+	funcs: map[int]interface{}{},
 }
 
 // GoClangCursorVisitor calls the cursor visitor.
 //export GoClangCursorVisitor
 func GoClangCursorVisitor(cursor, parent C.CXCursor, cfct unsafe.Pointer) (status ChildVisitResult) {
 	i := *(*C.int)(cfct)
-	f := visitors.lookup(int(i))
+	f := visitors.lookup(int(i)).(*CursorVisitor)
 
 	return (*f)(Cursor{cursor}, Cursor{parent})
 }
@@ -0,0 +1,35 @@
+package clang
+
+import "strings"
+
+// IsUnnamed reports whether the declaration of the cursor has no name, e.g. an unnamed struct
+// or enumeration. Depending on the version of libclang, the spelling of unnamed declarations is
+// empty or describes their location, like "struct (unnamed at a.h:3:1)".
+func (c Cursor) IsUnnamed() bool {
+	name := c.Spelling()
+
+	return name == "" || strings.Contains(name, "(")
+}
+
+// TagKeyword returns the keyword naming the record or enumeration of the cursor in C: "union"
+// for unions, "enum" for enumerations and "struct" for all other records.
+func (c Cursor) TagKeyword() string {
+	switch c.Kind() {
+	case Cursor_UnionDecl:
+		return "union"
+	case Cursor_EnumDecl:
+		return "enum"
+	}
+
+	return "struct"
+}
+
+// HasMacroBody reports whether the macro definition of the cursor has a replacement list,
+// unlike e.g. include guards.
+func (c Cursor) HasMacroBody() bool {
+	tu := c.TranslationUnit()
+	tokens := tu.Tokenize(c.Extent())
+	defer tu.DisposeTokens(tokens)
+
+	return len(tokens) > 1
+}
@@ -0,0 +1,116 @@
+package clang
+
+//go:generate go run ../internal/cmd/gen-enumtext -o enumtext_gen.go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// enumName is the name of a single enumeration constant without the prefix of its type, e.g.
+// "FunctionDecl" for Cursor_FunctionDecl.
+type enumName struct {
+	value int64
+	name  string
+}
+
+// enumText converts the values of an enumeration type from and to their names.
+type enumText struct {
+	typ    string
+	prefix string
+	flags  bool
+	names  []enumName
+
+	byValue map[int64]string
+	byName  map[string]int64
+}
+
+func newEnumText(typ, prefix string, flags bool, names []enumName) *enumText {
+	e := &enumText{
+		typ:     typ,
+		prefix:  prefix,
+		flags:   flags,
+		names:   names,
+		byValue: make(map[int64]string, len(names)),
+		byName:  make(map[string]int64, 2*len(names)),
+	}
+
+	for _, n := range names {
+		e.byName[n.name] = n.value
+		e.byName[prefix+n.name] = n.value
+
+		// aliases keep the name of the constant declared first, range markers such as
+		// Cursor_FirstDecl never replace the name of the actual kind
+		if old, ok := e.byValue[n.value]; !ok || (isRangeMarker(old) && !isRangeMarker(n.name)) {
+			e.byValue[n.value] = n.name
+		}
+	}
+
+	return e
+}
+
+func isRangeMarker(name string) bool {
+	return strings.HasPrefix(name, "First") || strings.HasPrefix(name, "Last")
+}
+
+// format returns the name of v. Values of flag types without a name of their own are written as
+// the names of their bits joined by "|", unknown values as decimal number.
+func (e *enumText) format(v int64) string {
+	if name, ok := e.byValue[v]; ok {
+		return name
+	}
+
+	if !e.flags || v <= 0 {
+		return strconv.FormatInt(v, 10)
+	}
+
+	var parts []string
+	rest := v
+	for _, n := range e.names {
+		if n.value <= 0 || n.value&(n.value-1) != 0 || rest&n.value == 0 || e.byValue[n.value] != n.name {
+			continue
+		}
+
+		parts = append(parts, n.name)
+		rest &^= n.value
+	}
+	if rest != 0 {
+		parts = append(parts, strconv.FormatInt(rest, 10))
+	}
+
+	return strings.Join(parts, "|")
+}
+
+// parse returns the value of the name s. Names may be written with or without the prefix of the
+// constants, e.g. "FunctionDecl" and "Cursor_FunctionDecl", numbers are accepted as well.
+func (e *enumText) parse(s string) (int64, error) {
+	if !e.flags {
+		return e.parseOne(s)
+	}
+
+	var v int64
+	for _, part := range strings.Split(s, "|") {
+		p, err := e.parseOne(part)
+		if err != nil {
+			return 0, err
+		}
+
+		v |= p
+	}
+
+	return v, nil
+}
+
+func (e *enumText) parseOne(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	if v, ok := e.byName[s]; ok {
+		return v, nil
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("invalid %s %q", e.typ, s)
+}
@@ -0,0 +1,2145 @@
+package clang
+
+var accessSpecifierText = newEnumText("AccessSpecifier", "AccessSpecifier_", false, []enumName{
+	{int64(AccessSpecifier_Invalid), "Invalid"},
+	{int64(AccessSpecifier_Public), "Public"},
+	{int64(AccessSpecifier_Protected), "Protected"},
+	{int64(AccessSpecifier_Private), "Private"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (as AccessSpecifier) MarshalText() ([]byte, error) {
+	return []byte(accessSpecifierText.format(int64(as))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (as *AccessSpecifier) UnmarshalText(text []byte) error {
+	v, err := accessSpecifierText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*as = AccessSpecifier(v)
+
+	return nil
+}
+
+// ParseAccessSpecifier returns the AccessSpecifier with the given name, e.g. "Invalid".
+func ParseAccessSpecifier(s string) (AccessSpecifier, error) {
+	v, err := accessSpecifierText.parse(s)
+
+	return AccessSpecifier(v), err
+}
+
+var availabilityKindText = newEnumText("AvailabilityKind", "Availability_", false, []enumName{
+	{int64(Availability_Available), "Available"},
+	{int64(Availability_Deprecated), "Deprecated"},
+	{int64(Availability_NotAvailable), "NotAvailable"},
+	{int64(Availability_NotAccessible), "NotAccessible"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ak AvailabilityKind) MarshalText() ([]byte, error) {
+	return []byte(availabilityKindText.format(int64(ak))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ak *AvailabilityKind) UnmarshalText(text []byte) error {
+	v, err := availabilityKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ak = AvailabilityKind(v)
+
+	return nil
+}
+
+// ParseAvailabilityKind returns the AvailabilityKind with the given name, e.g. "Available".
+func ParseAvailabilityKind(s string) (AvailabilityKind, error) {
+	v, err := availabilityKindText.parse(s)
+
+	return AvailabilityKind(v), err
+}
+
+var callingConvText = newEnumText("CallingConv", "CallingConv_", false, []enumName{
+	{int64(CallingConv_Default), "Default"},
+	{int64(CallingConv_C), "C"},
+	{int64(CallingConv_X86StdCall), "X86StdCall"},
+	{int64(CallingConv_X86FastCall), "X86FastCall"},
+	{int64(CallingConv_X86ThisCall), "X86ThisCall"},
+	{int64(CallingConv_X86Pascal), "X86Pascal"},
+	{int64(CallingConv_AAPCS), "AAPCS"},
+	{int64(CallingConv_AAPCS_VFP), "AAPCS_VFP"},
+	{int64(CallingConv_X86RegCall), "X86RegCall"},
+	{int64(CallingConv_IntelOclBicc), "IntelOclBicc"},
+	{int64(CallingConv_Win64), "Win64"},
+	{int64(CallingConv_X86_64Win64), "X86_64Win64"},
+	{int64(CallingConv_X86_64SysV), "X86_64SysV"},
+	{int64(CallingConv_X86VectorCall), "X86VectorCall"},
+	{int64(CallingConv_Swift), "Swift"},
+	{int64(CallingConv_PreserveMost), "PreserveMost"},
+	{int64(CallingConv_PreserveAll), "PreserveAll"},
+	{int64(CallingConv_AArch64VectorCall), "AArch64VectorCall"},
+	{int64(CallingConv_SwiftAsync), "SwiftAsync"},
+	{int64(CallingConv_AArch64SVEPCS), "AArch64SVEPCS"},
+	{int64(CallingConv_Invalid), "Invalid"},
+	{int64(CallingConv_Unexposed), "Unexposed"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cc CallingConv) MarshalText() ([]byte, error) {
+	return []byte(callingConvText.format(int64(cc))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cc *CallingConv) UnmarshalText(text []byte) error {
+	v, err := callingConvText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cc = CallingConv(v)
+
+	return nil
+}
+
+// ParseCallingConv returns the CallingConv with the given name, e.g. "Default".
+func ParseCallingConv(s string) (CallingConv, error) {
+	v, err := callingConvText.parse(s)
+
+	return CallingConv(v), err
+}
+
+var childVisitResultText = newEnumText("ChildVisitResult", "ChildVisit_", false, []enumName{
+	{int64(ChildVisit_Break), "Break"},
+	{int64(ChildVisit_Continue), "Continue"},
+	{int64(ChildVisit_Recurse), "Recurse"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cvr ChildVisitResult) MarshalText() ([]byte, error) {
+	return []byte(childVisitResultText.format(int64(cvr))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cvr *ChildVisitResult) UnmarshalText(text []byte) error {
+	v, err := childVisitResultText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cvr = ChildVisitResult(v)
+
+	return nil
+}
+
+// ParseChildVisitResult returns the ChildVisitResult with the given name, e.g. "Break".
+func ParseChildVisitResult(s string) (ChildVisitResult, error) {
+	v, err := childVisitResultText.parse(s)
+
+	return ChildVisitResult(v), err
+}
+
+var codeCompleteFlagsText = newEnumText("CodeComplete_Flags", "CodeComplete_", true, []enumName{
+	{int64(CodeComplete_IncludeMacros), "IncludeMacros"},
+	{int64(CodeComplete_IncludeCodePatterns), "IncludeCodePatterns"},
+	{int64(CodeComplete_IncludeBriefComments), "IncludeBriefComments"},
+	{int64(CodeComplete_SkipPreamble), "SkipPreamble"},
+	{int64(CodeComplete_IncludeCompletionsWithFixIts), "IncludeCompletionsWithFixIts"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ccf CodeComplete_Flags) MarshalText() ([]byte, error) {
+	return []byte(codeCompleteFlagsText.format(int64(ccf))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ccf *CodeComplete_Flags) UnmarshalText(text []byte) error {
+	v, err := codeCompleteFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ccf = CodeComplete_Flags(v)
+
+	return nil
+}
+
+// ParseCodeCompleteFlags returns the CodeComplete_Flags with the given name, e.g. "IncludeMacros".
+//
+// Several flags can be combined with "|".
+func ParseCodeCompleteFlags(s string) (CodeComplete_Flags, error) {
+	v, err := codeCompleteFlagsText.parse(s)
+
+	return CodeComplete_Flags(v), err
+}
+
+var commentInlineCommandRenderKindText = newEnumText("CommentInlineCommandRenderKind", "CommentInlineCommandRenderKind_", false, []enumName{
+	{int64(CommentInlineCommandRenderKind_Normal), "Normal"},
+	{int64(CommentInlineCommandRenderKind_Bold), "Bold"},
+	{int64(CommentInlineCommandRenderKind_Monospaced), "Monospaced"},
+	{int64(CommentInlineCommandRenderKind_Emphasized), "Emphasized"},
+	{int64(CommentInlineCommandRenderKind_Anchor), "Anchor"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cicrk CommentInlineCommandRenderKind) MarshalText() ([]byte, error) {
+	return []byte(commentInlineCommandRenderKindText.format(int64(cicrk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cicrk *CommentInlineCommandRenderKind) UnmarshalText(text []byte) error {
+	v, err := commentInlineCommandRenderKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cicrk = CommentInlineCommandRenderKind(v)
+
+	return nil
+}
+
+// ParseCommentInlineCommandRenderKind returns the CommentInlineCommandRenderKind with the given name, e.g. "Normal".
+func ParseCommentInlineCommandRenderKind(s string) (CommentInlineCommandRenderKind, error) {
+	v, err := commentInlineCommandRenderKindText.parse(s)
+
+	return CommentInlineCommandRenderKind(v), err
+}
+
+var commentKindText = newEnumText("CommentKind", "Comment_", false, []enumName{
+	{int64(Comment_Null), "Null"},
+	{int64(Comment_Text), "Text"},
+	{int64(Comment_InlineCommand), "InlineCommand"},
+	{int64(Comment_HTMLStartTag), "HTMLStartTag"},
+	{int64(Comment_HTMLEndTag), "HTMLEndTag"},
+	{int64(Comment_Paragraph), "Paragraph"},
+	{int64(Comment_BlockCommand), "BlockCommand"},
+	{int64(Comment_ParamCommand), "ParamCommand"},
+	{int64(Comment_TParamCommand), "TParamCommand"},
+	{int64(Comment_VerbatimBlockCommand), "VerbatimBlockCommand"},
+	{int64(Comment_VerbatimBlockLine), "VerbatimBlockLine"},
+	{int64(Comment_VerbatimLine), "VerbatimLine"},
+	{int64(Comment_FullComment), "FullComment"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ck CommentKind) MarshalText() ([]byte, error) {
+	return []byte(commentKindText.format(int64(ck))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ck *CommentKind) UnmarshalText(text []byte) error {
+	v, err := commentKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ck = CommentKind(v)
+
+	return nil
+}
+
+// ParseCommentKind returns the CommentKind with the given name, e.g. "Null".
+func ParseCommentKind(s string) (CommentKind, error) {
+	v, err := commentKindText.parse(s)
+
+	return CommentKind(v), err
+}
+
+var commentParamPassDirectionText = newEnumText("CommentParamPassDirection", "CommentParamPassDirection_", false, []enumName{
+	{int64(CommentParamPassDirection_In), "In"},
+	{int64(CommentParamPassDirection_Out), "Out"},
+	{int64(CommentParamPassDirection_InOut), "InOut"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cppd CommentParamPassDirection) MarshalText() ([]byte, error) {
+	return []byte(commentParamPassDirectionText.format(int64(cppd))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cppd *CommentParamPassDirection) UnmarshalText(text []byte) error {
+	v, err := commentParamPassDirectionText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cppd = CommentParamPassDirection(v)
+
+	return nil
+}
+
+// ParseCommentParamPassDirection returns the CommentParamPassDirection with the given name, e.g. "In".
+func ParseCommentParamPassDirection(s string) (CommentParamPassDirection, error) {
+	v, err := commentParamPassDirectionText.parse(s)
+
+	return CommentParamPassDirection(v), err
+}
+
+var compilationDatabaseErrorText = newEnumText("CompilationDatabase_Error", "CompilationDatabase_", false, []enumName{
+	{int64(CompilationDatabase_NoError), "NoError"},
+	{int64(CompilationDatabase_CanNotLoadDatabase), "CanNotLoadDatabase"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cde CompilationDatabase_Error) MarshalText() ([]byte, error) {
+	return []byte(compilationDatabaseErrorText.format(int64(cde))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cde *CompilationDatabase_Error) UnmarshalText(text []byte) error {
+	v, err := compilationDatabaseErrorText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cde = CompilationDatabase_Error(v)
+
+	return nil
+}
+
+// ParseCompilationDatabaseError returns the CompilationDatabase_Error with the given name, e.g. "NoError".
+func ParseCompilationDatabaseError(s string) (CompilationDatabase_Error, error) {
+	v, err := compilationDatabaseErrorText.parse(s)
+
+	return CompilationDatabase_Error(v), err
+}
+
+var completionChunkKindText = newEnumText("CompletionChunkKind", "CompletionChunk_", false, []enumName{
+	{int64(CompletionChunk_Optional), "Optional"},
+	{int64(CompletionChunk_TypedText), "TypedText"},
+	{int64(CompletionChunk_Text), "Text"},
+	{int64(CompletionChunk_Placeholder), "Placeholder"},
+	{int64(CompletionChunk_Informative), "Informative"},
+	{int64(CompletionChunk_CurrentParameter), "CurrentParameter"},
+	{int64(CompletionChunk_LeftParen), "LeftParen"},
+	{int64(CompletionChunk_RightParen), "RightParen"},
+	{int64(CompletionChunk_LeftBracket), "LeftBracket"},
+	{int64(CompletionChunk_RightBracket), "RightBracket"},
+	{int64(CompletionChunk_LeftBrace), "LeftBrace"},
+	{int64(CompletionChunk_RightBrace), "RightBrace"},
+	{int64(CompletionChunk_LeftAngle), "LeftAngle"},
+	{int64(CompletionChunk_RightAngle), "RightAngle"},
+	{int64(CompletionChunk_Comma), "Comma"},
+	{int64(CompletionChunk_ResultType), "ResultType"},
+	{int64(CompletionChunk_Colon), "Colon"},
+	{int64(CompletionChunk_SemiColon), "SemiColon"},
+	{int64(CompletionChunk_Equal), "Equal"},
+	{int64(CompletionChunk_HorizontalSpace), "HorizontalSpace"},
+	{int64(CompletionChunk_VerticalSpace), "VerticalSpace"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cck CompletionChunkKind) MarshalText() ([]byte, error) {
+	return []byte(completionChunkKindText.format(int64(cck))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cck *CompletionChunkKind) UnmarshalText(text []byte) error {
+	v, err := completionChunkKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cck = CompletionChunkKind(v)
+
+	return nil
+}
+
+// ParseCompletionChunkKind returns the CompletionChunkKind with the given name, e.g. "Optional".
+func ParseCompletionChunkKind(s string) (CompletionChunkKind, error) {
+	v, err := completionChunkKindText.parse(s)
+
+	return CompletionChunkKind(v), err
+}
+
+var completionContextText = newEnumText("CompletionContext", "CompletionContext_", true, []enumName{
+	{int64(CompletionContext_Unexposed), "Unexposed"},
+	{int64(CompletionContext_AnyType), "AnyType"},
+	{int64(CompletionContext_AnyValue), "AnyValue"},
+	{int64(CompletionContext_ObjCObjectValue), "ObjCObjectValue"},
+	{int64(CompletionContext_ObjCSelectorValue), "ObjCSelectorValue"},
+	{int64(CompletionContext_CXXClassTypeValue), "CXXClassTypeValue"},
+	{int64(CompletionContext_DotMemberAccess), "DotMemberAccess"},
+	{int64(CompletionContext_ArrowMemberAccess), "ArrowMemberAccess"},
+	{int64(CompletionContext_ObjCPropertyAccess), "ObjCPropertyAccess"},
+	{int64(CompletionContext_EnumTag), "EnumTag"},
+	{int64(CompletionContext_UnionTag), "UnionTag"},
+	{int64(CompletionContext_StructTag), "StructTag"},
+	{int64(CompletionContext_ClassTag), "ClassTag"},
+	{int64(CompletionContext_Namespace), "Namespace"},
+	{int64(CompletionContext_NestedNameSpecifier), "NestedNameSpecifier"},
+	{int64(CompletionContext_ObjCInterface), "ObjCInterface"},
+	{int64(CompletionContext_ObjCProtocol), "ObjCProtocol"},
+	{int64(CompletionContext_ObjCCategory), "ObjCCategory"},
+	{int64(CompletionContext_ObjCInstanceMessage), "ObjCInstanceMessage"},
+	{int64(CompletionContext_ObjCClassMessage), "ObjCClassMessage"},
+	{int64(CompletionContext_ObjCSelectorName), "ObjCSelectorName"},
+	{int64(CompletionContext_MacroName), "MacroName"},
+	{int64(CompletionContext_NaturalLanguage), "NaturalLanguage"},
+	{int64(CompletionContext_IncludedFile), "IncludedFile"},
+	{int64(CompletionContext_Unknown), "Unknown"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cc CompletionContext) MarshalText() ([]byte, error) {
+	return []byte(completionContextText.format(int64(cc))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cc *CompletionContext) UnmarshalText(text []byte) error {
+	v, err := completionContextText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cc = CompletionContext(v)
+
+	return nil
+}
+
+// ParseCompletionContext returns the CompletionContext with the given name, e.g. "Unexposed".
+//
+// Several flags can be combined with "|".
+func ParseCompletionContext(s string) (CompletionContext, error) {
+	v, err := completionContextText.parse(s)
+
+	return CompletionContext(v), err
+}
+
+var cursorKindText = newEnumText("CursorKind", "Cursor_", false, []enumName{
+	{int64(Cursor_UnexposedDecl), "UnexposedDecl"},
+	{int64(Cursor_StructDecl), "StructDecl"},
+	{int64(Cursor_UnionDecl), "UnionDecl"},
+	{int64(Cursor_ClassDecl), "ClassDecl"},
+	{int64(Cursor_EnumDecl), "EnumDecl"},
+	{int64(Cursor_FieldDecl), "FieldDecl"},
+	{int64(Cursor_EnumConstantDecl), "EnumConstantDecl"},
+	{int64(Cursor_FunctionDecl), "FunctionDecl"},
+	{int64(Cursor_VarDecl), "VarDecl"},
+	{int64(Cursor_ParmDecl), "ParmDecl"},
+	{int64(Cursor_ObjCInterfaceDecl), "ObjCInterfaceDecl"},
+	{int64(Cursor_ObjCCategoryDecl), "ObjCCategoryDecl"},
+	{int64(Cursor_ObjCProtocolDecl), "ObjCProtocolDecl"},
+	{int64(Cursor_ObjCPropertyDecl), "ObjCPropertyDecl"},
+	{int64(Cursor_ObjCIvarDecl), "ObjCIvarDecl"},
+	{int64(Cursor_ObjCInstanceMethodDecl), "ObjCInstanceMethodDecl"},
+	{int64(Cursor_ObjCClassMethodDecl), "ObjCClassMethodDecl"},
+	{int64(Cursor_ObjCImplementationDecl), "ObjCImplementationDecl"},
+	{int64(Cursor_ObjCCategoryImplDecl), "ObjCCategoryImplDecl"},
+	{int64(Cursor_TypedefDecl), "TypedefDecl"},
+	{int64(Cursor_CXXMethod), "CXXMethod"},
+	{int64(Cursor_Namespace), "Namespace"},
+	{int64(Cursor_LinkageSpec), "LinkageSpec"},
+	{int64(Cursor_Constructor), "Constructor"},
+	{int64(Cursor_Destructor), "Destructor"},
+	{int64(Cursor_ConversionFunction), "ConversionFunction"},
+	{int64(Cursor_TemplateTypeParameter), "TemplateTypeParameter"},
+	{int64(Cursor_NonTypeTemplateParameter), "NonTypeTemplateParameter"},
+	{int64(Cursor_TemplateTemplateParameter), "TemplateTemplateParameter"},
+	{int64(Cursor_FunctionTemplate), "FunctionTemplate"},
+	{int64(Cursor_ClassTemplate), "ClassTemplate"},
+	{int64(Cursor_ClassTemplatePartialSpecialization), "ClassTemplatePartialSpecialization"},
+	{int64(Cursor_NamespaceAlias), "NamespaceAlias"},
+	{int64(Cursor_UsingDirective), "UsingDirective"},
+	{int64(Cursor_UsingDeclaration), "UsingDeclaration"},
+	{int64(Cursor_TypeAliasDecl), "TypeAliasDecl"},
+	{int64(Cursor_ObjCSynthesizeDecl), "ObjCSynthesizeDecl"},
+	{int64(Cursor_ObjCDynamicDecl), "ObjCDynamicDecl"},
+	{int64(Cursor_CXXAccessSpecifier), "CXXAccessSpecifier"},
+	{int64(Cursor_FirstDecl), "FirstDecl"},
+	{int64(Cursor_LastDecl), "LastDecl"},
+	{int64(Cursor_FirstRef), "FirstRef"},
+	{int64(Cursor_ObjCSuperClassRef), "ObjCSuperClassRef"},
+	{int64(Cursor_ObjCProtocolRef), "ObjCProtocolRef"},
+	{int64(Cursor_ObjCClassRef), "ObjCClassRef"},
+	{int64(Cursor_TypeRef), "TypeRef"},
+	{int64(Cursor_CXXBaseSpecifier), "CXXBaseSpecifier"},
+	{int64(Cursor_TemplateRef), "TemplateRef"},
+	{int64(Cursor_NamespaceRef), "NamespaceRef"},
+	{int64(Cursor_MemberRef), "MemberRef"},
+	{int64(Cursor_LabelRef), "LabelRef"},
+	{int64(Cursor_OverloadedDeclRef), "OverloadedDeclRef"},
+	{int64(Cursor_VariableRef), "VariableRef"},
+	{int64(Cursor_LastRef), "LastRef"},
+	{int64(Cursor_FirstInvalid), "FirstInvalid"},
+	{int64(Cursor_InvalidFile), "InvalidFile"},
+	{int64(Cursor_NoDeclFound), "NoDeclFound"},
+	{int64(Cursor_NotImplemented), "NotImplemented"},
+	{int64(Cursor_InvalidCode), "InvalidCode"},
+	{int64(Cursor_LastInvalid), "LastInvalid"},
+	{int64(Cursor_FirstExpr), "FirstExpr"},
+	{int64(Cursor_UnexposedExpr), "UnexposedExpr"},
+	{int64(Cursor_DeclRefExpr), "DeclRefExpr"},
+	{int64(Cursor_MemberRefExpr), "MemberRefExpr"},
+	{int64(Cursor_CallExpr), "CallExpr"},
+	{int64(Cursor_ObjCMessageExpr), "ObjCMessageExpr"},
+	{int64(Cursor_BlockExpr), "BlockExpr"},
+	{int64(Cursor_IntegerLiteral), "IntegerLiteral"},
+	{int64(Cursor_FloatingLiteral), "FloatingLiteral"},
+	{int64(Cursor_ImaginaryLiteral), "ImaginaryLiteral"},
+	{int64(Cursor_StringLiteral), "StringLiteral"},
+	{int64(Cursor_CharacterLiteral), "CharacterLiteral"},
+	{int64(Cursor_ParenExpr), "ParenExpr"},
+	{int64(Cursor_UnaryOperator), "UnaryOperator"},
+	{int64(Cursor_ArraySubscriptExpr), "ArraySubscriptExpr"},
+	{int64(Cursor_BinaryOperator), "BinaryOperator"},
+	{int64(Cursor_CompoundAssignOperator), "CompoundAssignOperator"},
+	{int64(Cursor_ConditionalOperator), "ConditionalOperator"},
+	{int64(Cursor_CStyleCastExpr), "CStyleCastExpr"},
+	{int64(Cursor_CompoundLiteralExpr), "CompoundLiteralExpr"},
+	{int64(Cursor_InitListExpr), "InitListExpr"},
+	{int64(Cursor_AddrLabelExpr), "AddrLabelExpr"},
+	{int64(Cursor_StmtExpr), "StmtExpr"},
+	{int64(Cursor_GenericSelectionExpr), "GenericSelectionExpr"},
+	{int64(Cursor_GNUNullExpr), "GNUNullExpr"},
+	{int64(Cursor_CXXStaticCastExpr), "CXXStaticCastExpr"},
+	{int64(Cursor_CXXDynamicCastExpr), "CXXDynamicCastExpr"},
+	{int64(Cursor_CXXReinterpretCastExpr), "CXXReinterpretCastExpr"},
+	{int64(Cursor_CXXConstCastExpr), "CXXConstCastExpr"},
+	{int64(Cursor_CXXFunctionalCastExpr), "CXXFunctionalCastExpr"},
+	{int64(Cursor_CXXTypeidExpr), "CXXTypeidExpr"},
+	{int64(Cursor_CXXBoolLiteralExpr), "CXXBoolLiteralExpr"},
+	{int64(Cursor_CXXNullPtrLiteralExpr), "CXXNullPtrLiteralExpr"},
+	{int64(Cursor_CXXThisExpr), "CXXThisExpr"},
+	{int64(Cursor_CXXThrowExpr), "CXXThrowExpr"},
+	{int64(Cursor_CXXNewExpr), "CXXNewExpr"},
+	{int64(Cursor_CXXDeleteExpr), "CXXDeleteExpr"},
+	{int64(Cursor_UnaryExpr), "UnaryExpr"},
+	{int64(Cursor_ObjCStringLiteral), "ObjCStringLiteral"},
+	{int64(Cursor_ObjCEncodeExpr), "ObjCEncodeExpr"},
+	{int64(Cursor_ObjCSelectorExpr), "ObjCSelectorExpr"},
+	{int64(Cursor_ObjCProtocolExpr), "ObjCProtocolExpr"},
+	{int64(Cursor_ObjCBridgedCastExpr), "ObjCBridgedCastExpr"},
+	{int64(Cursor_PackExpansionExpr), "PackExpansionExpr"},
+	{int64(Cursor_SizeOfPackExpr), "SizeOfPackExpr"},
+	{int64(Cursor_LambdaExpr), "LambdaExpr"},
+	{int64(Cursor_ObjCBoolLiteralExpr), "ObjCBoolLiteralExpr"},
+	{int64(Cursor_ObjCSelfExpr), "ObjCSelfExpr"},
+	{int64(Cursor_OMPArraySectionExpr), "OMPArraySectionExpr"},
+	{int64(Cursor_ObjCAvailabilityCheckExpr), "ObjCAvailabilityCheckExpr"},
+	{int64(Cursor_FixedPointLiteral), "FixedPointLiteral"},
+	{int64(Cursor_OMPArrayShapingExpr), "OMPArrayShapingExpr"},
+	{int64(Cursor_OMPIteratorExpr), "OMPIteratorExpr"},
+	{int64(Cursor_CXXAddrspaceCastExpr), "CXXAddrspaceCastExpr"},
+	{int64(Cursor_ConceptSpecializationExpr), "ConceptSpecializationExpr"},
+	{int64(Cursor_RequiresExpr), "RequiresExpr"},
+	{int64(Cursor_LastExpr), "LastExpr"},
+	{int64(Cursor_FirstStmt), "FirstStmt"},
+	{int64(Cursor_UnexposedStmt), "UnexposedStmt"},
+	{int64(Cursor_LabelStmt), "LabelStmt"},
+	{int64(Cursor_CompoundStmt), "CompoundStmt"},
+	{int64(Cursor_CaseStmt), "CaseStmt"},
+	{int64(Cursor_DefaultStmt), "DefaultStmt"},
+	{int64(Cursor_IfStmt), "IfStmt"},
+	{int64(Cursor_SwitchStmt), "SwitchStmt"},
+	{int64(Cursor_WhileStmt), "WhileStmt"},
+	{int64(Cursor_DoStmt), "DoStmt"},
+	{int64(Cursor_ForStmt), "ForStmt"},
+	{int64(Cursor_GotoStmt), "GotoStmt"},
+	{int64(Cursor_IndirectGotoStmt), "IndirectGotoStmt"},
+	{int64(Cursor_ContinueStmt), "ContinueStmt"},
+	{int64(Cursor_BreakStmt), "BreakStmt"},
+	{int64(Cursor_ReturnStmt), "ReturnStmt"},
+	{int64(Cursor_GCCAsmStmt), "GCCAsmStmt"},
+	{int64(Cursor_AsmStmt), "AsmStmt"},
+	{int64(Cursor_ObjCAtTryStmt), "ObjCAtTryStmt"},
+	{int64(Cursor_ObjCAtCatchStmt), "ObjCAtCatchStmt"},
+	{int64(Cursor_ObjCAtFinallyStmt), "ObjCAtFinallyStmt"},
+	{int64(Cursor_ObjCAtThrowStmt), "ObjCAtThrowStmt"},
+	{int64(Cursor_ObjCAtSynchronizedStmt), "ObjCAtSynchronizedStmt"},
+	{int64(Cursor_ObjCAutoreleasePoolStmt), "ObjCAutoreleasePoolStmt"},
+	{int64(Cursor_ObjCForCollectionStmt), "ObjCForCollectionStmt"},
+	{int64(Cursor_CXXCatchStmt), "CXXCatchStmt"},
+	{int64(Cursor_CXXTryStmt), "CXXTryStmt"},
+	{int64(Cursor_CXXForRangeStmt), "CXXForRangeStmt"},
+	{int64(Cursor_SEHTryStmt), "SEHTryStmt"},
+	{int64(Cursor_SEHExceptStmt), "SEHExceptStmt"},
+	{int64(Cursor_SEHFinallyStmt), "SEHFinallyStmt"},
+	{int64(Cursor_MSAsmStmt), "MSAsmStmt"},
+	{int64(Cursor_NullStmt), "NullStmt"},
+	{int64(Cursor_DeclStmt), "DeclStmt"},
+	{int64(Cursor_OMPParallelDirective), "OMPParallelDirective"},
+	{int64(Cursor_OMPSimdDirective), "OMPSimdDirective"},
+	{int64(Cursor_OMPForDirective), "OMPForDirective"},
+	{int64(Cursor_OMPSectionsDirective), "OMPSectionsDirective"},
+	{int64(Cursor_OMPSectionDirective), "OMPSectionDirective"},
+	{int64(Cursor_OMPSingleDirective), "OMPSingleDirective"},
+	{int64(Cursor_OMPParallelForDirective), "OMPParallelForDirective"},
+	{int64(Cursor_OMPParallelSectionsDirective), "OMPParallelSectionsDirective"},
+	{int64(Cursor_OMPTaskDirective), "OMPTaskDirective"},
+	{int64(Cursor_OMPMasterDirective), "OMPMasterDirective"},
+	{int64(Cursor_OMPCriticalDirective), "OMPCriticalDirective"},
+	{int64(Cursor_OMPTaskyieldDirective), "OMPTaskyieldDirective"},
+	{int64(Cursor_OMPBarrierDirective), "OMPBarrierDirective"},
+	{int64(Cursor_OMPTaskwaitDirective), "OMPTaskwaitDirective"},
+	{int64(Cursor_OMPFlushDirective), "OMPFlushDirective"},
+	{int64(Cursor_SEHLeaveStmt), "SEHLeaveStmt"},
+	{int64(Cursor_OMPOrderedDirective), "OMPOrderedDirective"},
+	{int64(Cursor_OMPAtomicDirective), "OMPAtomicDirective"},
+	{int64(Cursor_OMPForSimdDirective), "OMPForSimdDirective"},
+	{int64(Cursor_OMPParallelForSimdDirective), "OMPParallelForSimdDirective"},
+	{int64(Cursor_OMPTargetDirective), "OMPTargetDirective"},
+	{int64(Cursor_OMPTeamsDirective), "OMPTeamsDirective"},
+	{int64(Cursor_OMPTaskgroupDirective), "OMPTaskgroupDirective"},
+	{int64(Cursor_OMPCancellationPointDirective), "OMPCancellationPointDirective"},
+	{int64(Cursor_OMPCancelDirective), "OMPCancelDirective"},
+	{int64(Cursor_OMPTargetDataDirective), "OMPTargetDataDirective"},
+	{int64(Cursor_OMPTaskLoopDirective), "OMPTaskLoopDirective"},
+	{int64(Cursor_OMPTaskLoopSimdDirective), "OMPTaskLoopSimdDirective"},
+	{int64(Cursor_OMPDistributeDirective), "OMPDistributeDirective"},
+	{int64(Cursor_OMPTargetEnterDataDirective), "OMPTargetEnterDataDirective"},
+	{int64(Cursor_OMPTargetExitDataDirective), "OMPTargetExitDataDirective"},
+	{int64(Cursor_OMPTargetParallelDirective), "OMPTargetParallelDirective"},
+	{int64(Cursor_OMPTargetParallelForDirective), "OMPTargetParallelForDirective"},
+	{int64(Cursor_OMPTargetUpdateDirective), "OMPTargetUpdateDirective"},
+	{int64(Cursor_OMPDistributeParallelForDirective), "OMPDistributeParallelForDirective"},
+	{int64(Cursor_OMPDistributeParallelForSimdDirective), "OMPDistributeParallelForSimdDirective"},
+	{int64(Cursor_OMPDistributeSimdDirective), "OMPDistributeSimdDirective"},
+	{int64(Cursor_OMPTargetParallelForSimdDirective), "OMPTargetParallelForSimdDirective"},
+	{int64(Cursor_OMPTargetSimdDirective), "OMPTargetSimdDirective"},
+	{int64(Cursor_OMPTeamsDistributeDirective), "OMPTeamsDistributeDirective"},
+	{int64(Cursor_OMPTeamsDistributeSimdDirective), "OMPTeamsDistributeSimdDirective"},
+	{int64(Cursor_OMPTeamsDistributeParallelForSimdDirective), "OMPTeamsDistributeParallelForSimdDirective"},
+	{int64(Cursor_OMPTeamsDistributeParallelForDirective), "OMPTeamsDistributeParallelForDirective"},
+	{int64(Cursor_OMPTargetTeamsDirective), "OMPTargetTeamsDirective"},
+	{int64(Cursor_OMPTargetTeamsDistributeDirective), "OMPTargetTeamsDistributeDirective"},
+	{int64(Cursor_OMPTargetTeamsDistributeParallelForDirective), "OMPTargetTeamsDistributeParallelForDirective"},
+	{int64(Cursor_OMPTargetTeamsDistributeParallelForSimdDirective), "OMPTargetTeamsDistributeParallelForSimdDirective"},
+	{int64(Cursor_OMPTargetTeamsDistributeSimdDirective), "OMPTargetTeamsDistributeSimdDirective"},
+	{int64(Cursor_BuiltinBitCastExpr), "BuiltinBitCastExpr"},
+	{int64(Cursor_OMPMasterTaskLoopDirective), "OMPMasterTaskLoopDirective"},
+	{int64(Cursor_OMPParallelMasterTaskLoopDirective), "OMPParallelMasterTaskLoopDirective"},
+	{int64(Cursor_OMPMasterTaskLoopSimdDirective), "OMPMasterTaskLoopSimdDirective"},
+	{int64(Cursor_OMPParallelMasterTaskLoopSimdDirective), "OMPParallelMasterTaskLoopSimdDirective"},
+	{int64(Cursor_OMPParallelMasterDirective), "OMPParallelMasterDirective"},
+	{int64(Cursor_OMPDepobjDirective), "OMPDepobjDirective"},
+	{int64(Cursor_OMPScanDirective), "OMPScanDirective"},
+	{int64(Cursor_OMPTileDirective), "OMPTileDirective"},
+	{int64(Cursor_OMPCanonicalLoop), "OMPCanonicalLoop"},
+	{int64(Cursor_OMPInteropDirective), "OMPInteropDirective"},
+	{int64(Cursor_OMPDispatchDirective), "OMPDispatchDirective"},
+	{int64(Cursor_OMPMaskedDirective), "OMPMaskedDirective"},
+	{int64(Cursor_OMPUnrollDirective), "OMPUnrollDirective"},
+	{int64(Cursor_OMPMetaDirective), "OMPMetaDirective"},
+	{int64(Cursor_OMPGenericLoopDirective), "OMPGenericLoopDirective"},
+	{int64(Cursor_OMPTeamsGenericLoopDirective), "OMPTeamsGenericLoopDirective"},
+	{int64(Cursor_OMPTargetTeamsGenericLoopDirective), "OMPTargetTeamsGenericLoopDirective"},
+	{int64(Cursor_OMPParallelGenericLoopDirective), "OMPParallelGenericLoopDirective"},
+	{int64(Cursor_OMPTargetParallelGenericLoopDirective), "OMPTargetParallelGenericLoopDirective"},
+	{int64(Cursor_OMPParallelMaskedDirective), "OMPParallelMaskedDirective"},
+	{int64(Cursor_OMPMaskedTaskLoopDirective), "OMPMaskedTaskLoopDirective"},
+	{int64(Cursor_OMPMaskedTaskLoopSimdDirective), "OMPMaskedTaskLoopSimdDirective"},
+	{int64(Cursor_OMPParallelMaskedTaskLoopDirective), "OMPParallelMaskedTaskLoopDirective"},
+	{int64(Cursor_OMPParallelMaskedTaskLoopSimdDirective), "OMPParallelMaskedTaskLoopSimdDirective"},
+	{int64(Cursor_LastStmt), "LastStmt"},
+	{int64(Cursor_TranslationUnit), "TranslationUnit"},
+	{int64(Cursor_FirstAttr), "FirstAttr"},
+	{int64(Cursor_UnexposedAttr), "UnexposedAttr"},
+	{int64(Cursor_IBActionAttr), "IBActionAttr"},
+	{int64(Cursor_IBOutletAttr), "IBOutletAttr"},
+	{int64(Cursor_IBOutletCollectionAttr), "IBOutletCollectionAttr"},
+	{int64(Cursor_CXXFinalAttr), "CXXFinalAttr"},
+	{int64(Cursor_CXXOverrideAttr), "CXXOverrideAttr"},
+	{int64(Cursor_AnnotateAttr), "AnnotateAttr"},
+	{int64(Cursor_AsmLabelAttr), "AsmLabelAttr"},
+	{int64(Cursor_PackedAttr), "PackedAttr"},
+	{int64(Cursor_PureAttr), "PureAttr"},
+	{int64(Cursor_ConstAttr), "ConstAttr"},
+	{int64(Cursor_NoDuplicateAttr), "NoDuplicateAttr"},
+	{int64(Cursor_CUDAConstantAttr), "CUDAConstantAttr"},
+	{int64(Cursor_CUDADeviceAttr), "CUDADeviceAttr"},
+	{int64(Cursor_CUDAGlobalAttr), "CUDAGlobalAttr"},
+	{int64(Cursor_CUDAHostAttr), "CUDAHostAttr"},
+	{int64(Cursor_CUDASharedAttr), "CUDASharedAttr"},
+	{int64(Cursor_VisibilityAttr), "VisibilityAttr"},
+	{int64(Cursor_DLLExport), "DLLExport"},
+	{int64(Cursor_DLLImport), "DLLImport"},
+	{int64(Cursor_NSReturnsRetained), "NSReturnsRetained"},
+	{int64(Cursor_NSReturnsNotRetained), "NSReturnsNotRetained"},
+	{int64(Cursor_NSReturnsAutoreleased), "NSReturnsAutoreleased"},
+	{int64(Cursor_NSConsumesSelf), "NSConsumesSelf"},
+	{int64(Cursor_NSConsumed), "NSConsumed"},
+	{int64(Cursor_ObjCException), "ObjCException"},
+	{int64(Cursor_ObjCNSObject), "ObjCNSObject"},
+	{int64(Cursor_ObjCIndependentClass), "ObjCIndependentClass"},
+	{int64(Cursor_ObjCPreciseLifetime), "ObjCPreciseLifetime"},
+	{int64(Cursor_ObjCReturnsInnerPointer), "ObjCReturnsInnerPointer"},
+	{int64(Cursor_ObjCRequiresSuper), "ObjCRequiresSuper"},
+	{int64(Cursor_ObjCRootClass), "ObjCRootClass"},
+	{int64(Cursor_ObjCSubclassingRestricted), "ObjCSubclassingRestricted"},
+	{int64(Cursor_ObjCExplicitProtocolImpl), "ObjCExplicitProtocolImpl"},
+	{int64(Cursor_ObjCDesignatedInitializer), "ObjCDesignatedInitializer"},
+	{int64(Cursor_ObjCRuntimeVisible), "ObjCRuntimeVisible"},
+	{int64(Cursor_ObjCBoxable), "ObjCBoxable"},
+	{int64(Cursor_FlagEnum), "FlagEnum"},
+	{int64(Cursor_ConvergentAttr), "ConvergentAttr"},
+	{int64(Cursor_WarnUnusedAttr), "WarnUnusedAttr"},
+	{int64(Cursor_WarnUnusedResultAttr), "WarnUnusedResultAttr"},
+	{int64(Cursor_AlignedAttr), "AlignedAttr"},
+	{int64(Cursor_LastAttr), "LastAttr"},
+	{int64(Cursor_PreprocessingDirective), "PreprocessingDirective"},
+	{int64(Cursor_MacroDefinition), "MacroDefinition"},
+	{int64(Cursor_MacroExpansion), "MacroExpansion"},
+	{int64(Cursor_MacroInstantiation), "MacroInstantiation"},
+	{int64(Cursor_InclusionDirective), "InclusionDirective"},
+	{int64(Cursor_FirstPreprocessing), "FirstPreprocessing"},
+	{int64(Cursor_LastPreprocessing), "LastPreprocessing"},
+	{int64(Cursor_ModuleImportDecl), "ModuleImportDecl"},
+	{int64(Cursor_TypeAliasTemplateDecl), "TypeAliasTemplateDecl"},
+	{int64(Cursor_StaticAssert), "StaticAssert"},
+	{int64(Cursor_FriendDecl), "FriendDecl"},
+	{int64(Cursor_ConceptDecl), "ConceptDecl"},
+	{int64(Cursor_FirstExtraDecl), "FirstExtraDecl"},
+	{int64(Cursor_LastExtraDecl), "LastExtraDecl"},
+	{int64(Cursor_OverloadCandidate), "OverloadCandidate"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ck CursorKind) MarshalText() ([]byte, error) {
+	return []byte(cursorKindText.format(int64(ck))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ck *CursorKind) UnmarshalText(text []byte) error {
+	v, err := cursorKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ck = CursorKind(v)
+
+	return nil
+}
+
+// ParseCursorKind returns the CursorKind with the given name, e.g. "UnexposedDecl".
+func ParseCursorKind(s string) (CursorKind, error) {
+	v, err := cursorKindText.parse(s)
+
+	return CursorKind(v), err
+}
+
+var cursorExceptionSpecificationKindText = newEnumText("Cursor_ExceptionSpecificationKind", "Cursor_ExceptionSpecificationKind_", false, []enumName{
+	{int64(Cursor_ExceptionSpecificationKind_None), "None"},
+	{int64(Cursor_ExceptionSpecificationKind_DynamicNone), "DynamicNone"},
+	{int64(Cursor_ExceptionSpecificationKind_Dynamic), "Dynamic"},
+	{int64(Cursor_ExceptionSpecificationKind_MSAny), "MSAny"},
+	{int64(Cursor_ExceptionSpecificationKind_BasicNoexcept), "BasicNoexcept"},
+	{int64(Cursor_ExceptionSpecificationKind_ComputedNoexcept), "ComputedNoexcept"},
+	{int64(Cursor_ExceptionSpecificationKind_Unevaluated), "Unevaluated"},
+	{int64(Cursor_ExceptionSpecificationKind_Uninstantiated), "Uninstantiated"},
+	{int64(Cursor_ExceptionSpecificationKind_Unparsed), "Unparsed"},
+	{int64(Cursor_ExceptionSpecificationKind_NoThrow), "NoThrow"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (cesk Cursor_ExceptionSpecificationKind) MarshalText() ([]byte, error) {
+	return []byte(cursorExceptionSpecificationKindText.format(int64(cesk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (cesk *Cursor_ExceptionSpecificationKind) UnmarshalText(text []byte) error {
+	v, err := cursorExceptionSpecificationKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*cesk = Cursor_ExceptionSpecificationKind(v)
+
+	return nil
+}
+
+// ParseCursorExceptionSpecificationKind returns the Cursor_ExceptionSpecificationKind with the given name, e.g. "None".
+func ParseCursorExceptionSpecificationKind(s string) (Cursor_ExceptionSpecificationKind, error) {
+	v, err := cursorExceptionSpecificationKindText.parse(s)
+
+	return Cursor_ExceptionSpecificationKind(v), err
+}
+
+var declQualifierKindText = newEnumText("DeclQualifierKind", "DeclQualifier_", true, []enumName{
+	{int64(DeclQualifier_None), "None"},
+	{int64(DeclQualifier_In), "In"},
+	{int64(DeclQualifier_Inout), "Inout"},
+	{int64(DeclQualifier_Out), "Out"},
+	{int64(DeclQualifier_Bycopy), "Bycopy"},
+	{int64(DeclQualifier_Byref), "Byref"},
+	{int64(DeclQualifier_Oneway), "Oneway"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (dqk DeclQualifierKind) MarshalText() ([]byte, error) {
+	return []byte(declQualifierKindText.format(int64(dqk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (dqk *DeclQualifierKind) UnmarshalText(text []byte) error {
+	v, err := declQualifierKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*dqk = DeclQualifierKind(v)
+
+	return nil
+}
+
+// ParseDeclQualifierKind returns the DeclQualifierKind with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseDeclQualifierKind(s string) (DeclQualifierKind, error) {
+	v, err := declQualifierKindText.parse(s)
+
+	return DeclQualifierKind(v), err
+}
+
+var diagnosticDisplayOptionsText = newEnumText("DiagnosticDisplayOptions", "Diagnostic_", true, []enumName{
+	{int64(Diagnostic_DisplaySourceLocation), "DisplaySourceLocation"},
+	{int64(Diagnostic_DisplayColumn), "DisplayColumn"},
+	{int64(Diagnostic_DisplaySourceRanges), "DisplaySourceRanges"},
+	{int64(Diagnostic_DisplayOption), "DisplayOption"},
+	{int64(Diagnostic_DisplayCategoryId), "DisplayCategoryId"},
+	{int64(Diagnostic_DisplayCategoryName), "DisplayCategoryName"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ddo DiagnosticDisplayOptions) MarshalText() ([]byte, error) {
+	return []byte(diagnosticDisplayOptionsText.format(int64(ddo))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ddo *DiagnosticDisplayOptions) UnmarshalText(text []byte) error {
+	v, err := diagnosticDisplayOptionsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ddo = DiagnosticDisplayOptions(v)
+
+	return nil
+}
+
+// ParseDiagnosticDisplayOptions returns the DiagnosticDisplayOptions with the given name, e.g. "DisplaySourceLocation".
+//
+// Several flags can be combined with "|".
+func ParseDiagnosticDisplayOptions(s string) (DiagnosticDisplayOptions, error) {
+	v, err := diagnosticDisplayOptionsText.parse(s)
+
+	return DiagnosticDisplayOptions(v), err
+}
+
+var diagnosticSeverityText = newEnumText("DiagnosticSeverity", "Diagnostic_", false, []enumName{
+	{int64(Diagnostic_Ignored), "Ignored"},
+	{int64(Diagnostic_Note), "Note"},
+	{int64(Diagnostic_Warning), "Warning"},
+	{int64(Diagnostic_Error), "Error"},
+	{int64(Diagnostic_Fatal), "Fatal"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ds DiagnosticSeverity) MarshalText() ([]byte, error) {
+	return []byte(diagnosticSeverityText.format(int64(ds))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ds *DiagnosticSeverity) UnmarshalText(text []byte) error {
+	v, err := diagnosticSeverityText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ds = DiagnosticSeverity(v)
+
+	return nil
+}
+
+// ParseDiagnosticSeverity returns the DiagnosticSeverity with the given name, e.g. "Ignored".
+func ParseDiagnosticSeverity(s string) (DiagnosticSeverity, error) {
+	v, err := diagnosticSeverityText.parse(s)
+
+	return DiagnosticSeverity(v), err
+}
+
+var errorCodeText = newEnumText("ErrorCode", "Error_", false, []enumName{
+	{int64(Error_Success), "Success"},
+	{int64(Error_Failure), "Failure"},
+	{int64(Error_Crashed), "Crashed"},
+	{int64(Error_InvalidArguments), "InvalidArguments"},
+	{int64(Error_ASTReadError), "ASTReadError"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ec ErrorCode) MarshalText() ([]byte, error) {
+	return []byte(errorCodeText.format(int64(ec))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ec *ErrorCode) UnmarshalText(text []byte) error {
+	v, err := errorCodeText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ec = ErrorCode(v)
+
+	return nil
+}
+
+// ParseErrorCode returns the ErrorCode with the given name, e.g. "Success".
+func ParseErrorCode(s string) (ErrorCode, error) {
+	v, err := errorCodeText.parse(s)
+
+	return ErrorCode(v), err
+}
+
+var evalResultKindText = newEnumText("EvalResultKind", "Eval_", false, []enumName{
+	{int64(Eval_Int), "Int"},
+	{int64(Eval_Float), "Float"},
+	{int64(Eval_ObjCStrLiteral), "ObjCStrLiteral"},
+	{int64(Eval_StrLiteral), "StrLiteral"},
+	{int64(Eval_CFStr), "CFStr"},
+	{int64(Eval_Other), "Other"},
+	{int64(Eval_UnExposed), "UnExposed"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (erk EvalResultKind) MarshalText() ([]byte, error) {
+	return []byte(evalResultKindText.format(int64(erk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (erk *EvalResultKind) UnmarshalText(text []byte) error {
+	v, err := evalResultKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*erk = EvalResultKind(v)
+
+	return nil
+}
+
+// ParseEvalResultKind returns the EvalResultKind with the given name, e.g. "Int".
+func ParseEvalResultKind(s string) (EvalResultKind, error) {
+	v, err := evalResultKindText.parse(s)
+
+	return EvalResultKind(v), err
+}
+
+var globalOptFlagsText = newEnumText("GlobalOptFlags", "GlobalOpt_", true, []enumName{
+	{int64(GlobalOpt_None), "None"},
+	{int64(GlobalOpt_ThreadBackgroundPriorityForIndexing), "ThreadBackgroundPriorityForIndexing"},
+	{int64(GlobalOpt_ThreadBackgroundPriorityForEditing), "ThreadBackgroundPriorityForEditing"},
+	{int64(GlobalOpt_ThreadBackgroundPriorityForAll), "ThreadBackgroundPriorityForAll"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (gof GlobalOptFlags) MarshalText() ([]byte, error) {
+	return []byte(globalOptFlagsText.format(int64(gof))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (gof *GlobalOptFlags) UnmarshalText(text []byte) error {
+	v, err := globalOptFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*gof = GlobalOptFlags(v)
+
+	return nil
+}
+
+// ParseGlobalOptFlags returns the GlobalOptFlags with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseGlobalOptFlags(s string) (GlobalOptFlags, error) {
+	v, err := globalOptFlagsText.parse(s)
+
+	return GlobalOptFlags(v), err
+}
+
+var idxAttrKindText = newEnumText("IdxAttrKind", "IdxAttr_", false, []enumName{
+	{int64(IdxAttr_Unexposed), "Unexposed"},
+	{int64(IdxAttr_IBAction), "IBAction"},
+	{int64(IdxAttr_IBOutlet), "IBOutlet"},
+	{int64(IdxAttr_IBOutletCollection), "IBOutletCollection"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iak IdxAttrKind) MarshalText() ([]byte, error) {
+	return []byte(idxAttrKindText.format(int64(iak))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iak *IdxAttrKind) UnmarshalText(text []byte) error {
+	v, err := idxAttrKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iak = IdxAttrKind(v)
+
+	return nil
+}
+
+// ParseIdxAttrKind returns the IdxAttrKind with the given name, e.g. "Unexposed".
+func ParseIdxAttrKind(s string) (IdxAttrKind, error) {
+	v, err := idxAttrKindText.parse(s)
+
+	return IdxAttrKind(v), err
+}
+
+var idxDeclInfoFlagsText = newEnumText("IdxDeclInfoFlags", "IdxDeclFlag_", true, []enumName{
+	{int64(IdxDeclFlag_Skipped), "Skipped"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (idif IdxDeclInfoFlags) MarshalText() ([]byte, error) {
+	return []byte(idxDeclInfoFlagsText.format(int64(idif))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (idif *IdxDeclInfoFlags) UnmarshalText(text []byte) error {
+	v, err := idxDeclInfoFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*idif = IdxDeclInfoFlags(v)
+
+	return nil
+}
+
+// ParseIdxDeclInfoFlags returns the IdxDeclInfoFlags with the given name, e.g. "Skipped".
+//
+// Several flags can be combined with "|".
+func ParseIdxDeclInfoFlags(s string) (IdxDeclInfoFlags, error) {
+	v, err := idxDeclInfoFlagsText.parse(s)
+
+	return IdxDeclInfoFlags(v), err
+}
+
+var idxEntityCXXTemplateKindText = newEnumText("IdxEntityCXXTemplateKind", "IdxEntity_", false, []enumName{
+	{int64(IdxEntity_NonTemplate), "NonTemplate"},
+	{int64(IdxEntity_Template), "Template"},
+	{int64(IdxEntity_TemplatePartialSpecialization), "TemplatePartialSpecialization"},
+	{int64(IdxEntity_TemplateSpecialization), "TemplateSpecialization"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iecxxtk IdxEntityCXXTemplateKind) MarshalText() ([]byte, error) {
+	return []byte(idxEntityCXXTemplateKindText.format(int64(iecxxtk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iecxxtk *IdxEntityCXXTemplateKind) UnmarshalText(text []byte) error {
+	v, err := idxEntityCXXTemplateKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iecxxtk = IdxEntityCXXTemplateKind(v)
+
+	return nil
+}
+
+// ParseIdxEntityCXXTemplateKind returns the IdxEntityCXXTemplateKind with the given name, e.g. "NonTemplate".
+func ParseIdxEntityCXXTemplateKind(s string) (IdxEntityCXXTemplateKind, error) {
+	v, err := idxEntityCXXTemplateKindText.parse(s)
+
+	return IdxEntityCXXTemplateKind(v), err
+}
+
+var idxEntityKindText = newEnumText("IdxEntityKind", "IdxEntity_", false, []enumName{
+	{int64(IdxEntity_Unexposed), "Unexposed"},
+	{int64(IdxEntity_Typedef), "Typedef"},
+	{int64(IdxEntity_Function), "Function"},
+	{int64(IdxEntity_Variable), "Variable"},
+	{int64(IdxEntity_Field), "Field"},
+	{int64(IdxEntity_EnumConstant), "EnumConstant"},
+	{int64(IdxEntity_ObjCClass), "ObjCClass"},
+	{int64(IdxEntity_ObjCProtocol), "ObjCProtocol"},
+	{int64(IdxEntity_ObjCCategory), "ObjCCategory"},
+	{int64(IdxEntity_ObjCInstanceMethod), "ObjCInstanceMethod"},
+	{int64(IdxEntity_ObjCClassMethod), "ObjCClassMethod"},
+	{int64(IdxEntity_ObjCProperty), "ObjCProperty"},
+	{int64(IdxEntity_ObjCIvar), "ObjCIvar"},
+	{int64(IdxEntity_Enum), "Enum"},
+	{int64(IdxEntity_Struct), "Struct"},
+	{int64(IdxEntity_Union), "Union"},
+	{int64(IdxEntity_CXXClass), "CXXClass"},
+	{int64(IdxEntity_CXXNamespace), "CXXNamespace"},
+	{int64(IdxEntity_CXXNamespaceAlias), "CXXNamespaceAlias"},
+	{int64(IdxEntity_CXXStaticVariable), "CXXStaticVariable"},
+	{int64(IdxEntity_CXXStaticMethod), "CXXStaticMethod"},
+	{int64(IdxEntity_CXXInstanceMethod), "CXXInstanceMethod"},
+	{int64(IdxEntity_CXXConstructor), "CXXConstructor"},
+	{int64(IdxEntity_CXXDestructor), "CXXDestructor"},
+	{int64(IdxEntity_CXXConversionFunction), "CXXConversionFunction"},
+	{int64(IdxEntity_CXXTypeAlias), "CXXTypeAlias"},
+	{int64(IdxEntity_CXXInterface), "CXXInterface"},
+	{int64(IdxEntity_CXXConcept), "CXXConcept"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iek IdxEntityKind) MarshalText() ([]byte, error) {
+	return []byte(idxEntityKindText.format(int64(iek))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iek *IdxEntityKind) UnmarshalText(text []byte) error {
+	v, err := idxEntityKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iek = IdxEntityKind(v)
+
+	return nil
+}
+
+// ParseIdxEntityKind returns the IdxEntityKind with the given name, e.g. "Unexposed".
+func ParseIdxEntityKind(s string) (IdxEntityKind, error) {
+	v, err := idxEntityKindText.parse(s)
+
+	return IdxEntityKind(v), err
+}
+
+var idxEntityLanguageText = newEnumText("IdxEntityLanguage", "IdxEntityLang_", false, []enumName{
+	{int64(IdxEntityLang_None), "None"},
+	{int64(IdxEntityLang_C), "C"},
+	{int64(IdxEntityLang_ObjC), "ObjC"},
+	{int64(IdxEntityLang_CXX), "CXX"},
+	{int64(IdxEntityLang_Swift), "Swift"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iel IdxEntityLanguage) MarshalText() ([]byte, error) {
+	return []byte(idxEntityLanguageText.format(int64(iel))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iel *IdxEntityLanguage) UnmarshalText(text []byte) error {
+	v, err := idxEntityLanguageText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iel = IdxEntityLanguage(v)
+
+	return nil
+}
+
+// ParseIdxEntityLanguage returns the IdxEntityLanguage with the given name, e.g. "None".
+func ParseIdxEntityLanguage(s string) (IdxEntityLanguage, error) {
+	v, err := idxEntityLanguageText.parse(s)
+
+	return IdxEntityLanguage(v), err
+}
+
+var idxEntityRefKindText = newEnumText("IdxEntityRefKind", "IdxEntityRef_", false, []enumName{
+	{int64(IdxEntityRef_Direct), "Direct"},
+	{int64(IdxEntityRef_Implicit), "Implicit"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ierk IdxEntityRefKind) MarshalText() ([]byte, error) {
+	return []byte(idxEntityRefKindText.format(int64(ierk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ierk *IdxEntityRefKind) UnmarshalText(text []byte) error {
+	v, err := idxEntityRefKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ierk = IdxEntityRefKind(v)
+
+	return nil
+}
+
+// ParseIdxEntityRefKind returns the IdxEntityRefKind with the given name, e.g. "Direct".
+func ParseIdxEntityRefKind(s string) (IdxEntityRefKind, error) {
+	v, err := idxEntityRefKindText.parse(s)
+
+	return IdxEntityRefKind(v), err
+}
+
+var idxObjCContainerKindText = newEnumText("IdxObjCContainerKind", "IdxObjCContainer_", false, []enumName{
+	{int64(IdxObjCContainer_ForwardRef), "ForwardRef"},
+	{int64(IdxObjCContainer_Interface), "Interface"},
+	{int64(IdxObjCContainer_Implementation), "Implementation"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iocck IdxObjCContainerKind) MarshalText() ([]byte, error) {
+	return []byte(idxObjCContainerKindText.format(int64(iocck))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iocck *IdxObjCContainerKind) UnmarshalText(text []byte) error {
+	v, err := idxObjCContainerKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iocck = IdxObjCContainerKind(v)
+
+	return nil
+}
+
+// ParseIdxObjCContainerKind returns the IdxObjCContainerKind with the given name, e.g. "ForwardRef".
+func ParseIdxObjCContainerKind(s string) (IdxObjCContainerKind, error) {
+	v, err := idxObjCContainerKindText.parse(s)
+
+	return IdxObjCContainerKind(v), err
+}
+
+var indexOptFlagsText = newEnumText("IndexOptFlags", "IndexOpt_", true, []enumName{
+	{int64(IndexOpt_None), "None"},
+	{int64(IndexOpt_SuppressRedundantRefs), "SuppressRedundantRefs"},
+	{int64(IndexOpt_IndexFunctionLocalSymbols), "IndexFunctionLocalSymbols"},
+	{int64(IndexOpt_IndexImplicitTemplateInstantiations), "IndexImplicitTemplateInstantiations"},
+	{int64(IndexOpt_SuppressWarnings), "SuppressWarnings"},
+	{int64(IndexOpt_SkipParsedBodiesInSession), "SkipParsedBodiesInSession"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (iof IndexOptFlags) MarshalText() ([]byte, error) {
+	return []byte(indexOptFlagsText.format(int64(iof))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (iof *IndexOptFlags) UnmarshalText(text []byte) error {
+	v, err := indexOptFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*iof = IndexOptFlags(v)
+
+	return nil
+}
+
+// ParseIndexOptFlags returns the IndexOptFlags with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseIndexOptFlags(s string) (IndexOptFlags, error) {
+	v, err := indexOptFlagsText.parse(s)
+
+	return IndexOptFlags(v), err
+}
+
+var languageKindText = newEnumText("LanguageKind", "Language_", false, []enumName{
+	{int64(Language_Invalid), "Invalid"},
+	{int64(Language_C), "C"},
+	{int64(Language_ObjC), "ObjC"},
+	{int64(Language_CPlusPlus), "CPlusPlus"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (lk LanguageKind) MarshalText() ([]byte, error) {
+	return []byte(languageKindText.format(int64(lk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (lk *LanguageKind) UnmarshalText(text []byte) error {
+	v, err := languageKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*lk = LanguageKind(v)
+
+	return nil
+}
+
+// ParseLanguageKind returns the LanguageKind with the given name, e.g. "Invalid".
+func ParseLanguageKind(s string) (LanguageKind, error) {
+	v, err := languageKindText.parse(s)
+
+	return LanguageKind(v), err
+}
+
+var linkageKindText = newEnumText("LinkageKind", "Linkage_", false, []enumName{
+	{int64(Linkage_Invalid), "Invalid"},
+	{int64(Linkage_NoLinkage), "NoLinkage"},
+	{int64(Linkage_Internal), "Internal"},
+	{int64(Linkage_UniqueExternal), "UniqueExternal"},
+	{int64(Linkage_External), "External"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (lk LinkageKind) MarshalText() ([]byte, error) {
+	return []byte(linkageKindText.format(int64(lk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (lk *LinkageKind) UnmarshalText(text []byte) error {
+	v, err := linkageKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*lk = LinkageKind(v)
+
+	return nil
+}
+
+// ParseLinkageKind returns the LinkageKind with the given name, e.g. "Invalid".
+func ParseLinkageKind(s string) (LinkageKind, error) {
+	v, err := linkageKindText.parse(s)
+
+	return LinkageKind(v), err
+}
+
+var loadDiagErrorText = newEnumText("LoadDiag_Error", "LoadDiag_", false, []enumName{
+	{int64(LoadDiag_None), "None"},
+	{int64(LoadDiag_Unknown), "Unknown"},
+	{int64(LoadDiag_CannotLoad), "CannotLoad"},
+	{int64(LoadDiag_InvalidFile), "InvalidFile"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (lde LoadDiag_Error) MarshalText() ([]byte, error) {
+	return []byte(loadDiagErrorText.format(int64(lde))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (lde *LoadDiag_Error) UnmarshalText(text []byte) error {
+	v, err := loadDiagErrorText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*lde = LoadDiag_Error(v)
+
+	return nil
+}
+
+// ParseLoadDiagError returns the LoadDiag_Error with the given name, e.g. "None".
+func ParseLoadDiagError(s string) (LoadDiag_Error, error) {
+	v, err := loadDiagErrorText.parse(s)
+
+	return LoadDiag_Error(v), err
+}
+
+var nameRefFlagsText = newEnumText("NameRefFlags", "NameRange_", true, []enumName{
+	{int64(NameRange_WantQualifier), "WantQualifier"},
+	{int64(NameRange_WantTemplateArgs), "WantTemplateArgs"},
+	{int64(NameRange_WantSinglePiece), "WantSinglePiece"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (nrf NameRefFlags) MarshalText() ([]byte, error) {
+	return []byte(nameRefFlagsText.format(int64(nrf))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (nrf *NameRefFlags) UnmarshalText(text []byte) error {
+	v, err := nameRefFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*nrf = NameRefFlags(v)
+
+	return nil
+}
+
+// ParseNameRefFlags returns the NameRefFlags with the given name, e.g. "WantQualifier".
+//
+// Several flags can be combined with "|".
+func ParseNameRefFlags(s string) (NameRefFlags, error) {
+	v, err := nameRefFlagsText.parse(s)
+
+	return NameRefFlags(v), err
+}
+
+var printingPolicyPropertyText = newEnumText("PrintingPolicyProperty", "PrintingPolicy_", false, []enumName{
+	{int64(PrintingPolicy_Indentation), "Indentation"},
+	{int64(PrintingPolicy_SuppressSpecifiers), "SuppressSpecifiers"},
+	{int64(PrintingPolicy_SuppressTagKeyword), "SuppressTagKeyword"},
+	{int64(PrintingPolicy_IncludeTagDefinition), "IncludeTagDefinition"},
+	{int64(PrintingPolicy_SuppressScope), "SuppressScope"},
+	{int64(PrintingPolicy_SuppressUnwrittenScope), "SuppressUnwrittenScope"},
+	{int64(PrintingPolicy_SuppressInitializers), "SuppressInitializers"},
+	{int64(PrintingPolicy_ConstantArraySizeAsWritten), "ConstantArraySizeAsWritten"},
+	{int64(PrintingPolicy_AnonymousTagLocations), "AnonymousTagLocations"},
+	{int64(PrintingPolicy_SuppressStrongLifetime), "SuppressStrongLifetime"},
+	{int64(PrintingPolicy_SuppressLifetimeQualifiers), "SuppressLifetimeQualifiers"},
+	{int64(PrintingPolicy_SuppressTemplateArgsInCXXConstructors), "SuppressTemplateArgsInCXXConstructors"},
+	{int64(PrintingPolicy_Bool), "Bool"},
+	{int64(PrintingPolicy_Restrict), "Restrict"},
+	{int64(PrintingPolicy_Alignof), "Alignof"},
+	{int64(PrintingPolicy_UnderscoreAlignof), "UnderscoreAlignof"},
+	{int64(PrintingPolicy_UseVoidForZeroParams), "UseVoidForZeroParams"},
+	{int64(PrintingPolicy_TerseOutput), "TerseOutput"},
+	{int64(PrintingPolicy_PolishForDeclaration), "PolishForDeclaration"},
+	{int64(PrintingPolicy_Half), "Half"},
+	{int64(PrintingPolicy_MSWChar), "MSWChar"},
+	{int64(PrintingPolicy_IncludeNewlines), "IncludeNewlines"},
+	{int64(PrintingPolicy_MSVCFormatting), "MSVCFormatting"},
+	{int64(PrintingPolicy_ConstantsAsWritten), "ConstantsAsWritten"},
+	{int64(PrintingPolicy_SuppressImplicitBase), "SuppressImplicitBase"},
+	{int64(PrintingPolicy_FullyQualifiedName), "FullyQualifiedName"},
+	{int64(PrintingPolicy_LastProperty), "LastProperty"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ppp PrintingPolicyProperty) MarshalText() ([]byte, error) {
+	return []byte(printingPolicyPropertyText.format(int64(ppp))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ppp *PrintingPolicyProperty) UnmarshalText(text []byte) error {
+	v, err := printingPolicyPropertyText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ppp = PrintingPolicyProperty(v)
+
+	return nil
+}
+
+// ParsePrintingPolicyProperty returns the PrintingPolicyProperty with the given name, e.g. "Indentation".
+func ParsePrintingPolicyProperty(s string) (PrintingPolicyProperty, error) {
+	v, err := printingPolicyPropertyText.parse(s)
+
+	return PrintingPolicyProperty(v), err
+}
+
+var propertyAttrKindText = newEnumText("PropertyAttrKind", "PropertyAttr_", true, []enumName{
+	{int64(PropertyAttr_noattr), "noattr"},
+	{int64(PropertyAttr_readonly), "readonly"},
+	{int64(PropertyAttr_getter), "getter"},
+	{int64(PropertyAttr_assign), "assign"},
+	{int64(PropertyAttr_readwrite), "readwrite"},
+	{int64(PropertyAttr_retain), "retain"},
+	{int64(PropertyAttr_copy), "copy"},
+	{int64(PropertyAttr_nonatomic), "nonatomic"},
+	{int64(PropertyAttr_setter), "setter"},
+	{int64(PropertyAttr_atomic), "atomic"},
+	{int64(PropertyAttr_weak), "weak"},
+	{int64(PropertyAttr_strong), "strong"},
+	{int64(PropertyAttr_unsafe_unretained), "unsafe_unretained"},
+	{int64(PropertyAttr_class), "class"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (pak PropertyAttrKind) MarshalText() ([]byte, error) {
+	return []byte(propertyAttrKindText.format(int64(pak))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (pak *PropertyAttrKind) UnmarshalText(text []byte) error {
+	v, err := propertyAttrKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*pak = PropertyAttrKind(v)
+
+	return nil
+}
+
+// ParsePropertyAttrKind returns the PropertyAttrKind with the given name, e.g. "noattr".
+//
+// Several flags can be combined with "|".
+func ParsePropertyAttrKind(s string) (PropertyAttrKind, error) {
+	v, err := propertyAttrKindText.parse(s)
+
+	return PropertyAttrKind(v), err
+}
+
+var refQualifierKindText = newEnumText("RefQualifierKind", "RefQualifier_", false, []enumName{
+	{int64(RefQualifier_None), "None"},
+	{int64(RefQualifier_LValue), "LValue"},
+	{int64(RefQualifier_RValue), "RValue"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (rqk RefQualifierKind) MarshalText() ([]byte, error) {
+	return []byte(refQualifierKindText.format(int64(rqk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (rqk *RefQualifierKind) UnmarshalText(text []byte) error {
+	v, err := refQualifierKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*rqk = RefQualifierKind(v)
+
+	return nil
+}
+
+// ParseRefQualifierKind returns the RefQualifierKind with the given name, e.g. "None".
+func ParseRefQualifierKind(s string) (RefQualifierKind, error) {
+	v, err := refQualifierKindText.parse(s)
+
+	return RefQualifierKind(v), err
+}
+
+var reparseFlagsText = newEnumText("Reparse_Flags", "Reparse_", true, []enumName{
+	{int64(Reparse_None), "None"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (rf Reparse_Flags) MarshalText() ([]byte, error) {
+	return []byte(reparseFlagsText.format(int64(rf))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (rf *Reparse_Flags) UnmarshalText(text []byte) error {
+	v, err := reparseFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*rf = Reparse_Flags(v)
+
+	return nil
+}
+
+// ParseReparseFlags returns the Reparse_Flags with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseReparseFlags(s string) (Reparse_Flags, error) {
+	v, err := reparseFlagsText.parse(s)
+
+	return Reparse_Flags(v), err
+}
+
+var resultText = newEnumText("Result", "Result_", false, []enumName{
+	{int64(Result_Success), "Success"},
+	{int64(Result_Invalid), "Invalid"},
+	{int64(Result_VisitBreak), "VisitBreak"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (r Result) MarshalText() ([]byte, error) {
+	return []byte(resultText.format(int64(r))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (r *Result) UnmarshalText(text []byte) error {
+	v, err := resultText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = Result(v)
+
+	return nil
+}
+
+// ParseResult returns the Result with the given name, e.g. "Success".
+func ParseResult(s string) (Result, error) {
+	v, err := resultText.parse(s)
+
+	return Result(v), err
+}
+
+var saveErrorText = newEnumText("SaveError", "SaveError_", false, []enumName{
+	{int64(SaveError_None), "None"},
+	{int64(SaveError_Unknown), "Unknown"},
+	{int64(SaveError_TranslationErrors), "TranslationErrors"},
+	{int64(SaveError_InvalidTU), "InvalidTU"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (se SaveError) MarshalText() ([]byte, error) {
+	return []byte(saveErrorText.format(int64(se))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (se *SaveError) UnmarshalText(text []byte) error {
+	v, err := saveErrorText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*se = SaveError(v)
+
+	return nil
+}
+
+// ParseSaveError returns the SaveError with the given name, e.g. "None".
+func ParseSaveError(s string) (SaveError, error) {
+	v, err := saveErrorText.parse(s)
+
+	return SaveError(v), err
+}
+
+var saveTranslationUnitFlagsText = newEnumText("SaveTranslationUnit_Flags", "SaveTranslationUnit_", true, []enumName{
+	{int64(SaveTranslationUnit_None), "None"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (stuf SaveTranslationUnit_Flags) MarshalText() ([]byte, error) {
+	return []byte(saveTranslationUnitFlagsText.format(int64(stuf))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (stuf *SaveTranslationUnit_Flags) UnmarshalText(text []byte) error {
+	v, err := saveTranslationUnitFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*stuf = SaveTranslationUnit_Flags(v)
+
+	return nil
+}
+
+// ParseSaveTranslationUnitFlags returns the SaveTranslationUnit_Flags with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseSaveTranslationUnitFlags(s string) (SaveTranslationUnit_Flags, error) {
+	v, err := saveTranslationUnitFlagsText.parse(s)
+
+	return SaveTranslationUnit_Flags(v), err
+}
+
+var storageClassText = newEnumText("StorageClass", "SC_", false, []enumName{
+	{int64(SC_Invalid), "Invalid"},
+	{int64(SC_None), "None"},
+	{int64(SC_Extern), "Extern"},
+	{int64(SC_Static), "Static"},
+	{int64(SC_PrivateExtern), "PrivateExtern"},
+	{int64(SC_OpenCLWorkGroupLocal), "OpenCLWorkGroupLocal"},
+	{int64(SC_Auto), "Auto"},
+	{int64(SC_Register), "Register"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (sc StorageClass) MarshalText() ([]byte, error) {
+	return []byte(storageClassText.format(int64(sc))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (sc *StorageClass) UnmarshalText(text []byte) error {
+	v, err := storageClassText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*sc = StorageClass(v)
+
+	return nil
+}
+
+// ParseStorageClass returns the StorageClass with the given name, e.g. "Invalid".
+func ParseStorageClass(s string) (StorageClass, error) {
+	v, err := storageClassText.parse(s)
+
+	return StorageClass(v), err
+}
+
+var symbolRoleText = newEnumText("SymbolRole", "SymbolRole_", true, []enumName{
+	{int64(SymbolRole_None), "None"},
+	{int64(SymbolRole_Declaration), "Declaration"},
+	{int64(SymbolRole_Definition), "Definition"},
+	{int64(SymbolRole_Reference), "Reference"},
+	{int64(SymbolRole_Read), "Read"},
+	{int64(SymbolRole_Write), "Write"},
+	{int64(SymbolRole_Call), "Call"},
+	{int64(SymbolRole_Dynamic), "Dynamic"},
+	{int64(SymbolRole_AddressOf), "AddressOf"},
+	{int64(SymbolRole_Implicit), "Implicit"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (sr SymbolRole) MarshalText() ([]byte, error) {
+	return []byte(symbolRoleText.format(int64(sr))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (sr *SymbolRole) UnmarshalText(text []byte) error {
+	v, err := symbolRoleText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*sr = SymbolRole(v)
+
+	return nil
+}
+
+// ParseSymbolRole returns the SymbolRole with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseSymbolRole(s string) (SymbolRole, error) {
+	v, err := symbolRoleText.parse(s)
+
+	return SymbolRole(v), err
+}
+
+var tlsKindText = newEnumText("TLSKind", "TLS_", false, []enumName{
+	{int64(TLS_None), "None"},
+	{int64(TLS_Dynamic), "Dynamic"},
+	{int64(TLS_Static), "Static"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tlsk TLSKind) MarshalText() ([]byte, error) {
+	return []byte(tlsKindText.format(int64(tlsk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tlsk *TLSKind) UnmarshalText(text []byte) error {
+	v, err := tlsKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tlsk = TLSKind(v)
+
+	return nil
+}
+
+// ParseTLSKind returns the TLSKind with the given name, e.g. "None".
+func ParseTLSKind(s string) (TLSKind, error) {
+	v, err := tlsKindText.parse(s)
+
+	return TLSKind(v), err
+}
+
+var tuResourceUsageKindText = newEnumText("TUResourceUsageKind", "TUResourceUsage_", false, []enumName{
+	{int64(TUResourceUsage_AST), "AST"},
+	{int64(TUResourceUsage_Identifiers), "Identifiers"},
+	{int64(TUResourceUsage_Selectors), "Selectors"},
+	{int64(TUResourceUsage_GlobalCompletionResults), "GlobalCompletionResults"},
+	{int64(TUResourceUsage_SourceManagerContentCache), "SourceManagerContentCache"},
+	{int64(TUResourceUsage_AST_SideTables), "AST_SideTables"},
+	{int64(TUResourceUsage_SourceManager_Membuffer_Malloc), "SourceManager_Membuffer_Malloc"},
+	{int64(TUResourceUsage_SourceManager_Membuffer_MMap), "SourceManager_Membuffer_MMap"},
+	{int64(TUResourceUsage_ExternalASTSource_Membuffer_Malloc), "ExternalASTSource_Membuffer_Malloc"},
+	{int64(TUResourceUsage_ExternalASTSource_Membuffer_MMap), "ExternalASTSource_Membuffer_MMap"},
+	{int64(TUResourceUsage_Preprocessor), "Preprocessor"},
+	{int64(TUResourceUsage_PreprocessingRecord), "PreprocessingRecord"},
+	{int64(TUResourceUsage_SourceManager_DataStructures), "SourceManager_DataStructures"},
+	{int64(TUResourceUsage_Preprocessor_HeaderSearch), "Preprocessor_HeaderSearch"},
+	{int64(TUResourceUsage_MEMORY_IN_BYTES_BEGIN), "MEMORY_IN_BYTES_BEGIN"},
+	{int64(TUResourceUsage_MEMORY_IN_BYTES_END), "MEMORY_IN_BYTES_END"},
+	{int64(TUResourceUsage_First), "First"},
+	{int64(TUResourceUsage_Last), "Last"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (turuk TUResourceUsageKind) MarshalText() ([]byte, error) {
+	return []byte(tuResourceUsageKindText.format(int64(turuk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (turuk *TUResourceUsageKind) UnmarshalText(text []byte) error {
+	v, err := tuResourceUsageKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*turuk = TUResourceUsageKind(v)
+
+	return nil
+}
+
+// ParseTUResourceUsageKind returns the TUResourceUsageKind with the given name, e.g. "AST".
+func ParseTUResourceUsageKind(s string) (TUResourceUsageKind, error) {
+	v, err := tuResourceUsageKindText.parse(s)
+
+	return TUResourceUsageKind(v), err
+}
+
+var templateArgumentKindText = newEnumText("TemplateArgumentKind", "TemplateArgumentKind_", false, []enumName{
+	{int64(TemplateArgumentKind_Null), "Null"},
+	{int64(TemplateArgumentKind_Type), "Type"},
+	{int64(TemplateArgumentKind_Declaration), "Declaration"},
+	{int64(TemplateArgumentKind_NullPtr), "NullPtr"},
+	{int64(TemplateArgumentKind_Integral), "Integral"},
+	{int64(TemplateArgumentKind_Template), "Template"},
+	{int64(TemplateArgumentKind_TemplateExpansion), "TemplateExpansion"},
+	{int64(TemplateArgumentKind_Expression), "Expression"},
+	{int64(TemplateArgumentKind_Pack), "Pack"},
+	{int64(TemplateArgumentKind_Invalid), "Invalid"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tak TemplateArgumentKind) MarshalText() ([]byte, error) {
+	return []byte(templateArgumentKindText.format(int64(tak))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tak *TemplateArgumentKind) UnmarshalText(text []byte) error {
+	v, err := templateArgumentKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tak = TemplateArgumentKind(v)
+
+	return nil
+}
+
+// ParseTemplateArgumentKind returns the TemplateArgumentKind with the given name, e.g. "Null".
+func ParseTemplateArgumentKind(s string) (TemplateArgumentKind, error) {
+	v, err := templateArgumentKindText.parse(s)
+
+	return TemplateArgumentKind(v), err
+}
+
+var tokenKindText = newEnumText("TokenKind", "Token_", false, []enumName{
+	{int64(Token_Punctuation), "Punctuation"},
+	{int64(Token_Keyword), "Keyword"},
+	{int64(Token_Identifier), "Identifier"},
+	{int64(Token_Literal), "Literal"},
+	{int64(Token_Comment), "Comment"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tk TokenKind) MarshalText() ([]byte, error) {
+	return []byte(tokenKindText.format(int64(tk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tk *TokenKind) UnmarshalText(text []byte) error {
+	v, err := tokenKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tk = TokenKind(v)
+
+	return nil
+}
+
+// ParseTokenKind returns the TokenKind with the given name, e.g. "Punctuation".
+func ParseTokenKind(s string) (TokenKind, error) {
+	v, err := tokenKindText.parse(s)
+
+	return TokenKind(v), err
+}
+
+var translationUnitFlagsText = newEnumText("TranslationUnit_Flags", "TranslationUnit_", true, []enumName{
+	{int64(TranslationUnit_None), "None"},
+	{int64(TranslationUnit_DetailedPreprocessingRecord), "DetailedPreprocessingRecord"},
+	{int64(TranslationUnit_Incomplete), "Incomplete"},
+	{int64(TranslationUnit_PrecompiledPreamble), "PrecompiledPreamble"},
+	{int64(TranslationUnit_CacheCompletionResults), "CacheCompletionResults"},
+	{int64(TranslationUnit_ForSerialization), "ForSerialization"},
+	{int64(TranslationUnit_CXXChainedPCH), "CXXChainedPCH"},
+	{int64(TranslationUnit_SkipFunctionBodies), "SkipFunctionBodies"},
+	{int64(TranslationUnit_IncludeBriefCommentsInCodeCompletion), "IncludeBriefCommentsInCodeCompletion"},
+	{int64(TranslationUnit_CreatePreambleOnFirstParse), "CreatePreambleOnFirstParse"},
+	{int64(TranslationUnit_KeepGoing), "KeepGoing"},
+	{int64(TranslationUnit_SingleFileParse), "SingleFileParse"},
+	{int64(TranslationUnit_LimitSkipFunctionBodiesToPreamble), "LimitSkipFunctionBodiesToPreamble"},
+	{int64(TranslationUnit_IncludeAttributedTypes), "IncludeAttributedTypes"},
+	{int64(TranslationUnit_VisitImplicitAttributes), "VisitImplicitAttributes"},
+	{int64(TranslationUnit_IgnoreNonErrorsFromIncludedFiles), "IgnoreNonErrorsFromIncludedFiles"},
+	{int64(TranslationUnit_RetainExcludedConditionalBlocks), "RetainExcludedConditionalBlocks"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tuf TranslationUnit_Flags) MarshalText() ([]byte, error) {
+	return []byte(translationUnitFlagsText.format(int64(tuf))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tuf *TranslationUnit_Flags) UnmarshalText(text []byte) error {
+	v, err := translationUnitFlagsText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tuf = TranslationUnit_Flags(v)
+
+	return nil
+}
+
+// ParseTranslationUnitFlags returns the TranslationUnit_Flags with the given name, e.g. "None".
+//
+// Several flags can be combined with "|".
+func ParseTranslationUnitFlags(s string) (TranslationUnit_Flags, error) {
+	v, err := translationUnitFlagsText.parse(s)
+
+	return TranslationUnit_Flags(v), err
+}
+
+var typeKindText = newEnumText("TypeKind", "Type_", false, []enumName{
+	{int64(Type_Invalid), "Invalid"},
+	{int64(Type_Unexposed), "Unexposed"},
+	{int64(Type_Void), "Void"},
+	{int64(Type_Bool), "Bool"},
+	{int64(Type_Char_U), "Char_U"},
+	{int64(Type_UChar), "UChar"},
+	{int64(Type_Char16), "Char16"},
+	{int64(Type_Char32), "Char32"},
+	{int64(Type_UShort), "UShort"},
+	{int64(Type_UInt), "UInt"},
+	{int64(Type_ULong), "ULong"},
+	{int64(Type_ULongLong), "ULongLong"},
+	{int64(Type_UInt128), "UInt128"},
+	{int64(Type_Char_S), "Char_S"},
+	{int64(Type_SChar), "SChar"},
+	{int64(Type_WChar), "WChar"},
+	{int64(Type_Short), "Short"},
+	{int64(Type_Int), "Int"},
+	{int64(Type_Long), "Long"},
+	{int64(Type_LongLong), "LongLong"},
+	{int64(Type_Int128), "Int128"},
+	{int64(Type_Float), "Float"},
+	{int64(Type_Double), "Double"},
+	{int64(Type_LongDouble), "LongDouble"},
+	{int64(Type_NullPtr), "NullPtr"},
+	{int64(Type_Overload), "Overload"},
+	{int64(Type_Dependent), "Dependent"},
+	{int64(Type_ObjCId), "ObjCId"},
+	{int64(Type_ObjCClass), "ObjCClass"},
+	{int64(Type_ObjCSel), "ObjCSel"},
+	{int64(Type_Float128), "Float128"},
+	{int64(Type_Half), "Half"},
+	{int64(Type_Float16), "Float16"},
+	{int64(Type_ShortAccum), "ShortAccum"},
+	{int64(Type_Accum), "Accum"},
+	{int64(Type_LongAccum), "LongAccum"},
+	{int64(Type_UShortAccum), "UShortAccum"},
+	{int64(Type_UAccum), "UAccum"},
+	{int64(Type_ULongAccum), "ULongAccum"},
+	{int64(Type_BFloat16), "BFloat16"},
+	{int64(Type_Ibm128), "Ibm128"},
+	{int64(Type_FirstBuiltin), "FirstBuiltin"},
+	{int64(Type_LastBuiltin), "LastBuiltin"},
+	{int64(Type_Complex), "Complex"},
+	{int64(Type_Pointer), "Pointer"},
+	{int64(Type_BlockPointer), "BlockPointer"},
+	{int64(Type_LValueReference), "LValueReference"},
+	{int64(Type_RValueReference), "RValueReference"},
+	{int64(Type_Record), "Record"},
+	{int64(Type_Enum), "Enum"},
+	{int64(Type_Typedef), "Typedef"},
+	{int64(Type_ObjCInterface), "ObjCInterface"},
+	{int64(Type_ObjCObjectPointer), "ObjCObjectPointer"},
+	{int64(Type_FunctionNoProto), "FunctionNoProto"},
+	{int64(Type_FunctionProto), "FunctionProto"},
+	{int64(Type_ConstantArray), "ConstantArray"},
+	{int64(Type_Vector), "Vector"},
+	{int64(Type_IncompleteArray), "IncompleteArray"},
+	{int64(Type_VariableArray), "VariableArray"},
+	{int64(Type_DependentSizedArray), "DependentSizedArray"},
+	{int64(Type_MemberPointer), "MemberPointer"},
+	{int64(Type_Auto), "Auto"},
+	{int64(Type_Elaborated), "Elaborated"},
+	{int64(Type_Pipe), "Pipe"},
+	{int64(Type_OCLImage1dRO), "OCLImage1dRO"},
+	{int64(Type_OCLImage1dArrayRO), "OCLImage1dArrayRO"},
+	{int64(Type_OCLImage1dBufferRO), "OCLImage1dBufferRO"},
+	{int64(Type_OCLImage2dRO), "OCLImage2dRO"},
+	{int64(Type_OCLImage2dArrayRO), "OCLImage2dArrayRO"},
+	{int64(Type_OCLImage2dDepthRO), "OCLImage2dDepthRO"},
+	{int64(Type_OCLImage2dArrayDepthRO), "OCLImage2dArrayDepthRO"},
+	{int64(Type_OCLImage2dMSAARO), "OCLImage2dMSAARO"},
+	{int64(Type_OCLImage2dArrayMSAARO), "OCLImage2dArrayMSAARO"},
+	{int64(Type_OCLImage2dMSAADepthRO), "OCLImage2dMSAADepthRO"},
+	{int64(Type_OCLImage2dArrayMSAADepthRO), "OCLImage2dArrayMSAADepthRO"},
+	{int64(Type_OCLImage3dRO), "OCLImage3dRO"},
+	{int64(Type_OCLImage1dWO), "OCLImage1dWO"},
+	{int64(Type_OCLImage1dArrayWO), "OCLImage1dArrayWO"},
+	{int64(Type_OCLImage1dBufferWO), "OCLImage1dBufferWO"},
+	{int64(Type_OCLImage2dWO), "OCLImage2dWO"},
+	{int64(Type_OCLImage2dArrayWO), "OCLImage2dArrayWO"},
+	{int64(Type_OCLImage2dDepthWO), "OCLImage2dDepthWO"},
+	{int64(Type_OCLImage2dArrayDepthWO), "OCLImage2dArrayDepthWO"},
+	{int64(Type_OCLImage2dMSAAWO), "OCLImage2dMSAAWO"},
+	{int64(Type_OCLImage2dArrayMSAAWO), "OCLImage2dArrayMSAAWO"},
+	{int64(Type_OCLImage2dMSAADepthWO), "OCLImage2dMSAADepthWO"},
+	{int64(Type_OCLImage2dArrayMSAADepthWO), "OCLImage2dArrayMSAADepthWO"},
+	{int64(Type_OCLImage3dWO), "OCLImage3dWO"},
+	{int64(Type_OCLImage1dRW), "OCLImage1dRW"},
+	{int64(Type_OCLImage1dArrayRW), "OCLImage1dArrayRW"},
+	{int64(Type_OCLImage1dBufferRW), "OCLImage1dBufferRW"},
+	{int64(Type_OCLImage2dRW), "OCLImage2dRW"},
+	{int64(Type_OCLImage2dArrayRW), "OCLImage2dArrayRW"},
+	{int64(Type_OCLImage2dDepthRW), "OCLImage2dDepthRW"},
+	{int64(Type_OCLImage2dArrayDepthRW), "OCLImage2dArrayDepthRW"},
+	{int64(Type_OCLImage2dMSAARW), "OCLImage2dMSAARW"},
+	{int64(Type_OCLImage2dArrayMSAARW), "OCLImage2dArrayMSAARW"},
+	{int64(Type_OCLImage2dMSAADepthRW), "OCLImage2dMSAADepthRW"},
+	{int64(Type_OCLImage2dArrayMSAADepthRW), "OCLImage2dArrayMSAADepthRW"},
+	{int64(Type_OCLImage3dRW), "OCLImage3dRW"},
+	{int64(Type_OCLSampler), "OCLSampler"},
+	{int64(Type_OCLEvent), "OCLEvent"},
+	{int64(Type_OCLQueue), "OCLQueue"},
+	{int64(Type_OCLReserveID), "OCLReserveID"},
+	{int64(Type_ObjCObject), "ObjCObject"},
+	{int64(Type_ObjCTypeParam), "ObjCTypeParam"},
+	{int64(Type_Attributed), "Attributed"},
+	{int64(Type_OCLIntelSubgroupAVCMcePayload), "OCLIntelSubgroupAVCMcePayload"},
+	{int64(Type_OCLIntelSubgroupAVCImePayload), "OCLIntelSubgroupAVCImePayload"},
+	{int64(Type_OCLIntelSubgroupAVCRefPayload), "OCLIntelSubgroupAVCRefPayload"},
+	{int64(Type_OCLIntelSubgroupAVCSicPayload), "OCLIntelSubgroupAVCSicPayload"},
+	{int64(Type_OCLIntelSubgroupAVCMceResult), "OCLIntelSubgroupAVCMceResult"},
+	{int64(Type_OCLIntelSubgroupAVCImeResult), "OCLIntelSubgroupAVCImeResult"},
+	{int64(Type_OCLIntelSubgroupAVCRefResult), "OCLIntelSubgroupAVCRefResult"},
+	{int64(Type_OCLIntelSubgroupAVCSicResult), "OCLIntelSubgroupAVCSicResult"},
+	{int64(Type_OCLIntelSubgroupAVCImeResultSingleRefStreamout), "OCLIntelSubgroupAVCImeResultSingleRefStreamout"},
+	{int64(Type_OCLIntelSubgroupAVCImeResultDualRefStreamout), "OCLIntelSubgroupAVCImeResultDualRefStreamout"},
+	{int64(Type_OCLIntelSubgroupAVCImeSingleRefStreamin), "OCLIntelSubgroupAVCImeSingleRefStreamin"},
+	{int64(Type_OCLIntelSubgroupAVCImeDualRefStreamin), "OCLIntelSubgroupAVCImeDualRefStreamin"},
+	{int64(Type_ExtVector), "ExtVector"},
+	{int64(Type_Atomic), "Atomic"},
+	{int64(Type_BTFTagAttributed), "BTFTagAttributed"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tk TypeKind) MarshalText() ([]byte, error) {
+	return []byte(typeKindText.format(int64(tk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tk *TypeKind) UnmarshalText(text []byte) error {
+	v, err := typeKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tk = TypeKind(v)
+
+	return nil
+}
+
+// ParseTypeKind returns the TypeKind with the given name, e.g. "Invalid".
+func ParseTypeKind(s string) (TypeKind, error) {
+	v, err := typeKindText.parse(s)
+
+	return TypeKind(v), err
+}
+
+var typeLayoutErrorText = newEnumText("TypeLayoutError", "TypeLayoutError_", false, []enumName{
+	{int64(TypeLayoutError_Invalid), "Invalid"},
+	{int64(TypeLayoutError_Incomplete), "Incomplete"},
+	{int64(TypeLayoutError_Dependent), "Dependent"},
+	{int64(TypeLayoutError_NotConstantSize), "NotConstantSize"},
+	{int64(TypeLayoutError_InvalidFieldName), "InvalidFieldName"},
+	{int64(TypeLayoutError_Undeduced), "Undeduced"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tle TypeLayoutError) MarshalText() ([]byte, error) {
+	return []byte(typeLayoutErrorText.format(int64(tle))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tle *TypeLayoutError) UnmarshalText(text []byte) error {
+	v, err := typeLayoutErrorText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tle = TypeLayoutError(v)
+
+	return nil
+}
+
+// ParseTypeLayoutError returns the TypeLayoutError with the given name, e.g. "Invalid".
+func ParseTypeLayoutError(s string) (TypeLayoutError, error) {
+	v, err := typeLayoutErrorText.parse(s)
+
+	return TypeLayoutError(v), err
+}
+
+var typeNullabilityKindText = newEnumText("TypeNullabilityKind", "TypeNullability_", false, []enumName{
+	{int64(TypeNullability_NonNull), "NonNull"},
+	{int64(TypeNullability_Nullable), "Nullable"},
+	{int64(TypeNullability_Unspecified), "Unspecified"},
+	{int64(TypeNullability_Invalid), "Invalid"},
+	{int64(TypeNullability_NullableResult), "NullableResult"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (tnk TypeNullabilityKind) MarshalText() ([]byte, error) {
+	return []byte(typeNullabilityKindText.format(int64(tnk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (tnk *TypeNullabilityKind) UnmarshalText(text []byte) error {
+	v, err := typeNullabilityKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*tnk = TypeNullabilityKind(v)
+
+	return nil
+}
+
+// ParseTypeNullabilityKind returns the TypeNullabilityKind with the given name, e.g. "NonNull".
+func ParseTypeNullabilityKind(s string) (TypeNullabilityKind, error) {
+	v, err := typeNullabilityKindText.parse(s)
+
+	return TypeNullabilityKind(v), err
+}
+
+var visibilityKindText = newEnumText("VisibilityKind", "Visibility_", false, []enumName{
+	{int64(Visibility_Invalid), "Invalid"},
+	{int64(Visibility_Hidden), "Hidden"},
+	{int64(Visibility_Protected), "Protected"},
+	{int64(Visibility_Default), "Default"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (vk VisibilityKind) MarshalText() ([]byte, error) {
+	return []byte(visibilityKindText.format(int64(vk))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (vk *VisibilityKind) UnmarshalText(text []byte) error {
+	v, err := visibilityKindText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*vk = VisibilityKind(v)
+
+	return nil
+}
+
+// ParseVisibilityKind returns the VisibilityKind with the given name, e.g. "Invalid".
+func ParseVisibilityKind(s string) (VisibilityKind, error) {
+	v, err := visibilityKindText.parse(s)
+
+	return VisibilityKind(v), err
+}
+
+var visitorResultText = newEnumText("VisitorResult", "Visit_", false, []enumName{
+	{int64(Visit_Break), "Break"},
+	{int64(Visit_Continue), "Continue"},
+})
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (vr VisitorResult) MarshalText() ([]byte, error) {
+	return []byte(visitorResultText.format(int64(vr))), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (vr *VisitorResult) UnmarshalText(text []byte) error {
+	v, err := visitorResultText.parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*vr = VisitorResult(v)
+
+	return nil
+}
+
+// ParseVisitorResult returns the VisitorResult with the given name, e.g. "Break".
+func ParseVisitorResult(s string) (VisitorResult, error) {
+	v, err := visitorResultText.parse(s)
+
+	return VisitorResult(v), err
+}
@@ -0,0 +1,80 @@
+package clang
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEnumText(t *testing.T) {
+	table := []struct {
+		value interface {
+			MarshalText() ([]byte, error)
+		}
+		text string
+	}{
+		{CursorKind(Cursor_FunctionDecl), "FunctionDecl"},
+		{CursorKind(Cursor_InvalidFile), "InvalidFile"},
+		{Cursor_UnexposedDecl, "UnexposedDecl"},
+		{TypeKind(Type_Pointer), "Pointer"},
+		{DiagnosticSeverity(Diagnostic_Warning), "Warning"},
+		{StorageClass(SC_Static), "Static"},
+		{SymbolRole(SymbolRole_Reference | SymbolRole_Read), "Reference|Read"},
+		{SymbolRole(SymbolRole_None), "None"},
+		{CursorKind(100000), "100000"},
+	}
+
+	for _, tt := range table {
+		b, err := tt.value.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.text {
+			t.Errorf("expected %q. got=%q", tt.text, b)
+		}
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	if k, err := ParseCursorKind("FunctionDecl"); err != nil || k != Cursor_FunctionDecl {
+		t.Errorf("expected %d. got=%d %v", Cursor_FunctionDecl, k, err)
+	}
+	if k, err := ParseCursorKind("Cursor_StructDecl"); err != nil || k != Cursor_StructDecl {
+		t.Errorf("expected %d. got=%d %v", Cursor_StructDecl, k, err)
+	}
+	if k, err := ParseCursorKind("AsmStmt"); err != nil || k != Cursor_GCCAsmStmt {
+		t.Errorf("expected alias to parse to %d. got=%d %v", Cursor_GCCAsmStmt, k, err)
+	}
+	if r, err := ParseSymbolRole("Definition | Write"); err != nil || r != SymbolRole_Definition|SymbolRole_Write {
+		t.Errorf("expected %d. got=%d %v", SymbolRole_Definition|SymbolRole_Write, r, err)
+	}
+	if _, err := ParseLinkageKind("Nowhere"); err == nil {
+		t.Error("expected error for unknown name")
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	type entry struct {
+		Kind     CursorKind
+		Access   AccessSpecifier
+		Severity DiagnosticSeverity
+	}
+
+	in := entry{Cursor_CXXMethod, AccessSpecifier_Protected, Diagnostic_Error}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Kind":"CXXMethod","Access":"Protected","Severity":"Error"}`; string(b) != want {
+		t.Errorf("expected %s. got=%s", want, b)
+	}
+
+	var out entry
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %+v. got=%+v", in, out)
+	}
+}
@@ -0,0 +1,10 @@
+package clang
+
+// #include "go-clang.h"
+import "C"
+
+// Data returns the values identifying the file. On Unix systems they are the device, the inode
+// and the modification time of the file.
+func (fui FileUniqueID) Data() [3]uint64 {
+	return [3]uint64{uint64(fui.c.data[0]), uint64(fui.c.data[1]), uint64(fui.c.data[2])}
+}
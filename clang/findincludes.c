@@ -0,0 +1,8 @@
+#include "_cgo_export.h"
+#include "go-clang.h"
+
+CXResult go_clang_find_includes_in_file(CXTranslationUnit tu, CXFile file, void *fct) {
+	CXCursorAndRangeVisitor visitor = {(uintptr_t)fct, (enum CXVisitorResult (*)(void *, CXCursor, CXSourceRange))&GoClangCursorAndRangeVisitor};
+
+	return clang_findIncludesInFile(tu, file, visitor);
+}
@@ -0,0 +1,37 @@
+package clang
+
+// #include "go-clang.h"
+import "C"
+import (
+	"unsafe"
+)
+
+// IncludeVisitor is invoked for each inclusion directive found by
+// TranslationUnit.IncludesInFile with the cursor of the directive and its source range.
+//
+// The visitor should return Visit_Continue to continue the search or Visit_Break to stop it.
+type IncludeVisitor func(cursor Cursor, r SourceRange) VisitorResult
+
+// GoClangCursorAndRangeVisitor calls the include visitor.
+//
+//export GoClangCursorAndRangeVisitor
+func GoClangCursorAndRangeVisitor(cfct unsafe.Pointer, cursor C.CXCursor, r C.CXSourceRange) VisitorResult {
+	i := *(*C.int)(cfct)
+	f := visitors.lookup(int(i)).(*IncludeVisitor)
+
+	return (*f)(Cursor{cursor}, SourceRange{r})
+}
+
+// IncludesInFile finds the #import and #include directives in a file of the translation unit.
+// It is FindIncludesInFile with a Go function as visitor.
+//
+// Returns one of the Result values.
+func (tu TranslationUnit) IncludesInFile(file File, visitor IncludeVisitor) Result {
+	i := visitors.register(&visitor)
+	defer visitors.unregister(i)
+
+	// we need a pointer to the index because the context of the visitor is a void pointer.
+	ci := C.int(i)
+
+	return Result(C.go_clang_find_includes_in_file(tu.c, file.c, unsafe.Pointer(&ci)))
+}
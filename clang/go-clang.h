@@ -6,5 +6,7 @@
 #include "clang-c/Index.h"
 
 unsigned go_clang_visit_children(CXCursor c, void *fct);
+void go_clang_get_inclusions(CXTranslationUnit tu, void *fct);
+CXResult go_clang_find_includes_in_file(CXTranslationUnit tu, CXFile file, void *fct);
 
 #endif
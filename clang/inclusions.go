@@ -0,0 +1,43 @@
+package clang
+
+// #include "go-clang.h"
+import "C"
+import (
+	"unsafe"
+)
+
+// InclusionVisitor is invoked for each file included by a translation unit.
+//
+// The first argument is the included file. The inclusion stack holds the locations of the
+// inclusion directives which led to the file, the innermost directive first. It is empty for
+// the main file of the translation unit.
+type InclusionVisitor func(includedFile File, inclusionStack []SourceLocation)
+
+// GoClangInclusionVisitor calls the inclusion visitor.
+//
+//export GoClangInclusionVisitor
+func GoClangInclusionVisitor(includedFile C.CXFile, inclusionStack *C.CXSourceLocation, includeLen C.uint, cfct unsafe.Pointer) {
+	i := *(*C.int)(cfct)
+	f := visitors.lookup(int(i)).(*InclusionVisitor)
+
+	stack := make([]SourceLocation, int(includeLen))
+	if includeLen > 0 {
+		for j, loc := range unsafe.Slice(inclusionStack, int(includeLen)) {
+			stack[j] = SourceLocation{loc}
+		}
+	}
+
+	(*f)(File{includedFile}, stack)
+}
+
+// Inclusions visits the set of preprocessor inclusions in the translation unit. The visitor is
+// invoked once for every included file, including the main file.
+func (tu TranslationUnit) Inclusions(visitor InclusionVisitor) {
+	i := visitors.register(&visitor)
+	defer visitors.unregister(i)
+
+	// we need a pointer to the index because clang_getInclusions data parameter is a void pointer.
+	ci := C.int(i)
+
+	C.go_clang_get_inclusions(tu.c, unsafe.Pointer(&ci))
+}
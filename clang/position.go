@@ -0,0 +1,351 @@
+package clang
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Position is a position in a source file which is detached from its translation unit, so it
+// can be kept after the translation unit has been disposed.
+//
+// Line and Column are 1-based, Column and Offset count UTF-8 bytes. The zero Position is
+// invalid.
+type Position struct {
+	File   string
+	Line   uint32
+	Column uint32
+	Offset uint32
+}
+
+// IsValid reports whether the position refers to a file.
+func (p Position) IsValid() bool {
+	return p.File != "" && p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Before reports whether p is located before q in the same file.
+func (p Position) Before(q Position) bool {
+	if p.Line != q.Line {
+		return p.Line < q.Line
+	}
+
+	return p.Column < q.Column
+}
+
+// Span is a range in a source file between two positions. End is exclusive.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// IsValid reports whether the span refers to a file.
+func (s Span) IsValid() bool {
+	return s.Start.IsValid() && s.End.IsValid()
+}
+
+// Contains reports whether the position p is located inside the span.
+func (s Span) Contains(p Position) bool {
+	return p.File == s.Start.File && !p.Before(s.Start) && p.Before(s.End)
+}
+
+func (s Span) String() string {
+	if !s.IsValid() {
+		return "-"
+	}
+	if s.Start.File != s.End.File {
+		return fmt.Sprintf("%s-%s", s.Start, s.End)
+	}
+	if s.Start.Line == s.End.Line {
+		return fmt.Sprintf("%s-%d", s.Start, s.End.Column)
+	}
+
+	return fmt.Sprintf("%s-%d:%d", s.Start, s.End.Line, s.End.Column)
+}
+
+func newPosition(file File, line, column, offset uint32) Position {
+	if file.c == nil {
+		return Position{}
+	}
+
+	return Position{
+		File:   file.Name(),
+		Line:   line,
+		Column: column,
+		Offset: offset,
+	}
+}
+
+// Position returns the expansion position of the location, see ExpansionLocation.
+func (sl SourceLocation) Position() Position {
+	return newPosition(sl.ExpansionLocation())
+}
+
+// SpellingPosition returns the spelling position of the location, see SpellingLocation.
+func (sl SourceLocation) SpellingPosition() Position {
+	return newPosition(sl.SpellingLocation())
+}
+
+// PresumedPosition returns the presumed position of the location as specified by #line
+// directives, see PresumedLocation. The offset of presumed positions is always 0.
+func (sl SourceLocation) PresumedPosition() Position {
+	filename, line, column := sl.PresumedLocation()
+	if filename == "" {
+		return Position{}
+	}
+
+	return Position{
+		File:   filename,
+		Line:   line,
+		Column: column,
+	}
+}
+
+// Span returns the expansion span of the range.
+func (sr SourceRange) Span() Span {
+	return Span{sr.Start().Position(), sr.End().Position()}
+}
+
+// SpellingSpan returns the spelling span of the range.
+func (sr SourceRange) SpellingSpan() Span {
+	return Span{sr.Start().SpellingPosition(), sr.End().SpellingPosition()}
+}
+
+// PresumedSpan returns the presumed span of the range.
+func (sr SourceRange) PresumedSpan() Span {
+	return Span{sr.Start().PresumedPosition(), sr.End().PresumedPosition()}
+}
+
+// LocationForPosition returns the source location of the position in the translation unit. The
+// location is looked up by line and column, or by the offset if the line is 0. A null location
+// is returned if the file is not part of the translation unit.
+func (tu TranslationUnit) LocationForPosition(p Position) SourceLocation {
+	file := tu.File(p.File)
+	if file.c == nil {
+		return NewNullLocation()
+	}
+
+	if p.Line == 0 {
+		return tu.LocationForOffset(file, p.Offset)
+	}
+
+	return tu.Location(file, p.Line, p.Column)
+}
+
+// RangeForSpan returns the source range of the span in the translation unit.
+func (tu TranslationUnit) RangeForSpan(s Span) SourceRange {
+	return tu.LocationForPosition(s.Start).Range(tu.LocationForPosition(s.End))
+}
+
+// SourceText returns the content of the file as it is seen by the translation unit, including
+// unsaved changes.
+func (tu TranslationUnit) SourceText(file File) (*SourceText, bool) {
+	text, ok := tu.FileContents(file)
+	if !ok {
+		return nil, false
+	}
+
+	return NewSourceText(file.Name(), text), true
+}
+
+// ColumnUnit is the unit columns are counted in.
+type ColumnUnit uint32
+
+const (
+	// ColumnUnit_Byte counts UTF-8 bytes, as columns of libclang do.
+	ColumnUnit_Byte ColumnUnit = iota
+	// ColumnUnit_UTF16 counts UTF-16 code units, as positions of the Language Server Protocol do.
+	ColumnUnit_UTF16
+	// ColumnUnit_Codepoint counts Unicode code points.
+	ColumnUnit_Codepoint
+)
+
+func (cu ColumnUnit) Spelling() string {
+	switch cu {
+	case ColumnUnit_Byte:
+		return "ColumnUnit=Byte"
+	case ColumnUnit_UTF16:
+		return "ColumnUnit=UTF16"
+	case ColumnUnit_Codepoint:
+		return "ColumnUnit=Codepoint"
+	}
+
+	return fmt.Sprintf("ColumnUnit unknown %d", int(cu))
+}
+
+func (cu ColumnUnit) String() string {
+	return cu.Spelling()
+}
+
+// SourceText is the content of a source file with an index of its lines, used to convert
+// between offsets, positions and columns in different units.
+type SourceText struct {
+	file string
+	text string
+	// lines holds the offsets of the line starts.
+	lines []uint32
+}
+
+// NewSourceText returns the SourceText of the content text of the file with the name file.
+func NewSourceText(file, text string) *SourceText {
+	st := &SourceText{
+		file:  file,
+		text:  text,
+		lines: []uint32{0},
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			st.lines = append(st.lines, uint32(i+1))
+		}
+	}
+
+	return st
+}
+
+// Text returns the complete content.
+func (st *SourceText) Text() string {
+	return st.text
+}
+
+// NumLines returns the number of lines.
+func (st *SourceText) NumLines() uint32 {
+	return uint32(len(st.lines))
+}
+
+// Line returns the 1-based line without its line terminator.
+func (st *SourceText) Line(line uint32) string {
+	if line == 0 || line > uint32(len(st.lines)) {
+		return ""
+	}
+
+	start := st.lines[line-1]
+	end := uint32(len(st.text))
+	if line < uint32(len(st.lines)) {
+		end = st.lines[line] - 1
+	}
+	if end > start && st.text[end-1] == '\r' {
+		end--
+	}
+
+	return st.text[start:end]
+}
+
+// Position returns the position of the byte offset.
+func (st *SourceText) Position(offset uint32) Position {
+	if offset > uint32(len(st.text)) {
+		offset = uint32(len(st.text))
+	}
+
+	i := sort.Search(len(st.lines), func(i int) bool {
+		return st.lines[i] > offset
+	}) - 1
+
+	return Position{
+		File:   st.file,
+		Line:   uint32(i + 1),
+		Column: offset - st.lines[i] + 1,
+		Offset: offset,
+	}
+}
+
+// PositionAt returns the position of the 1-based line and column, where column is counted in
+// unit. Columns after the end of the line are clamped to the end of the line.
+func (st *SourceText) PositionAt(line, column uint32, unit ColumnUnit) Position {
+	if line == 0 {
+		line = 1
+	}
+	if line > uint32(len(st.lines)) {
+		return st.Position(uint32(len(st.text)))
+	}
+
+	col := ConvertColumn(st.Line(line), column, unit, ColumnUnit_Byte)
+
+	return Position{
+		File:   st.file,
+		Line:   line,
+		Column: col,
+		Offset: st.lines[line-1] + col - 1,
+	}
+}
+
+// Column returns the 1-based column of the position p counted in unit.
+func (st *SourceText) Column(p Position, unit ColumnUnit) uint32 {
+	return ConvertColumn(st.Line(p.Line), p.Column, ColumnUnit_Byte, unit)
+}
+
+// UTF16Position returns the 0-based line and UTF-16 character offset of p, as they are used by
+// the Language Server Protocol.
+func (st *SourceText) UTF16Position(p Position) (line, character uint32) {
+	if p.Line == 0 {
+		return 0, 0
+	}
+
+	return p.Line - 1, st.Column(p, ColumnUnit_UTF16) - 1
+}
+
+// PositionFromUTF16 returns the position of a 0-based line and UTF-16 character offset, as they
+// are used by the Language Server Protocol.
+func (st *SourceText) PositionFromUTF16(line, character uint32) Position {
+	return st.PositionAt(line+1, character+1, ColumnUnit_UTF16)
+}
+
+// ConvertColumn converts the 1-based column of the text of a line from one unit to another.
+// Columns beyond the end of the line are clamped to the column following the last character.
+// Columns which point into the middle of a character are rounded down to its start.
+func ConvertColumn(line string, column uint32, from, to ColumnUnit) uint32 {
+	if column == 0 {
+		return 0
+	}
+	if from == to {
+		return column
+	}
+
+	var bytes, units, points uint32
+
+	target := column - 1
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+
+		width := uint32(1)
+		if utf16.IsSurrogate(r) || r > 0xffff {
+			width = 2
+		}
+
+		var next uint32
+		switch from {
+		case ColumnUnit_Byte:
+			next = bytes + uint32(size)
+		case ColumnUnit_UTF16:
+			next = units + width
+		default:
+			next = points + 1
+		}
+
+		if next > target {
+			break
+		}
+
+		bytes += uint32(size)
+		units += width
+		points++
+		i += size
+	}
+
+	switch to {
+	case ColumnUnit_Byte:
+		return bytes + 1
+	case ColumnUnit_UTF16:
+		return units + 1
+	}
+
+	return points + 1
+}
@@ -0,0 +1,76 @@
+package clang
+
+import (
+	"testing"
+)
+
+func TestConvertColumn(t *testing.T) {
+	// "ä" is 2 bytes and 1 UTF-16 unit, "😀" is 4 bytes and 2 UTF-16 units
+	line := "a ä 😀 b"
+
+	table := []struct {
+		column   uint32
+		from, to ColumnUnit
+		want     uint32
+	}{
+		{1, ColumnUnit_Byte, ColumnUnit_UTF16, 1},
+		{5, ColumnUnit_Byte, ColumnUnit_UTF16, 4},
+		{6, ColumnUnit_Byte, ColumnUnit_UTF16, 5},
+		{10, ColumnUnit_Byte, ColumnUnit_UTF16, 7},
+		{11, ColumnUnit_Byte, ColumnUnit_UTF16, 8},
+		{11, ColumnUnit_Byte, ColumnUnit_Codepoint, 7},
+		{7, ColumnUnit_UTF16, ColumnUnit_Byte, 10},
+		{6, ColumnUnit_UTF16, ColumnUnit_Byte, 6},
+		{7, ColumnUnit_Codepoint, ColumnUnit_UTF16, 8},
+		{100, ColumnUnit_UTF16, ColumnUnit_Byte, uint32(len(line)) + 1},
+		{4, ColumnUnit_Byte, ColumnUnit_UTF16, 3},
+	}
+
+	for _, tt := range table {
+		if got := ConvertColumn(line, tt.column, tt.from, tt.to); got != tt.want {
+			t.Errorf("%d from %s to %s: expected %d. got=%d", tt.column, tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestSourceText(t *testing.T) {
+	st := NewSourceText("a.c", "int x;\r\nchar *s = \"😀\"; int y;\n")
+
+	if n := st.NumLines(); n != 3 {
+		t.Errorf("expected 3 lines. got=%d", n)
+	}
+	if l := st.Line(1); l != "int x;" {
+		t.Errorf("expected line without terminator. got=%q", l)
+	}
+
+	p := st.Position(26)
+	if p != (Position{File: "a.c", Line: 2, Column: 19, Offset: 26}) {
+		t.Errorf("unexpected position %+v", p)
+	}
+
+	line, character := st.UTF16Position(p)
+	if line != 1 || character != 16 {
+		t.Errorf("expected 1:16. got=%d:%d", line, character)
+	}
+
+	if q := st.PositionFromUTF16(line, character); q != p {
+		t.Errorf("expected %+v. got=%+v", p, q)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	s := Span{
+		Start: Position{File: "a.c", Line: 2, Column: 5},
+		End:   Position{File: "a.c", Line: 4, Column: 1},
+	}
+
+	if !s.Contains(Position{File: "a.c", Line: 3, Column: 80}) {
+		t.Error("expected position to be contained")
+	}
+	if s.Contains(Position{File: "a.c", Line: 4, Column: 1}) {
+		t.Error("expected end to be exclusive")
+	}
+	if got := s.String(); got != "a.c:2:5-4:1" {
+		t.Errorf("expected a.c:2:5-4:1. got=%s", got)
+	}
+}
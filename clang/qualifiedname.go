@@ -0,0 +1,27 @@
+package clang
+
+import "strings"
+
+// QualifiedName returns the spelling of the cursor qualified with the names of its enclosing
+// namespaces and records, e.g. "ns::Outer::Inner". Anonymous and unnamed namespaces and
+// records are named "(anonymous)".
+func (c Cursor) QualifiedName() string {
+	names := []string{c.Spelling()}
+	for p := c.SemanticParent(); !p.IsNull() && p.Kind() != Cursor_TranslationUnit; p = p.SemanticParent() {
+		switch p.Kind() {
+		case Cursor_Namespace, Cursor_StructDecl, Cursor_UnionDecl, Cursor_ClassDecl,
+			Cursor_ClassTemplate, Cursor_ClassTemplatePartialSpecialization:
+			name := p.Spelling()
+			if p.IsAnonymous() || p.IsUnnamed() {
+				name = "(anonymous)"
+			}
+			names = append(names, name)
+		}
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return strings.Join(names, "::")
+}
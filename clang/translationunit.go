@@ -66,3 +66,18 @@ func (tu TranslationUnit) Diagnostics() []Diagnostic {
 
 	return s
 }
+
+// FileContents retrieves the buffer associated with the given file.
+//
+// Returns the contents of the file and true, or false if the file is not loaded by the
+// translation unit.
+func (tu TranslationUnit) FileContents(file File) (string, bool) {
+	var size C.size_t
+
+	o := C.clang_getFileContents(tu.c, file.c, &size)
+	if o == nil {
+		return "", false
+	}
+
+	return C.GoStringN(o, C.int(size)), true
+}
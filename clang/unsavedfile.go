@@ -2,6 +2,7 @@ package clang
 
 // #include "go-clang.h"
 import "C"
+import "unsafe"
 
 // NewUnsavedFile returns the new UnsavedFile from filename and contents.
 func NewUnsavedFile(filename, contents string) UnsavedFile {
@@ -13,3 +14,13 @@ func NewUnsavedFile(filename, contents string) UnsavedFile {
 		},
 	}
 }
+
+// Dispose frees the memory of the filename and contents of an UnsavedFile created by
+// NewUnsavedFile.
+//
+// libclang copies unsaved files while parsing, so they can be disposed as soon as the call
+// they were passed to has returned.
+func (uf UnsavedFile) Dispose() {
+	C.free(unsafe.Pointer(uf.c.Filename))
+	C.free(unsafe.Pointer(uf.c.Contents))
+}
@@ -0,0 +1,96 @@
+// Command cgo-bindgen generates cgo bindings for a C header.
+//
+// Usage:
+//
+//	cgo-bindgen [-config file] [-pkg name] [-prefix p,p...] [-o file] header.h [-- clang args...]
+//
+// The structs, unions, enumerations, typedefs of numbers, integer macros and functions declared
+// by the header are bound. Structs are written as Go structs with the same layout, which is
+// checked at compile time against the C structs. Functions are wrapped by Go functions which
+// convert their parameters and results, passing Go strings as char * and Go functions as
+// callbacks. A callback is a typedef of a function pointer with a void * user data parameter,
+// and a function taking a callback has to take its user data as a later parameter.
+//
+// The configuration is a JSON encoded bindgen.Config, which selects the declarations and the
+// char * parameters converted to strings. The exported functions implementing the callbacks are
+// written to a second file next to the output, with the suffix _callbacks.go. Declarations which
+// can not be bound are reported on the standard error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/bindgen"
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func main() {
+	config := flag.String("config", "", "JSON configuration file")
+	pkg := flag.String("pkg", "", "name of the generated package, overrides the configuration")
+	prefix := flag.String("prefix", "", "comma separated prefixes removed from C names")
+	out := flag.String("o", "", "output file, defaults to the name of the header with the suffix .go")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: cgo-bindgen [flags] header.h [-- clang args...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if err := run(*config, *pkg, *prefix, *out, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "cgo-bindgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(config, pkg, prefix, out, header string, args []string) error {
+	cfg := &bindgen.Config{}
+	if config != "" {
+		var err error
+		if cfg, err = bindgen.LoadConfig(config); err != nil {
+			return err
+		}
+	}
+	if pkg != "" {
+		cfg.Package = pkg
+	}
+	if cfg.Package == "" {
+		cfg.Package = "main"
+	}
+	if prefix != "" {
+		cfg.Prefixes = append(cfg.Prefixes, strings.Split(prefix, ",")...)
+	}
+	if out == "" {
+		out = strings.TrimSuffix(filepath.Base(header), filepath.Ext(header)) + ".go"
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	m, err := bindgen.Bind(idx, header, args, cfg)
+	if err != nil {
+		return err
+	}
+	for _, s := range m.Skipped {
+		fmt.Fprintln(os.Stderr, "cgo-bindgen: skipped", s)
+	}
+
+	src, err := bindgen.Generate(m)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		return err
+	}
+
+	callbacks, err := bindgen.GenerateCallbacks(m)
+	if err != nil || callbacks == nil {
+		return err
+	}
+
+	return os.WriteFile(strings.TrimSuffix(out, ".go")+"_callbacks.go", callbacks, 0o644)
+}
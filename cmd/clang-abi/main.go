@@ -0,0 +1,131 @@
+// Command clang-abi compares the exported surface of two versions of a set of C headers and
+// reports whether the new version breaks binaries or source code built against the old one.
+//
+// Usage:
+//
+//	clang-abi [-json] [-compatible] old new [-- clang args...]
+//
+// old and new are headers or directories of headers. Every header of a directory is parsed on
+// its own, with the directory added to the include path, and only the declarations in files
+// below the directory are compared. The functions, records, enumerations and typedefs of both
+// versions are compared, and every difference is reported as ABI-breaking, API-breaking or
+// compatible. Compatible changes, like added functions, are only reported with -compatible.
+//
+// The exit status is 2 if a change breaks the ABI or the API, and 1 if an error occurs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-clang/clang-v15/abi"
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "write the changes as JSON")
+	compatible := flag.Bool("compatible", false, "also report compatible changes")
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "usage: clang-abi [-json] [-compatible] old new [-- clang args...]")
+		os.Exit(1)
+	}
+
+	breaking, err := run(flag.Arg(0), flag.Arg(1), flag.Args()[2:], *compatible, *asJSON)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clang-abi:", err)
+		os.Exit(1)
+	}
+	if breaking {
+		os.Exit(2)
+	}
+}
+
+// options are the options headers are parsed with.
+const options = uint32(clang.TranslationUnit_SkipFunctionBodies | clang.TranslationUnit_KeepGoing)
+
+// run compares the header sets and reports whether a change breaks the ABI or the API.
+func run(oldPath, newPath string, args []string, compatible, asJSON bool) (bool, error) {
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	before, err := surface(idx, oldPath, args)
+	if err != nil {
+		return false, err
+	}
+	after, err := surface(idx, newPath, args)
+	if err != nil {
+		return false, err
+	}
+
+	changes := abi.Compare(before, after)
+	breaking := abi.Worst(changes) >= abi.Impact_API
+	if !compatible {
+		n := 0
+		for _, c := range changes {
+			if c.Impact != abi.Impact_Compatible {
+				changes[n] = c
+				n++
+			}
+		}
+		changes = changes[:n]
+	}
+
+	if asJSON {
+		return breaking, abi.WriteJSON(os.Stdout, changes)
+	}
+
+	return breaking, abi.WriteText(os.Stdout, changes)
+}
+
+// surface returns the merged surface of the header or of the headers below the directory.
+func surface(idx clang.Index, path string, args []string) (*abi.Surface, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, headers := filepath.Dir(path), []string{path}
+	if info.IsDir() {
+		root, headers = path, nil
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			switch filepath.Ext(p) {
+			case ".h", ".hh", ".hpp", ".hxx":
+				if !info.IsDir() {
+					headers = append(headers, p)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	args = append([]string{"-I", root}, args...)
+	col := abi.Collector{Root: root}
+
+	s := abi.NewSurface()
+	for _, h := range headers {
+		var tu clang.TranslationUnit
+		if ec := idx.ParseTranslationUnit2(h, args, nil, options, &tu); ec != clang.Error_Success {
+			return nil, fmt.Errorf("parse %s: %s", h, ec)
+		}
+		s.Merge(col.Collect(tu))
+		tu.Dispose()
+	}
+
+	return s, nil
+}
@@ -0,0 +1,100 @@
+// Command clang-callgraph writes the call graph of a C or C++ project as DOT or JSON.
+//
+// Usage:
+//
+//	clang-callgraph [-p build-dir] [-format dot|json] [-o file] [-system] [-impact name,...]
+//
+// Every translation unit of the compilation database is parsed and the call graphs are merged.
+// With -impact, only the functions which may directly or indirectly call one of the named
+// functions are written. Names may be qualified, e.g. "ns::Cls::method", or USRs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-clang/clang-v15/callgraph"
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	format := flag.String("format", "dot", "output format: dot or json")
+	out := flag.String("o", "", "output file (default: standard output)")
+	system := flag.Bool("system", false, "include functions defined in system headers")
+	impact := flag.String("impact", "", "comma separated functions whose callers are written")
+	flag.Parse()
+
+	if err := run(*buildDir, *format, *out, *system, *impact); err != nil {
+		fmt.Fprintln(os.Stderr, "clang-callgraph:", err)
+		os.Exit(1)
+	}
+}
+
+func run(buildDir, format, out string, system bool, impact string) error {
+	if format != "dot" && format != "json" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	g := callgraph.NewGraph()
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, uint32(clang.TranslationUnit_KeepGoing))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-callgraph:", err)
+
+			continue
+		}
+
+		b := callgraph.Builder{SkipSystemHeaders: !system, Directory: cmd.Directory}
+		g.Merge(b.Build(tu))
+		tu.Dispose()
+	}
+
+	if impact != "" {
+		var usrs []string
+		for _, name := range strings.Split(impact, ",") {
+			found := g.Lookup(strings.TrimSpace(name))
+			if len(found) == 0 {
+				return fmt.Errorf("no function named %q", name)
+			}
+			usrs = append(usrs, found...)
+		}
+		g = g.Subgraph(g.Impact(usrs...))
+	}
+
+	if out == "" {
+		return write(os.Stdout, g, format)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	err = write(f, g, format)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+func write(w io.Writer, g *callgraph.Graph, format string) error {
+	if format == "json" {
+		return g.WriteJSON(w)
+	}
+
+	return g.WriteDOT(w)
+}
@@ -0,0 +1,139 @@
+// Command clang-ctags writes tags files for C and C++ projects using the clang parser, so
+// overloads, macros and namespaces are tagged correctly.
+//
+// Usage:
+//
+//	clang-ctags [-p build-dir] [-root dir] [-e] [-o file] [-system] [files...]
+//
+// Every translation unit of the compilation database, or only the given files, is parsed and
+// its declarations are written in the format of universal-ctags, or of etags with -e. Headers
+// included by several translation units are tagged once. File names are relative to the root.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	root := flag.String("root", "", "directory file names are made relative to (default: current directory)")
+	etags := flag.Bool("e", false, "write an etags TAGS file")
+	out := flag.String("o", "", "output file, - for stdout (default: tags or TAGS)")
+	system := flag.Bool("system", false, "tag declarations in system headers")
+	flag.Parse()
+
+	if err := run(*buildDir, *root, *out, *etags, *system, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "clang-ctags:", err)
+		os.Exit(1)
+	}
+}
+
+func run(buildDir, root, out string, etags, system bool, files []string) error {
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		root = wd
+	}
+	if out == "" {
+		out = "tags"
+		if etags {
+			out = "TAGS"
+		}
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		cmds = selectCommands(cmds, files)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	col := newCollector(system)
+	options := uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_SkipFunctionBodies |
+		clang.TranslationUnit_KeepGoing)
+
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-ctags:", err)
+
+			continue
+		}
+
+		col.add(tu)
+		tu.Dispose()
+	}
+
+	tags := col.tags
+	for i := range tags {
+		tags[i].file = relative(root, tags[i].file)
+	}
+	tags = sortTags(tags)
+
+	if out == "-" {
+		return write(os.Stdout, tags, etags)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	err = write(f, tags, etags)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+func write(w io.Writer, tags []tag, etags bool) error {
+	if etags {
+		return writeEtags(w, tags)
+	}
+
+	return writeCtags(w, tags)
+}
+
+func selectCommands(cmds []compdb.Command, files []string) []compdb.Command {
+	want := map[string]bool{}
+	for _, f := range files {
+		if abs, err := filepath.Abs(f); err == nil {
+			want[abs] = true
+		}
+	}
+
+	var selected []compdb.Command
+	for _, cmd := range cmds {
+		if want[cmd.Path()] {
+			selected = append(selected, cmd)
+		}
+	}
+
+	return selected
+}
+
+// relative returns the name of file relative to root, or the absolute name if the file is
+// outside of root.
+func relative(root, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
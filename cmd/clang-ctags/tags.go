@@ -0,0 +1,294 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// tag is a single entry of a tags file.
+type tag struct {
+	name string
+	file string
+	line uint32
+	// offset is the byte offset of the start of the line.
+	offset uint32
+	// text is the text of the line the tag is defined on.
+	text string
+	// nameEnd is the byte offset of the end of the name in text.
+	nameEnd int
+
+	kind      kind
+	scopeKind string
+	scope     string
+	signature string
+	access    string
+	typeref   string
+	fileScope bool
+}
+
+// kind is a kind of universal-ctags for C and C++.
+type kind struct {
+	letter byte
+	name   string
+}
+
+var (
+	kindClass      = kind{'c', "class"}
+	kindMacro      = kind{'d', "macro"}
+	kindEnumerator = kind{'e', "enumerator"}
+	kindFunction   = kind{'f', "function"}
+	kindEnum       = kind{'g', "enum"}
+	kindMember     = kind{'m', "member"}
+	kindNamespace  = kind{'n', "namespace"}
+	kindPrototype  = kind{'p', "prototype"}
+	kindStruct     = kind{'s', "struct"}
+	kindTypedef    = kind{'t', "typedef"}
+	kindUnion      = kind{'u', "union"}
+	kindVariable   = kind{'v', "variable"}
+	kindExternVar  = kind{'x', "externvar"}
+	kindAlias      = kind{'A', "alias"}
+)
+
+// kindOf returns the tag kind of the declaration c. ok is false for declarations which are not
+// tagged.
+func kindOf(c clang.Cursor) (kind, bool) {
+	switch c.Kind() {
+	case clang.Cursor_Namespace:
+		return kindNamespace, true
+	case clang.Cursor_NamespaceAlias:
+		return kindAlias, true
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization:
+		return kindClass, true
+	case clang.Cursor_StructDecl:
+		return kindStruct, true
+	case clang.Cursor_UnionDecl:
+		return kindUnion, true
+	case clang.Cursor_EnumDecl:
+		return kindEnum, true
+	case clang.Cursor_EnumConstantDecl:
+		return kindEnumerator, true
+	case clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate, clang.Cursor_CXXMethod, clang.Cursor_Constructor,
+		clang.Cursor_Destructor, clang.Cursor_ConversionFunction:
+		if c.IsCursorDefinition() {
+			return kindFunction, true
+		}
+
+		return kindPrototype, true
+	case clang.Cursor_FieldDecl:
+		return kindMember, true
+	case clang.Cursor_VarDecl:
+		if c.IsCursorDefinition() {
+			return kindVariable, true
+		}
+
+		return kindExternVar, true
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl, clang.Cursor_TypeAliasTemplateDecl:
+		return kindTypedef, true
+	case clang.Cursor_MacroDefinition:
+		return kindMacro, true
+	}
+
+	return kind{}, false
+}
+
+// collector collects the tags of translation units. Files reached by several translation
+// units are only tagged once.
+type collector struct {
+	system bool
+	// done holds the files tagged by earlier translation units.
+	done map[clang.FileUniqueID]bool
+	// current holds the files tagged by the current translation unit.
+	current map[clang.FileUniqueID]bool
+	skip    map[clang.FileUniqueID]bool
+	sources map[string]*clang.SourceText
+
+	tu   clang.TranslationUnit
+	tags []tag
+}
+
+func newCollector(system bool) *collector {
+	return &collector{
+		system: system,
+		done:   map[clang.FileUniqueID]bool{},
+	}
+}
+
+// add collects the tags of a translation unit.
+func (col *collector) add(tu clang.TranslationUnit) {
+	col.tu = tu
+	col.current = map[clang.FileUniqueID]bool{}
+	col.skip = map[clang.FileUniqueID]bool{}
+	col.sources = map[string]*clang.SourceText{}
+
+	col.visitChildren(tu.TranslationUnitCursor())
+
+	for id := range col.current {
+		col.done[id] = true
+	}
+}
+
+func (col *collector) visitChildren(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		col.visit(c)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// tagged reports whether declarations in the file of loc are tagged by this translation unit.
+func (col *collector) tagged(loc clang.SourceLocation) (clang.File, bool) {
+	if !col.system && loc.IsInSystemHeader() {
+		return clang.File{}, false
+	}
+
+	file, _, _, _ := loc.ExpansionLocation()
+	if file.Name() == "" {
+		return file, false
+	}
+
+	id, err := file.UniqueID()
+	if err != 0 {
+		return file, true
+	}
+	if col.skip[id] {
+		return file, false
+	}
+	if !col.current[id] {
+		if col.done[id] {
+			col.skip[id] = true
+
+			return file, false
+		}
+		col.current[id] = true
+	}
+
+	return file, true
+}
+
+func (col *collector) visit(c clang.Cursor) {
+	file, ok := col.tagged(c.Location())
+	if !ok {
+		return
+	}
+
+	if c.Kind() == clang.Cursor_LinkageSpec {
+		col.visitChildren(c)
+
+		return
+	}
+
+	k, ok := kindOf(c)
+	if !ok {
+		return
+	}
+
+	if name := c.Spelling(); name != "" {
+		col.tag(c, file, name, k)
+	}
+
+	switch k {
+	case kindNamespace, kindClass, kindStruct, kindUnion, kindEnum:
+		col.visitChildren(c)
+	}
+}
+
+func (col *collector) tag(c clang.Cursor, file clang.File, name string, k kind) {
+	p := c.Location().Position()
+
+	t := tag{
+		name: name,
+		file: p.File,
+		line: p.Line,
+		kind: k,
+	}
+
+	if src := col.source(file); src != nil {
+		t.text = src.Line(p.Line)
+		t.offset = p.Offset - (p.Column - 1)
+		t.nameEnd = int(p.Column-1) + len(name)
+		if t.nameEnd > len(t.text) {
+			t.nameEnd = len(t.text)
+		}
+	}
+
+	t.scopeKind, t.scope = scopeOf(c)
+
+	switch k {
+	case kindFunction, kindPrototype:
+		if i := strings.IndexByte(c.DisplayName(), '('); i >= 0 {
+			t.signature = c.DisplayName()[i:]
+		}
+		if c.Kind() != clang.Cursor_Constructor && c.Kind() != clang.Cursor_Destructor {
+			t.typeref = c.ResultType().Spelling()
+		}
+	case kindMember, kindVariable, kindExternVar:
+		t.typeref = c.Type().Spelling()
+	case kindTypedef:
+		t.typeref = c.TypedefDeclUnderlyingType().Spelling()
+	}
+
+	switch c.AccessSpecifier() {
+	case clang.AccessSpecifier_Public:
+		t.access = "public"
+	case clang.AccessSpecifier_Protected:
+		t.access = "protected"
+	case clang.AccessSpecifier_Private:
+		t.access = "private"
+	}
+
+	t.fileScope = c.Linkage() == clang.Linkage_Internal
+
+	col.tags = append(col.tags, t)
+}
+
+func (col *collector) source(file clang.File) *clang.SourceText {
+	name := file.Name()
+	if src, ok := col.sources[name]; ok {
+		return src
+	}
+
+	src, _ := col.tu.SourceText(file)
+	col.sources[name] = src
+
+	return src
+}
+
+// scopeOf returns the kind and the qualified name of the scope of c, e.g. "class" and
+// "ns::Foo" for a method of the class ns::Foo.
+func scopeOf(c clang.Cursor) (string, string) {
+	parent := c.SemanticParent()
+	if parent.IsNull() {
+		return "", ""
+	}
+
+	var scopeKind string
+	switch parent.Kind() {
+	case clang.Cursor_Namespace:
+		scopeKind = "namespace"
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization:
+		scopeKind = "class"
+	case clang.Cursor_StructDecl:
+		scopeKind = "struct"
+	case clang.Cursor_UnionDecl:
+		scopeKind = "union"
+	case clang.Cursor_EnumDecl:
+		scopeKind = "enum"
+	default:
+		return "", ""
+	}
+
+	var names []string
+	for p := parent; !p.IsNull() && p.Kind() != clang.Cursor_TranslationUnit; p = p.SemanticParent() {
+		name := p.Spelling()
+		if name == "" {
+			name = "__anon"
+		}
+		names = append(names, name)
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return scopeKind, strings.Join(names, "::")
+}
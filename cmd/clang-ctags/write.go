@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// sortTags sorts the tags by name, file and line as expected for sorted tags files, and removes
+// duplicates.
+func sortTags(tags []tag) []tag {
+	sort.SliceStable(tags, func(i, j int) bool {
+		a, b := tags[i], tags[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.file != b.file {
+			return a.file < b.file
+		}
+
+		return a.line < b.line
+	})
+
+	out := tags[:0]
+	for i, t := range tags {
+		if i > 0 {
+			p := out[len(out)-1]
+			if p.name == t.name && p.file == t.file && p.line == t.line && p.kind == t.kind {
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+
+	return out
+}
+
+var patternEscaper = strings.NewReplacer(`\`, `\\`, `/`, `\/`)
+
+// writeCtags writes a sorted tags file in the extended format of universal-ctags.
+func writeCtags(w io.Writer, tags []tag) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "!_TAG_FILE_FORMAT\t2\t/extended format; --format=1 will not append ;\" to lines/\n")
+	fmt.Fprintf(bw, "!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/\n")
+	fmt.Fprintf(bw, "!_TAG_PROGRAM_NAME\tclang-ctags\t//\n")
+
+	for _, t := range tags {
+		fmt.Fprintf(bw, "%s\t%s\t/^%s$/;\"\t%c", t.name, t.file, patternEscaper.Replace(t.text), t.kind.letter)
+		fmt.Fprintf(bw, "\tline:%d", t.line)
+		if t.scope != "" {
+			fmt.Fprintf(bw, "\t%s:%s", t.scopeKind, t.scope)
+		}
+		if t.typeref != "" {
+			fmt.Fprintf(bw, "\ttyperef:typename:%s", t.typeref)
+		}
+		if t.fileScope {
+			fmt.Fprintf(bw, "\tfile:")
+		}
+		if t.access != "" {
+			fmt.Fprintf(bw, "\taccess:%s", t.access)
+		}
+		if t.signature != "" {
+			fmt.Fprintf(bw, "\tsignature:%s", t.signature)
+		}
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+// writeEtags writes a TAGS file as used by Emacs. Tags are grouped by file in the order the
+// files first appear.
+func writeEtags(w io.Writer, tags []tag) error {
+	var files []string
+	byFile := map[string][]tag{}
+	for _, t := range tags {
+		if _, ok := byFile[t.file]; !ok {
+			files = append(files, t.file)
+		}
+		byFile[t.file] = append(byFile[t.file], t)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, file := range files {
+		ts := byFile[file]
+		sort.SliceStable(ts, func(i, j int) bool {
+			return ts[i].line < ts[j].line
+		})
+
+		var section strings.Builder
+		for _, t := range ts {
+			// The text is the start of the line up to the end of the name.
+			fmt.Fprintf(&section, "%s\x7f%s\x01%d,%d\n", t.text[:t.nameEnd], t.name, t.line, t.offset)
+		}
+
+		fmt.Fprintf(bw, "\f\n%s,%d\n%s", file, section.Len(), section.String())
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testTags() []tag {
+	return []tag{
+		{name: "push", file: "src/vec.h", line: 9, offset: 120, text: "  void push(int v);", nameEnd: 11, kind: kindPrototype,
+			scopeKind: "class", scope: "ns::Vec", signature: "(int)", access: "public", typeref: "void"},
+		{name: "Vec", file: "src/vec.h", line: 3, offset: 30, text: "class Vec {", nameEnd: 9, kind: kindClass,
+			scopeKind: "namespace", scope: "ns"},
+		{name: "push", file: "src/vec.h", line: 9, offset: 120, text: "  void push(int v);", nameEnd: 11, kind: kindPrototype,
+			scopeKind: "class", scope: "ns::Vec", signature: "(int)", access: "public", typeref: "void"},
+		{name: "MAX", file: "src/a/b.c", line: 1, offset: 0, text: "#define MAX 10 // a/b", nameEnd: 11, kind: kindMacro,
+			fileScope: true},
+	}
+}
+
+func TestWriteCtags(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCtags(&buf, sortTags(testTags())); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"!_TAG_FILE_FORMAT\t2\t/extended format; --format=1 will not append ;\" to lines/",
+		"!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/",
+		"!_TAG_PROGRAM_NAME\tclang-ctags\t//",
+		"MAX\tsrc/a/b.c\t/^#define MAX 10 \\/\\/ a\\/b$/;\"\td\tline:1\tfile:",
+		"Vec\tsrc/vec.h\t/^class Vec {$/;\"\tc\tline:3\tnamespace:ns",
+		"push\tsrc/vec.h\t/^  void push(int v);$/;\"\tp\tline:9\tclass:ns::Vec\ttyperef:typename:void\taccess:public\tsignature:(int)",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines. got=%q", len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: expected %q. got=%q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestWriteEtags(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeEtags(&buf, sortTags(testTags())); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\f\nsrc/a/b.c,20\n#define MAX\x7fMAX\x011,0\n" +
+		"\f\nsrc/vec.h,42\nclass Vec\x7fVec\x013,30\n  void push\x7fpush\x019,120\n"
+	if buf.String() != want {
+		t.Errorf("expected %q. got=%q", want, buf.String())
+	}
+}
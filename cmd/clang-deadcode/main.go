@@ -0,0 +1,92 @@
+// Command clang-deadcode reports the functions, global variables, macros and types of a C or
+// C++ project which are defined but never referenced.
+//
+// Usage:
+//
+//	clang-deadcode [-p build-dir] [-exported] [-json]
+//
+// Every translation unit of the compilation database is parsed. Symbols only visible in their
+// translation unit, like static functions, are reported separately from symbols with external
+// linkage unused in the whole project. Symbols of the exported API, i.e. declared in headers
+// without hidden visibility, are only reported with -exported.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/deadcode"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	exported := flag.Bool("exported", false, "report unused symbols of the exported API")
+	asJSON := flag.Bool("json", false, "write the findings as JSON")
+	flag.Parse()
+
+	if err := run(*buildDir, *exported, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, "clang-deadcode:", err)
+		os.Exit(1)
+	}
+}
+
+func run(buildDir string, exported, asJSON bool) error {
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	var units []*deadcode.Unit
+	options := uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_KeepGoing)
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-deadcode:", err)
+
+			continue
+		}
+
+		units = append(units, deadcode.Collector{Directory: cmd.Directory}.Collect(tu))
+		tu.Dispose()
+	}
+
+	findings := deadcode.Find(units, exported)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(findings)
+	}
+
+	headings := []struct {
+		category deadcode.Category
+		title    string
+	}{
+		{deadcode.Category_Local, "Unused within their translation unit"},
+		{deadcode.Category_Project, "Unused in the project"},
+		{deadcode.Category_Exported, "Unused exported API"},
+	}
+	for _, h := range headings {
+		first := true
+		for _, f := range findings {
+			if f.Category != h.category {
+				continue
+			}
+			if first {
+				fmt.Printf("%s:\n", h.title)
+				first = false
+			}
+			fmt.Printf("\t%s: %s %s\n", f.Definition, f.Kind.Spelling(), f.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+// Command clang-diagbaseline records the diagnostics of a project and compares runs against
+// a recorded baseline.
+//
+// Usage:
+//
+//	clang-diagbaseline record [-p build-dir] [-root dir] [-o baseline.json] [files...]
+//	clang-diagbaseline diff [-v] old.json new.json
+//
+// record parses every translation unit of the compilation database, or only the given files,
+// and writes the diagnostics as a baseline. diff compares two baselines and exits with status 1
+// if the new one contains diagnostics which are not part of the old one.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/diagbaseline"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	status := 0
+
+	switch os.Args[1] {
+	case "record":
+		err = record(os.Args[2:])
+	case "diff":
+		status, err = diff(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clang-diagbaseline:", err)
+		os.Exit(2)
+	}
+
+	os.Exit(status)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: clang-diagbaseline record [-p build-dir] [-root dir] [-o baseline.json] [files...]")
+	fmt.Fprintln(os.Stderr, "       clang-diagbaseline diff [-v] old.json new.json")
+	os.Exit(2)
+}
+
+func record(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	buildDir := fs.String("p", ".", "directory containing compile_commands.json")
+	root := fs.String("root", "", "directory file paths are made relative to (default: current directory)")
+	out := fs.String("o", "", "output file (default: stdout)")
+	fs.Parse(args)
+
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		*root = wd
+	}
+
+	cmds, err := compdb.Load(*buildDir)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		cmds = selectCommands(cmds, fs.Args())
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	rec := diagbaseline.Recorder{Root: *root}
+	seen := map[diagbaseline.Entry]bool{}
+
+	var entries []diagbaseline.Entry
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, clang.DefaultEditingTranslationUnitOptions())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-diagbaseline:", err)
+
+			continue
+		}
+
+		diags := tu.Diagnostics()
+		for _, e := range rec.Entries(tu, diags) {
+			// headers are part of several translation units, record their diagnostics only once
+			if !seen[e] {
+				seen[e] = true
+				entries = append(entries, e)
+			}
+		}
+
+		for _, d := range diags {
+			d.Dispose()
+		}
+		tu.Dispose()
+	}
+
+	b := diagbaseline.New(entries)
+	if *out == "" {
+		return b.Write(os.Stdout)
+	}
+
+	return b.Save(*out)
+}
+
+func selectCommands(cmds []compdb.Command, files []string) []compdb.Command {
+	want := map[string]bool{}
+	for _, f := range files {
+		if abs, err := filepath.Abs(f); err == nil {
+			want[abs] = true
+		}
+	}
+
+	var selected []compdb.Command
+	for _, cmd := range cmds {
+		if want[cmd.Path()] {
+			selected = append(selected, cmd)
+		}
+	}
+
+	return selected
+}
+
+func diff(args []string) (int, error) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "also list unchanged diagnostics")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		usage()
+	}
+
+	before, err := diagbaseline.Load(fs.Arg(0))
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := diagbaseline.Load(fs.Arg(1))
+	if err != nil {
+		return 0, err
+	}
+
+	d := diagbaseline.Compare(before.Entries, after.Entries)
+
+	for _, e := range d.New {
+		fmt.Println("new:      ", e)
+	}
+	for _, e := range d.Fixed {
+		fmt.Println("fixed:    ", e)
+	}
+	if *verbose {
+		for _, e := range d.Unchanged {
+			fmt.Println("unchanged:", e)
+		}
+	}
+
+	fmt.Printf("%d new, %d fixed, %d unchanged\n", len(d.New), len(d.Fixed), len(d.Unchanged))
+
+	if len(d.New) > 0 {
+		return 1, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,126 @@
+// Command clang-includes analyses the include graph of a C or C++ project.
+//
+// Usage:
+//
+//	clang-includes [-p build-dir] [-format text|dot|json] [-o file] [-system] [-top n] [-universal fraction]
+//
+// Every translation unit of the compilation database is parsed. The text report lists the
+// include cycles, the headers with the highest transitive cost and the headers included by at
+// least the given fraction of all translation units. The dot and json formats export the graph.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/includes"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	format := flag.String("format", "text", "output format: text, dot or json")
+	out := flag.String("o", "", "output file (default: standard output)")
+	system := flag.Bool("system", false, "include the directives in system headers")
+	top := flag.Int("top", 20, "number of the most expensive headers in the text report")
+	universal := flag.Float64("universal", 0.9, "fraction of translation units a universal header is included by")
+	flag.Parse()
+
+	if err := run(*buildDir, *format, *out, *system, *top, *universal); err != nil {
+		fmt.Fprintln(os.Stderr, "clang-includes:", err)
+		os.Exit(1)
+	}
+}
+
+func run(buildDir, format, out string, system bool, top int, universal float64) error {
+	if format != "text" && format != "dot" && format != "json" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	g := includes.NewGraph()
+	options := uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_KeepGoing |
+		clang.TranslationUnit_SkipFunctionBodies)
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-includes:", err)
+
+			continue
+		}
+
+		b := includes.Builder{SkipSystemHeaders: !system, Directory: cmd.Directory}
+		g.Merge(b.Build(tu))
+		tu.Dispose()
+	}
+
+	if out == "" {
+		return write(os.Stdout, g, format, system, top, universal)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	err = write(f, g, format, system, top, universal)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+func write(w io.Writer, g *includes.Graph, format string, system bool, top int, universal float64) error {
+	switch format {
+	case "dot":
+		return g.WriteDOT(w)
+	case "json":
+		return g.WriteJSON(w)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	cycles := g.Cycles()
+	fmt.Fprintf(tw, "Include cycles: %d\n", len(cycles))
+	for _, cycle := range cycles {
+		fmt.Fprintln(tw)
+		for _, f := range cycle {
+			fmt.Fprintf(tw, "\t%s\n", f)
+		}
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "Most expensive headers:")
+	fmt.Fprintln(tw, "\tUNITS\tFILES\tBYTES\tHEADER")
+	n := 0
+	for _, c := range g.Costs() {
+		if n == top {
+			break
+		}
+		if f := g.Files[c.File]; f != nil && f.System && !system {
+			continue
+		}
+		n++
+		fmt.Fprintf(tw, "\t%d\t%d\t%d\t%s\n", c.Units, c.Files, c.Bytes, c.File)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Headers included by at least %.0f%% of %d translation units:\n", universal*100, len(g.Units))
+	for _, u := range g.Universal(universal, system) {
+		fmt.Fprintf(tw, "\t%d\t%s\n", u.Units, u.File)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,152 @@
+// Command clang-iwyu reports the unused and the missing includes of C and C++ source files.
+//
+// Usage:
+//
+//	clang-iwyu [-p build-dir] [-keep patterns] [-exports file] [-fix] [files...]
+//
+// Every translation unit of the compilation database, or only the given files, is analysed.
+// Includes whose spelling matches one of the comma separated -keep patterns are never reported
+// unused. The exports file is a JSON object mapping headers to the headers they export. With
+// -fix the unused includes are removed and the missing includes are added to the files.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/iwyu"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	keep := flag.String("keep", "", "comma separated patterns of includes which are never unused")
+	exports := flag.String("exports", "", "JSON file mapping headers to the headers they export")
+	fix := flag.Bool("fix", false, "apply the edits to the files")
+	flag.Parse()
+
+	found, err := run(*buildDir, *keep, *exports, *fix, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clang-iwyu:", err)
+		os.Exit(1)
+	}
+	if found && !*fix {
+		os.Exit(2)
+	}
+}
+
+// run analyses the files and reports whether unused or missing includes were found.
+func run(buildDir, keep, exports string, fix bool, files []string) (bool, error) {
+	var a iwyu.Analyzer
+	if keep != "" {
+		a.Keep = strings.Split(keep, ",")
+	}
+	if exports != "" {
+		b, err := os.ReadFile(exports)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(b, &a.Exports); err != nil {
+			return false, fmt.Errorf("read %s: %w", exports, err)
+		}
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return false, err
+	}
+	if len(files) > 0 {
+		cmds = selectCommands(cmds, files)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	found := false
+	options := uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_KeepGoing)
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-iwyu:", err)
+
+			continue
+		}
+
+		a.Directory = cmd.Directory
+		r, err := a.Analyze(tu)
+		tu.Dispose()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-iwyu:", err)
+
+			continue
+		}
+
+		if len(r.Unused) > 0 || len(r.Missing) > 0 {
+			found = true
+		}
+		report(r)
+
+		if fix && len(r.Edits) > 0 {
+			if err := apply(r); err != nil {
+				return found, err
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func report(r *iwyu.Report) {
+	for _, inc := range r.Unused {
+		fmt.Printf("%s:%d: unused %s\n", r.File, inc.Line, inc.Directive())
+	}
+
+	for _, m := range r.Missing {
+		names := make([]string, len(m.Uses))
+		for i, u := range m.Uses {
+			names[i] = u.Name
+		}
+
+		line := uint32(0)
+		if len(m.Uses) > 0 {
+			line = m.Uses[0].Site.Start.Line
+		}
+		fmt.Printf("%s:%d: missing %s for %s", r.File, line, m.Include.Directive(), strings.Join(names, ", "))
+		if m.Via != "" {
+			fmt.Printf(" (included through %s)", m.Via)
+		}
+		fmt.Println()
+	}
+}
+
+func apply(r *iwyu.Report) error {
+	b, err := os.ReadFile(r.File)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.File, []byte(iwyu.Apply(string(b), r.Edits)), 0o644)
+}
+
+func selectCommands(cmds []compdb.Command, files []string) []compdb.Command {
+	want := map[string]bool{}
+	for _, f := range files {
+		if abs, err := filepath.Abs(f); err == nil {
+			want[abs] = true
+		}
+	}
+
+	var selected []compdb.Command
+	for _, cmd := range cmds {
+		if want[cmd.Path()] {
+			selected = append(selected, cmd)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,174 @@
+// Command clang-layout reports the memory layout of the structs, classes and unions of a C or
+// C++ project in the style of pahole, ordered by the number of bytes they waste.
+//
+// Usage:
+//
+//	clang-layout [-p build-dir] [-top n] [-record name] [-cacheline bytes] [-system] [-json]
+//	clang-layout [-p build-dir] -targets triple,triple... [-system] [-json]
+//
+// Every translation unit of the compilation database is parsed. For each record the offsets
+// and sizes of its fields are listed together with the holes between them, the trailing
+// padding, the fields crossing a cache line boundary and an order of the fields which makes
+// the record smaller. Only the n records wasting the most bytes are reported, or the records
+// with the given name.
+//
+// With -targets, every translation unit is parsed once for each target triple instead, and the
+// records and typedefs whose size, alignment or field offsets differ between the targets are
+// reported together with the reason, like the width of pointers or the size of long.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/layout"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	top := flag.Int("top", 20, "number of records to report, 0 for all")
+	record := flag.String("record", "", "report only the records with this name, e.g. \"struct foo\" or ns::Foo")
+	cacheLine := flag.Int64("cacheline", layout.DefaultCacheLine, "cache line size in bytes")
+	system := flag.Bool("system", false, "report records defined in system headers")
+	asJSON := flag.Bool("json", false, "write the reports as JSON")
+	targets := flag.String("targets", "", "comma separated target triples to compare the layouts of")
+	flag.Parse()
+
+	var err error
+	if *targets != "" {
+		err = portability(*buildDir, strings.Split(*targets, ","), *system, *asJSON)
+	} else {
+		err = run(*buildDir, *record, *top, *cacheLine, *system, *asJSON)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clang-layout:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the options translation units are parsed with.
+const options = uint32(clang.TranslationUnit_SkipFunctionBodies | clang.TranslationUnit_KeepGoing)
+
+func run(buildDir, record string, top int, cacheLine int64, system, asJSON bool) error {
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	seen := map[string]bool{}
+	var reports []layout.Report
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-layout:", err)
+
+			continue
+		}
+
+		col := layout.Collector{SkipSystemHeaders: !system, Directory: cmd.Directory}
+		for _, r := range col.Collect(tu) {
+			if seen[r.USR] || (record != "" && r.Name != record && r.Name != "struct "+record &&
+				r.Name != "union "+record) {
+				continue
+			}
+			seen[r.USR] = true
+			reports = append(reports, layout.Analyze(r, cacheLine))
+		}
+		tu.Dispose()
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		a, b := reports[i], reports[j]
+		if a.Wasted != b.Wasted {
+			return a.Wasted > b.Wasted
+		}
+		if a.Savings() != b.Savings() {
+			return a.Savings() > b.Savings()
+		}
+
+		return a.Name < b.Name
+	})
+	if record == "" {
+		kept := reports[:0]
+		for _, rep := range reports {
+			if rep.Wasted > 0 || len(rep.Crossings) > 0 {
+				kept = append(kept, rep)
+			}
+		}
+		reports = kept
+	}
+	if top > 0 && len(reports) > top {
+		reports = reports[:top]
+	}
+
+	if asJSON {
+		return layout.WriteJSON(os.Stdout, reports)
+	}
+
+	for i, rep := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := layout.WriteText(os.Stdout, rep); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func portability(buildDir string, triples []string, system, asJSON bool) error {
+	if len(triples) < 2 {
+		return fmt.Errorf("need at least two targets to compare, got %q", strings.Join(triples, ","))
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	type key struct{ typ, field string }
+	seen := map[key]bool{}
+	var divs []layout.Divergence
+	for _, cmd := range cmds {
+		col := layout.Collector{SkipSystemHeaders: !system, Directory: cmd.Directory}
+		snaps, err := layout.Targets(idx, cmd, triples, options, col)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-layout:", err)
+
+			continue
+		}
+
+		for _, d := range layout.Compare(snaps) {
+			if k := (key{d.Type, d.Field}); !seen[k] {
+				seen[k] = true
+				divs = append(divs, d)
+			}
+		}
+	}
+
+	sort.SliceStable(divs, func(i, j int) bool {
+		return divs[i].Type < divs[j].Type
+	})
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(divs)
+	}
+
+	return layout.WritePortability(os.Stdout, divs)
+}
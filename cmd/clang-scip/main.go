@@ -0,0 +1,100 @@
+// Command clang-scip indexes the cross references of a C or C++ project and writes them as SCIP
+// or LSIF index for code search and code review tools.
+//
+// Usage:
+//
+//	clang-scip [-p build-dir] [-root dir] [-format scip|lsif] [-o file] [-system]
+//
+// Every translation unit of the compilation database is parsed. Files below the root become
+// documents of the index, declarations in system headers are skipped unless -system is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/scip"
+	"github.com/go-clang/clang-v15/xref"
+)
+
+func main() {
+	buildDir := flag.String("p", ".", "directory containing compile_commands.json")
+	root := flag.String("root", "", "project root (default: current directory)")
+	format := flag.String("format", "scip", "output format: scip or lsif")
+	out := flag.String("o", "", "output file (default: index.scip or dump.lsif)")
+	system := flag.Bool("system", false, "index declarations in system headers")
+	flag.Parse()
+
+	if err := run(*buildDir, *root, *format, *out, *system); err != nil {
+		fmt.Fprintln(os.Stderr, "clang-scip:", err)
+		os.Exit(1)
+	}
+}
+
+func run(buildDir, root, format, out string, system bool) error {
+	if format != "scip" && format != "lsif" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	if out == "" {
+		out = "index.scip"
+		if format == "lsif" {
+			out = "dump.lsif"
+		}
+	}
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		root = wd
+	}
+
+	cmds, err := compdb.Load(buildDir)
+	if err != nil {
+		return err
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	ix := scip.NewIndexer(root)
+	ix.Tool = "clang-scip"
+	ix.Arguments = os.Args[1:]
+
+	ex := xref.Extractor{SkipSystemHeaders: !system}
+	options := uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_KeepGoing)
+
+	for _, cmd := range cmds {
+		tu, err := cmd.Parse(idx, nil, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "clang-scip:", err)
+
+			continue
+		}
+
+		ix.Add(ex.Extract(tu))
+		tu.Dispose()
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	if format == "lsif" {
+		err = ix.WriteLSIF(f)
+		for _, serr := range ix.Skipped {
+			fmt.Fprintln(os.Stderr, "clang-scip: skip document:", serr)
+		}
+	} else {
+		err = ix.WriteSCIP(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
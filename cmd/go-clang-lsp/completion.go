@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/completion"
+)
+
+// maxCompletionItems is the maximum number of items sent to the client. If more results match,
+// the list is marked as incomplete, so the client asks again while the prefix grows.
+const maxCompletionItems = 200
+
+// acceptedCommand is run by the client when a completion item is accepted. The accepted
+// completions are preferred the next time.
+const acceptedCommand = "go-clang-lsp.completionAccepted"
+
+// codeCompleteOptions returns the options code completion is run with.
+func codeCompleteOptions() uint32 {
+	return clang.DefaultCodeCompleteOptions() |
+		uint32(clang.CodeComplete_IncludeMacros) |
+		uint32(clang.CodeComplete_IncludeBriefComments)
+}
+
+func (s *server) completion(params json.RawMessage) (interface{}, error) {
+	var p textDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.parsed(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completion is run at the start of the identifier under the cursor, the part already typed
+	// is used as filter.
+	pos := d.src.PositionFromUTF16(p.Position.Line, p.Position.Character)
+	line := d.src.Line(pos.Line)
+	start := pos.Column - 1
+	for start > 0 && isIdentByte(line[start-1]) {
+		start--
+	}
+	prefix := line[start : pos.Column-1]
+	startPos := d.src.PositionAt(pos.Line, start+1, clang.ColumnUnit_Byte)
+
+	unsaved := s.unsavedFiles()
+	defer disposeUnsavedFiles(unsaved)
+
+	results := d.tu.CodeCompleteAt(d.path, startPos.Line, startPos.Column, unsaved, codeCompleteOptions())
+	if results == nil {
+		return nil, errorf(codeRequestFailed, "code completion failed at %s", pos)
+	}
+	defer results.Dispose()
+
+	replace := lspRange{Start: toLSPPosition(d.src, startPos), End: toLSPPosition(d.src, pos)}
+	all := results.Results()
+	ranker := completion.Ranker{
+		Contexts: results.Contexts(),
+		History:  s.history,
+		Limit:    maxCompletionItems,
+	}
+	matches := ranker.Rank(prefix, completion.Candidates(all))
+
+	list := completionList{
+		IsIncomplete: len(matches) == maxCompletionItems,
+		Items:        make([]completionItem, 0, len(matches)),
+	}
+
+	for i, m := range matches {
+		it := completion.Render(all[m.Index])
+
+		item := completionItem{
+			Label:         it.Label,
+			Kind:          completionKind(it.Kind),
+			Detail:        it.Detail,
+			Documentation: it.Documentation,
+			Deprecated:    it.Availability == clang.Availability_Deprecated,
+			SortText:      fmt.Sprintf("%05d", i),
+			FilterText:    it.FilterText,
+			TextEdit: &textEdit{
+				Range:   replace,
+				NewText: it.InsertText,
+			},
+			Command: &command{
+				Title:     "accepted",
+				Command:   acceptedCommand,
+				Arguments: []interface{}{m.FilterText, int(m.Kind)},
+			},
+		}
+		if s.snippets {
+			item.InsertTextFormat = insertTextFormatSnippet
+			item.TextEdit.NewText = it.Snippet
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	return list, nil
+}
+
+func (s *server) executeCommand(params json.RawMessage) (interface{}, error) {
+	var p executeCommandParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Command != acceptedCommand {
+		return nil, errorf(codeInvalidParams, "unknown command %q", p.Command)
+	}
+
+	var text string
+	var kind int
+	if len(p.Arguments) != 2 || json.Unmarshal(p.Arguments[0], &text) != nil || json.Unmarshal(p.Arguments[1], &kind) != nil {
+		return nil, errorf(codeInvalidParams, "invalid arguments of %s", p.Command)
+	}
+	s.history.Use(completion.Candidate{FilterText: text, Kind: clang.CursorKind(kind)})
+
+	return nil, nil
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b >= 0x80
+}
+
+func completionKind(kind clang.CursorKind) int {
+	switch kind {
+	case clang.Cursor_CXXMethod, clang.Cursor_ConversionFunction, clang.Cursor_ObjCInstanceMethodDecl, clang.Cursor_ObjCClassMethodDecl:
+		return completionMethod
+	case clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate:
+		return completionFunction
+	case clang.Cursor_Constructor, clang.Cursor_Destructor:
+		return completionConstructor
+	case clang.Cursor_FieldDecl:
+		return completionField
+	case clang.Cursor_VarDecl, clang.Cursor_ParmDecl:
+		return completionVariable
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate, clang.Cursor_ObjCInterfaceDecl:
+		return completionClass
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl:
+		return completionStruct
+	case clang.Cursor_ObjCProtocolDecl:
+		return completionInterface
+	case clang.Cursor_ObjCPropertyDecl:
+		return completionProperty
+	case clang.Cursor_EnumDecl:
+		return completionEnum
+	case clang.Cursor_EnumConstantDecl:
+		return completionEnumMember
+	case clang.Cursor_Namespace:
+		return completionModule
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl, clang.Cursor_TemplateTypeParameter:
+		return completionTypeParameter
+	case clang.Cursor_MacroDefinition:
+		return completionConstant
+	case clang.Cursor_NotImplemented:
+		return completionKeyword
+	}
+
+	return completionText
+}
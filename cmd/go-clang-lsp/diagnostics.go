@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// diagnostics returns the diagnostics of the document's translation unit which are located in
+// the document itself. Notes are attached as related information.
+func (s *server) diagnostics(d *document) []diagnostic {
+	diags := []diagnostic{}
+
+	for _, cd := range d.tu.Diagnostics() {
+		if diag, ok := s.diagnostic(d, cd); ok {
+			diags = append(diags, diag)
+		}
+		cd.Dispose()
+	}
+
+	return diags
+}
+
+func (s *server) diagnostic(d *document, cd clang.Diagnostic) (diagnostic, bool) {
+	severity := lspSeverity(cd.Severity())
+	if severity == 0 {
+		return diagnostic{}, false
+	}
+
+	loc := cd.Location()
+	p := loc.Position()
+	if !p.IsValid() || p.File != d.path {
+		return diagnostic{}, false
+	}
+
+	r := lspRange{Start: toLSPPosition(d.src, p), End: toLSPPosition(d.src, p)}
+	for i := uint32(0); i < cd.NumRanges(); i++ {
+		span := cd.Range(i).Span()
+		if span.IsValid() && span.Start.File == d.path && span.Contains(p) {
+			r = toLSPRange(d.src, span)
+
+			break
+		}
+	}
+
+	_, enable := cd.Option()
+	diag := diagnostic{
+		Range:    r,
+		Severity: severity,
+		Code:     enable,
+		Source:   "clang",
+		Message:  cd.Spelling(),
+	}
+
+	children := cd.ChildDiagnostics()
+	for i := uint32(0); i < children.NumDiagnosticsInSet(); i++ {
+		note := children.DiagnosticInSet(i)
+		nloc := note.Location()
+		file, _, _, _ := nloc.ExpansionLocation()
+		if st, ok := s.sourceText(d.tu, file); ok {
+			np := nloc.Position()
+			diag.RelatedInformation = append(diag.RelatedInformation, diagnosticRelatedInformation{
+				Location: location{
+					URI:   pathToURI(np.File),
+					Range: lspRange{Start: toLSPPosition(st, np), End: toLSPPosition(st, np)},
+				},
+				Message: note.Spelling(),
+			})
+		}
+		note.Dispose()
+	}
+
+	return diag, true
+}
+
+func lspSeverity(severity clang.DiagnosticSeverity) int {
+	switch severity {
+	case clang.Diagnostic_Fatal, clang.Diagnostic_Error:
+		return severityError
+	case clang.Diagnostic_Warning:
+		return severityWarning
+	case clang.Diagnostic_Note:
+		return severityInformation
+	}
+
+	return 0
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/symbols"
+)
+
+// parseOptions returns the options every translation unit of an open document is parsed with.
+func parseOptions() uint32 {
+	return clang.DefaultEditingTranslationUnitOptions() |
+		uint32(clang.TranslationUnit_KeepGoing) |
+		uint32(clang.TranslationUnit_DetailedPreprocessingRecord) |
+		uint32(clang.TranslationUnit_IncludeBriefCommentsInCodeCompletion)
+}
+
+// document is a file opened by the client together with its translation unit.
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    string
+	src     *clang.SourceText
+
+	tu    clang.TranslationUnit
+	hasTU bool
+
+	// index caches the symbols of the translation unit for workspace symbol searches.
+	index    []symbols.Symbol
+	hasIndex bool
+}
+
+func (d *document) setText(text string) {
+	d.text = text
+	d.src = clang.NewSourceText(d.path, text)
+}
+
+func (d *document) dispose() {
+	if d.hasTU {
+		d.tu.Dispose()
+		d.tu = clang.TranslationUnit{}
+		d.hasTU = false
+	}
+}
+
+// update parses the document for the first time or reparses its translation unit. If
+// reparsing fails, the translation unit is parsed from scratch.
+func (s *server) update(d *document) error {
+	unsaved := s.unsavedFiles()
+	defer disposeUnsavedFiles(unsaved)
+
+	d.index, d.hasIndex = nil, false
+	if s.resolver != nil {
+		s.resolver.Invalidate(d.path)
+	}
+
+	if d.hasTU {
+		if d.tu.ReparseTranslationUnit(unsaved, d.tu.DefaultReparseOptions()) == 0 {
+			return nil
+		}
+
+		d.dispose()
+	}
+
+	tu, err := s.command(d.path).Parse(s.idx, unsaved, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	d.tu = tu
+	d.hasTU = true
+
+	return nil
+}
+
+func (s *server) didOpen(params json.RawMessage) error {
+	var p didOpenTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return err
+	}
+
+	if old, ok := s.docs[p.TextDocument.URI]; ok {
+		old.dispose()
+	}
+
+	d := &document{
+		uri:     p.TextDocument.URI,
+		path:    uriToPath(p.TextDocument.URI),
+		version: p.TextDocument.Version,
+	}
+	d.setText(p.TextDocument.Text)
+	s.docs[d.uri] = d
+
+	return s.refresh(d)
+}
+
+func (s *server) didChange(params json.RawMessage) error {
+	var p didChangeTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	// Only full document synchronization is announced, so the last change holds the whole text.
+	if n := len(p.ContentChanges); n > 0 {
+		d.setText(p.ContentChanges[n-1].Text)
+	}
+	d.version = p.TextDocument.Version
+
+	return s.refresh(d)
+}
+
+func (s *server) didClose(params json.RawMessage) error {
+	var p didCloseTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	d.dispose()
+	delete(s.docs, d.uri)
+
+	return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         d.uri,
+		Diagnostics: []diagnostic{},
+	})
+}
+
+// refresh reparses the document and publishes its diagnostics.
+func (s *server) refresh(d *document) error {
+	if err := s.update(d); err != nil {
+		return err
+	}
+
+	return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         d.uri,
+		Version:     d.version,
+		Diagnostics: s.diagnostics(d),
+	})
+}
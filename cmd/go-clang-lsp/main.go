@@ -0,0 +1,46 @@
+// Command go-clang-lsp is a small C/C++ language server built on the clang bindings.
+//
+// It speaks the Language Server Protocol over stdin and stdout and supports diagnostics,
+// completion, go to definition, hover and document symbols. Compile flags are read from the
+// compile_commands.json in the directory given by -p, the workspace root or the
+// "compilationDatabasePath" initialization option.
+//
+// Usage:
+//
+//	go-clang-lsp [-p build-dir] [-log file] [-- fallback flags...]
+//
+// The fallback flags are used for files which are not part of the compilation database.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+)
+
+func main() {
+	buildDir := flag.String("p", "", "directory containing compile_commands.json")
+	logFile := flag.String("log", "", "write the log to this file instead of stderr")
+	flag.Parse()
+
+	var w io.Writer = os.Stderr
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	s := newServer(newConn(os.Stdin, os.Stdout), log.New(w, "go-clang-lsp: ", log.LstdFlags))
+	s.buildDir = *buildDir
+	s.fallbackFlags = flag.Args()
+
+	if err := s.run(); err != nil {
+		s.log.Print(err)
+		os.Exit(1)
+	}
+}
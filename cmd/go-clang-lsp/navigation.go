@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// cursorAt returns the cursor at the position of the request.
+func (s *server) cursorAt(p textDocumentPositionParams) (*document, clang.Cursor, error) {
+	d, err := s.parsed(p.TextDocument.URI)
+	if err != nil {
+		return nil, clang.Cursor{}, err
+	}
+
+	pos := d.src.PositionFromUTF16(p.Position.Line, p.Position.Character)
+
+	return d, d.tu.Cursor(d.tu.LocationForPosition(pos)), nil
+}
+
+func (s *server) definition(params json.RawMessage) (interface{}, error) {
+	var p textDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, c, err := s.cursorAt(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Kind() == clang.Cursor_InclusionDirective {
+		if f := c.IncludedFile(); f.Name() != "" {
+			return []location{{URI: pathToURI(f.Name())}}, nil
+		}
+	}
+
+	target := c.Referenced()
+	if target.IsNull() {
+		return []location{}, nil
+	}
+	if def := target.Definition(); !def.IsNull() {
+		target = def
+	} else if locs := s.otherDefinitions(c); len(locs) > 0 {
+		return locs, nil
+	}
+
+	loc, ok := s.lspLocation(d.tu, target.Location().Range(target.Location()))
+	if !ok {
+		return []location{}, nil
+	}
+
+	return []location{loc}, nil
+}
+
+// otherDefinitions returns the location of the definition of the symbol referenced by c in the
+// other translation units of the compilation database. The translation units are searched in
+// the order ranked by the resolver, and the search stops at the first one defining the symbol,
+// so that a request parses as few translation units as possible.
+func (s *server) otherDefinitions(c clang.Cursor) []location {
+	r := s.definitions()
+	if r == nil {
+		return nil
+	}
+
+	cand, ok, err := r.Definition(c)
+	if err != nil {
+		s.log.Print(err)
+	}
+	if !ok {
+		return nil
+	}
+
+	st := s.fileText(cand.Position.File)
+	if st == nil {
+		return nil
+	}
+
+	return []location{{
+		URI:   pathToURI(cand.Position.File),
+		Range: toLSPRange(st, clang.Span{Start: cand.Position, End: cand.Position}),
+	}}
+}
+
+func (s *server) hover(params json.RawMessage) (interface{}, error) {
+	var p textDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, c, err := s.cursorAt(p)
+	if err != nil {
+		return nil, err
+	}
+
+	target := c.Referenced()
+	if target.IsNull() {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "```%s\n%s\n```", s.languageID(d.path), signature(target))
+	if doc := target.BriefCommentText(); doc != "" {
+		fmt.Fprintf(&b, "\n\n%s", doc)
+	}
+
+	h := hover{
+		Contents: markupContent{
+			Kind:  "markdown",
+			Value: b.String(),
+		},
+	}
+	if span := c.Extent().Span(); span.IsValid() && span.Start.File == d.path {
+		r := toLSPRange(d.src, span)
+		h.Range = &r
+	}
+
+	return h, nil
+}
+
+// signature returns a short declaration of the cursor for display.
+func signature(c clang.Cursor) string {
+	switch c.Kind() {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_FunctionTemplate, clang.Cursor_ConversionFunction:
+		return c.ResultType().Spelling() + " " + qualifiedDisplayName(c)
+	case clang.Cursor_Constructor, clang.Cursor_Destructor:
+		return qualifiedDisplayName(c)
+	case clang.Cursor_VarDecl, clang.Cursor_ParmDecl, clang.Cursor_FieldDecl:
+		return c.Type().Spelling() + " " + c.Spelling()
+	case clang.Cursor_EnumConstantDecl:
+		return fmt.Sprintf("%s = %d", c.Spelling(), c.EnumConstantDeclValue())
+	case clang.Cursor_StructDecl:
+		return "struct " + c.Type().Spelling()
+	case clang.Cursor_UnionDecl:
+		return "union " + c.Type().Spelling()
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate:
+		return "class " + qualifiedDisplayName(c)
+	case clang.Cursor_EnumDecl:
+		return "enum " + c.Type().Spelling()
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		return "typedef " + c.TypedefDeclUnderlyingType().Spelling() + " " + c.Spelling()
+	case clang.Cursor_Namespace:
+		return "namespace " + qualifiedDisplayName(c)
+	case clang.Cursor_MacroDefinition:
+		return "#define " + c.Spelling()
+	}
+
+	return c.DisplayName()
+}
+
+// qualifiedDisplayName prefixes the display name of c with the names of its enclosing
+// namespaces and classes.
+func qualifiedDisplayName(c clang.Cursor) string {
+	name := c.DisplayName()
+	for p := c.SemanticParent(); !p.IsNull(); p = p.SemanticParent() {
+		switch p.Kind() {
+		case clang.Cursor_Namespace, clang.Cursor_ClassDecl, clang.Cursor_StructDecl, clang.Cursor_ClassTemplate:
+			name = p.Spelling() + "::" + name
+		case clang.Cursor_TranslationUnit:
+			return name
+		}
+	}
+
+	return name
+}
+
+// languageID returns the LSP language identifier of the file at path.
+func (s *server) languageID(path string) string {
+	if strings.HasSuffix(path, ".c") || s.headerLanguage(path) == "c-header" {
+		return "c"
+	}
+
+	return "cpp"
+}
+
+func (s *server) documentSymbol(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		TextDocument textDocumentIdentifier `json:"textDocument"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.parsed(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.symbols(d.tu.TranslationUnitCursor()), nil
+}
+
+// symbols returns the symbols declared in the document below the cursor parent.
+func (d *document) symbols(parent clang.Cursor) []documentSymbol {
+	syms := []documentSymbol{}
+
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if !c.Location().IsFromMainFile() {
+			return clang.ChildVisit_Continue
+		}
+
+		if c.Kind() == clang.Cursor_LinkageSpec {
+			syms = append(syms, d.symbols(c)...)
+
+			return clang.ChildVisit_Continue
+		}
+
+		kind := symbolKind(c.Kind())
+		if kind == 0 || c.Spelling() == "" {
+			return clang.ChildVisit_Continue
+		}
+
+		sym := documentSymbol{
+			Name:           c.DisplayName(),
+			Kind:           kind,
+			Range:          toLSPRange(d.src, c.Extent().Span()),
+			SelectionRange: toLSPRange(d.src, c.Location().Range(c.Location()).Span()),
+		}
+		switch c.Kind() {
+		case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_FunctionTemplate:
+			sym.Detail = c.ResultType().Spelling()
+		case clang.Cursor_VarDecl, clang.Cursor_FieldDecl:
+			sym.Detail = c.Type().Spelling()
+		case clang.Cursor_Namespace, clang.Cursor_ClassDecl, clang.Cursor_StructDecl, clang.Cursor_UnionDecl,
+			clang.Cursor_EnumDecl, clang.Cursor_ClassTemplate:
+			sym.Children = d.symbols(c)
+		}
+		syms = append(syms, sym)
+
+		return clang.ChildVisit_Continue
+	})
+
+	return syms
+}
+
+func symbolKind(kind clang.CursorKind) int {
+	switch kind {
+	case clang.Cursor_Namespace:
+		return symbolNamespace
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate, clang.Cursor_ObjCInterfaceDecl:
+		return symbolClass
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl:
+		return symbolStruct
+	case clang.Cursor_EnumDecl:
+		return symbolEnum
+	case clang.Cursor_EnumConstantDecl:
+		return symbolEnumMember
+	case clang.Cursor_CXXMethod, clang.Cursor_ConversionFunction, clang.Cursor_ObjCInstanceMethodDecl, clang.Cursor_ObjCClassMethodDecl:
+		return symbolMethod
+	case clang.Cursor_Constructor, clang.Cursor_Destructor:
+		return symbolConstructor
+	case clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate:
+		return symbolFunction
+	case clang.Cursor_FieldDecl:
+		return symbolField
+	case clang.Cursor_VarDecl:
+		return symbolVariable
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		return symbolTypeParameter
+	case clang.Cursor_MacroDefinition:
+		return symbolConstant
+	case clang.Cursor_ObjCProtocolDecl:
+		return symbolInterface
+	case clang.Cursor_ObjCPropertyDecl:
+		return symbolProperty
+	}
+
+	return 0
+}
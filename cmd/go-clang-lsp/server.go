@@ -0,0 +1,395 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/completion"
+	"github.com/go-clang/clang-v15/definition"
+)
+
+// errExit is returned by run after the exit notification.
+var errExit = errors.New("exit")
+
+// server is the state of the language server. Requests are handled one after another, so no
+// locking is needed for the libclang objects.
+type server struct {
+	conn *conn
+	log  *log.Logger
+
+	buildDir      string
+	fallbackFlags []string
+	snippets      bool
+	history       *completion.History
+
+	idx         clang.Index
+	db          clang.CompilationDatabase
+	hasDB       bool
+	docs        map[string]*document
+	commands    *compdb.Resolver
+	resolver    *definition.Resolver
+	initialized bool
+	shutdown    bool
+}
+
+func newServer(c *conn, l *log.Logger) *server {
+	return &server{
+		conn:    c,
+		log:     l,
+		docs:    map[string]*document{},
+		history: completion.NewHistory(1000),
+	}
+}
+
+type handler func(s *server, params json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"initialize":                  (*server).initialize,
+	"shutdown":                    (*server).handleShutdown,
+	"textDocument/completion":     (*server).completion,
+	"textDocument/definition":     (*server).definition,
+	"textDocument/hover":          (*server).hover,
+	"textDocument/documentSymbol": (*server).documentSymbol,
+	"workspace/symbol":            (*server).workspaceSymbol,
+	"workspace/executeCommand":    (*server).executeCommand,
+}
+
+type notificationHandler func(s *server, params json.RawMessage) error
+
+var notificationHandlers = map[string]notificationHandler{
+	"initialized":            func(*server, json.RawMessage) error { return nil },
+	"textDocument/didOpen":   (*server).didOpen,
+	"textDocument/didChange": (*server).didChange,
+	"textDocument/didClose":  (*server).didClose,
+}
+
+// run handles requests until the exit notification is received or the connection is closed.
+func (s *server) run() error {
+	defer s.dispose()
+
+	for {
+		req, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var rerr *rpcError
+			if errors.As(err, &rerr) {
+				s.log.Print(err)
+
+				continue
+			}
+
+			return err
+		}
+
+		if req.ID == nil {
+			if req.Method == "exit" {
+				if !s.shutdown {
+					return errors.New("exit without shutdown")
+				}
+
+				return nil
+			}
+
+			if h, ok := notificationHandlers[req.Method]; ok && s.initialized {
+				if err := h(s, req.Params); err != nil {
+					s.log.Printf("%s: %v", req.Method, err)
+				}
+			}
+
+			continue
+		}
+
+		result, err := s.handle(req)
+		if err := s.conn.reply(req.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) handle(req *request) (interface{}, error) {
+	h, ok := handlers[req.Method]
+	if !ok {
+		return nil, errorf(codeMethodNotFound, "method %q not found", req.Method)
+	}
+	if !s.initialized && req.Method != "initialize" {
+		return nil, errorf(codeServerNotInitialized, "server not initialized")
+	}
+	if s.shutdown {
+		return nil, errorf(codeInvalidRequest, "server is shutting down")
+	}
+
+	return h(s, req.Params)
+}
+
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+
+	return nil
+}
+
+func (s *server) initialize(params json.RawMessage) (interface{}, error) {
+	var p initializeParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if opts := p.InitializationOptions; opts != nil {
+		if opts.CompilationDatabasePath != "" {
+			s.buildDir = opts.CompilationDatabasePath
+		}
+		if len(opts.FallbackFlags) > 0 {
+			s.fallbackFlags = opts.FallbackFlags
+		}
+	}
+	if s.buildDir == "" && p.RootURI != "" {
+		s.buildDir = uriToPath(p.RootURI)
+	}
+
+	s.snippets = p.Capabilities.TextDocument.Completion.CompletionItem.SnippetSupport
+	s.idx = clang.NewIndex(0, 0)
+
+	if s.buildDir != "" {
+		if err, db := clang.FromDirectory(s.buildDir); err == clang.CompilationDatabase_NoError {
+			s.db = db
+			s.hasDB = true
+		} else {
+			s.log.Printf("no compilation database in %s: %v", s.buildDir, err)
+		}
+	}
+
+	s.initialized = true
+
+	return initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    1,
+			},
+			CompletionProvider: completionOptions{
+				TriggerCharacters: []string{".", ">", ":"},
+			},
+			DefinitionProvider:      true,
+			HoverProvider:           true,
+			DocumentSymbolProvider:  true,
+			WorkspaceSymbolProvider: true,
+			ExecuteCommandProvider: executeCommandOptions{
+				Commands: []string{acceptedCommand},
+			},
+		},
+		ServerInfo: serverInfo{
+			Name: "go-clang-lsp",
+		},
+	}, nil
+}
+
+func (s *server) handleShutdown(json.RawMessage) (interface{}, error) {
+	s.shutdown = true
+
+	return nil, nil
+}
+
+// dispose frees all libclang objects.
+func (s *server) dispose() {
+	for _, d := range s.docs {
+		d.dispose()
+	}
+	s.docs = map[string]*document{}
+
+	if s.hasDB {
+		s.db.Dispose()
+		s.hasDB = false
+	}
+	if s.initialized {
+		s.idx.Dispose()
+		s.initialized = false
+	}
+}
+
+// command returns the compile command for the file at path. The command of files which are not
+// part of the compilation database is inferred from the most similar file. Without a
+// compilation database files are compiled with the fallback flags.
+func (s *server) command(path string) compdb.Command {
+	if s.hasDB {
+		if cmds := compdb.Commands(s.db.CompileCommands(path)); len(cmds) > 0 {
+			return cmds[0]
+		}
+		if cmd, ok := s.allCommands().Infer(path); ok {
+			return cmd
+		}
+	}
+
+	args := []string{"clang"}
+	if lang := s.headerLanguage(path); lang != "" {
+		args = append(args, "-x"+lang)
+	}
+	args = append(args, s.fallbackFlags...)
+
+	return compdb.Command{
+		Directory: filepath.Dir(path),
+		Filename:  path,
+		Args:      append(args, path),
+	}
+}
+
+// headerLanguage returns the language the header at path is parsed with, or "" if path is not
+// a header. Headers with a C++ extension are C++ headers. The language of .h headers is the one
+// of the source with the same base name in the same directory, e.g. foo.c for foo.h, and C++ if
+// there is no such source.
+func (s *server) headerLanguage(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".hh", ".hpp", ".hxx":
+		return "c++-header"
+	case ".h":
+		// the pairing below decides
+	default:
+		return ""
+	}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, src := range []struct{ ext, lang string }{
+		{".c", "c-header"},
+		{".cc", "c++-header"},
+		{".cpp", "c++-header"},
+		{".cxx", "c++-header"},
+	} {
+		if _, ok := s.docs[pathToURI(base+src.ext)]; ok {
+			return src.lang
+		}
+		if _, err := os.Stat(base + src.ext); err == nil {
+			return src.lang
+		}
+	}
+
+	return "c++-header"
+}
+
+// allCommands returns the resolver for the commands of the compilation database, which is
+// loaded on first use.
+func (s *server) allCommands() *compdb.Resolver {
+	if s.commands == nil {
+		s.commands = compdb.NewResolver(compdb.Commands(s.db.AllCompileCommands()))
+	}
+
+	return s.commands
+}
+
+// definitions returns the resolver for definitions in other translation units of the
+// compilation database, or nil if there is no compilation database.
+func (s *server) definitions() *definition.Resolver {
+	if s.resolver == nil && s.hasDB {
+		s.resolver = definition.NewResolver(s.idx, s.allCommands().Commands())
+	}
+
+	return s.resolver
+}
+
+// unsavedFiles returns the contents of all open documents. The caller has to dispose them.
+func (s *server) unsavedFiles() []clang.UnsavedFile {
+	files := make([]clang.UnsavedFile, 0, len(s.docs))
+	for _, d := range s.docs {
+		files = append(files, clang.NewUnsavedFile(d.path, d.text))
+	}
+
+	return files
+}
+
+func disposeUnsavedFiles(files []clang.UnsavedFile) {
+	for _, f := range files {
+		f.Dispose()
+	}
+}
+
+func (s *server) document(uri string) (*document, error) {
+	d, ok := s.docs[uri]
+	if !ok {
+		return nil, errorf(codeRequestFailed, "document %s is not open", uri)
+	}
+
+	return d, nil
+}
+
+// parsed returns the open document with the given URI if it has a translation unit. The
+// translation unit is missing if the last parse of the document failed.
+func (s *server) parsed(uri string) (*document, error) {
+	d, err := s.document(uri)
+	if err != nil {
+		return nil, err
+	}
+	if !d.hasTU {
+		return nil, errorf(codeRequestFailed, "document %s could not be parsed", uri)
+	}
+
+	return d, nil
+}
+
+// sourceText returns the text of file as seen by tu, open documents are preferred.
+func (s *server) sourceText(tu clang.TranslationUnit, file clang.File) (*clang.SourceText, bool) {
+	name := file.Name()
+	if d, ok := s.docs[pathToURI(name)]; ok {
+		return d.src, true
+	}
+
+	return tu.SourceText(file)
+}
+
+// lspLocation converts a clang range into an LSP location using the text of its file.
+func (s *server) lspLocation(tu clang.TranslationUnit, r clang.SourceRange) (location, bool) {
+	span := r.Span()
+	if !span.IsValid() {
+		return location{}, false
+	}
+
+	file, _, _, _ := r.Start().ExpansionLocation()
+	st, ok := s.sourceText(tu, file)
+	if !ok {
+		return location{}, false
+	}
+
+	return location{
+		URI:   pathToURI(span.Start.File),
+		Range: toLSPRange(st, span),
+	}, true
+}
+
+func toLSPPosition(st *clang.SourceText, p clang.Position) position {
+	line, character := st.UTF16Position(p)
+
+	return position{Line: line, Character: character}
+}
+
+func toLSPRange(st *clang.SourceText, s clang.Span) lspRange {
+	return lspRange{
+		Start: toLSPPosition(st, s.Start),
+		End:   toLSPPosition(st, s.End),
+	}
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	u := url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(path),
+	}
+
+	return u.String()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// client drives a server over in-memory pipes.
+type client struct {
+	t    *testing.T
+	conn *conn
+	id   int
+	done chan error
+}
+
+// message is a response or a notification sent by the server.
+type message struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+	Result json.RawMessage  `json:"result"`
+	Error  *rpcError        `json:"error"`
+}
+
+func newClient(t *testing.T) *client {
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+
+	s := newServer(newConn(reqR, respW), log.New(io.Discard, "", 0))
+	c := &client{
+		t:    t,
+		conn: newConn(respR, reqW),
+		done: make(chan error, 1),
+	}
+	go func() {
+		c.done <- s.run()
+		respW.Close()
+	}()
+
+	return c
+}
+
+func (c *client) next() message {
+	c.t.Helper()
+
+	header, err := c.conn.r.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.conn.r.R, body); err != nil {
+		c.t.Fatal(err)
+	}
+
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		c.t.Fatal(err)
+	}
+
+	return m
+}
+
+func (c *client) call(method string, params, result interface{}) {
+	c.t.Helper()
+
+	c.id++
+	id := json.RawMessage(strconv.Itoa(c.id))
+	if err := c.conn.write(map[string]interface{}{"jsonrpc": "2.0", "id": &id, "method": method, "params": params}); err != nil {
+		c.t.Fatal(err)
+	}
+
+	m := c.next()
+	if m.ID == nil || string(*m.ID) != string(id) {
+		c.t.Fatalf("%s: expected response %s. got=%+v", method, id, m)
+	}
+	if m.Error != nil {
+		c.t.Fatalf("%s: %v", method, m.Error)
+	}
+	if result != nil {
+		if err := json.Unmarshal(m.Result, result); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func TestDefinitionAndHover(t *testing.T) {
+	path, err := filepath.Abs("testdata/add.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri := pathToURI(path)
+
+	c := newClient(t)
+	c.call("initialize", initializeParams{}, nil)
+	c.notify("initialized", struct{}{})
+	c.notify("textDocument/didOpen", didOpenTextDocumentParams{
+		TextDocument: textDocumentItem{URI: uri, LanguageID: "c", Version: 1, Text: string(text)},
+	})
+
+	var diags publishDiagnosticsParams
+	if m := c.next(); m.Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("expected diagnostics. got=%+v", m)
+	} else if err := json.Unmarshal(m.Params, &diags); err != nil {
+		t.Fatal(err)
+	}
+	if diags.URI != uri || len(diags.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics for %s. got=%+v", uri, diags)
+	}
+
+	// the call of add in main
+	call := textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: 6, Character: 8},
+	}
+
+	var locs []location
+	c.call("textDocument/definition", call, &locs)
+	want := location{URI: uri, Range: lspRange{Start: position{Line: 1, Character: 11}, End: position{Line: 1, Character: 11}}}
+	if len(locs) != 1 || locs[0] != want {
+		t.Errorf("expected definition %+v. got=%+v", want, locs)
+	}
+
+	var h hover
+	c.call("textDocument/hover", call, &h)
+	if !strings.HasPrefix(h.Contents.Value, "```c\nint add(int, int)\n```") {
+		t.Errorf("expected signature of add. got=%q", h.Contents.Value)
+	}
+	if !strings.Contains(h.Contents.Value, "add returns the sum of a and b.") {
+		t.Errorf("expected brief comment of add. got=%q", h.Contents.Value)
+	}
+
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeaderLanguage(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.h", "a.c", "b.h", "b.cc", "c.h"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := newServer(nil, nil)
+	s.docs[pathToURI(filepath.Join(dir, "d.c"))] = &document{}
+
+	table := []struct {
+		name string
+		want string
+	}{
+		{"a.h", "c-header"},
+		{"b.h", "c++-header"},
+		{"c.h", "c++-header"},
+		{"d.h", "c-header"},
+		{"e.hpp", "c++-header"},
+		{"a.c", ""},
+	}
+
+	for _, tt := range table {
+		if got := s.headerLanguage(filepath.Join(dir, tt.name)); got != tt.want {
+			t.Errorf("%s: expected %q. got=%q", tt.name, tt.want, got)
+		}
+	}
+}
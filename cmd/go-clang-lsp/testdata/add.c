@@ -0,0 +1,8 @@
+/// add returns the sum of a and b.
+static int add(int a, int b) {
+	return a + b;
+}
+
+int main(void) {
+	return add(1, 2);
+}
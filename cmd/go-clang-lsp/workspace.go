@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/symbols"
+)
+
+// maxWorkspaceSymbols is the maximal number of symbols returned by a workspace symbol search.
+const maxWorkspaceSymbols = 100
+
+// workspaceSymbol searches the symbols of the translation units of all open documents.
+func (s *server) workspaceSymbol(params json.RawMessage) (interface{}, error) {
+	var p workspaceSymbolParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	uris := make([]string, 0, len(s.docs))
+	for uri := range s.docs {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	ix := symbols.NewIndex()
+	for _, uri := range uris {
+		d := s.docs[uri]
+		if !d.hasTU {
+			continue
+		}
+		if !d.hasIndex {
+			d.index = symbols.Extractor{SkipSystemHeaders: true}.Extract(d.tu)
+			d.hasIndex = true
+		}
+		ix.Add(d.index...)
+	}
+
+	texts := map[string]*clang.SourceText{}
+	result := []symbolInformation{}
+	for _, h := range ix.Search(symbols.Query{Pattern: p.Query, Limit: maxWorkspaceSymbols}) {
+		kind := symbolKind(h.Kind)
+		if kind == 0 {
+			kind = symbolVariable
+		}
+
+		st, ok := texts[h.Location.File]
+		if !ok {
+			st = s.fileText(h.Location.File)
+			texts[h.Location.File] = st
+		}
+		if st == nil {
+			continue
+		}
+
+		end := h.Location
+		end.Column += uint32(len(h.Name))
+		end.Offset += uint32(len(h.Name))
+
+		result = append(result, symbolInformation{
+			Name: h.Name,
+			Kind: kind,
+			Location: location{
+				URI:   pathToURI(h.Location.File),
+				Range: toLSPRange(st, clang.Span{Start: h.Location, End: end}),
+			},
+			ContainerName: h.Container,
+		})
+	}
+
+	return result, nil
+}
+
+// fileText returns the text of an open document or of the file on disk, or nil.
+func (s *server) fileText(path string) *clang.SourceText {
+	if d, ok := s.docs[pathToURI(path)]; ok {
+		return d.src
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	return clang.NewSourceText(path, string(b))
+}
@@ -0,0 +1,109 @@
+// Package compdb provides Go values for the commands of a compilation database and parses
+// translation units with them.
+package compdb
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Command is a single compiler invocation of a compilation database which is detached from
+// libclang.
+type Command struct {
+	// Directory is the working directory of the invocation.
+	Directory string `json:"directory"`
+	// Filename is the source file that is compiled.
+	Filename string `json:"file"`
+	// Args is the complete command line including the compiler as first argument.
+	Args []string `json:"arguments"`
+}
+
+// NewCommand copies a clang.CompileCommand into a Command.
+func NewCommand(cc clang.CompileCommand) Command {
+	c := Command{
+		Directory: cc.Directory(),
+		Filename:  cc.Filename(),
+		Args:      make([]string, cc.NumArgs()),
+	}
+
+	for i := range c.Args {
+		c.Args[i] = cc.Arg(uint32(i))
+	}
+
+	return c
+}
+
+// Commands copies and disposes the given compile commands.
+func Commands(ccs clang.CompileCommands) []Command {
+	defer ccs.Dispose()
+
+	cmds := make([]Command, ccs.Size())
+	for i := range cmds {
+		cmds[i] = NewCommand(ccs.Command(uint32(i)))
+	}
+
+	return cmds
+}
+
+// Load returns all commands of the compilation database found in buildDir.
+func Load(buildDir string) ([]Command, error) {
+	err, db := clang.FromDirectory(buildDir)
+	if err != clang.CompilationDatabase_NoError {
+		return nil, fmt.Errorf("load compilation database from %s: %w", buildDir, err)
+	}
+	defer db.Dispose()
+
+	return Commands(db.AllCompileCommands()), nil
+}
+
+// Path returns the absolute path of the compiled file.
+func (c Command) Path() string {
+	return Abs(c.Directory, c.Filename)
+}
+
+// ClangArgs returns the arguments of the command as they are expected by
+// clang.Index.ParseTranslationUnit. The compiler, the source file and all options concerning
+// the output are removed, and the working directory of the command is passed on.
+func (c Command) ClangArgs() []string {
+	var args []string
+
+	if c.Directory != "" {
+		args = append(args, "-working-directory", c.Directory)
+	}
+
+	path := c.Path()
+	for i := 1; i < len(c.Args); i++ {
+		a := c.Args[i]
+
+		switch {
+		case a == "-c", a == "-MD", a == "-MMD", a == "-MP":
+			continue
+		case a == "-o", a == "-MF", a == "-MT", a == "-MQ":
+			i++
+
+			continue
+		case strings.HasPrefix(a, "-o"), strings.HasPrefix(a, "-MF"):
+			continue
+		case a == c.Filename, !strings.HasPrefix(a, "-") && c.Directory != "" && filepath.Join(c.Directory, a) == path:
+			continue
+		}
+
+		args = append(args, a)
+	}
+
+	return args
+}
+
+// Parse parses the translation unit of the command.
+func (c Command) Parse(idx clang.Index, unsavedFiles []clang.UnsavedFile, options uint32) (clang.TranslationUnit, error) {
+	var tu clang.TranslationUnit
+
+	if ec := idx.ParseTranslationUnit2(c.Path(), c.ClangArgs(), unsavedFiles, options, &tu); ec != clang.Error_Success {
+		return tu, fmt.Errorf("parse %s: %s", c.Path(), ec)
+	}
+
+	return tu, nil
+}
@@ -0,0 +1,257 @@
+package compdb
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Resolver returns the commands of files, inferring one from the most similar command for
+// files which are not part of the compilation database, like headers and new sources.
+type Resolver struct {
+	cmds   []Command
+	byPath map[string][]Command
+}
+
+// NewResolver returns a resolver for the commands of a compilation database.
+func NewResolver(cmds []Command) *Resolver {
+	r := &Resolver{
+		cmds:   cmds,
+		byPath: map[string][]Command{},
+	}
+	for _, cmd := range cmds {
+		r.byPath[cmd.Path()] = append(r.byPath[cmd.Path()], cmd)
+	}
+
+	return r
+}
+
+// Commands returns all commands of the compilation database.
+func (r *Resolver) Commands() []Command {
+	return r.cmds
+}
+
+// Command returns the command of the file at the absolute path file. The command is inferred
+// if the file is not part of the compilation database. ok is false if the database is empty.
+func (r *Resolver) Command(file string) (cmd Command, ok bool) {
+	if cmds := r.byPath[filepath.Clean(file)]; len(cmds) > 0 {
+		return cmds[0], true
+	}
+
+	return r.Infer(file)
+}
+
+// Infer returns a command for the file at the absolute path file, derived from the command of
+// the most similar file of the compilation database. Sources with the same base name are
+// preferred, so foo.cc donates its command to foo.h, followed by the files nearest to file and
+// with the longest common prefix of their names.
+//
+// The donor command is rewritten to compile file, and the language is set explicitly for
+// headers, e.g. to c++-header. ok is false if the database is empty.
+func (r *Resolver) Infer(file string) (cmd Command, ok bool) {
+	if len(r.cmds) == 0 {
+		return Command{}, false
+	}
+
+	file = filepath.Clean(file)
+	donor := r.donor(file)
+
+	return rewrite(donor, file), true
+}
+
+// donor returns the command most similar to file.
+func (r *Resolver) donor(file string) Command {
+	type candidate struct {
+		cmd    Command
+		path   string
+		stem   bool
+		dist   int
+		prefix int
+	}
+
+	dir, name := filepath.Dir(file), Stem(file)
+
+	cands := make([]candidate, len(r.cmds))
+	for i, cmd := range r.cmds {
+		path := cmd.Path()
+		s := Stem(path)
+		cands[i] = candidate{
+			cmd:    cmd,
+			path:   path,
+			stem:   s == name,
+			dist:   Distance(dir, filepath.Dir(path)),
+			prefix: commonPrefix(s, name),
+		}
+	}
+
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if a.stem != b.stem {
+			return a.stem
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		if a.prefix != b.prefix {
+			return a.prefix > b.prefix
+		}
+
+		return a.path < b.path
+	})
+
+	return cands[0].cmd
+}
+
+// rewrite returns the command donor compiling file instead of its own source. Explicit
+// language options of donor are removed. Headers get the header language of the donor, and
+// the standard of a C donor is dropped for C++ sources and vice versa.
+func rewrite(donor Command, file string) Command {
+	donorLang := language(donor)
+	fileLang := languageOf(file)
+	if fileLang == "" {
+		fileLang = donorLang
+	}
+
+	var lang string
+	if isHeader(file) {
+		lang = strings.TrimSuffix(donorLang, "-header") + "-header"
+		if fileLang == "c++" || fileLang == "c++-header" {
+			lang = "c++-header"
+		}
+	}
+	dropStd := strings.TrimSuffix(fileLang, "-header") != strings.TrimSuffix(donorLang, "-header")
+
+	path := donor.Path()
+	args := []string{}
+	replaced := false
+	for i := 0; i < len(donor.Args); i++ {
+		a := donor.Args[i]
+
+		switch {
+		case i == 0:
+		case a == "-x":
+			i++
+
+			continue
+		case strings.HasPrefix(a, "-x"):
+			continue
+		case dropStd && (strings.HasPrefix(a, "-std=") || strings.HasPrefix(a, "--std=")):
+			continue
+		case a == donor.Filename, !strings.HasPrefix(a, "-") && donor.Directory != "" && filepath.Join(donor.Directory, a) == path:
+			if lang != "" {
+				args = append(args, "-x", lang)
+			}
+			args = append(args, file)
+			replaced = true
+
+			continue
+		}
+
+		args = append(args, a)
+	}
+	if !replaced {
+		if lang != "" {
+			args = append(args, "-x", lang)
+		}
+		args = append(args, file)
+	}
+
+	return Command{
+		Directory: donor.Directory,
+		Filename:  file,
+		Args:      args,
+	}
+}
+
+// language returns the language the command compiles its source as, e.g. "c" or "c++".
+func language(cmd Command) string {
+	lang := ""
+	for i := 1; i < len(cmd.Args); i++ {
+		a := cmd.Args[i]
+
+		switch {
+		case a == "-x" && i+1 < len(cmd.Args):
+			lang = cmd.Args[i+1]
+			i++
+		case strings.HasPrefix(a, "-x") && len(a) > 2:
+			lang = a[2:]
+		}
+	}
+	if lang != "" {
+		return lang
+	}
+
+	if lang = languageOf(cmd.Filename); lang != "" {
+		return lang
+	}
+	if len(cmd.Args) > 0 && strings.Contains(filepath.Base(cmd.Args[0]), "++") {
+		return "c++"
+	}
+
+	return "c"
+}
+
+// languageOf returns the language of the file by its extension, or "" if the extension does
+// not tell.
+func languageOf(file string) string {
+	if filepath.Ext(file) == ".C" {
+		return "c++"
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".c":
+		return "c"
+	case ".cc", ".cpp", ".cxx", ".c++", ".cp":
+		return "c++"
+	case ".m":
+		return "objective-c"
+	case ".mm":
+		return "objective-c++"
+	case ".hh", ".hpp", ".hxx", ".h++":
+		return "c++-header"
+	}
+
+	return ""
+}
+
+// isHeader reports whether the file is a header by its extension. Files without an extension,
+// like the headers of the C++ standard library, count as headers.
+func isHeader(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".h", ".hh", ".hpp", ".hxx", ".h++", ".inc", ".inl", ".ipp", ".tcc", "":
+		return true
+	}
+
+	return false
+}
+
+// Stem returns the base name of the file without its extension, e.g. "foo" for "src/foo.cc".
+func Stem(path string) string {
+	base := filepath.Base(path)
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Distance returns the number of directories between the directories a and b, i.e. the
+// length of the path from one to the other.
+func Distance(a, b string) int {
+	as := strings.Split(filepath.ToSlash(a), "/")
+	bs := strings.Split(filepath.ToSlash(b), "/")
+
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+
+	return len(as) + len(bs) - 2*n
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,66 @@
+package compdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	cmds := []Command{
+		{Directory: "/p/build", Filename: "/p/src/foo.cc", Args: []string{"c++", "-std=c++17", "-I/p/include", "-c", "/p/src/foo.cc", "-o", "foo.o"}},
+		{Directory: "/p/build", Filename: "/p/src/bar.cc", Args: []string{"c++", "-DBAR", "-c", "/p/src/bar.cc"}},
+		{Directory: "/p/lib", Filename: "util.c", Args: []string{"cc", "-std=c11", "-xc", "-c", "util.c"}},
+	}
+	r := NewResolver(cmds)
+
+	tests := []struct {
+		file string
+		want Command
+	}{
+		{
+			"/p/src/bar.cc",
+			cmds[1],
+		},
+		{
+			"/p/include/foo.h",
+			Command{Directory: "/p/build", Filename: "/p/include/foo.h",
+				Args: []string{"c++", "-std=c++17", "-I/p/include", "-c", "-x", "c++-header", "/p/include/foo.h", "-o", "foo.o"}},
+		},
+		{
+			"/p/src/bar_impl.cc",
+			Command{Directory: "/p/build", Filename: "/p/src/bar_impl.cc",
+				Args: []string{"c++", "-DBAR", "-c", "/p/src/bar_impl.cc"}},
+		},
+		{
+			"/p/lib/util.h",
+			Command{Directory: "/p/lib", Filename: "/p/lib/util.h",
+				Args: []string{"cc", "-std=c11", "-c", "-x", "c-header", "/p/lib/util.h"}},
+		},
+		{
+			"/p/lib/util.hpp",
+			Command{Directory: "/p/lib", Filename: "/p/lib/util.hpp",
+				Args: []string{"cc", "-c", "-x", "c++-header", "/p/lib/util.hpp"}},
+		},
+		{
+			"/p/lib/new.cc",
+			Command{Directory: "/p/lib", Filename: "/p/lib/new.cc",
+				Args: []string{"cc", "-c", "/p/lib/new.cc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := r.Command(tt.file)
+		if !ok {
+			t.Errorf("Command(%s) failed", tt.file)
+
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Command(%s) = %+v, want %+v", tt.file, got, tt.want)
+		}
+	}
+
+	if _, ok := NewResolver(nil).Command("/p/a.h"); ok {
+		t.Error("Command succeeded without commands")
+	}
+}
@@ -0,0 +1,56 @@
+package compdb
+
+import (
+	"path/filepath"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Abs returns the cleaned absolute form of the file name, resolving relative names against
+// the directory dir. libclang reports file names as they were passed to the compiler, so they
+// are relative to the working directory of the command. Empty names stay empty, and relative
+// names stay relative if dir is empty.
+func Abs(dir, name string) string {
+	if name == "" {
+		return ""
+	}
+	if !filepath.IsAbs(name) && dir != "" {
+		name = filepath.Join(dir, name)
+	}
+
+	return filepath.Clean(name)
+}
+
+// Paths resolves the file names of a translation unit with Abs and caches the results, as the
+// same few files are reported for most cursors. The zero Paths leaves relative names relative.
+type Paths struct {
+	// Directory is the working directory of the compiler.
+	Directory string
+
+	cache map[string]string
+}
+
+// Abs returns the cleaned absolute form of the file name.
+func (p *Paths) Abs(name string) string {
+	if abs, ok := p.cache[name]; ok {
+		return abs
+	}
+	if p.cache == nil {
+		p.cache = map[string]string{}
+	}
+
+	abs := Abs(p.Directory, name)
+	p.cache[name] = abs
+
+	return abs
+}
+
+// Position returns pos with the cleaned absolute form of its file name. Invalid positions are
+// returned unchanged.
+func (p *Paths) Position(pos clang.Position) clang.Position {
+	if pos.IsValid() {
+		pos.File = p.Abs(pos.File)
+	}
+
+	return pos
+}
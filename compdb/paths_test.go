@@ -0,0 +1,39 @@
+package compdb
+
+import (
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func TestPaths(t *testing.T) {
+	p := Paths{Directory: "/p/build"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", ""},
+		{"foo.c", "/p/build/foo.c"},
+		{"../src/./foo.c", "/p/src/foo.c"},
+		{"/usr/include//stdio.h", "/usr/include/stdio.h"},
+	}
+
+	for _, tt := range tests {
+		if got := p.Abs(tt.name); got != tt.want {
+			t.Errorf("expected %q for %q. got=%q", tt.want, tt.name, got)
+		}
+	}
+
+	if got := Abs("", "./foo.c"); got != "foo.c" {
+		t.Errorf("expected relative name without directory. got=%q", got)
+	}
+
+	pos := p.Position(clang.Position{File: "foo.c", Line: 3, Column: 1, Offset: 10})
+	if pos.File != "/p/build/foo.c" || pos.Line != 3 {
+		t.Errorf("expected position in /p/build/foo.c. got=%+v", pos)
+	}
+	if got := p.Position(clang.Position{File: "foo.c"}); got.File != "foo.c" {
+		t.Errorf("expected invalid position unchanged. got=%+v", got)
+	}
+}
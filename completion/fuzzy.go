@@ -0,0 +1,171 @@
+package completion
+
+// Scores of a single matched character of the pattern.
+const (
+	scoreMatch       = 1
+	scoreCase        = 1
+	scoreConsecutive = 2
+	scorePrefix      = 3
+	scoreWordStart   = 2
+)
+
+// Matcher matches texts against a pattern the way completion lists are usually filtered: the
+// characters of the pattern have to appear in order, case-insensitively, but not necessarily
+// next to each other. Matches at the start of the text, at word starts ("_b" or "aB") and of
+// consecutive characters score higher.
+//
+// A Matcher reuses its buffers and must not be used concurrently.
+type Matcher struct {
+	pattern []byte
+	lower   []byte
+	perfect int
+
+	prev, cur []int
+	lowText   []byte
+}
+
+// NewMatcher returns a matcher for pattern.
+func NewMatcher(pattern string) *Matcher {
+	m := &Matcher{
+		pattern: []byte(pattern),
+		lower:   make([]byte, len(pattern)),
+	}
+	for i := 0; i < len(pattern); i++ {
+		m.lower[i] = toLower(pattern[i])
+	}
+	if len(pattern) > 0 {
+		m.perfect = scoreMatch + scoreCase + scorePrefix +
+			(len(pattern)-1)*(scoreMatch+scoreCase+scoreConsecutive)
+	}
+
+	return m
+}
+
+// Pattern returns the pattern of the matcher.
+func (m *Matcher) Pattern() string {
+	return string(m.pattern)
+}
+
+// Score reports whether text matches the pattern and returns the quality of the best match
+// in the range (0, 1]. The empty pattern matches every text with quality 1.
+func (m *Matcher) Score(text string) (float64, bool) {
+	np, nt := len(m.lower), len(text)
+	if np == 0 {
+		return 1, true
+	}
+	if np > nt {
+		return 0, false
+	}
+
+	m.lowText = m.lowText[:0]
+	j := 0
+	for i := 0; i < nt; i++ {
+		c := toLower(text[i])
+		m.lowText = append(m.lowText, c)
+		if j < np && c == m.lower[j] {
+			j++
+		}
+	}
+	if j < np {
+		return 0, false
+	}
+
+	best := m.align(text)
+	q := float64(best) / float64(m.perfect)
+	if q > 1 {
+		q = 1
+	}
+
+	// Prefer texts which are not much longer than the pattern.
+	return q * (0.9 + 0.1*float64(np)/float64(nt)), true
+}
+
+// align returns the score of the best alignment of the pattern in text. It is only called for
+// texts which contain the pattern as subsequence.
+func (m *Matcher) align(text string) int {
+	const none = -1 << 30
+
+	nt := len(text)
+	if cap(m.prev) < nt {
+		m.prev = make([]int, nt)
+		m.cur = make([]int, nt)
+	}
+	prev, cur := m.prev[:nt], m.cur[:nt]
+
+	for j := range m.lower {
+		// bestBefore is the best score of the previous pattern character matched before i-1.
+		bestBefore := none
+		for i := 0; i < nt; i++ {
+			if j > 0 && i >= 2 && prev[i-2] > bestBefore {
+				bestBefore = prev[i-2]
+			}
+
+			cur[i] = none
+			if m.lowText[i] != m.lower[j] {
+				continue
+			}
+
+			s := scoreMatch + charBonus(text, i)
+			if text[i] == m.pattern[j] {
+				s += scoreCase
+			}
+
+			if j == 0 {
+				cur[i] = s
+
+				continue
+			}
+
+			from := bestBefore
+			if i >= 1 && prev[i-1] != none && prev[i-1]+scoreConsecutive > from {
+				from = prev[i-1] + scoreConsecutive
+			}
+			if from != none {
+				cur[i] = from + s
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	best := none
+	for _, s := range prev {
+		if s > best {
+			best = s
+		}
+	}
+
+	return best
+}
+
+// charBonus returns the bonus for matching the i-th byte of text.
+func charBonus(text string, i int) int {
+	if i == 0 {
+		return scorePrefix
+	}
+
+	p, c := text[i-1], text[i]
+	switch {
+	case !isAlnum(p) && isAlnum(c):
+		return scoreWordStart
+	case isLowerOrDigit(p) && 'A' <= c && c <= 'Z':
+		return scoreWordStart
+	}
+
+	return 0
+}
+
+func toLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
+
+func isLowerOrDigit(c byte) bool {
+	return 'a' <= c && c <= 'z' || '0' <= c && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isLowerOrDigit(c) || 'A' <= c && c <= 'Z' || c >= 0x80
+}
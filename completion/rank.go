@@ -0,0 +1,264 @@
+package completion
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Candidate holds what ranking needs to know about a completion result. Reading it is much
+// cheaper than rendering the whole result, so results are ranked first and only the ones shown
+// are rendered.
+type Candidate struct {
+	// Index is the index of the result in CodeCompleteResults.Results.
+	Index        int
+	FilterText   string
+	Kind         clang.CursorKind
+	Priority     uint32
+	Availability clang.AvailabilityKind
+}
+
+// Candidates returns the candidates of completion results.
+func Candidates(results []clang.CompletionResult) []Candidate {
+	cands := make([]Candidate, 0, len(results))
+
+	for i, r := range results {
+		cs := r.CompletionString()
+
+		c := Candidate{
+			Index:        i,
+			Kind:         r.CursorKind(),
+			Priority:     cs.Priority(),
+			Availability: cs.Availability(),
+		}
+		for k := uint32(0); k < cs.NumChunks(); k++ {
+			if cs.ChunkKind(k) == clang.CompletionChunk_TypedText {
+				c.FilterText += cs.ChunkText(k)
+			}
+		}
+		cands = append(cands, c)
+	}
+
+	return cands
+}
+
+// Match is a ranked candidate.
+type Match struct {
+	Candidate
+	// Quality is the quality of the fuzzy match of the filter text.
+	Quality float64
+	// Score combines the quality with the other ranking signals. Higher is better.
+	Score float64
+}
+
+// Ranker filters and ranks candidates against the typed prefix.
+type Ranker struct {
+	// Contexts is the bitmask of CodeCompleteResults.Contexts. Candidates which are expected
+	// in these contexts are preferred.
+	Contexts uint64
+	// History holds recently accepted completions, which are preferred. It may be nil.
+	History *History
+	// Limit is the maximum number of matches returned by Rank, 0 means no limit.
+	Limit int
+}
+
+// Rank returns the candidates matching prefix, best first. Unavailable candidates are dropped.
+func (r Ranker) Rank(prefix string, cands []Candidate) []Match {
+	m := NewMatcher(prefix)
+	matches := make([]Match, 0, 64)
+
+	var recent map[historyKey]float64
+	if r.History != nil {
+		recent = r.History.boosts()
+	}
+
+	for _, c := range cands {
+		if c.Availability == clang.Availability_NotAvailable {
+			continue
+		}
+
+		q, ok := m.Score(c.FilterText)
+		if !ok {
+			continue
+		}
+
+		s := q * priorityFactor(c.Priority) * availabilityFactor(c.Availability) * contextFactor(r.Contexts, c.Kind)
+		if b, ok := recent[historyKey{c.FilterText, c.Kind}]; ok {
+			s *= b
+		}
+
+		match := Match{Candidate: c, Quality: q, Score: s}
+		switch {
+		case r.Limit <= 0 || len(matches) < r.Limit:
+			matches = append(matches, match)
+			if len(matches) == r.Limit {
+				heap.Init((*worstFirst)(&matches))
+			}
+		case better(match, matches[0]):
+			// Only the best Limit matches are kept, the worst of them is on top of the heap.
+			matches[0] = match
+			heap.Fix((*worstFirst)(&matches), 0)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return better(matches[i], matches[j])
+	})
+
+	return matches
+}
+
+// better reports whether a ranks before b.
+func better(a, b Match) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.FilterText != b.FilterText {
+		return a.FilterText < b.FilterText
+	}
+
+	return a.Index < b.Index
+}
+
+// worstFirst is a heap of matches with the worst match on top.
+type worstFirst []Match
+
+func (h worstFirst) Len() int            { return len(h) }
+func (h worstFirst) Less(i, j int) bool  { return better(h[j], h[i]) }
+func (h worstFirst) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *worstFirst) Push(x interface{}) { *h = append(*h, x.(Match)) }
+
+func (h *worstFirst) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
+
+// priorityFactor maps the priority of clang, where smaller values are more likely
+// completions, to a factor between 1 and 2.
+func priorityFactor(priority uint32) float64 {
+	const worst = 80
+	if priority > worst {
+		priority = worst
+	}
+
+	return 1 + float64(worst-priority)/worst
+}
+
+func availabilityFactor(a clang.AvailabilityKind) float64 {
+	switch a {
+	case clang.Availability_Deprecated:
+		return 0.5
+	case clang.Availability_NotAccessible:
+		return 0.25
+	}
+
+	return 1
+}
+
+const (
+	memberContexts = uint64(clang.CompletionContext_DotMemberAccess | clang.CompletionContext_ArrowMemberAccess |
+		clang.CompletionContext_ObjCPropertyAccess)
+	typeContexts = uint64(clang.CompletionContext_AnyType | clang.CompletionContext_EnumTag |
+		clang.CompletionContext_UnionTag | clang.CompletionContext_StructTag | clang.CompletionContext_ClassTag |
+		clang.CompletionContext_ObjCInterface)
+	valueContexts = uint64(clang.CompletionContext_AnyValue | clang.CompletionContext_CXXClassTypeValue |
+		clang.CompletionContext_ObjCObjectValue)
+	scopeContexts = uint64(clang.CompletionContext_Namespace | clang.CompletionContext_NestedNameSpecifier)
+	macroContexts = uint64(clang.CompletionContext_MacroName)
+)
+
+// expectedContexts returns the contexts in which a declaration of kind is usually completed.
+func expectedContexts(kind clang.CursorKind) uint64 {
+	switch kind {
+	case clang.Cursor_FieldDecl, clang.Cursor_CXXMethod, clang.Cursor_ConversionFunction, clang.Cursor_ObjCPropertyDecl,
+		clang.Cursor_ObjCIvarDecl, clang.Cursor_ObjCInstanceMethodDecl:
+		return memberContexts
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl, clang.Cursor_EnumDecl,
+		clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl, clang.Cursor_ClassTemplate, clang.Cursor_ObjCInterfaceDecl,
+		clang.Cursor_TemplateTypeParameter:
+		return typeContexts | scopeContexts
+	case clang.Cursor_VarDecl, clang.Cursor_ParmDecl, clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate,
+		clang.Cursor_EnumConstantDecl:
+		return valueContexts
+	case clang.Cursor_Namespace, clang.Cursor_NamespaceAlias:
+		return scopeContexts
+	case clang.Cursor_MacroDefinition:
+		return macroContexts
+	}
+
+	return 0
+}
+
+// contextFactor prefers candidates which are expected in the completion contexts. Nothing is
+// preferred if the contexts are unknown.
+func contextFactor(contexts uint64, kind clang.CursorKind) float64 {
+	if contexts == 0 || contexts == uint64(clang.CompletionContext_Unknown) {
+		return 1
+	}
+	if contexts&expectedContexts(kind) != 0 {
+		return 1.5
+	}
+
+	return 1
+}
+
+type historyKey struct {
+	text string
+	kind clang.CursorKind
+}
+
+// History remembers the most recently accepted completions. It is safe for concurrent use.
+type History struct {
+	mu    sync.Mutex
+	size  int
+	clock uint64
+	used  map[historyKey]uint64
+}
+
+// NewHistory returns a history which remembers up to size completions.
+func NewHistory(size int) *History {
+	return &History{
+		size: size,
+		used: map[historyKey]uint64{},
+	}
+}
+
+// Use records that the candidate was accepted.
+func (h *History) Use(c Candidate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clock++
+	h.used[historyKey{c.FilterText, c.Kind}] = h.clock
+
+	if len(h.used) > h.size {
+		var oldest historyKey
+		min := h.clock
+		for k, t := range h.used {
+			if t < min {
+				oldest, min = k, t
+			}
+		}
+		delete(h.used, oldest)
+	}
+}
+
+// boosts returns the factor of every remembered completion. The last accepted completion is
+// boosted by 2, older ones by less.
+func (h *History) boosts() map[historyKey]float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	boosts := make(map[historyKey]float64, len(h.used))
+	for k, t := range h.used {
+		age := h.clock - t
+		boosts[k] = 1 + 1/(1+float64(age)/4)
+	}
+
+	return boosts
+}
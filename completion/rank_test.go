@@ -0,0 +1,139 @@
+package completion
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func TestMatcher(t *testing.T) {
+	table := []struct {
+		pattern string
+		text    string
+		ok      bool
+	}{
+		{"", "anything", true},
+		{"get", "getValue", true},
+		{"gv", "getValue", true},
+		{"GV", "getValue", true},
+		{"gvx", "getValue", false},
+		{"value", "val", false},
+		{"vg", "getValue", false},
+	}
+
+	for _, tt := range table {
+		if _, ok := NewMatcher(tt.pattern).Score(tt.text); ok != tt.ok {
+			t.Errorf("%q %q: expected %v. got=%v", tt.pattern, tt.text, tt.ok, ok)
+		}
+	}
+}
+
+func TestMatcherOrder(t *testing.T) {
+	// Each text should score higher than the following one.
+	table := []struct {
+		pattern string
+		texts   []string
+	}{
+		{"size", []string{"size", "sizeInBytes", "setSizeHint", "transactions_ize"}},
+		{"gv", []string{"get_value", "getValue", "give", "progVar", "ignoreValue"}},
+		{"Str", []string{"Str", "str", "myString"}},
+	}
+
+	for _, tt := range table {
+		m := NewMatcher(tt.pattern)
+
+		last := 2.0
+		for _, text := range tt.texts {
+			q, ok := m.Score(text)
+			if !ok {
+				t.Errorf("%q %q: expected a match", tt.pattern, text)
+
+				continue
+			}
+			if q >= last {
+				t.Errorf("%q %q: expected a score below %f. got=%f", tt.pattern, text, last, q)
+			}
+			last = q
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	cands := []Candidate{
+		{Index: 0, FilterText: "width", Kind: clang.Cursor_FieldDecl, Priority: 35},
+		{Index: 1, FilterText: "wide", Kind: clang.Cursor_FunctionDecl, Priority: 50},
+		{Index: 2, FilterText: "widget", Kind: clang.Cursor_StructDecl, Priority: 50},
+		{Index: 3, FilterText: "width_old", Kind: clang.Cursor_FieldDecl, Priority: 35, Availability: clang.Availability_Deprecated},
+		{Index: 4, FilterText: "wid", Kind: clang.Cursor_VarDecl, Priority: 35, Availability: clang.Availability_NotAvailable},
+		{Index: 5, FilterText: "height", Kind: clang.Cursor_FieldDecl, Priority: 35},
+	}
+
+	order := func(matches []Match) []int {
+		var idx []int
+		for _, m := range matches {
+			idx = append(idx, m.Index)
+		}
+
+		return idx
+	}
+
+	r := Ranker{}
+	if got, want := fmt.Sprint(order(r.Rank("wid", cands))), "[0 1 2 3]"; got != want {
+		t.Errorf("expected %s. got=%s", want, got)
+	}
+
+	r.Contexts = uint64(clang.CompletionContext_AnyType)
+	if got := order(r.Rank("wid", cands)); got[0] != 2 {
+		t.Errorf("expected the struct to be first in a type context. got=%v", got)
+	}
+
+	r.History = NewHistory(10)
+	r.Contexts = 0
+	r.History.Use(cands[1])
+	if got := order(r.Rank("wid", cands)); got[0] != 1 {
+		t.Errorf("expected the recently used function to be first. got=%v", got)
+	}
+
+	r.Limit = 2
+	if got := r.Rank("", cands); len(got) != 2 {
+		t.Errorf("expected 2 matches. got=%d", len(got))
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	h := NewHistory(2)
+	for _, text := range []string{"a", "b", "c"} {
+		h.Use(Candidate{FilterText: text})
+	}
+
+	boosts := h.boosts()
+	if len(boosts) != 2 {
+		t.Fatalf("expected 2 entries. got=%v", boosts)
+	}
+	if _, ok := boosts[historyKey{text: "a"}]; ok {
+		t.Errorf("expected the oldest entry to be dropped")
+	}
+	if boosts[historyKey{text: "c"}] <= boosts[historyKey{text: "b"}] {
+		t.Errorf("expected the last entry to be boosted most. got=%v", boosts)
+	}
+}
+
+func BenchmarkRank(b *testing.B) {
+	cands := make([]Candidate, 50000)
+	for i := range cands {
+		cands[i] = Candidate{
+			Index:      i,
+			FilterText: fmt.Sprintf("symbol_%d_getValueFor%d", i%977, i),
+			Kind:       clang.Cursor_FunctionDecl,
+			Priority:   uint32(i % 80),
+		}
+	}
+
+	r := Ranker{Limit: 100}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.Rank("gvf", cands)
+	}
+}
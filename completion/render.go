@@ -0,0 +1,258 @@
+// Package completion renders and ranks the results of clang code completion for display in
+// editors.
+package completion
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Chunk is a single piece of a completion string. Optional chunks hold the chunks of their
+// nested completion string.
+type Chunk struct {
+	Kind     clang.CompletionChunkKind
+	Text     string
+	Optional []Chunk
+}
+
+// Chunks copies the chunks of a completion string including all nested optional chunks.
+func Chunks(cs clang.CompletionString) []Chunk {
+	n := cs.NumChunks()
+	chunks := make([]Chunk, 0, n)
+
+	for i := uint32(0); i < n; i++ {
+		c := Chunk{
+			Kind: cs.ChunkKind(i),
+		}
+		if c.Kind == clang.CompletionChunk_Optional {
+			c.Optional = Chunks(cs.ChunkCompletionString(i))
+		} else {
+			c.Text = cs.ChunkText(i)
+		}
+		chunks = append(chunks, c)
+	}
+
+	return chunks
+}
+
+// Item is a completion result rendered for display.
+type Item struct {
+	// Label is the text shown in the completion list, e.g. "max(int a, int b)".
+	Label string
+	// Detail is the result type of functions and the type of variables.
+	Detail string
+	// FilterText is the text the typed prefix is matched against.
+	FilterText string
+	// InsertText is the plain text inserted when the completion is accepted. It is the typed
+	// text alone, as the arguments of calls cannot be inserted without snippets.
+	InsertText string
+	// Snippet is the text to insert in the snippet syntax of the Language Server Protocol, e.g.
+	// "max(${1:int a}, ${2:int b})".
+	Snippet string
+	// Documentation is the brief comment of the completed declaration.
+	Documentation string
+
+	Kind         clang.CursorKind
+	Availability clang.AvailabilityKind
+	Priority     uint32
+}
+
+// Render renders a completion result.
+func Render(r clang.CompletionResult) Item {
+	it := RenderString(r.CompletionString())
+	it.Kind = r.CursorKind()
+
+	return it
+}
+
+// RenderString renders a completion string. The Kind of the returned item is not set.
+func RenderString(cs clang.CompletionString) Item {
+	it := RenderChunks(Chunks(cs))
+	it.Documentation = cs.BriefComment()
+	it.Availability = cs.Availability()
+	it.Priority = cs.Priority()
+
+	return it
+}
+
+// RenderChunks renders the label, detail, filter, insert and snippet texts of chunks.
+func RenderChunks(chunks []Chunk) Item {
+	var label, filter, insert strings.Builder
+	var detail string
+
+	for _, c := range chunks {
+		switch c.Kind {
+		case clang.CompletionChunk_ResultType:
+			detail = c.Text
+		case clang.CompletionChunk_TypedText:
+			filter.WriteString(c.Text)
+			label.WriteString(c.Text)
+			insert.WriteString(c.Text)
+		case clang.CompletionChunk_Optional:
+			writeText(&label, c.Optional)
+		default:
+			label.WriteString(c.Text)
+		}
+	}
+
+	sw := snippetWriter{}
+	sw.write(chunks)
+
+	return Item{
+		Label:      label.String(),
+		Detail:     detail,
+		FilterText: filter.String(),
+		InsertText: insert.String(),
+		Snippet:    sw.String(),
+	}
+}
+
+// writeText writes the text of chunks as it would appear in source code. The result type and
+// informative chunks are left out.
+func writeText(b *strings.Builder, chunks []Chunk) {
+	for _, c := range chunks {
+		switch c.Kind {
+		case clang.CompletionChunk_ResultType, clang.CompletionChunk_Informative:
+		case clang.CompletionChunk_Optional:
+			writeText(b, c.Optional)
+		default:
+			b.WriteString(c.Text)
+		}
+	}
+}
+
+// snippetWriter renders chunks in the snippet syntax of the Language Server Protocol.
+// Placeholders become numbered fields and every optional chunk becomes a single field holding
+// its whole text, so it can be kept or deleted at once.
+type snippetWriter struct {
+	strings.Builder
+	fields int
+}
+
+func (sw *snippetWriter) write(chunks []Chunk) {
+	for _, c := range chunks {
+		switch c.Kind {
+		case clang.CompletionChunk_ResultType, clang.CompletionChunk_Informative:
+		case clang.CompletionChunk_Placeholder, clang.CompletionChunk_CurrentParameter:
+			sw.field(c.Text)
+		case clang.CompletionChunk_Optional:
+			var b strings.Builder
+			writeText(&b, c.Optional)
+			sw.field(b.String())
+		default:
+			sw.WriteString(escapeSnippet(c.Text))
+		}
+	}
+}
+
+func (sw *snippetWriter) field(text string) {
+	sw.fields++
+	sw.WriteString("${")
+	sw.WriteString(strconv.Itoa(sw.fields))
+	if text != "" {
+		sw.WriteString(":")
+		sw.WriteString(escapeSnippet(text))
+	}
+	sw.WriteString("}")
+}
+
+var snippetEscaper = strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`)
+
+// escapeSnippet escapes the characters which have a meaning in the snippet syntax.
+func escapeSnippet(s string) string {
+	return snippetEscaper.Replace(s)
+}
+
+// Signature is the signature of a function or macro as it is shown while its arguments are
+// typed, e.g. for the overload candidates of a call.
+type Signature struct {
+	// Label is the whole signature, e.g. "int max(int a, int b)".
+	Label string
+	// Parameters holds the byte offsets of the parameters in Label.
+	Parameters []Parameter
+	// Active is the index of the current parameter in Parameters, or -1.
+	Active int
+	// Documentation is the brief comment of the function.
+	Documentation string
+	Availability  clang.AvailabilityKind
+}
+
+// Parameter is a parameter of a signature. Its label is Signature.Label[Start:End].
+type Parameter struct {
+	Start, End int
+	Optional   bool
+}
+
+// NewSignature renders the signature of a completion string.
+func NewSignature(cs clang.CompletionString) Signature {
+	s := SignatureOfChunks(Chunks(cs))
+	s.Documentation = cs.BriefComment()
+	s.Availability = cs.Availability()
+
+	return s
+}
+
+// SignatureOfChunks renders the signature of chunks. The current parameter chunk determines
+// the active parameter.
+func SignatureOfChunks(chunks []Chunk) Signature {
+	s := Signature{
+		Active: -1,
+	}
+
+	var b strings.Builder
+	for _, c := range chunks {
+		if c.Kind == clang.CompletionChunk_ResultType {
+			b.WriteString(c.Text)
+			b.WriteString(" ")
+		}
+	}
+	s.writeSignature(&b, chunks, false)
+	s.Label = b.String()
+
+	return s
+}
+
+func (s *Signature) writeSignature(b *strings.Builder, chunks []Chunk, optional bool) {
+	for _, c := range chunks {
+		switch c.Kind {
+		case clang.CompletionChunk_ResultType:
+		case clang.CompletionChunk_Optional:
+			s.writeSignature(b, c.Optional, true)
+		case clang.CompletionChunk_Placeholder, clang.CompletionChunk_CurrentParameter:
+			if c.Kind == clang.CompletionChunk_CurrentParameter {
+				s.Active = len(s.Parameters)
+			}
+			start := b.Len()
+			b.WriteString(c.Text)
+			s.Parameters = append(s.Parameters, Parameter{Start: start, End: b.Len(), Optional: optional})
+		default:
+			b.WriteString(c.Text)
+		}
+	}
+}
+
+// Parameter returns the label of the i-th parameter.
+func (s Signature) Parameter(i int) string {
+	p := s.Parameters[i]
+
+	return s.Label[p.Start:p.End]
+}
+
+// Highlight returns the label with the active parameter enclosed in open and close, e.g.
+// Highlight("**", "**") for Markdown.
+func (s Signature) Highlight(open, close string) string {
+	if s.Active < 0 || s.Active >= len(s.Parameters) {
+		return s.Label
+	}
+
+	p := s.Parameters[s.Active]
+
+	return s.Label[:p.Start] + open + s.Label[p.Start:p.End] + close + s.Label[p.End:]
+}
+
+// String returns the label with the active parameter enclosed in brackets.
+func (s Signature) String() string {
+	return s.Highlight("[", "]")
+}
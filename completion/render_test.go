@@ -0,0 +1,112 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func chunk(kind clang.CompletionChunkKind, text string) Chunk {
+	return Chunk{Kind: kind, Text: text}
+}
+
+func TestRenderChunks(t *testing.T) {
+	// int max(int a, int b = 0)
+	chunks := []Chunk{
+		chunk(clang.CompletionChunk_ResultType, "int"),
+		chunk(clang.CompletionChunk_TypedText, "max"),
+		chunk(clang.CompletionChunk_LeftParen, "("),
+		chunk(clang.CompletionChunk_Placeholder, "int a"),
+		{Kind: clang.CompletionChunk_Optional, Optional: []Chunk{
+			chunk(clang.CompletionChunk_Comma, ", "),
+			chunk(clang.CompletionChunk_Placeholder, "int b"),
+		}},
+		chunk(clang.CompletionChunk_RightParen, ")"),
+		chunk(clang.CompletionChunk_Informative, " const"),
+	}
+
+	it := RenderChunks(chunks)
+	want := Item{
+		Label:      "max(int a, int b) const",
+		Detail:     "int",
+		FilterText: "max",
+		InsertText: "max",
+		Snippet:    "max(${1:int a}${2:, int b})",
+	}
+	if it != want {
+		t.Errorf("expected %+v. got=%+v", want, it)
+	}
+}
+
+func TestRenderRequired(t *testing.T) {
+	// int max(int a, int b)
+	chunks := []Chunk{
+		chunk(clang.CompletionChunk_ResultType, "int"),
+		chunk(clang.CompletionChunk_TypedText, "max"),
+		chunk(clang.CompletionChunk_LeftParen, "("),
+		chunk(clang.CompletionChunk_Placeholder, "int a"),
+		chunk(clang.CompletionChunk_Comma, ", "),
+		chunk(clang.CompletionChunk_Placeholder, "int b"),
+		chunk(clang.CompletionChunk_RightParen, ")"),
+	}
+
+	it := RenderChunks(chunks)
+	want := Item{
+		Label:      "max(int a, int b)",
+		Detail:     "int",
+		FilterText: "max",
+		InsertText: "max",
+		Snippet:    "max(${1:int a}, ${2:int b})",
+	}
+	if it != want {
+		t.Errorf("expected %+v. got=%+v", want, it)
+	}
+}
+
+func TestSnippetEscape(t *testing.T) {
+	chunks := []Chunk{
+		chunk(clang.CompletionChunk_TypedText, "f"),
+		chunk(clang.CompletionChunk_LeftParen, "("),
+		chunk(clang.CompletionChunk_Placeholder, "struct {int $x;}"),
+		chunk(clang.CompletionChunk_RightParen, ")"),
+	}
+
+	if got, want := RenderChunks(chunks).Snippet, `f(${1:struct {int \$x;\}})`; got != want {
+		t.Errorf("expected %q. got=%q", want, got)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	chunks := []Chunk{
+		chunk(clang.CompletionChunk_ResultType, "int"),
+		chunk(clang.CompletionChunk_Text, "max"),
+		chunk(clang.CompletionChunk_LeftParen, "("),
+		chunk(clang.CompletionChunk_Placeholder, "int a"),
+		chunk(clang.CompletionChunk_Comma, ", "),
+		chunk(clang.CompletionChunk_CurrentParameter, "int b"),
+		{Kind: clang.CompletionChunk_Optional, Optional: []Chunk{
+			chunk(clang.CompletionChunk_Comma, ", "),
+			chunk(clang.CompletionChunk_Placeholder, "int c"),
+		}},
+		chunk(clang.CompletionChunk_RightParen, ")"),
+	}
+
+	s := SignatureOfChunks(chunks)
+	if s.Label != "int max(int a, int b, int c)" {
+		t.Errorf("unexpected label %q", s.Label)
+	}
+	if len(s.Parameters) != 3 || s.Active != 1 {
+		t.Fatalf("expected 3 parameters with the second active. got=%+v", s)
+	}
+	for i, want := range []string{"int a", "int b", "int c"} {
+		if got := s.Parameter(i); got != want {
+			t.Errorf("parameter %d: expected %q. got=%q", i, want, got)
+		}
+	}
+	if !s.Parameters[2].Optional || s.Parameters[1].Optional {
+		t.Errorf("expected only the last parameter to be optional. got=%+v", s.Parameters)
+	}
+	if got, want := s.Highlight("**", "**"), "int max(int a, **int b**, int c)"; got != want {
+		t.Errorf("expected %q. got=%q", want, got)
+	}
+}
@@ -0,0 +1,271 @@
+// Package ctype decodes clang types into a tree of Go values.
+//
+// Decode turns a clang.Type into a Type: a Pointer, Reference, MemberPointer, Array, Function,
+// Record, Enum, Typedef, Elaborated, Builtin or Other, each with its qualifiers and nullability.
+// Declarations are referenced by a Decl holding their names and USR, so the tree holds no
+// libclang handles and stays valid after the translation unit is disposed. Types are written
+// as C declarations by Format.
+package ctype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Type is a decoded type. It is implemented by the pointer types of this package.
+type Type interface {
+	// Quals returns the qualifiers and the nullability of the type.
+	Quals() Qualifiers
+	// String writes the type with the Default format.
+	String() string
+
+	qualifiers() *Qualifiers
+}
+
+// Nullability is the nullability of a pointer type. It is 0 for types without nullability.
+type Nullability uint32
+
+const (
+	// Nullability_NonNull is a pointer which is never null, written _Nonnull.
+	Nullability_NonNull Nullability = iota + 1
+	// Nullability_Nullable is a pointer which can be null, written _Nullable.
+	Nullability_Nullable
+	// Nullability_NullableResult is a pointer which can be null, except on success when passed to
+	// a completion handler, written _Nullable_result.
+	Nullability_NullableResult
+	// Nullability_Unspecified is a pointer whose nullability is explicitly unspecified, written
+	// _Null_unspecified.
+	Nullability_Unspecified
+)
+
+func (n Nullability) Spelling() string {
+	switch n {
+	case Nullability_NonNull:
+		return "Nullability=NonNull"
+	case Nullability_Nullable:
+		return "Nullability=Nullable"
+	case Nullability_NullableResult:
+		return "Nullability=NullableResult"
+	case Nullability_Unspecified:
+		return "Nullability=Unspecified"
+	}
+
+	return fmt.Sprintf("Nullability unknown %d", int(n))
+}
+
+func (n Nullability) String() string {
+	return n.Spelling()
+}
+
+// Keyword returns the keyword the nullability is written with, e.g. "_Nonnull".
+func (n Nullability) Keyword() string {
+	switch n {
+	case Nullability_NonNull:
+		return "_Nonnull"
+	case Nullability_Nullable:
+		return "_Nullable"
+	case Nullability_NullableResult:
+		return "_Nullable_result"
+	case Nullability_Unspecified:
+		return "_Null_unspecified"
+	}
+
+	return ""
+}
+
+// Qualifiers are the qualifiers of a type. They are embedded in every type.
+type Qualifiers struct {
+	Const    bool
+	Volatile bool
+	Restrict bool
+	// Nullability is only set for pointers.
+	Nullability Nullability
+}
+
+// Quals returns the qualifiers.
+func (q Qualifiers) Quals() Qualifiers {
+	return q
+}
+
+func (q *Qualifiers) qualifiers() *Qualifiers {
+	return q
+}
+
+// merge returns the union of the qualifiers. The nullability of o takes precedence.
+func (q Qualifiers) merge(o Qualifiers) Qualifiers {
+	q.Const = q.Const || o.Const
+	q.Volatile = q.Volatile || o.Volatile
+	q.Restrict = q.Restrict || o.Restrict
+	if o.Nullability != 0 {
+		q.Nullability = o.Nullability
+	}
+
+	return q
+}
+
+// words returns the cv-qualifiers separated by spaces.
+func (q Qualifiers) words() string {
+	var words []string
+	if q.Const {
+		words = append(words, "const")
+	}
+	if q.Volatile {
+		words = append(words, "volatile")
+	}
+	if q.Restrict {
+		words = append(words, "restrict")
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Decl references the declaration of a record, enumeration or typedef.
+type Decl struct {
+	USR string
+	// Name is empty for unnamed declarations.
+	Name string
+	// QualifiedName is the name qualified with the enclosing namespaces and records, e.g.
+	// "ns::Outer::Inner".
+	QualifiedName string
+	Kind          clang.CursorKind
+	Location      clang.Position
+}
+
+// Builtin is a builtin type like int or void.
+type Builtin struct {
+	Qualifiers
+	Kind clang.TypeKind
+	// Name is the spelling of the type, e.g. "unsigned int".
+	Name string
+}
+
+// Pointer is a pointer, a block pointer or an Objective-C object pointer.
+type Pointer struct {
+	Qualifiers
+	Elem Type
+	// Block is set for block pointers, which are written with ^.
+	Block bool
+}
+
+// Reference is an lvalue or rvalue reference.
+type Reference struct {
+	Qualifiers
+	Elem   Type
+	RValue bool
+}
+
+// MemberPointer is a pointer to a member of the record Class.
+type MemberPointer struct {
+	Qualifiers
+	Class Type
+	Elem  Type
+}
+
+// Array is an array or a vector.
+type Array struct {
+	Qualifiers
+	Elem Type
+	// Len is -1 for arrays of incomplete, variable or dependent length.
+	Len    int64
+	Vector bool
+}
+
+// Function is a function type.
+type Function struct {
+	Qualifiers
+	Result   Type
+	Params   []Type
+	Variadic bool
+	// NoProto is set for functions declared without a prototype, like int f() in C.
+	NoProto bool
+	CC      clang.CallingConv
+}
+
+// Record is a struct, union or class.
+type Record struct {
+	Qualifiers
+	Decl Decl
+	// Args holds the template arguments of a template specialization. Arguments which are not
+	// types are nil.
+	Args []Type
+}
+
+// Enum is an enumeration.
+type Enum struct {
+	Qualifiers
+	Decl Decl
+	// Integer is the underlying integer type, it is nil if it is not known.
+	Integer Type
+}
+
+// Typedef is a typedef or a type alias.
+type Typedef struct {
+	Qualifiers
+	Decl       Decl
+	Underlying Type
+}
+
+// Elaborated is a type written with a keyword or a qualifier, like "struct point" or
+// "ns::Point".
+type Elaborated struct {
+	Qualifiers
+	Named Type
+	// Keyword is the keyword the type is written with, e.g. "struct", or empty.
+	Keyword string
+	// Qualifier is the nested name specifier the type is written with, e.g. "ns::", or empty.
+	Qualifier string
+}
+
+// Other is a type which is not decoded, like a template parameter or auto.
+type Other struct {
+	Qualifiers
+	Kind clang.TypeKind
+	// Spelling is the spelling of the type without its qualifiers.
+	Spelling string
+}
+
+func (t *Builtin) String() string {
+	return Default.String(t)
+}
+
+func (t *Pointer) String() string {
+	return Default.String(t)
+}
+
+func (t *Reference) String() string {
+	return Default.String(t)
+}
+
+func (t *MemberPointer) String() string {
+	return Default.String(t)
+}
+
+func (t *Array) String() string {
+	return Default.String(t)
+}
+
+func (t *Function) String() string {
+	return Default.String(t)
+}
+
+func (t *Record) String() string {
+	return Default.String(t)
+}
+
+func (t *Enum) String() string {
+	return Default.String(t)
+}
+
+func (t *Typedef) String() string {
+	return Default.String(t)
+}
+
+func (t *Elaborated) String() string {
+	return Default.String(t)
+}
+
+func (t *Other) String() string {
+	return Default.String(t)
+}
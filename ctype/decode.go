@@ -0,0 +1,188 @@
+package ctype
+
+import (
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Decode returns the tree of the type t. Nullability is only seen in translation units parsed
+// with clang.TranslationUnit_IncludeAttributedTypes, otherwise the attributes are removed from
+// the types.
+func Decode(t clang.Type) Type {
+	q := Qualifiers{
+		Const:       t.IsConstQualifiedType(),
+		Volatile:    t.IsVolatileQualifiedType(),
+		Restrict:    t.IsRestrictQualifiedType(),
+		Nullability: nullability(t.Nullability()),
+	}
+
+	var d Type
+	switch kind := t.Kind(); kind {
+	case clang.Type_Attributed, clang.Type_BTFTagAttributed:
+		d = Decode(t.ModifiedType())
+		p := d.qualifiers()
+		*p = p.merge(q)
+
+		return d
+	case clang.Type_Pointer, clang.Type_BlockPointer, clang.Type_ObjCObjectPointer:
+		d = &Pointer{
+			Elem:  Decode(t.PointeeType()),
+			Block: kind == clang.Type_BlockPointer,
+		}
+	case clang.Type_LValueReference, clang.Type_RValueReference:
+		d = &Reference{
+			Elem:   Decode(t.PointeeType()),
+			RValue: kind == clang.Type_RValueReference,
+		}
+	case clang.Type_MemberPointer:
+		d = &MemberPointer{
+			Class: Decode(t.ClassType()),
+			Elem:  Decode(t.PointeeType()),
+		}
+	case clang.Type_ConstantArray:
+		d = &Array{Elem: Decode(t.ArrayElementType()), Len: t.ArraySize()}
+	case clang.Type_IncompleteArray, clang.Type_VariableArray, clang.Type_DependentSizedArray:
+		d = &Array{Elem: Decode(t.ArrayElementType()), Len: -1}
+	case clang.Type_Vector, clang.Type_ExtVector:
+		d = &Array{Elem: Decode(t.ElementType()), Len: t.NumElements(), Vector: true}
+	case clang.Type_FunctionProto, clang.Type_FunctionNoProto:
+		f := &Function{
+			Result:  Decode(t.ResultType()),
+			Params:  []Type{},
+			NoProto: kind == clang.Type_FunctionNoProto,
+			CC:      t.FunctionTypeCallingConv(),
+		}
+		for i := 0; i < int(t.NumArgTypes()); i++ {
+			f.Params = append(f.Params, Decode(t.ArgType(uint32(i))))
+		}
+		f.Variadic = !f.NoProto && t.IsFunctionTypeVariadic()
+		d = f
+	case clang.Type_Record:
+		r := &Record{Decl: decl(t.Declaration())}
+		if n := t.NumTemplateArguments(); n > 0 {
+			r.Args = make([]Type, n)
+			for i := range r.Args {
+				if arg := t.TemplateArgumentAsType(uint32(i)); arg.Kind() != clang.Type_Invalid {
+					r.Args[i] = Decode(arg)
+				}
+			}
+		}
+		d = r
+	case clang.Type_Enum:
+		c := t.Declaration()
+		e := &Enum{Decl: decl(c)}
+		if it := c.EnumDeclIntegerType(); it.Kind() != clang.Type_Invalid {
+			e.Integer = Decode(it)
+		}
+		d = e
+	case clang.Type_Typedef:
+		c := t.Declaration()
+		d = &Typedef{
+			Decl:       decl(c),
+			Underlying: Decode(c.TypedefDeclUnderlyingType()),
+		}
+	case clang.Type_Elaborated:
+		e := &Elaborated{Named: Decode(t.NamedType())}
+		e.Keyword, e.Qualifier = elaboration(unqualified(t.Spelling()), e.Named)
+		d = e
+	default:
+		if kind >= clang.Type_FirstBuiltin && kind <= clang.Type_LastBuiltin {
+			d = &Builtin{Kind: kind, Name: unqualified(t.Spelling())}
+		} else {
+			d = &Other{Kind: kind, Spelling: unqualified(t.Spelling())}
+		}
+	}
+
+	*d.qualifiers() = q
+
+	return d
+}
+
+func nullability(n clang.TypeNullabilityKind) Nullability {
+	switch n {
+	case clang.TypeNullability_NonNull:
+		return Nullability_NonNull
+	case clang.TypeNullability_Nullable:
+		return Nullability_Nullable
+	case clang.TypeNullability_NullableResult:
+		return Nullability_NullableResult
+	case clang.TypeNullability_Unspecified:
+		return Nullability_Unspecified
+	}
+
+	return 0
+}
+
+// decl returns the reference to the declaration c.
+func decl(c clang.Cursor) Decl {
+	if c.IsNull() || c.Kind() == clang.Cursor_NoDeclFound {
+		return Decl{}
+	}
+
+	d := Decl{
+		USR:      c.USR(),
+		Name:     name(c),
+		Kind:     c.Kind(),
+		Location: c.Location().Position(),
+	}
+
+	if d.Name != "" {
+		d.QualifiedName = c.QualifiedName()
+	}
+
+	return d
+}
+
+// name returns the name of the declaration c, or "" if it is unnamed.
+func name(c clang.Cursor) string {
+	if c.IsUnnamed() {
+		return ""
+	}
+
+	return c.Spelling()
+}
+
+// unqualified removes the leading cv-qualifiers from the spelling of a type.
+func unqualified(spelling string) string {
+	for {
+		rest := spelling
+		for _, q := range []string{"const ", "volatile ", "restrict "} {
+			rest = strings.TrimPrefix(rest, q)
+		}
+		if rest == spelling {
+			return spelling
+		}
+		spelling = rest
+	}
+}
+
+// elaboration returns the keyword and the nested name specifier of the spelling of an
+// elaborated type naming the type named.
+func elaboration(spelling string, named Type) (keyword, qualifier string) {
+	for _, k := range []string{"struct", "union", "class", "enum", "typename"} {
+		if strings.HasPrefix(spelling, k+" ") {
+			keyword, spelling = k, spelling[len(k)+1:]
+
+			break
+		}
+	}
+
+	var n string
+	switch named := named.(type) {
+	case *Record:
+		n = named.Decl.Name
+	case *Enum:
+		n = named.Decl.Name
+	case *Typedef:
+		n = named.Decl.Name
+	}
+	if i := strings.IndexByte(spelling, '<'); i >= 0 {
+		spelling = spelling[:i]
+	}
+	if q := strings.TrimSuffix(spelling, n); n != "" && q != spelling && (q == "" || strings.HasSuffix(q, "::")) {
+		qualifier = q
+	}
+
+	return keyword, qualifier
+}
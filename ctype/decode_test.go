@@ -0,0 +1,93 @@
+package ctype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// plain returns the type named by an elaborated type without keyword and qualifier, which
+// newer versions of libclang report for every named type.
+func plain(t Type) Type {
+	if e, ok := t.(*Elaborated); ok && e.Keyword == "" && e.Qualifier == "" {
+		return e.Named
+	}
+
+	return t
+}
+
+func TestDecode(t *testing.T) {
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/types.c", nil, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+
+	vars := map[string]Type{}
+	tu.TranslationUnitCursor().Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if c.Kind() == clang.Cursor_VarDecl {
+			vars[c.Spelling()] = Decode(c.Type())
+		}
+
+		return clang.ChildVisit_Continue
+	})
+	// The decoded types must not depend on the translation unit.
+	tu.Dispose()
+
+	for name, want := range map[string]string{
+		"origin":  "const struct point *",
+		"table":   "int[4]",
+		"handler": "int (*)(const char *, ...)",
+		"corner":  "point_t",
+		"paint":   "enum color",
+	} {
+		if got := vars[name]; got == nil || got.String() != want {
+			t.Errorf("expected %s to be %q. got=%v", name, want, got)
+		}
+	}
+
+	origin, ok := vars["origin"].(*Pointer)
+	if !ok {
+		t.Fatalf("expected a pointer. got=%#v", vars["origin"])
+	}
+	elab, ok := origin.Elem.(*Elaborated)
+	if !ok || elab.Keyword != "struct" || !elab.Const {
+		t.Fatalf("expected const struct point. got=%#v", origin.Elem)
+	}
+	if r, ok := elab.Named.(*Record); !ok || r.Decl.QualifiedName != "point" || r.Decl.Kind != clang.Cursor_StructDecl || r.Decl.Location.Line != 1 {
+		t.Errorf("expected the record point declared on line 1. got=%#v", elab.Named)
+	}
+
+	if a, ok := vars["table"].(*Array); !ok || a.Len != 4 || a.Vector || !reflect.DeepEqual(a.Elem, &Builtin{Kind: clang.Type_Int, Name: "int"}) {
+		t.Errorf("expected an array of 4 int. got=%#v", vars["table"])
+	}
+
+	handler, ok := vars["handler"].(*Pointer)
+	if !ok {
+		t.Fatalf("expected a function pointer. got=%#v", vars["handler"])
+	}
+	if f, ok := handler.Elem.(*Function); !ok || !f.Variadic || f.NoProto || len(f.Params) != 1 {
+		t.Errorf("expected a variadic function with one parameter. got=%#v", handler.Elem)
+	} else if p, ok := f.Params[0].(*Pointer); !ok || !reflect.DeepEqual(p.Elem, &Builtin{Qualifiers: Qualifiers{Const: true}, Kind: clang.Type_Char_S, Name: "char"}) {
+		t.Errorf("expected a const char * parameter. got=%#v", f.Params[0])
+	}
+
+	if td, ok := plain(vars["corner"]).(*Typedef); !ok || td.Decl.Name != "point_t" || td.Decl.Kind != clang.Cursor_TypedefDecl {
+		t.Errorf("expected the typedef point_t. got=%#v", vars["corner"])
+	} else if u, ok := td.Underlying.(*Elaborated); !ok || u.Keyword != "struct" {
+		t.Errorf("expected point_t to name struct point. got=%#v", td.Underlying)
+	} else if r, ok := u.Named.(*Record); !ok || r.Decl.QualifiedName != "point" {
+		t.Errorf("expected point_t to name the record point. got=%#v", u.Named)
+	}
+
+	paint, ok := vars["paint"].(*Elaborated)
+	if !ok || paint.Keyword != "enum" {
+		t.Fatalf("expected enum color. got=%#v", vars["paint"])
+	}
+	if e, ok := paint.Named.(*Enum); !ok || e.Decl.Name != "color" || e.Integer == nil || e.Integer.String() != "unsigned int" {
+		t.Errorf("expected the enumeration color of unsigned int. got=%#v", paint.Named)
+	}
+}
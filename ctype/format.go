@@ -0,0 +1,211 @@
+package ctype
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Qualification is how Format qualifies the names of declarations.
+type Qualification uint32
+
+const (
+	// Qualification_AsWritten qualifies names as they are written in the source, which is only
+	// known for elaborated types.
+	Qualification_AsWritten Qualification = iota + 1
+	// Qualification_None writes names without their namespaces and enclosing records.
+	Qualification_None
+	// Qualification_Full qualifies names with all their namespaces and enclosing records.
+	Qualification_Full
+)
+
+func (q Qualification) Spelling() string {
+	switch q {
+	case Qualification_AsWritten:
+		return "Qualification=AsWritten"
+	case Qualification_None:
+		return "Qualification=None"
+	case Qualification_Full:
+		return "Qualification=Full"
+	}
+
+	return fmt.Sprintf("Qualification unknown %d", int(q))
+}
+
+func (q Qualification) String() string {
+	return q.Spelling()
+}
+
+// Format controls how types are written.
+type Format struct {
+	// Qualification defaults to Qualification_AsWritten.
+	Qualification Qualification
+	// Desugar writes typedefs as their underlying types.
+	Desugar bool
+	// Nullability writes the nullability of pointers, e.g. "int * _Nonnull".
+	Nullability bool
+}
+
+// Default is the format of the String methods of the types.
+var Default = Format{Qualification: Qualification_AsWritten, Nullability: true}
+
+// String writes the type t as an abstract declarator, e.g. "int (*)(const char *, ...)".
+func (f Format) String(t Type) string {
+	return f.declarator(t, Qualifiers{}, "")
+}
+
+// Declaration writes a declaration of name with the type t, e.g. "char *argv[]".
+func (f Format) Declaration(t Type, name string) string {
+	return f.declarator(t, Qualifiers{}, name)
+}
+
+// declarator writes the type t with the additional qualifiers around the declarator inner.
+func (f Format) declarator(t Type, extra Qualifiers, inner string) string {
+	if t == nil {
+		return join("?", inner)
+	}
+	q := t.Quals().merge(extra)
+
+	switch t := t.(type) {
+	case *Pointer:
+		op := "*"
+		if t.Block {
+			op = "^"
+		}
+
+		return f.declarator(t.Elem, Qualifiers{}, f.pointer(op, q, inner, t.Elem))
+	case *Reference:
+		op := "&"
+		if t.RValue {
+			op = "&&"
+		}
+
+		return f.declarator(t.Elem, Qualifiers{}, f.pointer(op, Qualifiers{}, inner, t.Elem))
+	case *MemberPointer:
+		return f.declarator(t.Elem, Qualifiers{}, f.pointer(f.String(t.Class)+"::*", q, inner, t.Elem))
+	case *Array:
+		n := ""
+		if t.Len >= 0 {
+			n = strconv.FormatInt(t.Len, 10)
+		}
+		if t.Vector {
+			return f.declarator(t.Elem, q, join("__attribute__((ext_vector_type("+n+")))", inner))
+		}
+
+		// The qualifiers of an array apply to its elements.
+		return f.declarator(t.Elem, q, inner+"["+n+"]")
+	case *Function:
+		params := make([]string, 0, len(t.Params)+1)
+		for _, p := range t.Params {
+			params = append(params, f.String(p))
+		}
+		switch {
+		case t.Variadic:
+			params = append(params, "...")
+		case len(params) == 0 && !t.NoProto:
+			params = append(params, "void")
+		}
+
+		return f.declarator(t.Result, Qualifiers{}, inner+"("+strings.Join(params, ", ")+")")
+	case *Typedef:
+		if f.Desugar && t.Underlying != nil {
+			return f.declarator(t.Underlying, q, inner)
+		}
+	case *Elaborated:
+		if td, ok := t.Named.(*Typedef); ok && f.Desugar && td.Underlying != nil {
+			return f.declarator(td.Underlying, q, inner)
+		}
+	}
+
+	s := f.name(t)
+	if w := q.words(); w != "" {
+		s = w + " " + s
+	}
+
+	return join(s, inner)
+}
+
+// pointer returns the declarator inner prefixed with the pointer operator op and its
+// qualifiers. Pointers to arrays and functions are parenthesized.
+func (f Format) pointer(op string, q Qualifiers, inner string, elem Type) string {
+	s := op + q.words()
+	if f.Nullability && q.Nullability != 0 {
+		s += " " + q.Nullability.Keyword()
+	}
+	if inner != "" && s != op {
+		s += " "
+	}
+	s += inner
+
+	switch elem.(type) {
+	case *Array, *Function:
+		s = "(" + s + ")"
+	}
+
+	return s
+}
+
+// name returns the name of the named type t without its qualifiers.
+func (f Format) name(t Type) string {
+	switch t := t.(type) {
+	case *Builtin:
+		return t.Name
+	case *Record:
+		s := f.declName(t.Decl)
+		if len(t.Args) > 0 {
+			args := make([]string, len(t.Args))
+			for i, a := range t.Args {
+				args[i] = "?"
+				if a != nil {
+					args[i] = f.String(a)
+				}
+			}
+			s += "<" + strings.Join(args, ", ") + ">"
+		}
+
+		return s
+	case *Enum:
+		return f.declName(t.Decl)
+	case *Typedef:
+		return f.declName(t.Decl)
+	case *Elaborated:
+		s := ""
+		if t.Keyword != "" {
+			s = t.Keyword + " "
+		}
+		if f.Qualification == 0 || f.Qualification == Qualification_AsWritten {
+			s += t.Qualifier
+		}
+
+		switch t.Named.(type) {
+		case *Builtin, *Record, *Enum, *Typedef, *Other:
+			return s + f.name(t.Named)
+		}
+
+		return s + f.String(t.Named)
+	case *Other:
+		return t.Spelling
+	}
+
+	return f.String(t)
+}
+
+func (f Format) declName(d Decl) string {
+	if f.Qualification == Qualification_Full && d.QualifiedName != "" {
+		return d.QualifiedName
+	}
+	if d.Name == "" {
+		return "(unnamed)"
+	}
+
+	return d.Name
+}
+
+// join appends the declarator inner to the type s.
+func join(s, inner string) string {
+	if inner == "" || strings.HasPrefix(inner, "[") {
+		return s + inner
+	}
+
+	return s + " " + inner
+}
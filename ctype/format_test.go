@@ -0,0 +1,117 @@
+package ctype
+
+import (
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func builtin(name string, q Qualifiers) *Builtin {
+	return &Builtin{Qualifiers: q, Kind: clang.Type_Int, Name: name}
+}
+
+func TestString(t *testing.T) {
+	int_ := builtin("int", Qualifiers{})
+	char := builtin("char", Qualifiers{})
+	constChar := builtin("char", Qualifiers{Const: true})
+
+	point := &Elaborated{
+		Keyword: "struct",
+		Named:   &Record{Decl: Decl{Name: "point", QualifiedName: "point", Kind: clang.Cursor_StructDecl}},
+	}
+
+	for _, tt := range []struct {
+		typ  Type
+		want string
+	}{
+		{int_, "int"},
+		{&Pointer{Elem: constChar}, "const char *"},
+		{&Pointer{Qualifiers: Qualifiers{Const: true}, Elem: char}, "char *const"},
+		{&Pointer{Elem: &Pointer{Qualifiers: Qualifiers{Const: true}, Elem: char}}, "char *const *"},
+		{&Pointer{Qualifiers: Qualifiers{Nullability: Nullability_NonNull}, Elem: point}, "struct point * _Nonnull"},
+		{&Array{Elem: &Pointer{Elem: char}, Len: 4}, "char *[4]"},
+		{&Pointer{Elem: &Array{Elem: int_, Len: 4}}, "int (*)[4]"},
+		{&Array{Qualifiers: Qualifiers{Const: true}, Elem: int_, Len: -1}, "const int[]"},
+		{&Pointer{Elem: &Function{Result: int_, Params: []Type{&Pointer{Elem: constChar}}, Variadic: true}},
+			"int (*)(const char *, ...)"},
+		{&Function{Result: builtin("void", Qualifiers{})}, "void (void)"},
+		{&Function{Result: int_, NoProto: true}, "int ()"},
+		{&Pointer{Elem: &Function{Result: &Pointer{Elem: &Function{Result: int_, Params: []Type{int_}}}, Params: []Type{char}}},
+			"int (*(*)(char))(int)"},
+		{&Reference{Elem: constChar, RValue: true}, "const char &&"},
+		{&MemberPointer{Class: &Record{Decl: Decl{Name: "S"}}, Elem: int_}, "int S::*"},
+		{&Pointer{Block: true, Elem: &Function{Result: int_, Params: []Type{int_}}}, "int (^)(int)"},
+		{&Array{Elem: builtin("float", Qualifiers{}), Len: 4, Vector: true}, "float __attribute__((ext_vector_type(4)))"},
+	} {
+		if got := tt.typ.String(); got != tt.want {
+			t.Errorf("String = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	size := &Typedef{
+		Decl:       Decl{Name: "size_t", QualifiedName: "std::size_t"},
+		Underlying: builtin("unsigned long", Qualifiers{}),
+	}
+	vector := &Record{
+		Decl: Decl{Name: "vector", QualifiedName: "std::vector", Kind: clang.Cursor_ClassDecl},
+		Args: []Type{&Elaborated{Qualifier: "std::", Named: size}, nil},
+	}
+	typ := &Reference{Elem: &Elaborated{
+		Qualifiers: Qualifiers{Const: true},
+		Qualifier:  "std::",
+		Named:      vector,
+	}}
+
+	for _, tt := range []struct {
+		format Format
+		want   string
+	}{
+		{Format{}, "const std::vector<std::size_t, ?> &"},
+		{Format{Qualification: Qualification_None}, "const vector<size_t, ?> &"},
+		{Format{Qualification: Qualification_Full}, "const std::vector<std::size_t, ?> &"},
+		{Format{Qualification: Qualification_None, Desugar: true}, "const vector<unsigned long, ?> &"},
+	} {
+		if got := tt.format.String(typ); got != tt.want {
+			t.Errorf("%+v: String = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	argv := &Array{Elem: &Pointer{Elem: builtin("char", Qualifiers{})}, Len: -1}
+	if got, want := Default.Declaration(argv, "argv"), "char *argv[]"; got != want {
+		t.Errorf("Declaration = %q, want %q", got, want)
+	}
+	nullable := &Pointer{Qualifiers: Qualifiers{Nullability: Nullability_Nullable}, Elem: builtin("int", Qualifiers{})}
+	if got, want := (Format{}).Declaration(nullable, "p"), "int *p"; got != want {
+		t.Errorf("Declaration without nullability = %q, want %q", got, want)
+	}
+}
+
+func TestElaboration(t *testing.T) {
+	named := func(name string) Type {
+		return &Record{Decl: Decl{Name: name}}
+	}
+
+	for _, tt := range []struct {
+		spelling           string
+		named              Type
+		keyword, qualifier string
+	}{
+		{"struct point", named("point"), "struct", ""},
+		{"ns::Foo", named("Foo"), "", "ns::"},
+		{"Foo::Foo", named("Foo"), "", "Foo::"},
+		{"ns::MyFoo", named("Foo"), "", ""},
+		{"class a::b::Vec<a::b::Vec<int>>", named("Vec"), "class", "a::b::"},
+		{"struct (unnamed at x.c:1:1)", named(""), "struct", ""},
+	} {
+		keyword, qualifier := elaboration(tt.spelling, tt.named)
+		if keyword != tt.keyword || qualifier != tt.qualifier {
+			t.Errorf("elaboration(%q) = %q, %q, want %q, %q", tt.spelling, keyword, qualifier, tt.keyword, tt.qualifier)
+		}
+	}
+
+	if got := unqualified("const volatile int"); got != "int" {
+		t.Errorf("unqualified = %q, want int", got)
+	}
+}
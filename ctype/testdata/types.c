@@ -0,0 +1,9 @@
+struct point { int x, y; };
+enum color { RED, GREEN };
+typedef struct point point_t;
+
+const struct point *origin;
+int table[4];
+int (*handler)(const char *, ...);
+point_t corner;
+enum color paint;
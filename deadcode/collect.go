@@ -0,0 +1,167 @@
+package deadcode
+
+import (
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Collector collects the symbols of translation units. System headers are skipped.
+type Collector struct {
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Collect returns the symbols defined and referenced by the translation unit. The translation
+// unit must be parsed with clang.TranslationUnit_DetailedPreprocessingRecord to see macros.
+func (c Collector) Collect(tu clang.TranslationUnit) *Unit {
+	w := walker{
+		Collector: c,
+		unit:      &Unit{References: map[string]bool{}},
+		defined:   map[string]int{},
+		header:    map[string]bool{},
+		paths:     compdb.Paths{Directory: c.Directory},
+	}
+	w.unit.Source = w.paths.Abs(tu.Spelling())
+
+	tu.TranslationUnitCursor().Visit(func(cursor, _ clang.Cursor) clang.ChildVisitResult {
+		if !cursor.Location().IsInSystemHeader() {
+			w.visit(cursor)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	for i := range w.unit.Symbols {
+		s := &w.unit.Symbols[i]
+		s.Header = s.Header || w.header[s.USR]
+	}
+
+	return w.unit
+}
+
+type walker struct {
+	Collector
+
+	unit *Unit
+	// defined maps the USRs of the collected symbols to their index.
+	defined map[string]int
+	// header holds the USRs declared in headers.
+	header map[string]bool
+	paths  compdb.Paths
+	// definitions is the stack of USRs of the definitions enclosing the current cursor.
+	// References from a definition to itself do not count.
+	definitions []string
+}
+
+func (w *walker) visit(c clang.Cursor) {
+	kind := c.Kind()
+
+	pushed := false
+	switch {
+	case kind.IsDeclaration(), kind == clang.Cursor_MacroDefinition:
+		if usr := w.declaration(c); usr != "" {
+			w.definitions = append(w.definitions, usr)
+			pushed = true
+		}
+	case kind.IsReference(), kind.IsExpression(), kind == clang.Cursor_MacroExpansion:
+		w.reference(c)
+	}
+
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		w.visit(child)
+
+		return clang.ChildVisit_Continue
+	})
+
+	if pushed {
+		w.definitions = w.definitions[:len(w.definitions)-1]
+	}
+}
+
+// declaration records the declaration c and returns its USR if it is the definition of a
+// candidate symbol.
+func (w *walker) declaration(c clang.Cursor) string {
+	usr := c.USR()
+	if usr == "" {
+		return ""
+	}
+
+	if !c.Location().IsFromMainFile() {
+		w.header[usr] = true
+	}
+
+	if !w.candidate(c) {
+		return ""
+	}
+	if _, ok := w.defined[usr]; !ok {
+		file, line, column, offset := c.Location().ExpansionLocation()
+		w.defined[usr] = len(w.unit.Symbols)
+		w.unit.Symbols = append(w.unit.Symbols, Symbol{
+			USR:  usr,
+			Name: c.Spelling(),
+			Kind: c.Kind(),
+			Definition: clang.Position{
+				File:   w.paths.Abs(file.Name()),
+				Line:   line,
+				Column: column,
+				Offset: offset,
+			},
+			Linkage:    c.Linkage(),
+			Visibility: c.Visibility(),
+		})
+	}
+
+	return usr
+}
+
+// candidate reports whether the declaration c is a definition which is reported if unused.
+func (w *walker) candidate(c clang.Cursor) bool {
+	switch c.Kind() {
+	case clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate:
+		return c.IsCursorDefinition() && c.Spelling() != "main"
+	case clang.Cursor_CXXMethod:
+		// Virtual methods are called through their base methods.
+		return c.IsCursorDefinition() && !c.CXXMethod_IsVirtual()
+	case clang.Cursor_VarDecl:
+		switch c.SemanticParent().Kind() {
+		case clang.Cursor_TranslationUnit, clang.Cursor_Namespace, clang.Cursor_StructDecl, clang.Cursor_ClassDecl:
+			return c.IsCursorDefinition()
+		}
+	case clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_UnionDecl, clang.Cursor_EnumDecl,
+		clang.Cursor_ClassTemplate:
+		return c.IsCursorDefinition() && !c.IsAnonymous()
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		return true
+	case clang.Cursor_MacroDefinition:
+		// Macros without a body, like include guards, are mostly used by preprocessor
+		// conditionals, which do not count as references.
+		return !c.IsMacroBuiltin() && c.HasMacroBody()
+	}
+
+	return false
+}
+
+func (w *walker) reference(c clang.Cursor) {
+	ref := c.Referenced()
+	if ref.IsNull() || ref.Equal(c) {
+		return
+	}
+
+	usr := ref.USR()
+	if usr == "" {
+		return
+	}
+	for _, d := range w.definitions {
+		if d == usr {
+			return
+		}
+	}
+	w.unit.References[usr] = true
+
+	// Using an enumerator uses its enumeration.
+	if ref.Kind() == clang.Cursor_EnumConstantDecl {
+		if usr := ref.SemanticParent().USR(); usr != "" {
+			w.unit.References[usr] = true
+		}
+	}
+}
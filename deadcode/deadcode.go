@@ -0,0 +1,167 @@
+// Package deadcode finds functions, global variables, macros and types which are defined but
+// never referenced in a project.
+//
+// Symbols only visible in one translation unit, such as static functions and types defined in
+// a source file, are unused if that translation unit does not reference them. All other
+// symbols are unused if no translation unit of the project references them. Symbols which are
+// declared in headers with default or protected visibility are considered exported API and are
+// only reported on request.
+package deadcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Category is the category of an unused symbol.
+type Category uint32
+
+const (
+	// Category_Local is a symbol only visible in its translation unit, e.g. a static function,
+	// which the translation unit does not use.
+	Category_Local Category = iota + 1
+	// Category_Project is a symbol with external linkage which is not part of the exported API
+	// and which no translation unit uses.
+	Category_Project
+	// Category_Exported is a symbol of the exported API which no translation unit uses.
+	Category_Exported
+)
+
+func (c Category) Spelling() string {
+	switch c {
+	case Category_Local:
+		return "Category=Local"
+	case Category_Project:
+		return "Category=Project"
+	case Category_Exported:
+		return "Category=Exported"
+	}
+
+	return fmt.Sprintf("Category unknown %d", int(c))
+}
+
+func (c Category) String() string {
+	return c.Spelling()
+}
+
+// MarshalText returns the name of the category without prefix, e.g. "Local".
+func (c Category) MarshalText() ([]byte, error) {
+	return []byte(strings.TrimPrefix(c.Spelling(), "Category=")), nil
+}
+
+// Symbol is a definition of a function, global variable, macro or type.
+type Symbol struct {
+	USR  string
+	Name string
+	Kind clang.CursorKind
+	// Definition is the position of the name in the definition.
+	Definition clang.Position
+	Linkage    clang.LinkageKind
+	Visibility clang.VisibilityKind
+	// Header is set if the symbol is declared or defined in a header, i.e. outside of the main
+	// file of the translation unit.
+	Header bool
+}
+
+// isLocal reports whether the symbol is only visible in its translation unit.
+func (s *Symbol) isLocal() bool {
+	return !s.Header && (s.Linkage != clang.Linkage_External || !isFunctionOrVariable(s.Kind))
+}
+
+// isExported reports whether the symbol is part of the exported API.
+func (s *Symbol) isExported() bool {
+	if !s.Header {
+		return false
+	}
+	if isFunctionOrVariable(s.Kind) && s.Linkage != clang.Linkage_External {
+		return false
+	}
+
+	return s.Visibility != clang.Visibility_Hidden
+}
+
+// Unit holds the symbols defined and referenced by a translation unit.
+type Unit struct {
+	// Source is the main file of the translation unit.
+	Source     string
+	Symbols    []Symbol
+	References map[string]bool
+}
+
+// Finding is an unused symbol.
+type Finding struct {
+	Symbol
+	Category Category
+	// Unit is the main file of the translation unit of a local symbol.
+	Unit string
+}
+
+// Find returns the unused symbols of the translation units sorted by the position of their
+// definitions. Exported symbols are only returned if exported is set.
+func Find(units []*Unit, exported bool) []Finding {
+	used := map[string]bool{}
+	for _, u := range units {
+		for usr := range u.References {
+			used[usr] = true
+		}
+	}
+
+	var findings []Finding
+	seen := map[string]bool{}
+
+	for _, u := range units {
+		for _, s := range u.Symbols {
+			f := Finding{Symbol: s}
+
+			switch {
+			case s.isLocal():
+				if u.References[s.USR] {
+					continue
+				}
+				f.Category = Category_Local
+				f.Unit = u.Source
+			case used[s.USR]:
+				continue
+			case s.Header && s.Linkage != clang.Linkage_External && isFunctionOrVariable(s.Kind):
+				// Static functions in headers are unused if no includer uses them.
+				f.Category = Category_Local
+			case s.isExported():
+				if !exported {
+					continue
+				}
+				f.Category = Category_Exported
+			default:
+				f.Category = Category_Project
+			}
+
+			key := f.Unit + "\x00" + s.USR
+			if !seen[key] {
+				seen[key] = true
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		p, q := findings[i].Definition, findings[j].Definition
+		if p.File != q.File {
+			return p.File < q.File
+		}
+
+		return p.Before(q)
+	})
+
+	return findings
+}
+
+func isFunctionOrVariable(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_FunctionDecl, clang.Cursor_FunctionTemplate, clang.Cursor_CXXMethod, clang.Cursor_VarDecl:
+		return true
+	}
+
+	return false
+}
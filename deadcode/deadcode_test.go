@@ -0,0 +1,139 @@
+package deadcode
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func symbol(usr string, kind clang.CursorKind, file string, line uint32, linkage clang.LinkageKind, header bool) Symbol {
+	return Symbol{
+		USR:        usr,
+		Name:       usr,
+		Kind:       kind,
+		Definition: clang.Position{File: file, Line: line, Column: 1},
+		Linkage:    linkage,
+		Visibility: clang.Visibility_Default,
+		Header:     header,
+	}
+}
+
+func TestFind(t *testing.T) {
+	// lib.h declares api and api_unused, and defines the static inline helper.
+	helper := symbol("helper", clang.Cursor_FunctionDecl, "/p/lib.h", 3, clang.Linkage_Internal, true)
+	api := symbol("api", clang.Cursor_FunctionDecl, "/p/lib.c", 10, clang.Linkage_External, true)
+	apiUnused := symbol("api_unused", clang.Cursor_FunctionDecl, "/p/lib.c", 20, clang.Linkage_External, true)
+	hidden := symbol("hidden_unused", clang.Cursor_FunctionDecl, "/p/lib.c", 30, clang.Linkage_External, true)
+	hidden.Visibility = clang.Visibility_Hidden
+
+	a := &Unit{
+		Source: "/p/lib.c",
+		Symbols: []Symbol{
+			helper,
+			api,
+			apiUnused,
+			hidden,
+			symbol("internal", clang.Cursor_FunctionDecl, "/p/lib.c", 5, clang.Linkage_External, false),
+			symbol("static_unused", clang.Cursor_FunctionDecl, "/p/lib.c", 6, clang.Linkage_Internal, false),
+			symbol("static_used", clang.Cursor_FunctionDecl, "/p/lib.c", 7, clang.Linkage_Internal, false),
+			symbol("c:@S@node", clang.Cursor_StructDecl, "/p/lib.c", 1, clang.Linkage_External, false),
+			symbol("c:lib.c@8@macro@MAX", clang.Cursor_MacroDefinition, "/p/lib.c", 2, clang.Linkage_NoLinkage, false),
+		},
+		References: map[string]bool{"static_used": true, "c:@S@node": true},
+	}
+
+	b := &Unit{
+		Source: "/p/main.c",
+		Symbols: []Symbol{
+			helper,
+			symbol("c:@S@node", clang.Cursor_StructDecl, "/p/main.c", 1, clang.Linkage_External, false),
+			symbol("used_elsewhere", clang.Cursor_VarDecl, "/p/main.c", 3, clang.Linkage_External, false),
+		},
+		References: map[string]bool{"api": true, "internal": true},
+	}
+
+	c := &Unit{
+		Source:     "/p/other.c",
+		Symbols:    []Symbol{symbol("used_elsewhere", clang.Cursor_VarDecl, "/p/main.c", 3, clang.Linkage_External, false)},
+		References: map[string]bool{"used_elsewhere": true},
+	}
+
+	type result struct {
+		USR      string
+		Category Category
+		Unit     string
+	}
+	results := func(findings []Finding) []result {
+		var rs []result
+		for _, f := range findings {
+			rs = append(rs, result{f.USR, f.Category, f.Unit})
+		}
+
+		return rs
+	}
+
+	want := []result{
+		{"c:lib.c@8@macro@MAX", Category_Local, "/p/lib.c"},
+		{"static_unused", Category_Local, "/p/lib.c"},
+		{"hidden_unused", Category_Project, ""},
+		{"helper", Category_Local, ""},
+		{"c:@S@node", Category_Local, "/p/main.c"},
+	}
+	if got := results(Find([]*Unit{a, b, c}, false)); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected findings\n%+v\ngot\n%+v", want, got)
+	}
+
+	want = []result{
+		{"c:lib.c@8@macro@MAX", Category_Local, "/p/lib.c"},
+		{"static_unused", Category_Local, "/p/lib.c"},
+		{"api_unused", Category_Exported, ""},
+		{"hidden_unused", Category_Project, ""},
+		{"helper", Category_Local, ""},
+		{"c:@S@node", Category_Local, "/p/main.c"},
+	}
+	if got := results(Find([]*Unit{a, b, c}, true)); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected findings with exported symbols\n%+v\ngot\n%+v", want, got)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/unused.c", nil, nil, clang.TranslationUnit_DetailedPreprocessingRecord)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	u := Collector{Directory: dir}.Collect(tu)
+	source := filepath.Join(dir, "testdata", "unused.c")
+	if u.Source != source {
+		t.Errorf("expected source %s. got=%s", source, u.Source)
+	}
+
+	var names []string
+	for _, s := range u.Symbols {
+		names = append(names, s.Name)
+	}
+	if want := []string{"used", "unused"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected the static functions %v as candidates. got=%v", want, names)
+	}
+
+	findings := Find([]*Unit{u}, false)
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding. got=%+v", findings)
+	}
+	f := findings[0]
+	if f.Name != "unused" || f.Category != Category_Local || f.Unit != source || f.Definition.Line != 5 {
+		t.Errorf("expected unused to be reported at %s:5. got=%+v", source, f)
+	}
+}
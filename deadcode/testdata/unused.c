@@ -0,0 +1,11 @@
+static int used(int x) {
+	return x + 1;
+}
+
+static int unused(int x) {
+	return x > 0 ? unused(x - 1) : used(x);
+}
+
+int main(void) {
+	return used(1);
+}
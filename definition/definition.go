@@ -0,0 +1,97 @@
+// Package definition finds the definitions of declarations across the translation units of a
+// compilation database. clang.Cursor.Definition only searches the translation unit of the
+// cursor, so it returns a null cursor for the common case of a function declared in a header
+// and defined in another source file.
+package definition
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Candidate is a definition of a symbol. It is detached from libclang, so it stays valid after
+// the translation unit it was found in has been disposed.
+type Candidate struct {
+	USR  string
+	Name string
+	Kind clang.CursorKind
+	// Position is the position of the name of the definition.
+	Position clang.Position
+	// Extent is the span of the complete definition.
+	Extent clang.Span
+	// Source is the source file of the translation unit the definition was found in.
+	Source string
+}
+
+// table holds the definitions of a translation unit by USR.
+type table map[string][]Candidate
+
+// add adds the candidates to the list unless a candidate at the same position is already part
+// of it. Definitions in headers are found in every translation unit including them, but they
+// are reported only once.
+func add(list []Candidate, cands ...Candidate) []Candidate {
+	for _, c := range cands {
+		dup := false
+		for _, o := range list {
+			if o.Position.File == c.Position.File && o.Position.Offset == c.Position.Offset {
+				dup = true
+
+				break
+			}
+		}
+		if !dup {
+			list = append(list, c)
+		}
+	}
+
+	return list
+}
+
+// rank returns the commands ordered by how likely their translation units define the symbols
+// declared in the file decl: sources with the same base name come first, e.g. foo.c for foo.h,
+// followed by the other sources in the directory of decl and then the sources of the nearest
+// directories.
+func rank(cmds []compdb.Command, decl string) []compdb.Command {
+	type ranked struct {
+		cmd  compdb.Command
+		path string
+		stem bool
+		dist int
+	}
+
+	dir := filepath.Dir(decl)
+	name := compdb.Stem(decl)
+
+	rs := make([]ranked, len(cmds))
+	for i, cmd := range cmds {
+		path := cmd.Path()
+		rs[i] = ranked{
+			cmd:  cmd,
+			path: path,
+			stem: compdb.Stem(path) == name,
+			dist: compdb.Distance(dir, filepath.Dir(path)),
+		}
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.stem != b.stem {
+			return a.stem
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+
+		return a.path < b.path
+	})
+
+	sorted := make([]compdb.Command, len(rs))
+	for i, r := range rs {
+		sorted[i] = r.cmd
+	}
+
+	return sorted
+}
@@ -0,0 +1,142 @@
+package definition
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+func TestRank(t *testing.T) {
+	cmds := []compdb.Command{
+		{Directory: "/p", Filename: "lib/z.c"},
+		{Directory: "/p", Filename: "app/main.c"},
+		{Directory: "/p", Filename: "lib/a.c"},
+		{Directory: "/p", Filename: "other/foo.c"},
+		{Directory: "/p", Filename: "lib/sub/b.c"},
+	}
+
+	var got []string
+	for _, cmd := range rank(cmds, "/p/lib/foo.h") {
+		got = append(got, cmd.Path())
+	}
+
+	want := []string{"/p/other/foo.c", "/p/lib/a.c", "/p/lib/z.c", "/p/lib/sub/b.c", "/p/app/main.c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rank = %v, want %v", got, want)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	inline := Candidate{USR: "c:@F@f", Position: clang.Position{File: "/p/a.h", Line: 3, Column: 13, Offset: 40}, Source: "/p/a.c"}
+	again := inline
+	again.Source = "/p/b.c"
+	other := Candidate{USR: "c:@F@f", Position: clang.Position{File: "/p/c.c", Line: 1, Column: 6, Offset: 5}, Source: "/p/c.c"}
+
+	got := add(nil, inline, again, other)
+	if len(got) != 2 || got[0] != inline || got[1] != other {
+		t.Errorf("add = %v, want [%v %v]", got, inline, other)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	// The commands run in dir, one level above the sources.
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var cmds []compdb.Command
+	for _, name := range []string{"shared.h", "main.c", "impl.c", "alt.c"} {
+		content, err := os.ReadFile(filepath.Join("testdata", "src", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(name) == ".c" {
+			cmds = append(cmds, compdb.Command{Directory: dir, Filename: "src/" + name, Args: []string{"cc", "-c", "src/" + name}})
+		}
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	// The translation unit is spelled relative to the directory of its command.
+	tu := idx.ParseTranslationUnit("src/main.c", []string{"-working-directory", dir}, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	var call clang.Cursor
+	tu.TranslationUnitCursor().Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if c.Kind() == clang.Cursor_CallExpr && c.Spelling() == "shared" {
+			call = c
+
+			return clang.ChildVisit_Break
+		}
+
+		return clang.ChildVisit_Recurse
+	})
+	if call.IsNull() {
+		t.Fatal("call of shared not found")
+	}
+
+	impl, alt, main := filepath.Join(src, "impl.c"), filepath.Join(src, "alt.c"), filepath.Join(src, "main.c")
+	sources := func(cands []Candidate) []string {
+		var got []string
+		for _, c := range cands {
+			got = append(got, c.Source)
+		}
+		sort.Strings(got)
+
+		return got
+	}
+
+	r := NewResolver(idx, cmds)
+	cand, ok, err := r.Definition(call)
+	if err != nil || !ok {
+		t.Fatalf("expected a definition. got=%v %v", ok, err)
+	}
+	if cand.Position.File != cand.Source || cand.Position.Line < 3 {
+		t.Errorf("expected the definition in the source of its translation unit. got=%+v", cand)
+	}
+	if len(r.tables) != 1 {
+		t.Errorf("expected the search to stop at the first definition. got=%d tables", len(r.tables))
+	}
+
+	cands, err := r.Definitions(call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sources(cands), []string{alt, impl}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected definitions in %v. got=%v", want, got)
+	}
+	for _, c := range cands {
+		if c.USR != "c:@F@shared" || c.Position.File != c.Source {
+			t.Errorf("unexpected candidate %+v", c)
+		}
+	}
+	if _, ok := r.tables[main]; ok {
+		t.Error("expected the translation unit of the cursor not to be parsed again")
+	}
+
+	// The definitions are kept after the translation units are disposed, until they are
+	// invalidated.
+	if err := os.WriteFile(impl, []byte("int unrelated;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cands, _ := r.Definitions(call); len(cands) != 2 {
+		t.Errorf("expected cached definitions. got=%v", sources(cands))
+	}
+	r.Invalidate(impl)
+	if cands, _ := r.Definitions(call); !reflect.DeepEqual(sources(cands), []string{alt}) {
+		t.Errorf("expected only %s after invalidation. got=%v", alt, sources(cands))
+	}
+}
@@ -0,0 +1,246 @@
+package definition
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// DefaultOptions are the options translation units are parsed with by default. Function bodies
+// are skipped, functions with a skipped body are still definitions.
+const DefaultOptions = uint32(clang.TranslationUnit_SkipFunctionBodies | clang.TranslationUnit_KeepGoing)
+
+// Resolver finds definitions in the translation units of a compilation database. Translation
+// units are parsed on demand, the first time they have to be searched, and only their
+// definitions are kept, so a Resolver holds no libclang objects besides the index.
+//
+// A Resolver is not safe for concurrent use.
+type Resolver struct {
+	// Options are the options translation units are parsed with.
+	Options uint32
+	// SkipSystemHeaders skips the definitions in system headers.
+	SkipSystemHeaders bool
+
+	idx    clang.Index
+	cmds   []compdb.Command
+	tables map[string]table
+	errs   map[string]error
+}
+
+// NewResolver returns a resolver which parses the translation units of the commands with idx.
+func NewResolver(idx clang.Index, cmds []compdb.Command) *Resolver {
+	return &Resolver{
+		Options: DefaultOptions,
+		idx:     idx,
+		cmds:    cmds,
+		tables:  map[string]table{},
+		errs:    map[string]error{},
+	}
+}
+
+// Invalidate drops the definitions of the translation unit of source, e.g. because the file
+// has been changed. They are collected again when they are needed.
+func (r *Resolver) Invalidate(source string) {
+	delete(r.tables, filepath.Clean(source))
+	delete(r.errs, filepath.Clean(source))
+}
+
+// Reset drops the definitions of all translation units.
+func (r *Resolver) Reset() {
+	r.tables = map[string]table{}
+	r.errs = map[string]error{}
+}
+
+// Definitions returns all definitions of the declaration c or the declaration c refers to.
+// The definition in the translation unit of c comes first, the others are ordered by the
+// proximity of their translation unit to the declaration.
+//
+// Symbols without external linkage are only searched in the translation unit of c. For all
+// other symbols every translation unit is searched, so several candidates are returned if the
+// symbol is defined more than once, e.g. for different configurations. The error reports the
+// first translation unit which could not be parsed, the candidates found in the others are
+// returned nonetheless.
+func (r *Resolver) Definitions(c clang.Cursor) ([]Candidate, error) {
+	var cands []Candidate
+	var err error
+
+	r.resolve(c, func(cand []Candidate, perr error) bool {
+		cands = add(cands, cand...)
+		if err == nil {
+			err = perr
+		}
+
+		return true
+	})
+
+	return cands, err
+}
+
+// Definition returns the first definition found for the declaration c or the declaration c
+// refers to. Translation units are searched in the order of Definitions, but the search stops
+// at the first one defining the symbol.
+func (r *Resolver) Definition(c clang.Cursor) (Candidate, bool, error) {
+	var cands []Candidate
+	var err error
+
+	r.resolve(c, func(cand []Candidate, perr error) bool {
+		cands = add(cands, cand...)
+		if err == nil {
+			err = perr
+		}
+
+		return len(cands) == 0
+	})
+
+	if len(cands) == 0 {
+		return Candidate{}, false, err
+	}
+
+	return cands[0], true, err
+}
+
+// resolve calls yield with the definitions of each searched translation unit until it
+// returns false.
+func (r *Resolver) resolve(c clang.Cursor, yield func([]Candidate, error) bool) {
+	target := c
+	if ref := c.Referenced(); !ref.IsNull() {
+		target = ref
+	}
+
+	usr := target.USR()
+	if usr == "" {
+		return
+	}
+
+	own := r.owner(c.TranslationUnit())
+	paths := &compdb.Paths{Directory: own.Directory}
+	source := own.Path()
+
+	if def := target.Definition(); !def.IsNull() {
+		cand, ok := r.candidate(def, usr, source, paths)
+		if ok && !yield([]Candidate{cand}, nil) {
+			return
+		}
+	}
+
+	switch target.Linkage() {
+	case clang.Linkage_External, clang.Linkage_Invalid:
+	default:
+		return
+	}
+
+	decl := paths.Abs(target.CanonicalCursor().Location().Position().File)
+
+	for _, cmd := range rank(r.cmds, decl) {
+		if cmd.Path() == source {
+			continue
+		}
+
+		t, err := r.table(cmd)
+		if !yield(t[usr], err) {
+			return
+		}
+	}
+}
+
+// owner returns the command of the translation unit tu. The spelling of tu is the file name
+// passed to the compiler, so it is resolved against the working directory of each command. A
+// translation unit without a command is taken to be compiled in the working directory of the
+// process.
+func (r *Resolver) owner(tu clang.TranslationUnit) compdb.Command {
+	spelling := tu.Spelling()
+	for _, cmd := range r.cmds {
+		if compdb.Abs(cmd.Directory, spelling) == cmd.Path() {
+			return cmd
+		}
+	}
+
+	dir, _ := os.Getwd()
+
+	return compdb.Command{Directory: dir, Filename: spelling}
+}
+
+// table returns the definitions of the translation unit of cmd and parses it if necessary.
+func (r *Resolver) table(cmd compdb.Command) (table, error) {
+	source := filepath.Clean(cmd.Path())
+	if t, ok := r.tables[source]; ok {
+		return t, r.errs[source]
+	}
+
+	t := table{}
+	tu, err := cmd.Parse(r.idx, nil, r.Options)
+	if err == nil {
+		r.collect(tu.TranslationUnitCursor(), t, source, &compdb.Paths{Directory: cmd.Directory})
+		tu.Dispose()
+	}
+
+	r.tables[source] = t
+	if err != nil {
+		r.errs[source] = err
+	}
+
+	return t, err
+}
+
+// collect adds the definitions below parent to t.
+func (r *Resolver) collect(parent clang.Cursor, t table, source string, paths *compdb.Paths) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		kind := c.Kind()
+		if !kind.IsDeclaration() && kind != clang.Cursor_LinkageSpec {
+			return clang.ChildVisit_Continue
+		}
+		if r.SkipSystemHeaders && c.Location().IsInSystemHeader() {
+			return clang.ChildVisit_Continue
+		}
+
+		if c.IsCursorDefinition() {
+			if usr := c.USR(); usr != "" {
+				if cand, ok := r.candidate(c, usr, source, paths); ok {
+					t[usr] = add(t[usr], cand)
+				}
+			}
+		}
+
+		if isScope(kind) {
+			r.collect(c, t, source, paths)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// candidate returns the candidate of the definition c found in the translation unit of source.
+func (r *Resolver) candidate(c clang.Cursor, usr, source string, paths *compdb.Paths) (Candidate, bool) {
+	pos := paths.Position(c.Location().Position())
+	if !pos.IsValid() {
+		return Candidate{}, false
+	}
+
+	extent := c.Extent().Span()
+
+	return Candidate{
+		USR:      usr,
+		Name:     c.Spelling(),
+		Kind:     c.Kind(),
+		Position: pos,
+		Extent: clang.Span{
+			Start: paths.Position(extent.Start),
+			End:   paths.Position(extent.End),
+		},
+		Source: source,
+	}, true
+}
+
+// isScope reports whether declarations of kind contain definitions with a USR.
+func isScope(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_LinkageSpec, clang.Cursor_UnexposedDecl,
+		clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl, clang.Cursor_EnumDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization:
+		return true
+	}
+
+	return false
+}
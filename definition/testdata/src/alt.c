@@ -0,0 +1,6 @@
+#include "shared.h"
+
+// shared is defined differently for another configuration.
+int shared(int x) {
+	return x - 1;
+}
@@ -0,0 +1,5 @@
+#include "shared.h"
+
+int shared(int x) {
+	return x + 1;
+}
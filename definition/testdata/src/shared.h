@@ -0,0 +1 @@
+int shared(int x);
@@ -0,0 +1,211 @@
+// Package diagbaseline records a baseline of diagnostics and compares later runs against it,
+// so that stricter warnings can be adopted without fixing all existing diagnostics first.
+//
+// Diagnostics are identified by a fingerprint which survives line shifts: it is computed from
+// the file, the warning option, the normalized message, the USR of the enclosing declaration
+// and the normalized tokens of the source line the diagnostic is reported on.
+package diagbaseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the baseline file format written by this package.
+const Version = 1
+
+// Entry is a single recorded diagnostic.
+type Entry struct {
+	// Fingerprint identifies the diagnostic independently of its line and column.
+	Fingerprint string `json:"fingerprint"`
+	// File is the path of the file the diagnostic is reported in, relative to the root of the recording.
+	File   string `json:"file"`
+	Line   uint32 `json:"line"`
+	Column uint32 `json:"column"`
+	// Severity is the lower case severity, e.g. "warning" or "error".
+	Severity string `json:"severity"`
+	// Option is the warning option which enables the diagnostic, e.g. "-Wshadow".
+	Option  string `json:"option,omitempty"`
+	Message string `json:"message"`
+	// USR is the USR of the declaration enclosing the diagnostic.
+	USR string `json:"usr,omitempty"`
+}
+
+func (e Entry) String() string {
+	s := fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Severity, e.Message)
+	if e.Option != "" {
+		s += " [" + e.Option + "]"
+	}
+
+	return s
+}
+
+var (
+	spaceRe    = regexp.MustCompile(`\s+`)
+	locationRe = regexp.MustCompile(`(\S+?):\d+(:\d+)?\b`)
+)
+
+// NormalizeMessage removes the parts of a diagnostic message which change when code is moved,
+// namely source locations of other entities, and collapses white space.
+func NormalizeMessage(msg string) string {
+	msg = locationRe.ReplaceAllString(msg, "$1")
+
+	return strings.TrimSpace(spaceRe.ReplaceAllString(msg, " "))
+}
+
+// NormalizeSnippet collapses white space of a source snippet.
+func NormalizeSnippet(snippet string) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(snippet, " "))
+}
+
+// Fingerprint computes the fingerprint of a diagnostic. The message and snippet are normalized
+// before they are hashed.
+func Fingerprint(file, option, message, usr, snippet string) string {
+	h := sha256.New()
+
+	for _, s := range []string{file, option, NormalizeMessage(message), usr, NormalizeSnippet(snippet)} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// Baseline is the content of a baseline file.
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"diagnostics"`
+}
+
+// New returns a baseline of the given entries, sorted by location.
+func New(entries []Entry) Baseline {
+	entries = append([]Entry(nil), entries...)
+	sortEntries(entries)
+
+	return Baseline{
+		Version: Version,
+		Entries: entries,
+	}
+}
+
+// Load reads a baseline file.
+func Load(filename string) (Baseline, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Baseline{}, err
+	}
+	defer f.Close()
+
+	b, err := Read(f)
+	if err != nil {
+		return b, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return b, nil
+}
+
+// Read reads a baseline from r.
+func Read(r io.Reader) (Baseline, error) {
+	var b Baseline
+
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return b, err
+	}
+	if b.Version != Version {
+		return b, fmt.Errorf("unsupported baseline version %d", b.Version)
+	}
+
+	return b, nil
+}
+
+// Write writes the baseline as indented JSON to w.
+func (b Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	return enc.Encode(b)
+}
+
+// Save writes the baseline to the file filename.
+func (b Baseline) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Write(f); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// Diff is the result of comparing two runs.
+type Diff struct {
+	// New holds the diagnostics which only occur in the new run.
+	New []Entry
+	// Fixed holds the diagnostics which only occur in the old run.
+	Fixed []Entry
+	// Unchanged holds the diagnostics of the new run which also occur in the old run.
+	Unchanged []Entry
+}
+
+// Compare compares the diagnostics of two runs by their fingerprints. Diagnostics with the same
+// fingerprint are paired in the order of their locations, so that duplicates are counted.
+func Compare(before, after []Entry) Diff {
+	var d Diff
+
+	remaining := map[string][]Entry{}
+	for _, e := range sorted(before) {
+		remaining[e.Fingerprint] = append(remaining[e.Fingerprint], e)
+	}
+
+	for _, e := range sorted(after) {
+		if len(remaining[e.Fingerprint]) > 0 {
+			remaining[e.Fingerprint] = remaining[e.Fingerprint][1:]
+			d.Unchanged = append(d.Unchanged, e)
+		} else {
+			d.New = append(d.New, e)
+		}
+	}
+
+	for _, rs := range remaining {
+		d.Fixed = append(d.Fixed, rs...)
+	}
+	sortEntries(d.Fixed)
+
+	return d
+}
+
+func sorted(entries []Entry) []Entry {
+	entries = append([]Entry(nil), entries...)
+	sortEntries(entries)
+
+	return entries
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+
+		return a.Fingerprint < b.Fingerprint
+	})
+}
@@ -0,0 +1,124 @@
+package diagbaseline
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	table := []struct {
+		msg  string
+		want string
+	}{
+		{"unused variable 'x'", "unused variable 'x'"},
+		{"redefinition of 'foo'  (previous at a.c:12:3)", "redefinition of 'foo' (previous at a.c)"},
+		{"\tconflicting types\n", "conflicting types"},
+	}
+
+	for _, tt := range table {
+		if got := NormalizeMessage(tt.msg); got != tt.want {
+			t.Errorf("expected %q. got=%q", tt.want, got)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("a.c", "-Wshadow", "declaration shadows a local variable", "c:@F@f", "int  x = 1;")
+	b := Fingerprint("a.c", "-Wshadow", "declaration  shadows a local variable", "c:@F@f", "int x = 1;")
+	if a != b {
+		t.Errorf("expected white space to be ignored. got=%s %s", a, b)
+	}
+
+	if c := Fingerprint("a.c", "-Wshadow", "declaration shadows a local variable", "c:@F@g", "int x = 1;"); c == a {
+		t.Error("expected different enclosing declarations to change the fingerprint")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	entry := func(fp string, line uint32) Entry {
+		return Entry{Fingerprint: fp, File: "a.c", Line: line}
+	}
+
+	before := []Entry{entry("x", 10), entry("y", 20), entry("y", 30), entry("z", 40)}
+	after := []Entry{entry("x", 12), entry("y", 22), entry("w", 50)}
+
+	got := Compare(before, after)
+	want := Diff{
+		New:       []Entry{entry("w", 50)},
+		Fixed:     []Entry{entry("y", 30), entry("z", 40)},
+		Unchanged: []Entry{entry("x", 12), entry("y", 22)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v. got=%+v", want, got)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	b := New([]Entry{
+		{Fingerprint: "b", File: "b.c", Line: 1, Column: 2, Severity: "warning", Option: "-Wshadow", Message: "m"},
+		{Fingerprint: "a", File: "a.c", Line: 3, Column: 4, Severity: "error", Message: "n", USR: "c:@F@main"},
+	})
+	if b.Entries[0].File != "a.c" {
+		t.Errorf("expected entries to be sorted. got=%+v", b.Entries)
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("expected %+v. got=%+v", b, got)
+	}
+
+	if _, err := Read(bytes.NewBufferString(`{"version": 99}`)); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	const source = `int f(int p) {
+	int unused;
+	return p;
+}
+`
+
+	record := func(content string) []Entry {
+		idx := clang.NewIndex(0, 0)
+		defer idx.Dispose()
+
+		us := []clang.UnsavedFile{clang.NewUnsavedFile("shift.c", content)}
+		defer us[0].Dispose()
+
+		tu := idx.ParseTranslationUnit("shift.c", []string{"-Wunused-variable"}, us, 0)
+		if !tu.IsValid() {
+			t.Fatal("tu is invalid")
+		}
+		defer tu.Dispose()
+
+		return Recorder{}.Entries(tu, tu.Diagnostics())
+	}
+
+	before := record(source)
+	after := record("int added;\n" + source)
+	if len(before) != 1 || len(after) != 1 {
+		t.Fatalf("expected one diagnostic before and after. got=%+v %+v", before, after)
+	}
+
+	if before[0].USR != "c:@F@f" {
+		t.Errorf("expected the diagnostic to be attributed to f. got=%q", before[0].USR)
+	}
+	if before[0].Line != 2 || after[0].Line != 3 {
+		t.Errorf("expected the diagnostic to move from line 2 to 3. got=%d %d", before[0].Line, after[0].Line)
+	}
+	if before[0].Fingerprint != after[0].Fingerprint {
+		t.Errorf("expected the fingerprint to survive the line shift. got=%s %s", before[0].Fingerprint, after[0].Fingerprint)
+	}
+}
@@ -0,0 +1,220 @@
+package diagbaseline
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Recorder turns the diagnostics of translation units into baseline entries.
+type Recorder struct {
+	// Root is the directory file paths are made relative to, so that baselines can be compared
+	// between checkouts in different locations. An empty Root keeps paths unchanged.
+	Root string
+}
+
+// Entries returns the entries of all diagnostics in diags, which must belong to tu. Notes,
+// ignored diagnostics and diagnostics without a location are skipped.
+func (r Recorder) Entries(tu clang.TranslationUnit, diags []clang.Diagnostic) []Entry {
+	var (
+		entries []Entry
+		sites   []site
+	)
+	for _, d := range diags {
+		if e, s, ok := r.entry(d); ok {
+			entries = append(entries, e)
+			sites = append(sites, s)
+		}
+	}
+
+	contents := map[string]string{}
+	for i, usr := range enclosingUSRs(tu, sites) {
+		name := sites[i].file.Name()
+		if _, ok := contents[name]; !ok {
+			contents[name], _ = tu.FileContents(sites[i].file)
+		}
+		entries[i] = r.finish(tu, entries[i], sites[i], usr, contents[name])
+	}
+
+	return entries
+}
+
+// Entry returns the entry of the diagnostic d, which must belong to tu.
+func (r Recorder) Entry(tu clang.TranslationUnit, d clang.Diagnostic) (Entry, bool) {
+	e, s, ok := r.entry(d)
+	if !ok {
+		return Entry{}, false
+	}
+
+	contents, _ := tu.FileContents(s.file)
+
+	return r.finish(tu, e, s, enclosingUSRs(tu, []site{s})[0], contents), true
+}
+
+// site is the location of a diagnostic in its file.
+type site struct {
+	file   clang.File
+	offset uint32
+}
+
+// entry returns the entry of the diagnostic d without its USR and fingerprint.
+func (r Recorder) entry(d clang.Diagnostic) (Entry, site, bool) {
+	sev := d.Severity()
+	if sev == clang.Diagnostic_Ignored || sev == clang.Diagnostic_Note {
+		return Entry{}, site{}, false
+	}
+
+	file, line, column, offset := d.Location().ExpansionLocation()
+	if file.Name() == "" {
+		return Entry{}, site{}, false
+	}
+
+	_, option := d.Option()
+
+	e := Entry{
+		File:     r.rel(file.Name()),
+		Line:     line,
+		Column:   column,
+		Severity: severityName(sev),
+		Option:   option,
+		Message:  d.Spelling(),
+	}
+
+	return e, site{file: file, offset: offset}, true
+}
+
+// finish sets the USR and the fingerprint of the entry e. contents is the buffer of its file.
+func (r Recorder) finish(tu clang.TranslationUnit, e Entry, s site, usr, contents string) Entry {
+	e.USR = usr
+	e.Fingerprint = Fingerprint(e.File, e.Option, e.Message, e.USR, lineSnippet(tu, s.file, contents, e.Line))
+
+	return e
+}
+
+func (r Recorder) rel(filename string) string {
+	if r.Root == "" {
+		return filename
+	}
+
+	rel, err := filepath.Rel(r.Root, filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filename
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func severityName(sev clang.DiagnosticSeverity) string {
+	switch sev {
+	case clang.Diagnostic_Warning:
+		return "warning"
+	case clang.Diagnostic_Error:
+		return "error"
+	case clang.Diagnostic_Fatal:
+		return "fatal"
+	}
+
+	return "note"
+}
+
+// enclosingUSRs returns the USRs of the innermost non-local declarations whose extents contain
+// the sites: functions, records, namespaces and global variables. The USRs of local
+// declarations contain their offset in the file, so they would change with every line added
+// above them. The AST is walked once for all sites.
+func enclosingUSRs(tu clang.TranslationUnit, sites []site) []string {
+	usrs := make([]string, len(sites))
+	if len(sites) == 0 {
+		return usrs
+	}
+
+	tu.TranslationUnitCursor().Visit(func(cursor, parent clang.Cursor) clang.ChildVisitResult {
+		f, _, _, start := cursor.Extent().Start().ExpansionLocation()
+		_, _, _, end := cursor.Extent().End().ExpansionLocation()
+
+		kind := cursor.Kind()
+		var usr string
+		if isFunction(kind) || isScope(kind) || kind == clang.Cursor_VarDecl {
+			usr = cursor.USR()
+		}
+
+		inside := false
+		for i, s := range sites {
+			if s.offset < start || s.offset > end || !f.IsEqual(s.file) {
+				continue
+			}
+
+			inside = true
+			if usr != "" {
+				usrs[i] = usr
+			}
+		}
+		// Everything below a function is local to it.
+		if !inside || isFunction(kind) {
+			return clang.ChildVisit_Continue
+		}
+
+		return clang.ChildVisit_Recurse
+	})
+
+	return usrs
+}
+
+func isFunction(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_Constructor, clang.Cursor_Destructor,
+		clang.Cursor_ConversionFunction, clang.Cursor_FunctionTemplate, clang.Cursor_ObjCInstanceMethodDecl,
+		clang.Cursor_ObjCClassMethodDecl:
+		return true
+	}
+
+	return false
+}
+
+func isScope(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_UnionDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization, clang.Cursor_EnumDecl:
+		return true
+	}
+
+	return false
+}
+
+// lineSnippet returns the tokens of the given line joined by single spaces. Comments are
+// dropped, so that the snippet only changes when the code of the line changes. contents is the
+// buffer of the file, which bounds the line.
+func lineSnippet(tu clang.TranslationUnit, file clang.File, contents string, line uint32) string {
+	begin := 0
+	for l := uint32(1); l < line; l++ {
+		i := strings.IndexByte(contents[begin:], '\n')
+		if i < 0 {
+			return ""
+		}
+		begin += i + 1
+	}
+
+	end := len(contents)
+	if i := strings.IndexByte(contents[begin:], '\n'); i >= 0 {
+		end = begin + i
+	}
+
+	r := tu.LocationForOffset(file, uint32(begin)).Range(tu.LocationForOffset(file, uint32(end)))
+
+	tokens := tu.Tokenize(r)
+	defer tu.DisposeTokens(tokens)
+
+	var parts []string
+	for _, t := range tokens {
+		if t.Kind() == clang.Token_Comment {
+			continue
+		}
+		if _, l, _, _ := tu.TokenLocation(t).ExpansionLocation(); l != line {
+			continue
+		}
+
+		parts = append(parts, tu.TokenSpelling(t))
+	}
+
+	return strings.Join(parts, " ")
+}
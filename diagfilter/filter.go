@@ -0,0 +1,165 @@
+package diagfilter
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Suppression describes a rule or an inline NOLINT comment together with the number of
+// diagnostics it suppressed.
+type Suppression struct {
+	// Rule is the suppressing rule, nil for NOLINT comments.
+	Rule *Rule
+	// File and Line locate the NOLINT comment, they are empty for rules.
+	File string
+	Line uint32
+	// Option is the warning option suppressed by the NOLINT comment, empty if the comment
+	// suppresses all diagnostics.
+	Option string
+	// Hits is the number of diagnostics that were suppressed.
+	Hits int
+}
+
+// Filter suppresses diagnostics according to a Config.
+//
+// A Filter is not safe for concurrent use.
+type Filter struct {
+	rules  []Suppression
+	nolint bool
+
+	// markers holds the NOLINT markers of every scanned file, indexed by the line they apply to.
+	markers map[string]map[uint32][]*marker
+	// ordered holds all NOLINT markers in the order they were found.
+	ordered []*marker
+}
+
+// NewFilter returns a new filter using the given configuration.
+func NewFilter(c Config) *Filter {
+	f := &Filter{
+		nolint:  c.NoLint,
+		markers: map[string]map[uint32][]*marker{},
+	}
+
+	for i := range c.Rules {
+		r := c.Rules[i]
+		f.rules = append(f.rules, Suppression{Rule: &r})
+	}
+
+	return f
+}
+
+// Apply returns the diagnostics of diags which are not suppressed. The main file of tu is
+// scanned for NOLINT comments beforehand, so that unused comments in it are reported by Unused.
+//
+// Apply does not dispose the suppressed diagnostics, they are still owned by the caller.
+func (f *Filter) Apply(tu clang.TranslationUnit, diags []clang.Diagnostic) []clang.Diagnostic {
+	if f.nolint {
+		f.scan(tu, tu.File(tu.Spelling()))
+	}
+
+	var kept []clang.Diagnostic
+	for _, d := range diags {
+		if !f.Suppressed(tu, d) {
+			kept = append(kept, d)
+		}
+	}
+
+	return kept
+}
+
+// Suppressed reports whether the diagnostic d of the translation unit tu is suppressed. The
+// hit count of the first matching suppression is incremented. Notes are never suppressed on
+// their own, they belong to the diagnostic they are attached to.
+func (f *Filter) Suppressed(tu clang.TranslationUnit, d clang.Diagnostic) bool {
+	if d.Severity() == clang.Diagnostic_Note {
+		return false
+	}
+
+	_, option := d.Option()
+	loc := d.Location()
+	file, line, _, _ := loc.ExpansionLocation()
+	filename := file.Name()
+
+	for i := range f.rules {
+		if f.rules[i].Rule.Match(option, filename, loc.IsInSystemHeader()) {
+			f.rules[i].Hits++
+
+			return true
+		}
+	}
+
+	if !f.nolint || filename == "" {
+		return false
+	}
+
+	f.scan(tu, file)
+	for _, m := range f.markers[filename][line] {
+		if m.option == "" || matchOption(m.option, option) {
+			m.hits++
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// scan collects the NOLINT markers of file unless it has already been scanned.
+func (f *Filter) scan(tu clang.TranslationUnit, file clang.File) {
+	filename := file.Name()
+	if filename == "" {
+		return
+	}
+	if _, ok := f.markers[filename]; ok {
+		return
+	}
+
+	lines := map[uint32][]*marker{}
+	for _, m := range scanNoLint(tu, file) {
+		lines[m.target] = append(lines[m.target], m)
+		f.ordered = append(f.ordered, m)
+	}
+	f.markers[filename] = lines
+}
+
+// Suppressions returns all rules followed by all NOLINT comments found so far, together with
+// the number of diagnostics they suppressed.
+func (f *Filter) Suppressions() []Suppression {
+	s := make([]Suppression, 0, len(f.rules)+len(f.ordered))
+	s = append(s, f.rules...)
+
+	markers := append([]*marker(nil), f.ordered...)
+	sort.SliceStable(markers, func(i, j int) bool {
+		if markers[i].file != markers[j].file {
+			return markers[i].file < markers[j].file
+		}
+
+		return markers[i].line < markers[j].line
+	})
+
+	for _, m := range markers {
+		s = append(s, Suppression{
+			File:   m.file,
+			Line:   m.line,
+			Option: m.option,
+			Hits:   m.hits,
+		})
+	}
+
+	return s
+}
+
+// Unused returns the suppressions which did not suppress a single diagnostic. NOLINT comments
+// are only reported for files that have been scanned, which are the main files of the
+// translation units given to Apply and files that contained diagnostics.
+func (f *Filter) Unused() []Suppression {
+	var unused []Suppression
+	for _, s := range f.Suppressions() {
+		if s.Hits == 0 {
+			unused = append(unused, s)
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,112 @@
+package diagfilter
+
+import (
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// marker is a single suppression of an inline NOLINT comment.
+type marker struct {
+	file string
+	// line is the line the comment is written on.
+	line uint32
+	// target is the line whose diagnostics are suppressed.
+	target uint32
+	// option is the suppressed warning option, an empty option suppresses everything.
+	option string
+	hits   int
+}
+
+// parseNoLint parses the text of a comment. It returns the suppressed options, whether the
+// comment applies to the next line instead of its own line, and whether the comment is a NOLINT
+// comment at all. An empty list of options means that all diagnostics are suppressed. The
+// NOLINTBEGIN and NOLINTEND markers of clang-tidy are not NOLINT comments.
+func parseNoLint(comment string) (options []string, nextLine bool, ok bool) {
+	rest := comment
+	for {
+		i := strings.Index(rest, "NOLINT")
+		if i < 0 {
+			return nil, false, false
+		}
+
+		rest = rest[i+len("NOLINT"):]
+		if !strings.HasPrefix(rest, "BEGIN") && !strings.HasPrefix(rest, "END") {
+			break
+		}
+	}
+
+	if strings.HasPrefix(rest, "NEXTLINE") {
+		rest = rest[len("NEXTLINE"):]
+		nextLine = true
+	}
+
+	if !strings.HasPrefix(rest, "(") {
+		return nil, nextLine, true
+	}
+
+	end := strings.Index(rest, ")")
+	if end < 0 {
+		return nil, nextLine, true
+	}
+
+	for _, o := range strings.Split(rest[1:end], ",") {
+		if o = normalizeOption(o); o != "" && o != "*" {
+			options = append(options, o)
+		} else if o == "*" {
+			return nil, nextLine, true
+		}
+	}
+
+	return options, nextLine, true
+}
+
+// scanNoLint tokenizes the complete file and returns the markers of all NOLINT comments in it.
+// The file is read from the buffer of the translation unit, which includes unsaved files.
+func scanNoLint(tu clang.TranslationUnit, file clang.File) []*marker {
+	filename := file.Name()
+
+	contents, ok := tu.FileContents(file)
+	if !ok || len(contents) == 0 {
+		return nil
+	}
+
+	r := tu.LocationForOffset(file, 0).Range(tu.LocationForOffset(file, uint32(len(contents))))
+
+	tokens := tu.Tokenize(r)
+	defer tu.DisposeTokens(tokens)
+
+	var markers []*marker
+	for _, t := range tokens {
+		if t.Kind() != clang.Token_Comment {
+			continue
+		}
+
+		options, nextLine, ok := parseNoLint(tu.TokenSpelling(t))
+		if !ok {
+			continue
+		}
+
+		_, line, _, _ := tu.TokenLocation(t).ExpansionLocation()
+		target := line
+		if nextLine {
+			_, endLine, _, _ := tu.TokenExtent(t).End().ExpansionLocation()
+			target = endLine + 1
+		}
+
+		if len(options) == 0 {
+			options = []string{""}
+		}
+
+		for _, o := range options {
+			markers = append(markers, &marker{
+				file:   filename,
+				line:   line,
+				target: target,
+				option: o,
+			})
+		}
+	}
+
+	return markers
+}
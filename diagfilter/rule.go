@@ -0,0 +1,266 @@
+// Package diagfilter suppresses diagnostics of legacy code bases that cannot be fixed yet.
+//
+// A Filter is configured with a list of rules which suppress diagnostics by their warning
+// option, by the path of the file they are reported in or by their location inside a system
+// header. Additionally, diagnostics can be suppressed inline with NOLINT comments which are
+// found by tokenizing the files of a translation unit:
+//
+//	int unused; // NOLINT(unused-variable)
+//	// NOLINTNEXTLINE(-Wshadow, -Wunused-variable)
+//	int shadow;
+//
+// The Filter counts how often every suppression was used, so that suppressions that became
+// stale can be reported and removed.
+package diagfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// RuleKind describes what part of a diagnostic a Rule matches.
+type RuleKind uint32
+
+const (
+	// Rule_Option matches the warning option of a diagnostic, e.g. "-Wunused-parameter".
+	Rule_Option RuleKind = iota
+	// Rule_Path matches the path of the file a diagnostic is reported in against a glob.
+	Rule_Path
+	// Rule_SystemHeader matches diagnostics that are reported inside a system header.
+	Rule_SystemHeader
+)
+
+var ruleKindNames = map[RuleKind]string{
+	Rule_Option:       "option",
+	Rule_Path:         "path",
+	Rule_SystemHeader: "system-header",
+}
+
+// Spelling returns the name of the rule kind as it is used in configuration files.
+func (rk RuleKind) Spelling() string {
+	if s, ok := ruleKindNames[rk]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("RuleKind unknown %d", int(rk))
+}
+
+func (rk RuleKind) String() string {
+	return rk.Spelling()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (rk RuleKind) MarshalText() ([]byte, error) {
+	if _, ok := ruleKindNames[rk]; !ok {
+		return nil, fmt.Errorf("unknown rule kind %d", int(rk))
+	}
+
+	return []byte(rk.Spelling()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (rk *RuleKind) UnmarshalText(text []byte) error {
+	for k, s := range ruleKindNames {
+		if s == string(text) {
+			*rk = k
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown rule kind %q", text)
+}
+
+// Rule suppresses every diagnostic it matches.
+type Rule struct {
+	// Kind defines how Pattern is interpreted.
+	Kind RuleKind `json:"kind"`
+	// Pattern is a glob over the warning option for Rule_Option, with or without the leading
+	// "-W", and a glob over the file path for Rule_Path. "**" matches any number of directories.
+	// Pattern is ignored for Rule_SystemHeader.
+	Pattern string `json:"pattern,omitempty"`
+	// Options restricts a Rule_Path or Rule_SystemHeader rule to diagnostics with one of the
+	// given warning options. An empty list matches all diagnostics.
+	Options []string `json:"options,omitempty"`
+}
+
+// String returns a short human readable description of the rule.
+func (r Rule) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Kind.Spelling())
+	if r.Kind != Rule_SystemHeader {
+		sb.WriteString(" ")
+		sb.WriteString(r.Pattern)
+	}
+	if len(r.Options) > 0 {
+		sb.WriteString(" [")
+		sb.WriteString(strings.Join(r.Options, ", "))
+		sb.WriteString("]")
+	}
+
+	return sb.String()
+}
+
+// Validate reports whether the rule is well-formed.
+func (r Rule) Validate() error {
+	switch r.Kind {
+	case Rule_Option, Rule_Path:
+		if r.Pattern == "" {
+			return fmt.Errorf("%s rule without pattern", r.Kind)
+		}
+		if _, err := path.Match(normalizeOption(r.Pattern), ""); err != nil {
+			return fmt.Errorf("%s rule %q: %w", r.Kind, r.Pattern, err)
+		}
+	case Rule_SystemHeader:
+	default:
+		return fmt.Errorf("unknown rule kind %d", int(r.Kind))
+	}
+
+	return nil
+}
+
+// Match reports whether the rule matches a diagnostic with the given warning option which is
+// reported in the file at path filename.
+func (r Rule) Match(option, filename string, inSystemHeader bool) bool {
+	switch r.Kind {
+	case Rule_Option:
+		return matchOption(r.Pattern, option)
+	case Rule_Path:
+		return matchPath(r.Pattern, filename) && r.matchOptions(option)
+	case Rule_SystemHeader:
+		return inSystemHeader && r.matchOptions(option)
+	}
+
+	return false
+}
+
+func (r Rule) matchOptions(option string) bool {
+	if len(r.Options) == 0 {
+		return true
+	}
+
+	for _, o := range r.Options {
+		if matchOption(o, option) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeOption strips the "-W" prefix of a warning option.
+func normalizeOption(option string) string {
+	return strings.TrimPrefix(strings.TrimSpace(option), "-W")
+}
+
+func matchOption(pattern, option string) bool {
+	option = normalizeOption(option)
+	if option == "" {
+		return false
+	}
+
+	ok, _ := path.Match(normalizeOption(pattern), option)
+
+	return ok
+}
+
+// matchPath matches filename against a slash separated glob pattern. A "**" element matches
+// zero or more path elements. Relative patterns match at any directory of filename.
+func matchPath(pattern, filename string) bool {
+	if filename == "" {
+		return false
+	}
+
+	filename = path.Clean(strings.ReplaceAll(filename, "\\", "/"))
+	pats := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	names := strings.Split(strings.TrimPrefix(filename, "/"), "/")
+
+	if strings.HasPrefix(pattern, "/") {
+		return matchElems(pats, names)
+	}
+
+	for i := range names {
+		if matchElems(pats, names[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchElems(pats, names []string) bool {
+	for len(pats) > 0 {
+		if pats[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchElems(pats[1:], names[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pats[0], names[0]); !ok {
+			return false
+		}
+
+		pats, names = pats[1:], names[1:]
+	}
+
+	return len(names) == 0
+}
+
+// Config is the content of a suppression configuration file.
+type Config struct {
+	// Rules are the suppression rules in the order they are checked.
+	Rules []Rule `json:"rules"`
+	// NoLint enables the suppression of diagnostics with NOLINT comments.
+	NoLint bool `json:"nolint"`
+}
+
+// Validate reports whether all rules of the configuration are well-formed.
+func (c Config) Validate() error {
+	for i, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadConfig reads a JSON suppression configuration file, for example:
+//
+//	{
+//		"nolint": true,
+//		"rules": [
+//			{"kind": "option", "pattern": "-Wunused-parameter"},
+//			{"kind": "path", "pattern": "third_party/**"},
+//			{"kind": "system-header", "options": ["-Wdeprecated-declarations"]}
+//		]
+//	}
+func LoadConfig(filename string) (Config, error) {
+	var c Config
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return c, err
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return c, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return c, nil
+}
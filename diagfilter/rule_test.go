@@ -0,0 +1,101 @@
+package diagfilter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRuleMatch(t *testing.T) {
+	table := []struct {
+		rule     Rule
+		option   string
+		filename string
+		system   bool
+		want     bool
+	}{
+		{Rule{Kind: Rule_Option, Pattern: "-Wunused-parameter"}, "-Wunused-parameter", "a.c", false, true},
+		{Rule{Kind: Rule_Option, Pattern: "unused-parameter"}, "-Wunused-parameter", "a.c", false, true},
+		{Rule{Kind: Rule_Option, Pattern: "-Wunused-*"}, "-Wunused-variable", "a.c", false, true},
+		{Rule{Kind: Rule_Option, Pattern: "-Wunused-*"}, "-Wshadow", "a.c", false, false},
+		{Rule{Kind: Rule_Option, Pattern: "*"}, "", "a.c", false, false},
+		{Rule{Kind: Rule_Path, Pattern: "third_party/**"}, "-Wshadow", "/src/third_party/zlib/inflate.c", false, true},
+		{Rule{Kind: Rule_Path, Pattern: "third_party/**"}, "-Wshadow", "/src/lib/inflate.c", false, false},
+		{Rule{Kind: Rule_Path, Pattern: "/src/*.c"}, "", "/src/main.c", false, true},
+		{Rule{Kind: Rule_Path, Pattern: "/src/*.c"}, "", "/src/lib/main.c", false, false},
+		{Rule{Kind: Rule_Path, Pattern: "**/gen/*_gen.h", Options: []string{"-Wconversion"}}, "-Wconversion", "/b/gen/x_gen.h", false, true},
+		{Rule{Kind: Rule_Path, Pattern: "**/gen/*_gen.h", Options: []string{"-Wconversion"}}, "-Wshadow", "/b/gen/x_gen.h", false, false},
+		{Rule{Kind: Rule_SystemHeader}, "-Wshadow", "/usr/include/stdio.h", true, true},
+		{Rule{Kind: Rule_SystemHeader}, "-Wshadow", "/src/main.c", false, false},
+	}
+
+	for i, tt := range table {
+		if got := tt.rule.Match(tt.option, tt.filename, tt.system); got != tt.want {
+			t.Errorf("%d: expected %s to match %q %q = %v. got=%v", i, tt.rule, tt.option, tt.filename, tt.want, got)
+		}
+	}
+}
+
+func TestParseNoLint(t *testing.T) {
+	table := []struct {
+		comment  string
+		options  []string
+		nextLine bool
+		ok       bool
+	}{
+		{"// just a comment", nil, false, false},
+		{"// NOLINT", nil, false, true},
+		{"/* NOLINT(*) */", nil, false, true},
+		{"// NOLINT(unused-variable)", []string{"unused-variable"}, false, true},
+		{"// NOLINTNEXTLINE(-Wshadow, -Wunused-variable)", []string{"shadow", "unused-variable"}, true, true},
+		{"// NOLINTNEXTLINE", nil, true, true},
+		{"// NOLINTBEGIN", nil, false, false},
+		{"// NOLINTEND(-Wshadow)", nil, false, false},
+		{"// NOLINTEND NOLINT(-Wshadow)", []string{"shadow"}, false, true},
+	}
+
+	for _, tt := range table {
+		options, nextLine, ok := parseNoLint(tt.comment)
+		if !reflect.DeepEqual(options, tt.options) || nextLine != tt.nextLine || ok != tt.ok {
+			t.Errorf("%q: expected %v %v %v. got=%v %v %v", tt.comment, tt.options, tt.nextLine, tt.ok, options, nextLine, ok)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "suppressions.json")
+	err := os.WriteFile(filename, []byte(`{
+		"nolint": true,
+		"rules": [
+			{"kind": "option", "pattern": "-Wunused-parameter"},
+			{"kind": "system-header", "options": ["-Wdeprecated-declarations"]}
+		]
+	}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		NoLint: true,
+		Rules: []Rule{
+			{Kind: Rule_Option, Pattern: "-Wunused-parameter"},
+			{Kind: Rule_SystemHeader, Options: []string{"-Wdeprecated-declarations"}},
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("expected %+v. got=%+v", want, c)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{"rules": [{"kind": "path"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(filename); err == nil {
+		t.Error("expected error for path rule without pattern")
+	}
+}
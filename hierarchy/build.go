@@ -0,0 +1,166 @@
+package hierarchy
+
+import (
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Builder builds the class hierarchies of translation units.
+type Builder struct {
+	// SkipSystemHeaders skips the classes defined in system headers. They are still recorded
+	// as base classes, but without their methods.
+	SkipSystemHeaders bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Build returns the class hierarchy of the translation unit.
+func (b Builder) Build(tu clang.TranslationUnit) *Hierarchy {
+	w := walker{
+		Builder:   b,
+		hierarchy: New(),
+		paths:     compdb.Paths{Directory: b.Directory},
+	}
+	w.children(tu.TranslationUnitCursor())
+
+	return w.hierarchy
+}
+
+type walker struct {
+	Builder
+
+	hierarchy *Hierarchy
+	paths     compdb.Paths
+}
+
+func (w *walker) children(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		w.visit(c)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) visit(c clang.Cursor) {
+	kind := c.Kind()
+	if w.SkipSystemHeaders && c.Location().IsInSystemHeader() {
+		return
+	}
+
+	switch {
+	case isRecord(kind):
+		if c.IsCursorDefinition() {
+			w.record(c)
+		}
+	case kind == clang.Cursor_Namespace, kind == clang.Cursor_LinkageSpec:
+		w.children(c)
+	}
+}
+
+// record adds the definition of the record c with its bases and virtual methods.
+func (w *walker) record(c clang.Cursor) {
+	class := w.class(c)
+	if class == nil || class.IsDefined() {
+		return
+	}
+	class.Definition = w.paths.Position(c.Location().Position())
+	class.Abstract = c.CXXRecord_IsAbstract()
+
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		switch kind := child.Kind(); {
+		case kind == clang.Cursor_CXXBaseSpecifier:
+			decl := child.Type().Declaration()
+			if decl.IsNull() {
+				decl = child.Referenced()
+			}
+			if base := w.class(decl); base != nil {
+				class.Bases = append(class.Bases, Base{
+					USR:     base.USR,
+					Virtual: child.IsVirtualBase(),
+					Access:  child.AccessSpecifier(),
+				})
+			}
+		case kind == clang.Cursor_CXXMethod, kind == clang.Cursor_Destructor:
+			if m := w.method(child); m != nil {
+				class.Methods = union(class.Methods, []string{m.USR})
+			}
+		case isRecord(kind):
+			if child.IsCursorDefinition() {
+				w.record(child)
+			}
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// class returns the class of the record declaration c and adds it if it is new.
+func (w *walker) class(c clang.Cursor) *Class {
+	if c.IsNull() || !isRecord(c.Kind()) {
+		return nil
+	}
+	usr := c.USR()
+	if usr == "" {
+		return nil
+	}
+
+	class, ok := w.hierarchy.Classes[usr]
+	if !ok {
+		class = &Class{
+			USR:  usr,
+			Name: c.QualifiedName(),
+			Kind: c.Kind(),
+		}
+		w.hierarchy.Classes[usr] = class
+	}
+
+	return class
+}
+
+// method returns the method declared by c and adds it if it is new. Methods which are not
+// virtual are ignored.
+func (w *walker) method(c clang.Cursor) *Method {
+	if !c.CXXMethod_IsVirtual() {
+		return nil
+	}
+	usr := c.USR()
+	if usr == "" {
+		return nil
+	}
+
+	if m, ok := w.hierarchy.Methods[usr]; ok {
+		return m
+	}
+
+	m := &Method{
+		USR:         usr,
+		Name:        c.QualifiedName(),
+		Signature:   c.DisplayName(),
+		Class:       c.SemanticParent().USR(),
+		PureVirtual: c.CXXMethod_IsPureVirtual(),
+		Declaration: w.paths.Position(c.Location().Position()),
+	}
+	w.hierarchy.Methods[usr] = m
+
+	overridden := c.OverriddenCursors()
+	for _, o := range overridden {
+		if om := w.method(o); om != nil {
+			m.Overrides = union(m.Overrides, []string{om.USR})
+		}
+	}
+	if len(overridden) > 0 {
+		clang.Dispose(overridden)
+	}
+
+	return m
+}
+
+func isRecord(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate,
+		clang.Cursor_ClassTemplatePartialSpecialization:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,332 @@
+// Package hierarchy builds the inheritance graph of C++ classes together with the virtual
+// methods overriding each other. Classes and methods are identified by their USR, so the
+// hierarchies of all translation units of a project can be merged.
+package hierarchy
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Base is a direct base class.
+type Base struct {
+	USR     string
+	Virtual bool
+	Access  clang.AccessSpecifier
+}
+
+// Class is a class, struct or class template.
+type Class struct {
+	USR string
+	// Name is the qualified name, e.g. "ns::Shape".
+	Name string
+	Kind clang.CursorKind
+	// Definition is the position of the name in the definition. It is invalid if the class is
+	// not defined in any of the translation units.
+	Definition clang.Position
+	// Abstract is set if the class has pure virtual methods which are not overridden.
+	Abstract bool
+	Bases    []Base
+	// Methods holds the USRs of the virtual methods declared in the class.
+	Methods []string
+}
+
+// IsDefined reports whether the definition of the class was seen.
+func (c *Class) IsDefined() bool {
+	return c.Definition.IsValid()
+}
+
+// Method is a virtual method.
+type Method struct {
+	USR string
+	// Name is the qualified name, e.g. "ns::Shape::area".
+	Name string
+	// Signature is the name together with the parameter types, e.g. "area()".
+	Signature string
+	// Class is the USR of the class declaring the method.
+	Class       string
+	PureVirtual bool
+	// Declaration is the position of the name in the declaration within the class.
+	Declaration clang.Position
+	// Overrides holds the USRs of the methods directly overridden by the method.
+	Overrides []string
+}
+
+// Hierarchy holds classes and their virtual methods.
+type Hierarchy struct {
+	Classes map[string]*Class
+	Methods map[string]*Method
+
+	subclasses map[string][]string
+	overriders map[string][]string
+}
+
+// New returns an empty hierarchy.
+func New() *Hierarchy {
+	return &Hierarchy{
+		Classes: map[string]*Class{},
+		Methods: map[string]*Method{},
+	}
+}
+
+// Merge adds the classes and methods of other to h. Information already present in h is
+// completed but not replaced.
+func (h *Hierarchy) Merge(other *Hierarchy) {
+	for usr, c := range other.Classes {
+		mine, ok := h.Classes[usr]
+		if !ok {
+			cp := *c
+			cp.Bases = append([]Base(nil), c.Bases...)
+			cp.Methods = append([]string(nil), c.Methods...)
+			h.Classes[usr] = &cp
+
+			continue
+		}
+
+		if !mine.IsDefined() && c.IsDefined() {
+			mine.Definition = c.Definition
+			mine.Abstract = c.Abstract
+			mine.Bases = append([]Base(nil), c.Bases...)
+		}
+		mine.Methods = union(mine.Methods, c.Methods)
+	}
+
+	for usr, m := range other.Methods {
+		mine, ok := h.Methods[usr]
+		if !ok {
+			cp := *m
+			cp.Overrides = append([]string(nil), m.Overrides...)
+			h.Methods[usr] = &cp
+
+			continue
+		}
+
+		if !mine.Declaration.IsValid() {
+			mine.Declaration = m.Declaration
+		}
+		mine.Overrides = union(mine.Overrides, m.Overrides)
+	}
+
+	h.subclasses = nil
+	h.overriders = nil
+}
+
+// union appends the elements of b missing in a to a.
+func union(a, b []string) []string {
+next:
+	for _, s := range b {
+		for _, t := range a {
+			if s == t {
+				continue next
+			}
+		}
+		a = append(a, s)
+	}
+
+	return a
+}
+
+func (h *Hierarchy) index() {
+	if h.subclasses != nil {
+		return
+	}
+
+	h.subclasses = map[string][]string{}
+	for usr, c := range h.Classes {
+		for _, b := range c.Bases {
+			h.subclasses[b.USR] = append(h.subclasses[b.USR], usr)
+		}
+	}
+
+	h.overriders = map[string][]string{}
+	for usr, m := range h.Methods {
+		for _, o := range m.Overrides {
+			h.overriders[o] = append(h.overriders[o], usr)
+		}
+	}
+}
+
+// closure returns the elements transitively reachable from usr through next, without usr.
+func closure(usr string, next func(string) []string) []string {
+	seen := map[string]bool{usr: true}
+	var result []string
+
+	queue := []string{usr}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, n := range next(u) {
+			if !seen[n] {
+				seen[n] = true
+				result = append(result, n)
+				queue = append(queue, n)
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Subclasses returns the USRs of all classes directly or indirectly derived from the class usr.
+func (h *Hierarchy) Subclasses(usr string) []string {
+	h.index()
+
+	return closure(usr, func(u string) []string { return h.subclasses[u] })
+}
+
+// Superclasses returns the USRs of all direct and indirect base classes of the class usr.
+func (h *Hierarchy) Superclasses(usr string) []string {
+	return closure(usr, func(u string) []string {
+		c, ok := h.Classes[u]
+		if !ok {
+			return nil
+		}
+
+		bases := make([]string, len(c.Bases))
+		for i, b := range c.Bases {
+			bases[i] = b.USR
+		}
+
+		return bases
+	})
+}
+
+// Overriders returns the USRs of all methods directly or indirectly overriding the method usr.
+func (h *Hierarchy) Overriders(usr string) []string {
+	h.index()
+
+	return closure(usr, func(u string) []string { return h.overriders[u] })
+}
+
+// Implementations returns the USRs of the methods implementing the virtual method usr: the
+// method itself unless it is pure virtual, and all of its overriders which are not pure
+// virtual.
+func (h *Hierarchy) Implementations(usr string) []string {
+	var impls []string
+	for _, u := range append([]string{usr}, h.Overriders(usr)...) {
+		if m, ok := h.Methods[u]; ok && !m.PureVirtual {
+			impls = append(impls, u)
+		}
+	}
+
+	return impls
+}
+
+// overrides reports whether the method usr directly or indirectly overrides the method base.
+func (h *Hierarchy) overrides(usr, base string) bool {
+	for _, u := range closure(usr, func(u string) []string {
+		if m, ok := h.Methods[u]; ok {
+			return m.Overrides
+		}
+
+		return nil
+	}) {
+		if u == base {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unimplemented returns the USRs of the pure virtual methods of the class usr and its base
+// classes which are not implemented by the class or one of its base classes.
+func (h *Hierarchy) Unimplemented(usr string) []string {
+	classes := append([]string{usr}, h.Superclasses(usr)...)
+
+	var pure, impls []string
+	for _, cu := range classes {
+		c, ok := h.Classes[cu]
+		if !ok {
+			continue
+		}
+		for _, mu := range c.Methods {
+			if m, ok := h.Methods[mu]; ok {
+				if m.PureVirtual {
+					pure = append(pure, mu)
+				} else {
+					impls = append(impls, mu)
+				}
+			}
+		}
+	}
+
+	var missing []string
+next:
+	for _, p := range pure {
+		for _, i := range impls {
+			if h.overrides(i, p) {
+				continue next
+			}
+		}
+		// A pure virtual method overridden by another pure virtual method of a derived class
+		// is reported once, as the most derived declaration.
+		for _, q := range pure {
+			if q != p && h.overrides(q, p) {
+				continue next
+			}
+		}
+		missing = append(missing, p)
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+// Incomplete returns the defined classes which fail to implement a pure virtual method of one
+// of their base classes, mapped to the USRs of these methods. Classes declaring pure virtual
+// methods themselves are interfaces and only reported for the methods they inherit.
+func (h *Hierarchy) Incomplete() map[string][]string {
+	incomplete := map[string][]string{}
+
+	for usr, c := range h.Classes {
+		if !c.IsDefined() {
+			continue
+		}
+
+		var inherited []string
+		for _, mu := range h.Unimplemented(usr) {
+			if m, ok := h.Methods[mu]; ok && m.Class != usr {
+				inherited = append(inherited, mu)
+			}
+		}
+		if len(inherited) > 0 {
+			incomplete[usr] = inherited
+		}
+	}
+
+	return incomplete
+}
+
+// Lookup returns the USRs of the classes and methods with the given qualified name or USR. A
+// name without qualification matches classes and methods of any scope.
+func (h *Hierarchy) Lookup(name string) []string {
+	if _, ok := h.Classes[name]; ok {
+		return []string{name}
+	}
+	if _, ok := h.Methods[name]; ok {
+		return []string{name}
+	}
+
+	match := func(qualified string) bool {
+		return qualified == name || len(qualified) > len(name)+2 && qualified[len(qualified)-len(name)-2:] == "::"+name
+	}
+
+	var usrs []string
+	for usr, c := range h.Classes {
+		if match(c.Name) {
+			usrs = append(usrs, usr)
+		}
+	}
+	for usr, m := range h.Methods {
+		if match(m.Name) {
+			usrs = append(usrs, usr)
+		}
+	}
+	sort.Strings(usrs)
+
+	return usrs
+}
@@ -0,0 +1,160 @@
+package hierarchy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// testHierarchies returns the hierarchies of two translation units:
+//
+//	struct Shape { virtual double area() = 0; virtual void draw() = 0; };   // shape.h
+//	struct Circle : Shape { double area() override; void draw() override; }; // a.cc
+//	struct Square : virtual Shape { double area() override; };              // b.cc
+//	struct Box : Square { void draw() override; };                          // b.cc
+func testHierarchies() (*Hierarchy, *Hierarchy) {
+	pos := func(file string, line uint32) clang.Position {
+		return clang.Position{File: file, Line: line, Column: 8, Offset: line * 10}
+	}
+
+	shape := func(h *Hierarchy) {
+		h.Classes["Shape"] = &Class{USR: "Shape", Name: "Shape", Kind: clang.Cursor_StructDecl, Definition: pos("/p/shape.h", 1),
+			Abstract: true, Methods: []string{"Shape::area", "Shape::draw"}}
+		h.Methods["Shape::area"] = &Method{USR: "Shape::area", Name: "Shape::area", Class: "Shape", PureVirtual: true}
+		h.Methods["Shape::draw"] = &Method{USR: "Shape::draw", Name: "Shape::draw", Class: "Shape", PureVirtual: true}
+	}
+
+	a := New()
+	shape(a)
+	a.Classes["Circle"] = &Class{USR: "Circle", Name: "Circle", Kind: clang.Cursor_StructDecl, Definition: pos("/p/a.cc", 2),
+		Bases: []Base{{USR: "Shape", Access: clang.AccessSpecifier_Public}}, Methods: []string{"Circle::area", "Circle::draw"}}
+	a.Methods["Circle::area"] = &Method{USR: "Circle::area", Name: "Circle::area", Class: "Circle", Overrides: []string{"Shape::area"}}
+	a.Methods["Circle::draw"] = &Method{USR: "Circle::draw", Name: "Circle::draw", Class: "Circle", Overrides: []string{"Shape::draw"}}
+
+	b := New()
+	shape(b)
+	b.Classes["Square"] = &Class{USR: "Square", Name: "geo::Square", Kind: clang.Cursor_StructDecl, Definition: pos("/p/b.cc", 3),
+		Abstract: true, Bases: []Base{{USR: "Shape", Virtual: true, Access: clang.AccessSpecifier_Public}}, Methods: []string{"Square::area"}}
+	b.Methods["Square::area"] = &Method{USR: "Square::area", Name: "geo::Square::area", Class: "Square", Overrides: []string{"Shape::area"}}
+	b.Classes["Box"] = &Class{USR: "Box", Name: "geo::Box", Kind: clang.Cursor_StructDecl, Definition: pos("/p/b.cc", 4),
+		Bases: []Base{{USR: "Square", Access: clang.AccessSpecifier_Public}}, Methods: []string{"Box::draw"}}
+	b.Methods["Box::draw"] = &Method{USR: "Box::draw", Name: "geo::Box::draw", Class: "Box", Overrides: []string{"Shape::draw"}}
+
+	return a, b
+}
+
+func TestHierarchy(t *testing.T) {
+	a, b := testHierarchies()
+
+	h := New()
+	h.Merge(a)
+	h.Merge(b)
+	h.Merge(a)
+
+	if n := len(h.Classes); n != 4 {
+		t.Errorf("expected 4 classes. got=%d", n)
+	}
+	if methods := h.Classes["Shape"].Methods; len(methods) != 2 {
+		t.Errorf("expected methods to be merged once. got=%v", methods)
+	}
+
+	if got := h.Subclasses("Shape"); !reflect.DeepEqual(got, []string{"Box", "Circle", "Square"}) {
+		t.Errorf("unexpected subclasses of Shape %v", got)
+	}
+	if got := h.Subclasses("Square"); !reflect.DeepEqual(got, []string{"Box"}) {
+		t.Errorf("unexpected subclasses of Square %v", got)
+	}
+	if got := h.Superclasses("Box"); !reflect.DeepEqual(got, []string{"Shape", "Square"}) {
+		t.Errorf("unexpected superclasses of Box %v", got)
+	}
+
+	if got := h.Implementations("Shape::area"); !reflect.DeepEqual(got, []string{"Circle::area", "Square::area"}) {
+		t.Errorf("unexpected implementations of Shape::area %v", got)
+	}
+	if got := h.Implementations("Circle::draw"); !reflect.DeepEqual(got, []string{"Circle::draw"}) {
+		t.Errorf("unexpected implementations of Circle::draw %v", got)
+	}
+
+	if got := h.Unimplemented("Square"); !reflect.DeepEqual(got, []string{"Shape::draw"}) {
+		t.Errorf("unexpected unimplemented methods of Square %v", got)
+	}
+	if got := h.Unimplemented("Box"); got != nil {
+		t.Errorf("expected Box to implement all methods. got=%v", got)
+	}
+	if got := h.Unimplemented("Shape"); len(got) != 2 {
+		t.Errorf("expected Shape to implement no method. got=%v", got)
+	}
+
+	want := map[string][]string{"Square": {"Shape::draw"}}
+	if got := h.Incomplete(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected incomplete classes %v. got=%v", want, got)
+	}
+
+	if got := h.Lookup("Square"); !reflect.DeepEqual(got, []string{"Square"}) {
+		t.Errorf("unexpected lookup of Square %v", got)
+	}
+	if got := h.Lookup("area"); len(got) != 3 {
+		t.Errorf("expected 3 methods named area. got=%v", got)
+	}
+	if got := h.Lookup("geo::Square::area"); !reflect.DeepEqual(got, []string{"Square::area"}) {
+		t.Errorf("unexpected lookup of geo::Square::area %v", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/shapes.cc", []string{"-std=c++11"}, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	h := Builder{Directory: dir}.Build(tu)
+
+	lookup := func(name string) string {
+		usrs := h.Lookup(name)
+		if len(usrs) != 1 {
+			t.Fatalf("expected one class or method named %s. got=%v", name, usrs)
+		}
+
+		return usrs[0]
+	}
+	shape, square, box := lookup("geo::Shape"), lookup("geo::Square"), lookup("geo::Box")
+	area, draw := lookup("geo::Shape::area"), lookup("geo::Shape::draw")
+
+	if c := h.Classes[shape]; !c.Abstract || c.Definition.File != filepath.Join(dir, "testdata", "shapes.cc") || c.Definition.Line != 2 {
+		t.Errorf("expected abstract geo::Shape defined at line 2. got=%+v", c)
+	}
+	if c := h.Classes[square]; !reflect.DeepEqual(c.Bases, []Base{{USR: shape, Virtual: true, Access: clang.AccessSpecifier_Public}}) {
+		t.Errorf("expected geo::Square to derive virtually from geo::Shape. got=%+v", c.Bases)
+	}
+	if c := h.Classes[box]; c.Kind != clang.Cursor_ClassDecl || c.Abstract || !reflect.DeepEqual(c.Bases, []Base{{USR: square, Access: clang.AccessSpecifier_Public}}) {
+		t.Errorf("expected concrete class geo::Box deriving from geo::Square. got=%+v", c)
+	}
+
+	if m := h.Methods[area]; !m.PureVirtual || m.Class != shape || m.Signature != "area()" {
+		t.Errorf("unexpected method geo::Shape::area %+v", m)
+	}
+	if got := h.Implementations(area); !reflect.DeepEqual(got, []string{lookup("geo::Square::area")}) {
+		t.Errorf("expected geo::Square::area to implement geo::Shape::area. got=%v", got)
+	}
+	if got := h.Overriders(draw); !reflect.DeepEqual(got, []string{lookup("geo::Box::draw")}) {
+		t.Errorf("expected geo::Box::draw to override geo::Shape::draw. got=%v", got)
+	}
+	if got := h.Unimplemented(square); !reflect.DeepEqual(got, []string{draw}) {
+		t.Errorf("expected geo::Square to miss geo::Shape::draw. got=%v", got)
+	}
+	if got := h.Unimplemented(box); len(got) != 0 {
+		t.Errorf("expected geo::Box to implement every method. got=%v", got)
+	}
+}
@@ -0,0 +1,13 @@
+namespace geo {
+struct Shape {
+  virtual ~Shape() {}
+  virtual double area() = 0;
+  virtual void draw() = 0;
+};
+struct Square : virtual Shape {
+  double area() override { return 1; }
+};
+class Box : public Square {
+  void draw() override {}
+};
+} // namespace geo
@@ -0,0 +1,107 @@
+package includes
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Builder builds the include graphs of translation units. The translation units must be parsed
+// with clang.TranslationUnit_DetailedPreprocessingRecord to report inclusion directives.
+type Builder struct {
+	// SkipSystemHeaders skips the directives in system headers. Directives including system
+	// headers are still recorded.
+	SkipSystemHeaders bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Build returns the include graph of the translation unit.
+func (b Builder) Build(tu clang.TranslationUnit) *Graph {
+	g := NewGraph()
+	paths := compdb.Paths{Directory: b.Directory}
+
+	var main string
+	var files []string
+	tu.Inclusions(func(f clang.File, stack []clang.SourceLocation) {
+		p := paths.Abs(f.Name())
+		if len(stack) == 0 {
+			main = p
+		} else {
+			files = append(files, p)
+		}
+
+		if _, ok := g.Files[p]; ok {
+			return
+		}
+		content, _ := tu.FileContents(f)
+		g.Files[p] = &File{
+			Path:   p,
+			Size:   int64(len(content)),
+			System: tu.Location(f, 1, 1).IsInSystemHeader(),
+		}
+	})
+	if main != "" {
+		sort.Strings(files)
+		g.Units[main] = dedupe(files)
+	}
+
+	tu.TranslationUnitCursor().Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if c.Kind() != clang.Cursor_InclusionDirective {
+			return clang.ChildVisit_Continue
+		}
+
+		loc := c.Location()
+		if b.SkipSystemHeaders && loc.IsInSystemHeader() {
+			return clang.ChildVisit_Continue
+		}
+		included := c.IncludedFile()
+		if included.Name() == "" {
+			return clang.ChildVisit_Continue
+		}
+
+		file, line, _, _ := loc.ExpansionLocation()
+		g.addEdge(Edge{
+			From:     paths.Abs(file.Name()),
+			To:       paths.Abs(included.Name()),
+			Line:     line,
+			Spelling: c.Spelling(),
+			Angled:   Angled(tu, c),
+		})
+
+		return clang.ChildVisit_Continue
+	})
+
+	return g
+}
+
+// Angled reports whether the inclusion directive c uses angle brackets.
+func Angled(tu clang.TranslationUnit, c clang.Cursor) bool {
+	tokens := tu.Tokenize(c.Extent())
+	defer tu.DisposeTokens(tokens)
+
+	for _, t := range tokens {
+		switch tu.TokenSpelling(t) {
+		case "<":
+			return true
+		case "#", "include", "include_next", "import":
+			continue
+		}
+
+		break
+	}
+
+	return false
+}
+
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
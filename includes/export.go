@@ -0,0 +1,141 @@
+package includes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type jsonGraph struct {
+	Files  []jsonFile `json:"files"`
+	Edges  []jsonEdge `json:"edges"`
+	Cycles [][]string `json:"cycles"`
+	Units  []jsonUnit `json:"units"`
+}
+
+type jsonFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	System bool   `json:"system,omitempty"`
+	// Files and Bytes are the transitive cost of including the file.
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+	// Units is the number of translation units including the file.
+	Units int `json:"units"`
+}
+
+type jsonEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Line     uint32 `json:"line"`
+	Spelling string `json:"spelling"`
+	Angled   bool   `json:"angled,omitempty"`
+}
+
+type jsonUnit struct {
+	Source string `json:"source"`
+	Files  int    `json:"files"`
+}
+
+// WriteJSON writes the graph as JSON object with the files and their costs, the directives,
+// the include cycles and the number of files of every translation unit.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out := jsonGraph{
+		Files:  []jsonFile{},
+		Edges:  []jsonEdge{},
+		Cycles: g.Cycles(),
+		Units:  []jsonUnit{},
+	}
+	if out.Cycles == nil {
+		out.Cycles = [][]string{}
+	}
+
+	units := g.unitCounts()
+	for _, path := range g.Paths() {
+		f := g.Files[path]
+		c := g.cost(path, units)
+		out.Files = append(out.Files, jsonFile{
+			Path:   f.Path,
+			Size:   f.Size,
+			System: f.System,
+			Files:  c.Files,
+			Bytes:  c.Bytes,
+			Units:  c.Units,
+		})
+
+		if files, ok := g.Units[path]; ok {
+			out.Units = append(out.Units, jsonUnit{Source: path, Files: len(files)})
+		}
+	}
+
+	for _, e := range g.Edges {
+		out.Edges = append(out.Edges, jsonEdge(e))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz. Main files are drawn as ellipses,
+// system headers dashed and the directives forming include cycles red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	ids := map[string]string{}
+	inCycle := map[string]int{}
+	for i, cycle := range g.Cycles() {
+		for _, f := range cycle {
+			inCycle[f] = i + 1
+		}
+	}
+
+	fmt.Fprintln(bw, "digraph includes {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+
+	for i, path := range g.Paths() {
+		f := g.Files[path]
+		id := "f" + strconv.Itoa(i)
+		ids[path] = id
+
+		attrs := "label=" + strconv.Quote(path)
+		if _, ok := g.Units[path]; ok {
+			attrs += ", shape=ellipse"
+		}
+		if f.System {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(bw, "\t%s [%s];\n", id, attrs)
+	}
+
+	seen := map[[2]string]bool{}
+	for _, e := range g.Edges {
+		key := [2]string{e.From, e.To}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		from, ok := ids[e.From]
+		if !ok {
+			continue
+		}
+		to, ok := ids[e.To]
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(bw, "\t%s -> %s", from, to)
+		if c := inCycle[e.From]; c != 0 && c == inCycle[e.To] {
+			fmt.Fprint(bw, " [color=red]")
+		}
+		fmt.Fprintln(bw, ";")
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
@@ -0,0 +1,346 @@
+// Package includes builds the include graph of a project from the inclusion directives of its
+// translation units and analyses it: include cycles, the transitive cost of headers and
+// headers included by almost every translation unit.
+package includes
+
+import (
+	"sort"
+)
+
+// File is a source file or header.
+type File struct {
+	Path string
+	// Size is the size of the content in bytes.
+	Size   int64
+	System bool
+}
+
+// Edge is an inclusion directive.
+type Edge struct {
+	// From is the file containing the directive, To the included file.
+	From, To string
+	Line     uint32
+	// Spelling is the file name as written in the directive, without delimiters.
+	Spelling string
+	// Angled is set for directives of the form #include <file>.
+	Angled bool
+}
+
+// Graph is the include graph of a project.
+type Graph struct {
+	Files map[string]*File
+	// Edges are in the order they were found.
+	Edges []Edge
+	// Units maps the main file of every translation unit to all files it includes, directly
+	// or indirectly.
+	Units map[string][]string
+
+	seen map[Edge]bool
+	out  map[string][]string
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		Files: map[string]*File{},
+		Units: map[string][]string{},
+	}
+}
+
+// Merge adds the files, edges and translation units of other to g.
+func (g *Graph) Merge(other *Graph) {
+	for path, f := range other.Files {
+		if _, ok := g.Files[path]; !ok {
+			cp := *f
+			g.Files[path] = &cp
+		}
+	}
+
+	for _, e := range other.Edges {
+		g.addEdge(e)
+	}
+
+	for source, files := range other.Units {
+		g.Units[source] = append([]string(nil), files...)
+	}
+
+	g.out = nil
+}
+
+func (g *Graph) addEdge(e Edge) {
+	if g.seen == nil {
+		g.seen = make(map[Edge]bool, len(g.Edges))
+		for _, e := range g.Edges {
+			g.seen[e] = true
+		}
+	}
+
+	if !g.seen[e] {
+		g.seen[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// Paths returns the paths of all files in sorted order.
+func (g *Graph) Paths() []string {
+	paths := make([]string, 0, len(g.Files))
+	for path := range g.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Includes returns the sorted files directly included by file.
+func (g *Graph) Includes(file string) []string {
+	if g.out == nil {
+		g.out = map[string][]string{}
+
+		seen := map[[2]string]bool{}
+		for _, e := range g.Edges {
+			if key := [2]string{e.From, e.To}; !seen[key] {
+				seen[key] = true
+				g.out[e.From] = append(g.out[e.From], e.To)
+			}
+		}
+		for _, to := range g.out {
+			sort.Strings(to)
+		}
+	}
+
+	return g.out[file]
+}
+
+// IncludedBy returns the directives including file.
+func (g *Graph) IncludedBy(file string) []Edge {
+	var edges []Edge
+	for _, e := range g.Edges {
+		if e.To == file {
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// Reachable returns the sorted files directly or indirectly included by file, without file.
+func (g *Graph) Reachable(file string) []string {
+	seen := map[string]bool{file: true}
+	var result []string
+
+	stack := []string{file}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, to := range g.Includes(f) {
+			if !seen[to] {
+				seen[to] = true
+				result = append(result, to)
+				stack = append(stack, to)
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Cycles returns the include cycles of the graph. Every cycle is a strongly connected set of
+// files in sorted order; the cycles are sorted by their first file. Files including themselves
+// form cycles of length one.
+func (g *Graph) Cycles() [][]string {
+	// Tarjan's algorithm, iterative to handle deep include chains.
+	type frame struct {
+		file string
+		next int
+	}
+
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+
+	for _, root := range g.Paths() {
+		if _, ok := index[root]; ok {
+			continue
+		}
+
+		frames := []frame{{file: root}}
+		index[root], low[root] = len(index), len(index)
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(frames) > 0 {
+			fr := &frames[len(frames)-1]
+			includes := g.Includes(fr.file)
+
+			if fr.next < len(includes) {
+				to := includes[fr.next]
+				fr.next++
+
+				if _, ok := index[to]; !ok {
+					index[to], low[to] = len(index), len(index)
+					stack = append(stack, to)
+					onStack[to] = true
+					frames = append(frames, frame{file: to})
+				} else if onStack[to] && index[to] < low[fr.file] {
+					low[fr.file] = index[to]
+				}
+
+				continue
+			}
+
+			file := fr.file
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].file
+				if low[file] < low[parent] {
+					low[parent] = low[file]
+				}
+			}
+
+			if low[file] != index[file] {
+				continue
+			}
+
+			var scc []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				scc = append(scc, top)
+				if top == file {
+					break
+				}
+			}
+
+			if len(scc) > 1 || g.includesItself(file) {
+				sort.Strings(scc)
+				cycles = append(cycles, scc)
+			}
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+func (g *Graph) includesItself(file string) bool {
+	for _, to := range g.Includes(file) {
+		if to == file {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cost is the transitive cost of including a header.
+type Cost struct {
+	File string
+	// Files is the number of files pulled in, including the header itself.
+	Files int
+	// Bytes is the total size of these files.
+	Bytes int64
+	// Units is the number of translation units including the header.
+	Units int
+}
+
+// Cost returns the transitive cost of including file.
+func (g *Graph) Cost(file string) Cost {
+	return g.cost(file, g.unitCounts())
+}
+
+func (g *Graph) cost(file string, units map[string]int) Cost {
+	c := Cost{File: file, Files: 1, Units: units[file]}
+	if f, ok := g.Files[file]; ok {
+		c.Bytes = f.Size
+	}
+
+	for _, r := range g.Reachable(file) {
+		c.Files++
+		if f, ok := g.Files[r]; ok {
+			c.Bytes += f.Size
+		}
+	}
+
+	return c
+}
+
+// unitCounts returns the number of translation units including each file.
+func (g *Graph) unitCounts() map[string]int {
+	counts := map[string]int{}
+	for _, files := range g.Units {
+		for _, f := range files {
+			counts[f]++
+		}
+	}
+
+	return counts
+}
+
+// Costs returns the costs of all included files, the most expensive first. The cost of a
+// header is weighted by the number of translation units including it, so headers which are
+// both big and widely used come first.
+func (g *Graph) Costs() []Cost {
+	units := g.unitCounts()
+
+	var costs []Cost
+	for _, path := range g.Paths() {
+		if _, ok := g.Units[path]; ok {
+			continue
+		}
+		costs = append(costs, g.cost(path, units))
+	}
+
+	sort.SliceStable(costs, func(i, j int) bool {
+		bi, bj := costs[i].Bytes*int64(costs[i].Units), costs[j].Bytes*int64(costs[j].Units)
+		if bi != bj {
+			return bi > bj
+		}
+
+		return costs[i].Bytes > costs[j].Bytes
+	})
+
+	return costs
+}
+
+// Usage is the number of translation units including a header.
+type Usage struct {
+	File  string
+	Units int
+}
+
+// Universal returns the headers included by at least the given fraction of all translation
+// units, e.g. 0.9, the most used first. System headers are left out unless system is set.
+func (g *Graph) Universal(fraction float64, system bool) []Usage {
+	counts := g.unitCounts()
+	min := fraction * float64(len(g.Units))
+
+	var usages []Usage
+	for file, n := range counts {
+		if float64(n) < min {
+			continue
+		}
+		if f, ok := g.Files[file]; ok && f.System && !system {
+			continue
+		}
+		usages = append(usages, Usage{File: file, Units: n})
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Units != usages[j].Units {
+			return usages[i].Units > usages[j].Units
+		}
+
+		return usages[i].File < usages[j].File
+	})
+
+	return usages
+}
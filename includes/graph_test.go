@@ -0,0 +1,202 @@
+package includes
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// testGraph returns the merged graph of three translation units:
+//
+//	a.cc -> a.h -> common.h -> <vector>
+//	b.cc -> b.h -> c.h -> b.h (cycle), b.h -> common.h
+//	c.cc -> common.h
+func testGraph() *Graph {
+	file := func(g *Graph, path string, size int64, system bool) {
+		g.Files[path] = &File{Path: path, Size: size, System: system}
+	}
+
+	a := NewGraph()
+	file(a, "/p/a.cc", 100, false)
+	file(a, "/p/a.h", 200, false)
+	file(a, "/p/common.h", 300, false)
+	file(a, "/usr/include/vector", 4000, true)
+	a.Edges = []Edge{
+		{From: "/p/a.cc", To: "/p/a.h", Line: 1, Spelling: "a.h"},
+		{From: "/p/a.h", To: "/p/common.h", Line: 1, Spelling: "common.h"},
+		{From: "/p/common.h", To: "/usr/include/vector", Line: 1, Spelling: "vector", Angled: true},
+	}
+	a.Units["/p/a.cc"] = []string{"/p/a.h", "/p/common.h", "/usr/include/vector"}
+
+	b := NewGraph()
+	file(b, "/p/b.cc", 100, false)
+	file(b, "/p/b.h", 10, false)
+	file(b, "/p/c.h", 20, false)
+	file(b, "/p/common.h", 300, false)
+	file(b, "/usr/include/vector", 4000, true)
+	b.Edges = []Edge{
+		{From: "/p/b.cc", To: "/p/b.h", Line: 1, Spelling: "b.h"},
+		{From: "/p/b.h", To: "/p/c.h", Line: 2, Spelling: "c.h"},
+		{From: "/p/c.h", To: "/p/b.h", Line: 2, Spelling: "b.h"},
+		{From: "/p/b.h", To: "/p/common.h", Line: 3, Spelling: "common.h"},
+		{From: "/p/common.h", To: "/usr/include/vector", Line: 1, Spelling: "vector", Angled: true},
+	}
+	b.Units["/p/b.cc"] = []string{"/p/b.h", "/p/c.h", "/p/common.h", "/usr/include/vector"}
+
+	c := NewGraph()
+	file(c, "/p/c.cc", 100, false)
+	file(c, "/p/common.h", 300, false)
+	file(c, "/usr/include/vector", 4000, true)
+	c.Edges = []Edge{
+		{From: "/p/c.cc", To: "/p/common.h", Line: 5, Spelling: "common.h"},
+		{From: "/p/common.h", To: "/usr/include/vector", Line: 1, Spelling: "vector", Angled: true},
+	}
+	c.Units["/p/c.cc"] = []string{"/p/common.h", "/usr/include/vector"}
+
+	g := NewGraph()
+	g.Merge(a)
+	g.Merge(b)
+	g.Merge(c)
+
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph()
+
+	if n := len(g.Edges); n != 8 {
+		t.Errorf("expected 8 distinct directives. got=%d", n)
+	}
+	if got := g.Reachable("/p/b.h"); !reflect.DeepEqual(got, []string{"/p/c.h", "/p/common.h", "/usr/include/vector"}) {
+		t.Errorf("unexpected files reachable from b.h %v", got)
+	}
+	if got := g.IncludedBy("/p/common.h"); len(got) != 3 {
+		t.Errorf("expected common.h to be included 3 times. got=%+v", got)
+	}
+
+	if got, want := g.Cycles(), [][]string{{"/p/b.h", "/p/c.h"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected cycles %v. got=%v", want, got)
+	}
+
+	if got, want := g.Cost("/p/a.h"), (Cost{File: "/p/a.h", Files: 3, Bytes: 4500, Units: 1}); got != want {
+		t.Errorf("expected cost %+v. got=%+v", want, got)
+	}
+	costs := g.Costs()
+	if len(costs) != 5 || costs[0].File != "/p/common.h" || costs[1].File != "/usr/include/vector" {
+		t.Errorf("unexpected costs %+v", costs)
+	}
+
+	want := []Usage{{File: "/p/common.h", Units: 3}}
+	if got := g.Universal(0.9, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected universal headers %v. got=%v", want, got)
+	}
+	if got := g.Universal(0.9, true); len(got) != 2 {
+		t.Errorf("expected the system header to be universal. got=%v", got)
+	}
+}
+
+func TestCyclesSelfInclude(t *testing.T) {
+	g := NewGraph()
+	g.Files["/p/x.h"] = &File{Path: "/p/x.h"}
+	g.Edges = []Edge{{From: "/p/x.h", To: "/p/x.h", Line: 1, Spelling: "x.h"}}
+
+	if got, want := g.Cycles(), [][]string{{"/p/x.h"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected cycles %v. got=%v", want, got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := testGraph()
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out jsonGraph
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Files) != 8 || len(out.Edges) != 8 || len(out.Cycles) != 1 || len(out.Units) != 3 {
+		t.Errorf("unexpected JSON output %+v", out)
+	}
+
+	buf.Reset()
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{
+		`f0 [label="/p/a.cc", shape=ellipse];`,
+		`f7 [label="/usr/include/vector", style=dashed];`,
+		"f3 -> f5 [color=red];",
+		"f5 -> f3 [color=red];",
+		"f6 -> f7;",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected DOT output to contain %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/main.c", []string{"-Itestdata"}, nil, uint32(clang.TranslationUnit_DetailedPreprocessingRecord))
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	g := Builder{Directory: dir}.Build(tu)
+
+	path := func(name string) string {
+		return filepath.Join(dir, "testdata", name)
+	}
+	main, a, b := path("main.c"), path("a.h"), path("b.h")
+
+	if got := g.Paths(); !reflect.DeepEqual(got, []string{a, b, main}) {
+		t.Errorf("unexpected files %v", got)
+	}
+	if f := g.Files[main]; f.Size != 68 || f.System {
+		t.Errorf("unexpected main file %+v", f)
+	}
+	if got := g.Units[main]; !reflect.DeepEqual(got, []string{a, b}) {
+		t.Errorf("expected main.c to include a.h and b.h. got=%v", got)
+	}
+
+	edges := append([]Edge(nil), g.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+
+		return edges[i].Line < edges[j].Line
+	})
+	want := []Edge{
+		{From: a, To: b, Line: 3, Spelling: "b.h"},
+		{From: b, To: a, Line: 3, Spelling: "a.h"},
+		{From: main, To: a, Line: 1, Spelling: "a.h"},
+		{From: main, To: b, Line: 2, Spelling: "b.h", Angled: true},
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("expected edges %+v. got=%+v", want, edges)
+	}
+
+	if got, want := g.Cycles(), [][]string{{a, b}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected cycles %v. got=%v", want, got)
+	}
+}
@@ -0,0 +1,5 @@
+#ifndef A_H
+#define A_H
+#include "b.h"
+static int a(void) { return 1; }
+#endif
@@ -0,0 +1,5 @@
+#ifndef B_H
+#define B_H
+#include "a.h"
+static int b(void) { return 2; }
+#endif
@@ -0,0 +1,236 @@
+// Package incremental runs an analysis over the translation units of a compilation database
+// and reuses the results of a previous run for translation units whose inputs did not change.
+//
+// The inputs of a translation unit are its compile command and the content of every file it
+// included. They are recorded together with the result of the analysis in a state directory,
+// so only translation units with changed arguments, sources or headers are parsed again.
+package incremental
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// stateVersion is the version of the on-disk format. States of other versions are discarded.
+const stateVersion = 1
+
+// FileState identifies the content of a file a translation unit included.
+type FileState struct {
+	Path string `json:"path"`
+	// Hash is the hex encoded SHA-256 of the content.
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+	// ModTime is the modification time as reported by clang.File.Time.
+	ModTime time.Time `json:"mtime"`
+}
+
+// unitState describes the inputs and the result of a translation unit.
+type unitState struct {
+	Source   string      `json:"source"`
+	ArgsHash string      `json:"args"`
+	Result   string      `json:"result"`
+	Files    []FileState `json:"files"`
+}
+
+type state struct {
+	Version int `json:"version"`
+	// Analysis identifies the analysis the results were produced by.
+	Analysis string                `json:"analysis"`
+	Units    map[string]*unitState `json:"units"`
+}
+
+// Driver keeps the state of an incremental analysis in a directory.
+//
+// A Driver must not be used concurrently.
+type Driver struct {
+	// Options are the options translation units are parsed with.
+	Options uint32
+
+	dir   string
+	state state
+}
+
+// Open opens the state in dir, which is created if it does not exist. The analysis identifies
+// the analysis and its version, the results of a different analysis are discarded.
+func Open(dir, analysis string) (*Driver, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "results"), 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &Driver{
+		dir: dir,
+		state: state{
+			Version:  stateVersion,
+			Analysis: analysis,
+			Units:    map[string]*unitState{},
+		},
+	}
+
+	b, err := os.ReadFile(d.statePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("read %s: %w", d.statePath(), err)
+	}
+	if s.Version == stateVersion && s.Analysis == analysis && s.Units != nil {
+		d.state = s
+	}
+
+	return d, nil
+}
+
+func (d *Driver) statePath() string {
+	return filepath.Join(d.dir, "state.json")
+}
+
+func (d *Driver) resultPath(name string) string {
+	return filepath.Join(d.dir, "results", name)
+}
+
+// Sources returns the source files of the translation units with a result.
+func (d *Driver) Sources() []string {
+	sources := make([]string, 0, len(d.state.Units))
+	for s := range d.state.Units {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	return sources
+}
+
+// Files returns the files the translation unit of source included when it was analyzed.
+func (d *Driver) Files(source string) []FileState {
+	if st, ok := d.state.Units[source]; ok {
+		return st.Files
+	}
+
+	return nil
+}
+
+// Result returns the result of the analysis of the translation unit of source.
+func (d *Driver) Result(source string) ([]byte, error) {
+	st, ok := d.state.Units[source]
+	if !ok {
+		return nil, fmt.Errorf("no result for %s", source)
+	}
+
+	return os.ReadFile(d.resultPath(st.Result))
+}
+
+// Stale reports whether the translation unit of cmd has to be analyzed again, because it was
+// never analyzed or its arguments or the content of one of its files changed.
+func (d *Driver) Stale(cmd compdb.Command) bool {
+	return d.stale(cmd, newChecker())
+}
+
+func (d *Driver) stale(cmd compdb.Command, c *checker) bool {
+	st, ok := d.state.Units[cmd.Path()]
+	if !ok || st.ArgsHash != hashArgs(cmd) {
+		return true
+	}
+
+	for _, f := range st.Files {
+		if !c.unchanged(f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Store records result as the result of the translation unit of cmd, which included files.
+// It is meant for callers which parse translation units themselves, Run stores the results of
+// the translation units it analyzes. The state is written by the next Save or Run.
+func (d *Driver) Store(cmd compdb.Command, files []FileState, result []byte) error {
+	st := &unitState{
+		Source:   cmd.Path(),
+		ArgsHash: hashArgs(cmd),
+		Result:   hashString(cmd.Path())[:16],
+		Files:    files,
+	}
+	if err := writeFile(d.resultPath(st.Result), result); err != nil {
+		return err
+	}
+	d.state.Units[st.Source] = st
+
+	return nil
+}
+
+// Save writes the state to the state directory.
+func (d *Driver) Save() error {
+	b, err := json.MarshalIndent(d.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(d.statePath(), b)
+}
+
+// checker checks whether files still have their recorded content. Headers are included by
+// many translation units, so the results are cached.
+type checker struct {
+	hashes map[string]string
+}
+
+func newChecker() *checker {
+	return &checker{hashes: map[string]string{}}
+}
+
+// unchanged reports whether the file still has the recorded content. The content is only
+// hashed if the size or the modification time changed.
+func (c *checker) unchanged(f FileState) bool {
+	hash, ok := c.hashes[f.Path]
+	if !ok {
+		fi, err := os.Stat(f.Path)
+		switch {
+		case err != nil:
+			hash = ""
+		// clang.File.Time has a resolution of seconds.
+		case fi.Size() == f.Size && fi.ModTime().Unix() == f.ModTime.Unix():
+			hash = f.Hash
+		default:
+			if b, err := os.ReadFile(f.Path); err == nil {
+				hash = hashString(string(b))
+			}
+		}
+		c.hashes[f.Path] = hash
+	}
+
+	return hash != "" && hash == f.Hash
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hashArgs(cmd compdb.Command) string {
+	return hashString(cmd.Directory + "\x00" + strings.Join(cmd.Args, "\x00"))
+}
+
+func writeFile(path string, b []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,192 @@
+package incremental
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// record stores result as the result of cmd with the current state of files, without parsing.
+func record(t *testing.T, d *Driver, cmd compdb.Command, result string, files ...string) {
+	t.Helper()
+
+	var states []FileState
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states = append(states, FileState{Path: f, Hash: hashString(string(b)), Size: fi.Size(), ModTime: fi.ModTime()})
+	}
+	if err := d.Store(cmd, states, []byte(result)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStale(t *testing.T) {
+	src := t.TempDir()
+	a := filepath.Join(src, "a.c")
+	h := filepath.Join(src, "a.h")
+	for _, f := range []string{a, h} {
+		if err := os.WriteFile(f, []byte("int a;"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := Open(t.TempDir(), "test/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := compdb.Command{Directory: src, Filename: "a.c", Args: []string{"cc", "-c", "a.c"}}
+	if !d.Stale(cmd) {
+		t.Error("Stale = false for a translation unit without result")
+	}
+
+	record(t, d, cmd, "result", a, h)
+	if d.Stale(cmd) {
+		t.Error("Stale = true for an unchanged translation unit")
+	}
+
+	other := cmd
+	other.Args = []string{"cc", "-DX", "-c", "a.c"}
+	if !d.Stale(other) {
+		t.Error("Stale = false after the arguments changed")
+	}
+
+	// Same size and a modification time in the same second, only the hash tells the difference.
+	if err := os.WriteFile(h, []byte("int b;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.state.Units[cmd.Path()].Files[1].ModTime = fi.ModTime().Add(time.Second)
+	if !d.Stale(cmd) {
+		t.Error("Stale = false after a header changed")
+	}
+
+	if err := os.Remove(h); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Stale(cmd) {
+		t.Error("Stale = false after a header was removed")
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	src := t.TempDir()
+	a := filepath.Join(src, "a.c")
+	if err := os.WriteFile(a, []byte("int a;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	d, err := Open(dir, "test/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := compdb.Command{Directory: src, Filename: "a.c", Args: []string{"cc", "-c", "a.c"}}
+	record(t, d, cmd, "result", a)
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = Open(dir, "test/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Sources(); len(got) != 1 || got[0] != a {
+		t.Errorf("Sources = %v, want [%s]", got, a)
+	}
+	if d.Stale(cmd) {
+		t.Error("Stale = true after reopening")
+	}
+	if b, err := d.Result(a); err != nil || string(b) != "result" {
+		t.Errorf("Result = %q, %v, want %q", b, err, "result")
+	}
+
+	d, err = Open(dir, "test/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Sources(); len(got) != 0 {
+		t.Errorf("Sources of another analysis = %v, want none", got)
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.h")
+	if err := os.WriteFile(filename, []byte("int a;\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := FileState{Path: filename, Hash: hashString("int a;\n"), Size: fi.Size(), ModTime: fi.ModTime().Truncate(time.Second)}
+	if !newChecker().unchanged(f) {
+		t.Error("expected unchanged file to be unchanged")
+	}
+
+	// Touching the file keeps the content.
+	later := fi.ModTime().Add(time.Hour)
+	if err := os.Chtimes(filename, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !newChecker().unchanged(f) {
+		t.Error("expected touched file to be unchanged")
+	}
+
+	if err := os.WriteFile(filename, []byte("long b;\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if newChecker().unchanged(f) {
+		t.Error("expected changed file to be changed")
+	}
+
+	if newChecker().unchanged(FileState{Path: filename + ".missing"}) {
+		t.Error("expected missing file to be changed")
+	}
+}
+
+func TestRunDuplicates(t *testing.T) {
+	src := t.TempDir()
+	a := filepath.Join(src, "a.c")
+	if err := os.WriteFile(a, []byte("int a;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Open(t.TempDir(), "test/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := compdb.Command{Directory: src, Filename: "a.c", Args: []string{"cc", "-c", "a.c"}}
+	other := cmd
+	other.Args = []string{"cc", "-DX", "-c", "a.c"}
+	record(t, d, cmd, "result", a)
+
+	// Neither command is parsed: the first is unchanged and the second compiles the same file.
+	stats, err := d.Run(clang.Index{}, []compdb.Command{cmd, other}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Reused != 1 || stats.Duplicates != 1 || stats.Analyzed != 0 || stats.Removed != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if d.Stale(cmd) {
+		t.Error("Stale = true for the first command after the run")
+	}
+}
@@ -0,0 +1,117 @@
+package incremental
+
+import (
+	"os"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Analyzer analyzes the translation unit of cmd. The result is stored as it is, callers
+// usually encode it with encoding/gob or encoding/json.
+type Analyzer func(cmd compdb.Command, tu clang.TranslationUnit) ([]byte, error)
+
+// Stats reports what a run did.
+type Stats struct {
+	Analyzed int
+	Reused   int
+	Removed  int
+	// Duplicates counts the commands which were ignored because an earlier command compiles the
+	// same source file.
+	Duplicates int
+	// Errors holds the translation units which could not be parsed or analyzed. Their previous
+	// results are kept.
+	Errors []error
+}
+
+// Run analyzes the translation units of cmds which are stale, and drops the results of
+// translation units which are no longer part of cmds. The state is saved when the run is done.
+//
+// The state is kept per source file, so only the first command of a source file is used. Later
+// commands compiling it again, e.g. for another configuration, would replace its result on
+// every run.
+func (d *Driver) Run(idx clang.Index, cmds []compdb.Command, analyze Analyzer) (Stats, error) {
+	var stats Stats
+
+	c := newChecker()
+	hashes := map[[3]uint64]string{}
+	current := map[string]bool{}
+
+	for _, cmd := range cmds {
+		if current[cmd.Path()] {
+			stats.Duplicates++
+
+			continue
+		}
+		current[cmd.Path()] = true
+
+		if !d.stale(cmd, c) {
+			stats.Reused++
+
+			continue
+		}
+
+		if err := d.analyze(idx, cmd, analyze, hashes); err != nil {
+			stats.Errors = append(stats.Errors, err)
+
+			continue
+		}
+		stats.Analyzed++
+	}
+
+	for source, st := range d.state.Units {
+		if !current[source] {
+			delete(d.state.Units, source)
+			os.Remove(d.resultPath(st.Result))
+			stats.Removed++
+		}
+	}
+
+	return stats, d.Save()
+}
+
+// analyze analyzes a single translation unit and records its inputs. The hashes of the files
+// are cached by their unique ID, which changes with their modification time.
+func (d *Driver) analyze(idx clang.Index, cmd compdb.Command, analyze Analyzer, hashes map[[3]uint64]string) error {
+	tu, err := cmd.Parse(idx, nil, d.Options)
+	if err != nil {
+		return err
+	}
+	defer tu.Dispose()
+
+	result, err := analyze(cmd, tu)
+	if err != nil {
+		return err
+	}
+
+	var files []FileState
+	seen := map[string]bool{}
+	tu.Inclusions(func(f clang.File, _ []clang.SourceLocation) {
+		path := compdb.Abs(cmd.Directory, f.Name())
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+
+		content, _ := tu.FileContents(f)
+
+		id, failed := f.UniqueID()
+		key := id.Data()
+		hash, ok := hashes[key]
+		if !ok || failed != 0 {
+			hash = hashString(content)
+			if failed == 0 {
+				hashes[key] = hash
+			}
+		}
+
+		files = append(files, FileState{
+			Path:    path,
+			Hash:    hash,
+			Size:    int64(len(content)),
+			ModTime: f.Time(),
+		})
+	})
+
+	return d.Store(cmd, files, result)
+}
@@ -0,0 +1,220 @@
+// Command gen-enumtext generates text marshaling and name parsing for the enumeration types of
+// the clang package.
+//
+// It parses the generated *_gen.go files of a directory, collects every integer type that has a
+// Spelling method together with the constants of its const block, and writes the methods
+// MarshalText and UnmarshalText as well as a ParseXxx function for each of them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// flagTypes are the enumerations whose values are combined as bit sets.
+var flagTypes = map[string]bool{
+	"CompletionContext":        true,
+	"DeclQualifierKind":        true,
+	"DiagnosticDisplayOptions": true,
+	"PropertyAttrKind":         true,
+	"SymbolRole":               true,
+}
+
+type enum struct {
+	name   string
+	recv   string
+	prefix string
+	consts []string
+	flags  bool
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the clang package")
+	out := flag.String("o", "enumtext_gen.go", "output file, relative to dir")
+	flag.Parse()
+
+	enums, err := collect(*dir, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(enums)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(*dir, *out), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func collect(dir, out string) ([]*enum, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*_gen.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+
+	var enums []*enum
+	for _, filename := range files {
+		if filepath.Base(filename) == out {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		types := map[string]bool{}
+		recvs := map[string]string{}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if id, ok := ts.Type.(*ast.Ident); ok && isInteger(id.Name) {
+						types[ts.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || d.Name.Name != "Spelling" {
+					continue
+				}
+
+				field := d.Recv.List[0]
+				if id, ok := field.Type.(*ast.Ident); ok && len(field.Names) > 0 {
+					recvs[id.Name] = field.Names[0].Name
+				}
+			}
+		}
+
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.CONST || len(d.Specs) == 0 {
+				continue
+			}
+
+			first := d.Specs[0].(*ast.ValueSpec)
+			id, ok := first.Type.(*ast.Ident)
+			if !ok || !types[id.Name] || recvs[id.Name] == "" {
+				continue
+			}
+
+			e := &enum{
+				name:  id.Name,
+				recv:  recvs[id.Name],
+				flags: flagTypes[id.Name] || strings.Contains(id.Name, "Flags"),
+			}
+			for _, spec := range d.Specs {
+				for _, n := range spec.(*ast.ValueSpec).Names {
+					e.consts = append(e.consts, n.Name)
+				}
+			}
+			e.prefix = commonPrefix(e.consts)
+
+			enums = append(enums, e)
+		}
+	}
+
+	sort.Slice(enums, func(i, j int) bool {
+		return enums[i].name < enums[j].name
+	})
+
+	return enums, nil
+}
+
+func isInteger(name string) bool {
+	switch name {
+	case "int32", "uint32", "int64", "uint64", "int", "uint":
+		return true
+	}
+
+	return false
+}
+
+// commonPrefix returns the longest common prefix of the names which ends with an underscore.
+func commonPrefix(names []string) string {
+	p := names[0]
+	for _, n := range names[1:] {
+		for !strings.HasPrefix(n, p) {
+			p = p[:len(p)-1]
+		}
+	}
+
+	return p[:strings.LastIndex(p, "_")+1]
+}
+
+// lowerFirst lower cases the leading upper case letters of s, keeping the last one of a longer
+// run which starts the next word, e.g. "TLSKind" becomes "tlsKind".
+func lowerFirst(s string) string {
+	r := []rune(s)
+
+	n := 0
+	for n < len(r) && unicode.IsUpper(r[n]) {
+		n++
+	}
+	if n > 1 && n < len(r) {
+		n--
+	}
+
+	for i := 0; i < n; i++ {
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
+
+func generate(enums []*enum) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("package clang\n\n")
+
+	for _, e := range enums {
+		table := lowerFirst(strings.ReplaceAll(e.name, "_", "")) + "Text"
+
+		fmt.Fprintf(&b, "var %s = newEnumText(%q, %q, %v, []enumName{\n", table, e.name, e.prefix, e.flags)
+		for _, c := range e.consts {
+			fmt.Fprintf(&b, "\t{int64(%s), %q},\n", c, strings.TrimPrefix(c, e.prefix))
+		}
+		b.WriteString("})\n\n")
+
+		fmt.Fprintf(&b, "// MarshalText implements the encoding.TextMarshaler interface.\n")
+		fmt.Fprintf(&b, "func (%s %s) MarshalText() ([]byte, error) {\n", e.recv, e.name)
+		fmt.Fprintf(&b, "\treturn []byte(%s.format(int64(%s))), nil\n}\n\n", table, e.recv)
+
+		fmt.Fprintf(&b, "// UnmarshalText implements the encoding.TextUnmarshaler interface.\n")
+		fmt.Fprintf(&b, "func (%s *%s) UnmarshalText(text []byte) error {\n", e.recv, e.name)
+		fmt.Fprintf(&b, "\tv, err := %s.parse(string(text))\n", table)
+		fmt.Fprintf(&b, "\tif err != nil {\n\t\treturn err\n\t}\n\n")
+		fmt.Fprintf(&b, "\t*%s = %s(v)\n\n\treturn nil\n}\n\n", e.recv, e.name)
+
+		example := strings.TrimPrefix(e.consts[0], e.prefix)
+		fmt.Fprintf(&b, "// Parse%s returns the %s with the given name, e.g. %q.\n", strings.ReplaceAll(e.name, "_", ""), e.name, example)
+		if e.flags {
+			fmt.Fprintf(&b, "//\n// Several flags can be combined with \"|\".\n")
+		}
+		fmt.Fprintf(&b, "func Parse%s(s string) (%s, error) {\n", strings.ReplaceAll(e.name, "_", ""), e.name)
+		fmt.Fprintf(&b, "\tv, err := %s.parse(s)\n\n", table)
+		fmt.Fprintf(&b, "\treturn %s(v), err\n}\n\n", e.name)
+	}
+
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,195 @@
+package iwyu
+
+import (
+	"fmt"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/includes"
+)
+
+// Analyze reports the unused and missing includes of the main file of the translation unit.
+// The translation unit must be parsed with clang.TranslationUnit_DetailedPreprocessingRecord
+// to see the uses of macros.
+func (a Analyzer) Analyze(tu clang.TranslationUnit) (*Report, error) {
+	f, err := a.collect(tu)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.analyze(f), nil
+}
+
+func (a Analyzer) collect(tu clang.TranslationUnit) (*facts, error) {
+	c := collector{
+		Analyzer: a,
+		tu:       tu,
+		paths:    compdb.Paths{Directory: a.Directory},
+		facts: &facts{
+			via:       map[string]string{},
+			system:    map[string]bool{},
+			spellings: map[string]Include{},
+		},
+	}
+
+	mainFile := tu.File(tu.Spelling())
+	if mainFile.Name() == "" {
+		return nil, fmt.Errorf("main file %s not found", tu.Spelling())
+	}
+	c.facts.main = c.paths.Abs(mainFile.Name())
+	c.facts.text, _ = tu.SourceText(mainFile)
+
+	c.inclusions()
+	if res := c.directives(mainFile); res != clang.Result_Success {
+		return nil, fmt.Errorf("find includes in %s: %s", c.facts.main, res)
+	}
+	c.uses()
+
+	return c.facts, nil
+}
+
+type collector struct {
+	Analyzer
+
+	tu    clang.TranslationUnit
+	paths compdb.Paths
+	facts *facts
+}
+
+// inclusions records through which include of the main file every header is reached.
+func (c *collector) inclusions() {
+	direct := map[uint32]string{}
+	indirect := map[string]uint32{}
+
+	c.tu.Inclusions(func(f clang.File, stack []clang.SourceLocation) {
+		if len(stack) == 0 {
+			return
+		}
+
+		p := c.paths.Abs(f.Name())
+		c.facts.system[p] = c.tu.Location(f, 1, 1).IsInSystemHeader()
+
+		_, line, _, _ := stack[len(stack)-1].ExpansionLocation()
+		if len(stack) == 1 {
+			direct[line] = p
+		} else if _, ok := indirect[p]; !ok {
+			indirect[p] = line
+		}
+	})
+
+	for _, p := range direct {
+		c.facts.via[p] = p
+	}
+	for p, line := range indirect {
+		if _, ok := c.facts.via[p]; !ok {
+			c.facts.via[p] = direct[line]
+		}
+	}
+
+	c.tu.TranslationUnitCursor().Visit(func(cursor, _ clang.Cursor) clang.ChildVisitResult {
+		if cursor.Kind() != clang.Cursor_InclusionDirective {
+			return clang.ChildVisit_Continue
+		}
+
+		included := cursor.IncludedFile()
+		if included.Name() == "" {
+			return clang.ChildVisit_Continue
+		}
+
+		p := c.paths.Abs(included.Name())
+		if _, ok := c.facts.spellings[p]; !ok {
+			c.facts.spellings[p] = Include{
+				File:     p,
+				Spelling: cursor.Spelling(),
+				Angled:   includes.Angled(c.tu, cursor),
+			}
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+// directives records the inclusion directives of the main file.
+func (c *collector) directives(mainFile clang.File) clang.Result {
+	return c.tu.IncludesInFile(mainFile, func(cursor clang.Cursor, r clang.SourceRange) clang.VisitorResult {
+		included := cursor.IncludedFile()
+		if included.Name() == "" {
+			return clang.Visit_Continue
+		}
+
+		_, line, _, _ := r.Start().ExpansionLocation()
+		inc := Include{
+			File:     c.paths.Abs(included.Name()),
+			Spelling: cursor.Spelling(),
+			Angled:   includes.Angled(c.tu, cursor),
+			Line:     line,
+		}
+		if st := c.facts.text; st != nil {
+			inc.Span = clang.Span{
+				Start: st.PositionAt(line, 1, clang.ColumnUnit_Byte),
+				End:   st.PositionAt(line+1, 1, clang.ColumnUnit_Byte),
+			}
+			inc.Span.Start.File = c.facts.main
+			inc.Span.End.File = c.facts.main
+		}
+		c.facts.includes = append(c.facts.includes, inc)
+
+		return clang.Visit_Continue
+	})
+}
+
+// uses records the references of the main file to declarations in other files.
+func (c *collector) uses() {
+	var visit func(cursor clang.Cursor)
+	visit = func(cursor clang.Cursor) {
+		switch kind := cursor.Kind(); {
+		case kind == clang.Cursor_InclusionDirective:
+			return
+		case kind.IsReference(), kind.IsExpression(), kind == clang.Cursor_MacroExpansion:
+			c.use(cursor)
+		}
+
+		cursor.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+			visit(child)
+
+			return clang.ChildVisit_Continue
+		})
+	}
+
+	c.tu.TranslationUnitCursor().Visit(func(cursor, _ clang.Cursor) clang.ChildVisitResult {
+		if cursor.Location().IsFromMainFile() {
+			visit(cursor)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (c *collector) use(cursor clang.Cursor) {
+	ref := cursor.Referenced()
+	if ref.IsNull() || ref.Equal(cursor) {
+		return
+	}
+
+	file, _, _, _ := ref.Location().ExpansionLocation()
+	if file.Name() == "" {
+		return
+	}
+	header := c.paths.Abs(file.Name())
+	if header == c.facts.main {
+		return
+	}
+
+	site := cursor.Location().Position()
+	site.File = c.facts.main
+	end := site
+	end.Column += uint32(len(ref.Spelling()))
+	end.Offset += uint32(len(ref.Spelling()))
+
+	c.facts.uses = append(c.facts.uses, Use{
+		USR:    ref.USR(),
+		Name:   ref.Spelling(),
+		Header: header,
+		Site:   clang.Span{Start: site, End: end},
+	})
+}
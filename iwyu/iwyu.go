@@ -0,0 +1,270 @@
+// Package iwyu finds the unused and the missing includes of source files in the style of
+// include-what-you-use.
+//
+// Every declaration referenced by a main file is attributed to the header declaring it. An
+// include is used if it provides one of these headers, either directly or as one of the headers
+// it exports. Headers which are only reachable through other headers are missing includes.
+// Declarations in system headers reached through a directly included system header are
+// attributed to that header, so using std::vector from <vector> does not ask for the internal
+// headers of the standard library.
+package iwyu
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Include is an inclusion directive.
+type Include struct {
+	// File is the path of the included file.
+	File string
+	// Spelling is the file name as written in the directive, without delimiters.
+	Spelling string
+	Angled   bool
+	Line     uint32
+	// Span is the span of the complete line of the directive, including the line terminator.
+	Span clang.Span
+}
+
+// Directive returns the directive including the file, e.g. `#include <vector>`.
+func (inc Include) Directive() string {
+	if inc.Angled {
+		return "#include <" + inc.Spelling + ">"
+	}
+
+	return `#include "` + inc.Spelling + `"`
+}
+
+// Use is a reference to a declaration in a header.
+type Use struct {
+	USR  string
+	Name string
+	// Header is the path of the header declaring the referenced declaration.
+	Header string
+	// Site is the span of the reference in the main file.
+	Site clang.Span
+}
+
+// Missing is a header which is used but only included indirectly.
+type Missing struct {
+	// Include is the directive which should be added.
+	Include Include
+	// Via is the path of the directly included file through which the header is reached.
+	Via string
+	// Uses holds the first use of every declaration of the header.
+	Uses []Use
+}
+
+// Edit replaces the text of Span by Text. Insertions have an empty span.
+type Edit struct {
+	Span clang.Span
+	Text string
+}
+
+// Report is the result of the analysis of a main file.
+type Report struct {
+	File    string
+	Unused  []Include
+	Missing []Missing
+	// Edits remove the unused includes and insert the missing includes after the last include.
+	Edits []Edit
+}
+
+// Analyzer finds unused and missing includes.
+type Analyzer struct {
+	// Exports maps the path of a header to the paths of the headers it exports, e.g. the
+	// headers included by an umbrella header. Declarations of exported headers count as
+	// declarations of the exporting header.
+	Exports map[string][]string
+	// Keep holds patterns of include spellings which are never reported unused, e.g. "config.h"
+	// for headers only used by preprocessor conditionals. Patterns are matched with
+	// filepath.Match.
+	Keep []string
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// facts are the information the analysis needs about a translation unit.
+type facts struct {
+	main string
+	text *clang.SourceText
+	// includes are the directives of the main file.
+	includes []Include
+	// via maps every file included by the translation unit to the file included by the main
+	// file through which it is reached.
+	via map[string]string
+	// system holds the system headers.
+	system map[string]bool
+	// spellings maps files to the first directive including them, anywhere in the translation
+	// unit.
+	spellings map[string]Include
+	uses      []Use
+}
+
+func (a Analyzer) analyze(f *facts) *Report {
+	r := &Report{File: f.main}
+
+	// provider maps every header provided by a direct include to the index of the include.
+	provider := map[string]int{}
+	for i, inc := range f.includes {
+		for _, h := range a.exported(inc.File) {
+			if _, ok := provider[h]; !ok {
+				provider[h] = i
+			}
+		}
+	}
+
+	used := make([]bool, len(f.includes))
+	missing := map[string]*Missing{}
+	seen := map[[2]string]bool{}
+
+	for _, u := range f.uses {
+		if u.Header == f.main {
+			continue
+		}
+		if i, ok := provider[u.Header]; ok {
+			used[i] = true
+
+			continue
+		}
+
+		via := f.via[u.Header]
+		if f.system[u.Header] && f.system[via] {
+			if i, ok := provider[via]; ok {
+				used[i] = true
+
+				continue
+			}
+		}
+
+		m, ok := missing[u.Header]
+		if !ok {
+			m = &Missing{Include: a.suggest(f, u.Header), Via: via}
+			missing[u.Header] = m
+		}
+		if key := [2]string{u.Header, u.USR}; !seen[key] {
+			seen[key] = true
+			m.Uses = append(m.Uses, u)
+		}
+	}
+
+	for i, inc := range f.includes {
+		if !used[i] && !a.keep(f.main, inc) {
+			r.Unused = append(r.Unused, inc)
+		}
+	}
+
+	for _, m := range missing {
+		r.Missing = append(r.Missing, *m)
+	}
+	sort.Slice(r.Missing, func(i, j int) bool {
+		return r.Missing[i].Include.Spelling < r.Missing[j].Include.Spelling
+	})
+
+	r.Edits = a.edits(f, r)
+
+	return r
+}
+
+// exported returns the header together with all headers it directly or indirectly exports.
+func (a Analyzer) exported(header string) []string {
+	seen := map[string]bool{header: true}
+	result := []string{header}
+
+	for i := 0; i < len(result); i++ {
+		for _, e := range a.Exports[result[i]] {
+			if !seen[e] {
+				seen[e] = true
+				result = append(result, e)
+			}
+		}
+	}
+
+	return result
+}
+
+// keep reports whether the include must not be reported unused. Besides the Keep patterns this
+// is the associated header of a source file, e.g. foo.h included by foo.cc.
+func (a Analyzer) keep(main string, inc Include) bool {
+	for _, pattern := range a.Keep {
+		if ok, _ := filepath.Match(pattern, inc.Spelling); ok {
+			return true
+		}
+	}
+
+	return compdb.Stem(inc.File) == compdb.Stem(main)
+}
+
+// suggest returns the directive to include header, spelled as the translation unit includes it
+// elsewhere or relative to the main file.
+func (a Analyzer) suggest(f *facts, header string) Include {
+	if inc, ok := f.spellings[header]; ok {
+		return Include{File: header, Spelling: inc.Spelling, Angled: inc.Angled}
+	}
+
+	spelling := header
+	if rel, err := filepath.Rel(filepath.Dir(f.main), header); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		spelling = filepath.ToSlash(rel)
+	}
+
+	return Include{File: header, Spelling: spelling, Angled: f.system[header]}
+}
+
+func (a Analyzer) edits(f *facts, r *Report) []Edit {
+	var edits []Edit
+
+	for _, inc := range r.Unused {
+		edits = append(edits, Edit{Span: inc.Span})
+	}
+
+	if len(r.Missing) == 0 {
+		return edits
+	}
+
+	var text strings.Builder
+	for _, m := range r.Missing {
+		fmt.Fprintln(&text, m.Include.Directive())
+	}
+
+	// Insert after the last include or at the start of the file.
+	var at clang.Position
+	if f.text != nil {
+		at = f.text.Position(0)
+	}
+	for _, inc := range f.includes {
+		if inc.Span.End.Offset >= at.Offset {
+			at = inc.Span.End
+		}
+	}
+	at.File = f.main
+
+	return append(edits, Edit{Span: clang.Span{Start: at, End: at}, Text: text.String()})
+}
+
+// Apply applies the edits to the content of a file. The edits must not overlap.
+func Apply(content string, edits []Edit) string {
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if si, sj := sorted[i].Span.Start.Offset, sorted[j].Span.Start.Offset; si != sj {
+			return si > sj
+		}
+
+		// Removals starting at an insertion are applied first.
+		return sorted[i].Span.End.Offset > sorted[j].Span.End.Offset
+	})
+
+	for _, e := range sorted {
+		start, end := int(e.Span.Start.Offset), int(e.Span.End.Offset)
+		if start > len(content) || end > len(content) || start > end {
+			continue
+		}
+		content = content[:start] + e.Text + content[end:]
+	}
+
+	return content
+}
@@ -0,0 +1,179 @@
+package iwyu
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+const source = `#include "a.h"
+#include "config.h"
+#include "util.h"
+#include <vector>
+
+int main() { std::vector<int> v; return helper() + detail(); }
+`
+
+func use(name, header string) Use {
+	return Use{USR: "c:@F@" + name, Name: name, Header: header}
+}
+
+// testFacts returns the facts of source, where util.h includes detail.h, <vector> includes
+// bits/stl_vector.h, and helper and detail are declared in util.h and detail.h.
+func testFacts() *facts {
+	st := clang.NewSourceText("/p/a.cc", source)
+	include := func(file, spelling string, angled bool, line uint32) Include {
+		return Include{
+			File:     file,
+			Spelling: spelling,
+			Angled:   angled,
+			Line:     line,
+			Span: clang.Span{
+				Start: st.PositionAt(line, 1, clang.ColumnUnit_Byte),
+				End:   st.PositionAt(line+1, 1, clang.ColumnUnit_Byte),
+			},
+		}
+	}
+	return &facts{
+		main: "/p/a.cc",
+		text: st,
+		includes: []Include{
+			include("/p/a.h", "a.h", false, 1),
+			include("/p/config.h", "config.h", false, 2),
+			include("/p/util.h", "util.h", false, 3),
+			include("/usr/include/c++/vector", "vector", true, 4),
+		},
+		via: map[string]string{
+			"/p/a.h":                             "/p/a.h",
+			"/p/config.h":                        "/p/config.h",
+			"/p/util.h":                          "/p/util.h",
+			"/p/detail.h":                        "/p/util.h",
+			"/usr/include/c++/vector":            "/usr/include/c++/vector",
+			"/usr/include/c++/bits/stl_vector.h": "/usr/include/c++/vector",
+		},
+		system: map[string]bool{
+			"/usr/include/c++/vector":            true,
+			"/usr/include/c++/bits/stl_vector.h": true,
+		},
+		spellings: map[string]Include{
+			"/p/detail.h": {File: "/p/detail.h", Spelling: "detail.h"},
+		},
+		uses: []Use{
+			use("vector", "/usr/include/c++/bits/stl_vector.h"),
+			use("detail", "/p/detail.h"),
+			use("detail", "/p/detail.h"),
+			use("main", "/p/a.cc"),
+		},
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	f := testFacts()
+
+	r := Analyzer{}.analyze(f)
+
+	var unused []string
+	for _, inc := range r.Unused {
+		unused = append(unused, inc.Spelling)
+	}
+	if want := []string{"config.h", "util.h"}; !reflect.DeepEqual(unused, want) {
+		t.Errorf("expected unused includes %v. got=%v", want, unused)
+	}
+
+	if len(r.Missing) != 1 {
+		t.Fatalf("expected 1 missing include. got=%+v", r.Missing)
+	}
+	m := r.Missing[0]
+	if m.Include.Directive() != `#include "detail.h"` || m.Via != "/p/util.h" || len(m.Uses) != 1 {
+		t.Errorf("unexpected missing include %+v", m)
+	}
+
+	want := `#include "a.h"
+#include <vector>
+#include "detail.h"
+
+int main() { std::vector<int> v; return helper() + detail(); }
+`
+	if got := Apply(source, r.Edits); got != want {
+		t.Errorf("expected edited source:\n%s\ngot:\n%s", want, got)
+	}
+
+	// config.h is kept, and helper is used from util.h.
+	f.uses = append(f.uses, use("helper", "/p/util.h"))
+	r = Analyzer{Keep: []string{"config.*"}}.analyze(f)
+	if len(r.Unused) != 0 {
+		t.Errorf("expected no unused includes. got=%+v", r.Unused)
+	}
+
+	// util.h exports detail.h.
+	r = Analyzer{Keep: []string{"config.h"}, Exports: map[string][]string{"/p/util.h": {"/p/detail.h"}}}.analyze(testFacts())
+	if len(r.Unused) != 0 || len(r.Missing) != 0 || len(r.Edits) != 0 {
+		t.Errorf("expected detail.h to be provided by util.h. got=%+v", r)
+	}
+}
+
+func TestApply(t *testing.T) {
+	edits := []Edit{
+		{Span: clang.Span{Start: clang.Position{Offset: 4}, End: clang.Position{Offset: 4}}, Text: "X"},
+		{Span: clang.Span{Start: clang.Position{Offset: 4}, End: clang.Position{Offset: 6}}},
+		{Span: clang.Span{Start: clang.Position{Offset: 0}, End: clang.Position{Offset: 1}}, Text: "A"},
+	}
+
+	if got, want := Apply("abcdefgh", edits), "AbcdXgh"; got != want {
+		t.Errorf("expected %q. got=%q", want, got)
+	}
+}
+
+func TestAnalyzeParsed(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/main.c", nil, nil, uint32(clang.TranslationUnit_DetailedPreprocessingRecord))
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	r, err := Analyzer{Directory: dir}.Analyze(tu)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := func(name string) string {
+		return filepath.Join(dir, "testdata", name)
+	}
+
+	if r.File != path("main.c") {
+		t.Errorf("expected report of %s. got=%s", path("main.c"), r.File)
+	}
+	if len(r.Unused) != 1 || r.Unused[0].File != path("unused.h") || r.Unused[0].Line != 1 {
+		t.Errorf("expected unused.h to be unused. got=%+v", r.Unused)
+	}
+	if len(r.Missing) != 1 {
+		t.Fatalf("expected one missing include. got=%+v", r.Missing)
+	}
+	m := r.Missing[0]
+	if m.Include.File != path("detail.h") || m.Include.Spelling != "detail.h" || m.Via != path("util.h") {
+		t.Errorf("expected detail.h to be missing via util.h. got=%+v", m)
+	}
+	if len(m.Uses) != 1 || m.Uses[0].Name != "detail" || m.Uses[0].Site.Start.Line != 4 {
+		t.Errorf("expected the use of detail on line 4. got=%+v", m.Uses)
+	}
+
+	content, err := os.ReadFile(path("main.c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "#include \"util.h\"\n#include \"detail.h\"\n\nint main(void) { return util() + detail(); }\n"
+	if got := Apply(string(content), r.Edits); got != want {
+		t.Errorf("expected\n%s\ngot=\n%s", want, got)
+	}
+}
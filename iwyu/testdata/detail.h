@@ -0,0 +1 @@
+int detail(void);
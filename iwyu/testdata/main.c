@@ -0,0 +1,4 @@
+#include "unused.h"
+#include "util.h"
+
+int main(void) { return util() + detail(); }
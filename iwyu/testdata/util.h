@@ -0,0 +1,3 @@
+#include "detail.h"
+
+int util(void);
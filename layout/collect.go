@@ -0,0 +1,312 @@
+package layout
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Inspect returns the layout of the record definition c. The error is a clang.TypeLayoutError
+// for incomplete and dependent records.
+func Inspect(c clang.Cursor) (*Record, error) {
+	t := c.Type()
+
+	size := t.SizeOf()
+	if size < 0 {
+		return nil, clang.TypeLayoutError(size)
+	}
+	align := t.AlignOf()
+	if align < 0 {
+		return nil, clang.TypeLayoutError(align)
+	}
+
+	r := &Record{
+		USR:      c.USR(),
+		Name:     t.Spelling(),
+		Kind:     c.Kind(),
+		Location: c.Location().Position(),
+		Size:     size,
+		Align:    align,
+	}
+
+	// Base classes and the pointer to the virtual table precede the fields.
+	prefixed, virtual := false, false
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		switch child.Kind() {
+		case clang.Cursor_CXXBaseSpecifier:
+			prefixed = true
+			virtual = virtual || child.IsVirtualBase() || hasVirtualBases(child.Type())
+		case clang.Cursor_CXXMethod, clang.Cursor_Destructor:
+			prefixed = prefixed || child.CXXMethod_IsVirtual()
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	r.Fields = fields(c, t)
+	sort.SliceStable(r.Fields, func(i, j int) bool {
+		return r.Fields[i].Offset < r.Fields[j].Offset
+	})
+	if prefixed && len(r.Fields) > 0 {
+		r.Prefix = r.Fields[0].Offset
+	} else if prefixed {
+		r.Prefix = size * 8
+	}
+	if virtual {
+		r.Suffix = max(size*8-r.end(), 0)
+	}
+
+	return r, nil
+}
+
+// hasVirtualBases reports whether the record of type t has virtual base classes, directly or
+// through one of its bases.
+func hasVirtualBases(t clang.Type) bool {
+	decl := t.CanonicalType().Declaration().Definition()
+	if decl.IsNull() {
+		return false
+	}
+
+	found := false
+	decl.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		if child.Kind() == clang.Cursor_CXXBaseSpecifier && (child.IsVirtualBase() || hasVirtualBases(child.Type())) {
+			found = true
+
+			return clang.ChildVisit_Break
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	return found
+}
+
+// fields returns the fields of the record c of type t. Anonymous records are reported as a
+// single field.
+func fields(c clang.Cursor, t clang.Type) []Field {
+	var fs []Field
+	var anonymous []clang.Cursor
+
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		switch child.Kind() {
+		case clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl:
+			if !child.IsAnonymousRecordDecl() {
+				break
+			}
+
+			offset, ok := anonymousOffset(t, child)
+			if !ok {
+				break
+			}
+			anonymous = append(anonymous, child)
+			fs = append(fs, field(child.Type(), "", offset))
+		case clang.Cursor_FieldDecl:
+			ft := child.Type()
+			if decl := ft.Declaration(); decl.IsAnonymousRecordDecl() {
+				for _, a := range anonymous {
+					if a.Equal(decl) {
+						return clang.ChildVisit_Continue
+					}
+				}
+			}
+
+			offset := child.OffsetOfField()
+			if offset < 0 {
+				break
+			}
+
+			f := field(ft, child.Spelling(), offset)
+			if child.IsBitField() {
+				f.BitField = true
+				f.BitWidth = int64(child.FieldDeclBitWidth())
+			}
+			fs = append(fs, f)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	return fs
+}
+
+func field(t clang.Type, name string, offset int64) Field {
+	f := Field{
+		Name:   name,
+		Type:   t.Spelling(),
+		Kind:   canonicalKind(t),
+		Offset: offset,
+		Size:   t.SizeOf(),
+		Align:  t.AlignOf(),
+	}
+	// Flexible array members and other incomplete fields occupy no space.
+	if f.Size < 0 {
+		f.Size = 0
+	}
+	if f.Align < 0 {
+		f.Align = 1
+	}
+
+	return f
+}
+
+// canonicalKind returns the kind of the canonical type of t, or of its element type for arrays.
+func canonicalKind(t clang.Type) clang.TypeKind {
+	t = t.CanonicalType()
+	for {
+		switch t.Kind() {
+		case clang.Type_ConstantArray, clang.Type_IncompleteArray, clang.Type_VariableArray, clang.Type_DependentSizedArray:
+			t = t.ArrayElementType().CanonicalType()
+		default:
+			return t.Kind()
+		}
+	}
+}
+
+// anonymousOffset returns the offset in bits of the anonymous record c in the record of type t.
+// libclang has no offset for anonymous records, so it is derived from the offset of the first
+// named field inside them, which can be looked up by its name in t.
+func anonymousOffset(t clang.Type, c clang.Cursor) (int64, bool) {
+	var offset int64
+	found := false
+
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		switch child.Kind() {
+		case clang.Cursor_FieldDecl:
+			name := child.Spelling()
+			if name == "" {
+				break
+			}
+
+			outer, inner := t.OffsetOf(name), child.OffsetOfField()
+			if outer < 0 || inner < 0 {
+				return clang.ChildVisit_Break
+			}
+			offset, found = outer-inner, true
+
+			return clang.ChildVisit_Break
+		case clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl:
+			if !child.IsAnonymousRecordDecl() {
+				break
+			}
+
+			o, ok := anonymousOffset(t, child)
+			if !ok {
+				break
+			}
+			inner, ok := anonymousOffset(c.Type(), child)
+			if !ok {
+				break
+			}
+			offset, found = o-inner, true
+
+			return clang.ChildVisit_Break
+		}
+
+		return clang.ChildVisit_Continue
+	})
+
+	return offset, found
+}
+
+// Collector collects the layouts of the records defined in translation units.
+type Collector struct {
+	// SkipSystemHeaders skips the records defined in system headers.
+	SkipSystemHeaders bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Collect returns the layouts of all complete, non-dependent records defined in the translation
+// unit, including nested records. Anonymous records are part of their enclosing record.
+func (col Collector) Collect(tu clang.TranslationUnit) []*Record {
+	return col.walk(tu).records
+}
+
+// CollectTypedefs returns the layouts of all typedefs and type aliases of complete,
+// non-dependent types declared at namespace scope in the translation unit.
+func (col Collector) CollectTypedefs(tu clang.TranslationUnit) []Typedef {
+	return col.walk(tu).typedefs
+}
+
+func (col Collector) walk(tu clang.TranslationUnit) *walker {
+	w := &walker{
+		Collector: col,
+		seen:      map[string]bool{},
+		paths:     compdb.Paths{Directory: col.Directory},
+	}
+	w.children(tu.TranslationUnitCursor())
+
+	return w
+}
+
+type walker struct {
+	Collector
+
+	records  []*Record
+	typedefs []Typedef
+	seen     map[string]bool
+	paths    compdb.Paths
+}
+
+func (w *walker) children(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		w.visit(c)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) visit(c clang.Cursor) {
+	kind := c.Kind()
+	if kind.IsDeclaration() && w.SkipSystemHeaders && c.Location().IsInSystemHeader() {
+		return
+	}
+
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_LinkageSpec, clang.Cursor_UnexposedDecl:
+		w.children(c)
+	case clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_UnionDecl:
+		if !c.IsCursorDefinition() || c.IsAnonymousRecordDecl() {
+			break
+		}
+
+		w.children(c)
+
+		usr := c.USR()
+		if usr == "" || w.seen[usr] {
+			break
+		}
+		w.seen[usr] = true
+
+		r, err := Inspect(c)
+		if err != nil {
+			break
+		}
+		r.Location.File = w.paths.Abs(r.Location.File)
+		w.records = append(w.records, r)
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		usr := c.USR()
+		if usr == "" || w.seen[usr] {
+			break
+		}
+		w.seen[usr] = true
+
+		t := c.Type()
+		size, align := t.SizeOf(), t.AlignOf()
+		if size < 0 || align < 0 {
+			break
+		}
+
+		loc := c.Location().Position()
+		loc.File = w.paths.Abs(loc.File)
+		w.typedefs = append(w.typedefs, Typedef{
+			Name:       t.Spelling(),
+			Underlying: c.TypedefDeclUnderlyingType().Spelling(),
+			Kind:       canonicalKind(t),
+			Location:   loc,
+			Size:       size,
+			Align:      align,
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package layout describes the memory layout of C and C++ records: the offsets and sizes of
+// their fields, the padding the compiler inserts between them, fields crossing cache lines and
+// an order of the fields which minimizes the size of the record.
+package layout
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// DefaultCacheLine is the size of a cache line in bytes on common hardware.
+const DefaultCacheLine = 64
+
+// Field is a field of a record.
+type Field struct {
+	// Name is empty for unnamed bit-fields and anonymous records.
+	Name string
+	Type string
+	// Kind is the kind of the canonical type of the field, or of its element type for arrays.
+	Kind clang.TypeKind
+	// Offset is the offset of the field in bits from the start of the record.
+	Offset int64
+	// Size is the size of the type of the field in bytes.
+	Size int64
+	// Align is the alignment of the type of the field in bytes.
+	Align    int64
+	BitField bool
+	// BitWidth is the width of a bit-field in bits.
+	BitWidth int64
+}
+
+// Bits returns the number of bits occupied by the field.
+func (f Field) Bits() int64 {
+	if f.BitField {
+		return f.BitWidth
+	}
+
+	return f.Size * 8
+}
+
+// End returns the offset in bits following the field.
+func (f Field) End() int64 {
+	return f.Offset + f.Bits()
+}
+
+// Record is the layout of a struct, class or union.
+type Record struct {
+	USR  string
+	Name string
+	// Kind is clang.Cursor_StructDecl, clang.Cursor_ClassDecl or clang.Cursor_UnionDecl.
+	Kind     clang.CursorKind
+	Location clang.Position
+	// Size and Align are in bytes.
+	Size  int64
+	Align int64
+	// Prefix is the number of bits before the first field which are occupied by base classes
+	// and the pointer to the virtual table. It is not reported as hole.
+	Prefix int64
+	// Suffix is the number of bits after the last field of a record with virtual base classes.
+	// Virtual bases are laid out after the fields, but libclang does not report their offsets,
+	// so the whole tail is attributed to them and not reported as padding.
+	Suffix int64
+	// Fields are ordered by their offset.
+	Fields []Field
+}
+
+// IsUnion reports whether the record is a union.
+func (r *Record) IsUnion() bool {
+	return r.Kind == clang.Cursor_UnionDecl
+}
+
+// Typedef is the layout of a typedef or type alias.
+type Typedef struct {
+	Name       string
+	Underlying string
+	// Kind is the kind of the canonical type, or of its element type for arrays.
+	Kind     clang.TypeKind
+	Location clang.Position
+	// Size and Align are in bytes.
+	Size  int64
+	Align int64
+}
+
+// Hole is unused space between two fields. Offset and Size are in bits.
+type Hole struct {
+	Offset int64
+	Size   int64
+	// After is the index of the field preceding the hole.
+	After int
+}
+
+// Holes returns the unused space between the fields of the record. Unions have no holes.
+func (r *Record) Holes() []Hole {
+	if r.IsUnion() {
+		return nil
+	}
+
+	var holes []Hole
+	end := r.Prefix
+	for i, f := range r.Fields {
+		if f.Offset > end {
+			holes = append(holes, Hole{Offset: end, Size: f.Offset - end, After: i - 1})
+		}
+		if e := f.End(); e > end {
+			end = e
+		}
+	}
+
+	return holes
+}
+
+// end returns the offset in bits following the last field.
+func (r *Record) end() int64 {
+	end := r.Prefix
+	for _, f := range r.Fields {
+		if e := f.End(); e > end {
+			end = e
+		}
+	}
+
+	return end
+}
+
+// Padding returns the number of bits after the last field, without the suffix.
+func (r *Record) Padding() int64 {
+	if p := r.Size*8 - r.end() - r.Suffix; p > 0 {
+		return p
+	}
+
+	return 0
+}
+
+// Wasted returns the number of bits in holes and padding.
+func (r *Record) Wasted() int64 {
+	wasted := r.Padding()
+	for _, h := range r.Holes() {
+		wasted += h.Size
+	}
+
+	return wasted
+}
+
+// CacheLineCrossings returns the indexes of the fields which straddle a cache line boundary,
+// although they are small enough to fit into a single cache line of the given size in bytes.
+func (r *Record) CacheLineCrossings(cacheLine int64) []int {
+	if cacheLine <= 0 {
+		return nil
+	}
+
+	var crossing []int
+	for i, f := range r.Fields {
+		if f.BitField || f.Size == 0 || f.Size > cacheLine || f.Offset%8 != 0 {
+			continue
+		}
+
+		start := f.Offset / 8
+		if start/cacheLine != (start+f.Size-1)/cacheLine {
+			crossing = append(crossing, i)
+		}
+	}
+
+	return crossing
+}
+
+// unit is a field or a run of adjacent bit-fields which are moved as a whole.
+type unit struct {
+	fields []Field
+	size   int64
+	align  int64
+}
+
+// Reorder returns the fields in an order which minimizes the size of the record, together with
+// the resulting size in bytes. The fields are sorted by decreasing alignment, runs of adjacent
+// bit-fields are kept together. The order of the fields is returned unchanged if no order is
+// smaller, e.g. for unions and packed records. Records with virtual base classes are not
+// reordered, as the placement of the virtual bases depends on the fields.
+func (r *Record) Reorder() ([]Field, int64) {
+	if r.IsUnion() || r.Suffix > 0 || len(r.Fields) < 2 {
+		return r.Fields, r.Size
+	}
+
+	var units []unit
+	for i := 0; i < len(r.Fields); i++ {
+		f := r.Fields[i]
+		if !f.BitField {
+			units = append(units, unit{fields: []Field{f}, size: f.Size, align: max(f.Align, 1)})
+
+			continue
+		}
+
+		j := i + 1
+		for j < len(r.Fields) && r.Fields[j].BitField && r.Fields[j].BitWidth > 0 {
+			j++
+		}
+
+		u := unit{fields: r.Fields[i:j], align: 1}
+		start, end := f.Offset/8, (r.Fields[j-1].End()+7)/8
+		for _, bf := range u.fields {
+			u.align = max(u.align, bf.Align)
+			end = max(end, (bf.End()+7)/8)
+		}
+		u.size = end - start
+		units = append(units, u)
+		i = j - 1
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		if units[i].align != units[j].align {
+			return units[i].align > units[j].align
+		}
+
+		return units[i].size > units[j].size
+	})
+
+	offset := r.Prefix / 8
+	fields := make([]Field, 0, len(r.Fields))
+	for _, u := range units {
+		offset = alignTo(offset, u.align)
+		delta := offset*8 - u.fields[0].Offset/8*8
+		for _, f := range u.fields {
+			f.Offset += delta
+			fields = append(fields, f)
+		}
+		offset += u.size
+	}
+	size := alignTo(offset, max(r.Align, 1))
+
+	if size >= r.Size {
+		return r.Fields, r.Size
+	}
+
+	return fields, size
+}
+
+func alignTo(n, align int64) int64 {
+	return (n + align - 1) / align * align
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
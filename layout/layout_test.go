@@ -0,0 +1,233 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// record returns a struct with the fields laid out in order as the compiler would.
+func record(name string, fields ...Field) *Record {
+	r := &Record{Name: name, Kind: clang.Cursor_StructDecl, Align: 1}
+
+	offset := int64(0)
+	for _, f := range fields {
+		offset = alignTo(offset, f.Align)
+		f.Offset = offset * 8
+		offset += f.Size
+		r.Align = max(r.Align, f.Align)
+		r.Fields = append(r.Fields, f)
+	}
+	r.Size = alignTo(offset, r.Align)
+
+	return r
+}
+
+var (
+	char = Field{Type: "char", Size: 1, Align: 1}
+	int_ = Field{Type: "int", Size: 4, Align: 4}
+	long = Field{Type: "long", Size: 8, Align: 8}
+)
+
+func named(f Field, name string) Field {
+	f.Name = name
+
+	return f
+}
+
+func TestHoles(t *testing.T) {
+	r := record("struct s", named(char, "a"), named(long, "b"), named(char, "c"), named(int_, "d"), named(char, "e"))
+
+	want := []Hole{{Offset: 8, Size: 56, After: 0}, {Offset: 136, Size: 24, After: 2}}
+	if got := r.Holes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Holes = %v, want %v", got, want)
+	}
+	if got := r.Padding(); got != 56 {
+		t.Errorf("Padding = %d, want 56", got)
+	}
+	if got := r.Wasted(); got != 136 {
+		t.Errorf("Wasted = %d, want 136", got)
+	}
+
+	order, size := r.Reorder()
+	var names []string
+	for _, f := range order {
+		names = append(names, f.Name)
+	}
+	if want := []string{"b", "d", "a", "c", "e"}; !reflect.DeepEqual(names, want) || size != 16 {
+		t.Errorf("Reorder = %v, %d, want %v, 16", names, size, want)
+	}
+	if order[1].Offset != 64 || order[4].Offset != 112 {
+		t.Errorf("Reorder offsets = %d, %d, want 64, 112", order[1].Offset, order[4].Offset)
+	}
+}
+
+func TestBitFields(t *testing.T) {
+	r := &Record{Name: "struct f", Kind: clang.Cursor_StructDecl, Size: 16, Align: 8, Fields: []Field{
+		{Name: "a", Type: "unsigned int", Size: 4, Align: 4, BitField: true, BitWidth: 3, Offset: 0},
+		{Name: "b", Type: "unsigned int", Size: 4, Align: 4, BitField: true, BitWidth: 5, Offset: 3},
+		{Name: "p", Type: "void *", Size: 8, Align: 8, Offset: 64},
+	}}
+
+	if got := r.Holes(); len(got) != 1 || got[0].Offset != 8 || got[0].Size != 56 {
+		t.Errorf("Holes = %v, want 7 bytes after the bit-fields", got)
+	}
+
+	order, size := r.Reorder()
+	if size != 16 || !reflect.DeepEqual(order, r.Fields) {
+		t.Errorf("Reorder = %v, %d, want the fields unchanged", order, size)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	r := &Record{Name: "union u", Kind: clang.Cursor_UnionDecl, Size: 8, Align: 4, Fields: []Field{
+		{Name: "c", Type: "char [5]", Size: 5, Align: 1},
+		{Name: "i", Type: "int", Size: 4, Align: 4},
+	}}
+
+	if got := r.Holes(); got != nil {
+		t.Errorf("Holes = %v, want none", got)
+	}
+	if got := r.Padding(); got != 24 {
+		t.Errorf("Padding = %d, want 24", got)
+	}
+}
+
+func TestVirtualBases(t *testing.T) {
+	// struct d : virtual b { char c; long l; }; with a vtable pointer and a 16 byte virtual base.
+	r := &Record{Name: "struct d", Kind: clang.Cursor_StructDecl, Size: 40, Align: 8, Prefix: 64, Suffix: 128, Fields: []Field{
+		named(char, "c"),
+		named(long, "l"),
+	}}
+	r.Fields[0].Offset = 64
+	r.Fields[1].Offset = 128
+
+	if got := r.Padding(); got != 0 {
+		t.Errorf("Padding = %d, want 0", got)
+	}
+	if got := r.Wasted(); got != 56 {
+		t.Errorf("Wasted = %d, want 56", got)
+	}
+	if order, size := r.Reorder(); !reflect.DeepEqual(order, r.Fields) || size != 40 {
+		t.Errorf("Reorder = %v, %d, want the fields unchanged", order, size)
+	}
+}
+
+func TestCacheLineCrossings(t *testing.T) {
+	r := record("struct c", Field{Name: "buf", Type: "char [60]", Size: 60, Align: 1}, named(long, "x"), named(long, "y"))
+	r.Fields[1].Offset = 60 * 8
+	r.Fields[1].Align = 4
+
+	if got := r.CacheLineCrossings(64); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("CacheLineCrossings = %v, want [1]", got)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	r := record("struct s", named(char, "a"), named(long, "b"))
+	r.Location = clang.Position{File: "/p/s.h", Line: 3, Column: 8}
+
+	var b strings.Builder
+	if err := WriteText(&b, Analyze(r, DefaultCacheLine)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `struct s {
+	/* /p/s.h:3:8 */
+	char a;                                  /*        0     1 */
+
+	/* XXX 7 bytes hole, try to pack */
+
+	long b;                                  /*        8     8 */
+
+	/* size: 16, align: 8, members: 2 */
+	/* sum members: 9 bytes, holes: 1, sum holes: 7 bytes */
+};
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/records.h", []string{"-x", "c", "-target", "x86_64-unknown-linux-gnu"}, nil, 0)
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	records := map[string]*Record{}
+	for _, r := range (Collector{}).Collect(tu) {
+		records[r.Name] = r
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records without the anonymous ones. got=%v", records)
+	}
+
+	type field struct {
+		name     string
+		offset   int64
+		size     int64
+		bitWidth int64
+	}
+	tests := []struct {
+		name    string
+		size    int64
+		fields  []field
+		holes   []Hole
+		padding int64
+	}{
+		{
+			name:    "struct padded",
+			size:    12,
+			fields:  []field{{"c", 0, 1, 0}, {"i", 32, 4, 0}, {"d", 64, 1, 0}},
+			holes:   []Hole{{Offset: 8, Size: 24, After: 0}},
+			padding: 24,
+		},
+		{
+			name:   "struct flags",
+			size:   8,
+			fields: []field{{"a", 0, 4, 3}, {"b", 3, 4, 5}, {"x", 32, 4, 0}},
+			holes:  []Hole{{Offset: 8, Size: 24, After: 1}},
+		},
+		{
+			// The anonymous union and struct are single fields.
+			name:    "struct tagged",
+			size:    24,
+			fields:  []field{{"kind", 0, 4, 0}, {"", 64, 8, 0}, {"", 128, 2, 0}},
+			holes:   []Hole{{Offset: 32, Size: 32, After: 0}},
+			padding: 48,
+		},
+	}
+
+	for _, tt := range tests {
+		r, ok := records[tt.name]
+		if !ok {
+			t.Errorf("%s: not collected", tt.name)
+
+			continue
+		}
+		if r.Size != tt.size {
+			t.Errorf("%s: expected size %d. got=%d", tt.name, tt.size, r.Size)
+		}
+
+		var fields []field
+		for _, f := range r.Fields {
+			fields = append(fields, field{f.Name, f.Offset, f.Size, f.BitWidth})
+		}
+		if !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: expected fields %v. got=%v", tt.name, tt.fields, fields)
+		}
+		if got := r.Holes(); !reflect.DeepEqual(got, tt.holes) {
+			t.Errorf("%s: expected holes %v. got=%v", tt.name, tt.holes, got)
+		}
+		if got := r.Padding(); got != tt.padding {
+			t.Errorf("%s: expected padding %d. got=%d", tt.name, tt.padding, got)
+		}
+	}
+}
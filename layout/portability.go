@@ -0,0 +1,524 @@
+package layout
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Snapshot holds the layouts of a translation unit compiled for a target.
+type Snapshot struct {
+	// Triple is the normalized target triple reported by clang.
+	Triple string
+	// PointerWidth is the width of pointers in bits.
+	PointerWidth int
+	Records      []*Record
+	Typedefs     []Typedef
+}
+
+// Targets parses the translation unit of cmd once for every target triple, replacing the target
+// of the command, and returns the layouts for each target. An error is returned if clang does
+// not confirm a target, e.g. because it is not supported by libclang.
+func Targets(idx clang.Index, cmd compdb.Command, triples []string, options uint32, col Collector) ([]*Snapshot, error) {
+	snaps := make([]*Snapshot, 0, len(triples))
+
+	for _, triple := range triples {
+		tu, err := retarget(cmd, triple).Parse(idx, nil, options)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", triple, err)
+		}
+
+		ti := tu.TargetInfo()
+		snap := &Snapshot{
+			Triple:       ti.Triple(),
+			PointerWidth: int(ti.PointerWidth()),
+		}
+		ti.Dispose()
+
+		if !confirms(snap, triple) {
+			tu.Dispose()
+
+			return nil, fmt.Errorf("parse %s for %s: got target %s", cmd.Path(), triple, snap.Triple)
+		}
+
+		w := col.walk(tu)
+		snap.Records, snap.Typedefs = w.records, w.typedefs
+		tu.Dispose()
+
+		snaps = append(snaps, snap)
+	}
+
+	return snaps, nil
+}
+
+// retarget returns cmd compiling for the target triple instead of its own target.
+func retarget(cmd compdb.Command, triple string) compdb.Command {
+	args := make([]string, 0, len(cmd.Args)+2)
+	for i := 0; i < len(cmd.Args); i++ {
+		a := cmd.Args[i]
+
+		switch {
+		case i == 0:
+			args = append(args, a, "-target", triple)
+
+			continue
+		case a == "-target", a == "--target":
+			i++
+
+			continue
+		case strings.HasPrefix(a, "--target="):
+			continue
+		}
+
+		args = append(args, a)
+	}
+	cmd.Args = args
+
+	return cmd
+}
+
+// confirms reports whether the snapshot was taken for the requested target triple. clang
+// normalizes triples, e.g. arm-none-eabi becomes armv4t-none-unknown-eabi and armv7m becomes
+// thumbv7m, so the architectures are compared by family, together with the pointer width.
+func confirms(snap *Snapshot, triple string) bool {
+	family, width := archFamily(triple)
+	if got, _ := archFamily(snap.Triple); got != family {
+		return false
+	}
+
+	return width == 0 || width == snap.PointerWidth
+}
+
+// archFamily returns the family of the architecture of a target triple and the width of its
+// pointers in bits, or 0 if the width is not known.
+func archFamily(triple string) (string, int) {
+	parts := strings.Split(triple, "-")
+	arch := parts[0]
+	env := parts[len(parts)-1]
+
+	switch {
+	case arch == "x86_64", arch == "amd64":
+		if len(parts) > 1 && strings.HasSuffix(env, "x32") {
+			return "x86_64", 32
+		}
+
+		return "x86_64", 64
+	case arch == "x86", len(arch) == 4 && arch[0] == 'i' && strings.HasSuffix(arch, "86"):
+		return "x86", 32
+	case arch == "arm64_32":
+		return "aarch64", 32
+	case strings.HasPrefix(arch, "aarch64"), strings.HasPrefix(arch, "arm64"):
+		if len(parts) > 1 && strings.HasSuffix(env, "ilp32") {
+			return "aarch64", 32
+		}
+
+		return "aarch64", 64
+	case strings.HasPrefix(arch, "arm"), strings.HasPrefix(arch, "thumb"):
+		return "arm", 32
+	case strings.HasPrefix(arch, "mips64"):
+		return "mips64", 64
+	case strings.HasPrefix(arch, "mips"):
+		return "mips", 32
+	case strings.HasPrefix(arch, "powerpc64"), strings.HasPrefix(arch, "ppc64"):
+		return "ppc64", 64
+	case strings.HasPrefix(arch, "powerpc"), strings.HasPrefix(arch, "ppc"):
+		return "ppc", 32
+	case arch == "sparcv9", arch == "sparc64":
+		return "sparc64", 64
+	case strings.HasPrefix(arch, "sparc"):
+		return "sparc", 32
+	case arch == "riscv32", arch == "wasm32":
+		return arch, 32
+	case arch == "riscv64", arch == "wasm64", arch == "s390x":
+		return arch, 64
+	}
+
+	return arch, 0
+}
+
+// Reason explains why the layout of a type differs between targets.
+type Reason uint32
+
+const (
+	// Reason_PointerWidth is a pointer or reference of different width.
+	Reason_PointerWidth Reason = iota + 1
+	// Reason_LongSize is a long of different size, e.g. on LP64 and LLP64 or ILP32 targets.
+	Reason_LongSize
+	// Reason_LongDoubleSize is a long double of different size.
+	Reason_LongDoubleSize
+	// Reason_WCharSize is a wchar_t of different size.
+	Reason_WCharSize
+	// Reason_Alignment is a type of the same size but with a different alignment, e.g. long
+	// long and double on i386.
+	Reason_Alignment
+	// Reason_Nested is a field of a record or enum type whose layout differs.
+	Reason_Nested
+	// Reason_Size is a type of different size for any other reason.
+	Reason_Size
+	// Reason_Offset is a field whose offset differs because of the fields preceding it.
+	Reason_Offset
+	// Reason_Missing is a type or field which is not declared for all targets.
+	Reason_Missing
+)
+
+func (r Reason) Spelling() string {
+	switch r {
+	case Reason_PointerWidth:
+		return "Reason=PointerWidth"
+	case Reason_LongSize:
+		return "Reason=LongSize"
+	case Reason_LongDoubleSize:
+		return "Reason=LongDoubleSize"
+	case Reason_WCharSize:
+		return "Reason=WCharSize"
+	case Reason_Alignment:
+		return "Reason=Alignment"
+	case Reason_Nested:
+		return "Reason=Nested"
+	case Reason_Size:
+		return "Reason=Size"
+	case Reason_Offset:
+		return "Reason=Offset"
+	case Reason_Missing:
+		return "Reason=Missing"
+	}
+
+	return fmt.Sprintf("Reason unknown %d", int(r))
+}
+
+func (r Reason) String() string {
+	return r.Spelling()
+}
+
+// Description returns a short explanation of the reason.
+func (r Reason) Description() string {
+	switch r {
+	case Reason_PointerWidth:
+		return "pointer width"
+	case Reason_LongSize:
+		return "size of long"
+	case Reason_LongDoubleSize:
+		return "size of long double"
+	case Reason_WCharSize:
+		return "size of wchar_t"
+	case Reason_Alignment:
+		return "alignment"
+	case Reason_Nested:
+		return "layout of nested type"
+	case Reason_Size:
+		return "size of type"
+	case Reason_Offset:
+		return "offset shifted by preceding fields"
+	case Reason_Missing:
+		return "not declared for every target"
+	}
+
+	return r.Spelling()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Reason) MarshalText() ([]byte, error) {
+	return []byte(strings.TrimPrefix(r.Spelling(), "Reason=")), nil
+}
+
+// Measure is the layout of a type or field for a target. Offset is the offset of a field in
+// bits, it is -1 for types. All values are -1 if the type or field is missing.
+type Measure struct {
+	Triple string
+	Size   int64
+	Align  int64
+	Offset int64
+}
+
+// Divergence is a type or a field of a record whose layout differs between targets.
+type Divergence struct {
+	Type string
+	// Field is empty if the divergence concerns the type as a whole.
+	Field    string `json:",omitempty"`
+	Reasons  []Reason
+	Measures []Measure
+}
+
+// Compare returns the types and fields whose layout differs between the snapshots, ordered by
+// type name.
+func Compare(snaps []*Snapshot) []Divergence {
+	records := make([]map[string]*Record, len(snaps))
+	typedefs := make([]map[string]Typedef, len(snaps))
+	names := map[string]bool{}
+	for i, s := range snaps {
+		records[i] = map[string]*Record{}
+		for _, r := range s.Records {
+			records[i][r.Name] = r
+			names[r.Name] = true
+		}
+		typedefs[i] = map[string]Typedef{}
+		for _, t := range s.Typedefs {
+			typedefs[i][t.Name] = t
+			names[t.Name] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	var divs []Divergence
+	for _, name := range sorted {
+		rs := make([]*Record, len(snaps))
+		ts := make([]*Typedef, len(snaps))
+		for i := range snaps {
+			if r, ok := records[i][name]; ok {
+				rs[i] = r
+			}
+			if t, ok := typedefs[i][name]; ok {
+				ts[i] = &t
+			}
+		}
+
+		if anyRecord(rs) {
+			divs = append(divs, compareRecords(name, snaps, rs)...)
+		} else {
+			divs = append(divs, compareTypedefs(name, snaps, ts)...)
+		}
+	}
+
+	return divs
+}
+
+func anyRecord(rs []*Record) bool {
+	for _, r := range rs {
+		if r != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compareRecords(name string, snaps []*Snapshot, rs []*Record) []Divergence {
+	whole := Divergence{Type: name, Measures: make([]Measure, len(snaps))}
+	missing := false
+	for i, r := range rs {
+		whole.Measures[i] = Measure{Triple: snaps[i].Triple, Size: -1, Align: -1, Offset: -1}
+		if r == nil {
+			missing = true
+
+			continue
+		}
+		whole.Measures[i].Size, whole.Measures[i].Align = r.Size, r.Align
+	}
+	if missing {
+		whole.Reasons = []Reason{Reason_Missing}
+
+		return []Divergence{whole}
+	}
+
+	// Fields are matched by name, unnamed fields by their type and their position among the
+	// unnamed fields of that type. Fields missing for the first targets are compared as well.
+	var keys []string
+	seen := map[string]bool{}
+	fields := make([]map[string]Field, len(rs))
+	for i, r := range rs {
+		fields[i] = map[string]Field{}
+		unnamed := map[string]int{}
+		for _, f := range r.Fields {
+			key := f.Name
+			if key == "" {
+				key = fmt.Sprintf("%s#%d", fieldName(f), unnamed[f.Type])
+				unnamed[f.Type]++
+			}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+			fields[i][key] = f
+		}
+	}
+
+	var divs []Divergence
+	reasons := map[Reason]bool{}
+	for _, key := range keys {
+		d := Divergence{Type: name, Field: key, Measures: make([]Measure, len(rs))}
+		fs := make([]Field, 0, len(rs))
+		for i := range rs {
+			f, ok := fields[i][key]
+			if !ok {
+				d.Measures[i] = Measure{Triple: snaps[i].Triple, Size: -1, Align: -1, Offset: -1}
+
+				continue
+			}
+			d.Measures[i] = Measure{Triple: snaps[i].Triple, Size: f.Size, Align: f.Align, Offset: f.Offset}
+			fs = append(fs, f)
+		}
+
+		var r Reason
+		switch {
+		case len(fs) < len(rs):
+			r = Reason_Missing
+		default:
+			r = fieldReason(fs)
+		}
+		if r == 0 {
+			continue
+		}
+
+		d.Reasons = []Reason{r}
+		reasons[r] = true
+		divs = append(divs, d)
+	}
+
+	if !same(whole.Measures, func(m Measure) int64 { return m.Size }) ||
+		!same(whole.Measures, func(m Measure) int64 { return m.Align }) {
+		for r := Reason_PointerWidth; r <= Reason_Missing; r++ {
+			if reasons[r] && r != Reason_Offset {
+				whole.Reasons = append(whole.Reasons, r)
+			}
+		}
+		if len(whole.Reasons) == 0 {
+			whole.Reasons = []Reason{Reason_Alignment}
+		}
+	}
+	if len(whole.Reasons) > 0 || len(divs) > 0 {
+		if len(whole.Reasons) == 0 {
+			whole.Reasons = []Reason{Reason_Offset}
+		}
+		divs = append([]Divergence{whole}, divs...)
+	}
+
+	return divs
+}
+
+// fieldReason returns why the layout of the field differs between targets, or 0.
+func fieldReason(fs []Field) Reason {
+	sizes, aligns, offsets := true, true, true
+	for _, f := range fs[1:] {
+		sizes = sizes && f.Bits() == fs[0].Bits()
+		aligns = aligns && f.Align == fs[0].Align
+		offsets = offsets && f.Offset == fs[0].Offset
+	}
+
+	switch {
+	case !sizes:
+		return kindReason(fs[0].Kind)
+	case !aligns:
+		if r := kindReason(fs[0].Kind); r == Reason_Nested {
+			return r
+		}
+
+		return Reason_Alignment
+	case !offsets:
+		return Reason_Offset
+	}
+
+	return 0
+}
+
+func compareTypedefs(name string, snaps []*Snapshot, ts []*Typedef) []Divergence {
+	d := Divergence{Type: name, Measures: make([]Measure, len(snaps))}
+	var kind clang.TypeKind
+	missing := false
+	for i, t := range ts {
+		d.Measures[i] = Measure{Triple: snaps[i].Triple, Size: -1, Align: -1, Offset: -1}
+		if t == nil {
+			missing = true
+
+			continue
+		}
+		d.Measures[i].Size, d.Measures[i].Align = t.Size, t.Align
+		kind = t.Kind
+	}
+
+	switch {
+	case missing:
+		d.Reasons = []Reason{Reason_Missing}
+	case !same(d.Measures, func(m Measure) int64 { return m.Size }):
+		d.Reasons = []Reason{kindReason(kind)}
+	case !same(d.Measures, func(m Measure) int64 { return m.Align }):
+		d.Reasons = []Reason{Reason_Alignment}
+	default:
+		return nil
+	}
+
+	return []Divergence{d}
+}
+
+// kindReason returns the reason a type of the kind differs in size.
+func kindReason(kind clang.TypeKind) Reason {
+	switch kind {
+	case clang.Type_Pointer, clang.Type_LValueReference, clang.Type_RValueReference, clang.Type_MemberPointer,
+		clang.Type_BlockPointer, clang.Type_ObjCObjectPointer:
+		return Reason_PointerWidth
+	case clang.Type_Long, clang.Type_ULong:
+		return Reason_LongSize
+	case clang.Type_LongDouble:
+		return Reason_LongDoubleSize
+	case clang.Type_WChar:
+		return Reason_WCharSize
+	case clang.Type_Record, clang.Type_Enum:
+		return Reason_Nested
+	}
+
+	return Reason_Size
+}
+
+func same(ms []Measure, value func(Measure) int64) bool {
+	for _, m := range ms[1:] {
+		if value(m) != value(ms[0]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WritePortability writes the divergences as text, every type followed by its fields.
+func WritePortability(w io.Writer, divs []Divergence) error {
+	var b strings.Builder
+
+	for _, d := range divs {
+		reasons := make([]string, len(d.Reasons))
+		for i, r := range d.Reasons {
+			reasons[i] = r.Description()
+		}
+
+		if d.Field == "" {
+			fmt.Fprintf(&b, "%s: %s\n", d.Type, strings.Join(reasons, ", "))
+			for _, m := range d.Measures {
+				if m.Size < 0 {
+					fmt.Fprintf(&b, "\t%-32s missing\n", m.Triple)
+				} else {
+					fmt.Fprintf(&b, "\t%-32s size %d, align %d\n", m.Triple, m.Size, m.Align)
+				}
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(&b, "\t.%s: %s\n", d.Field, strings.Join(reasons, ", "))
+		for _, m := range d.Measures {
+			if m.Size < 0 {
+				fmt.Fprintf(&b, "\t\t%-32s missing\n", m.Triple)
+			} else {
+				fmt.Fprintf(&b, "\t\t%-32s offset %s, size %d, align %d\n", m.Triple, bitOffset(m.Offset), m.Size, m.Align)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func bitOffset(offset int64) string {
+	if offset%8 != 0 {
+		return fmt.Sprintf("%d:%d", offset/8, offset%8)
+	}
+
+	return fmt.Sprint(offset / 8)
+}
@@ -0,0 +1,218 @@
+package layout
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+func TestRetarget(t *testing.T) {
+	cmd := compdb.Command{Args: []string{"cc", "-target", "x86_64-linux-gnu", "--target=i386", "-O2", "-c", "a.c"}}
+
+	got := retarget(cmd, "armv7-none-eabi").Args
+	want := []string{"cc", "-target", "armv7-none-eabi", "-O2", "-c", "a.c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("retarget = %v, want %v", got, want)
+	}
+	if len(cmd.Args) != 7 {
+		t.Errorf("retarget modified the command: %v", cmd.Args)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ptr := Field{Type: "void *", Kind: clang.Type_Pointer}
+	lng := Field{Type: "long", Kind: clang.Type_Long}
+	i64 := Field{Type: "long long", Kind: clang.Type_LongLong, Size: 8}
+	i32 := Field{Type: "int", Kind: clang.Type_Int, Size: 4, Align: 4}
+
+	lp64, ilp32 := ptr, ptr
+	lp64.Size, lp64.Align, ilp32.Size, ilp32.Align = 8, 8, 4, 4
+	long64, long32 := lng, lng
+	long64.Size, long64.Align, long32.Size, long32.Align = 8, 8, 4, 4
+	ll8, ll4 := i64, i64
+	ll8.Align, ll4.Align = 8, 4
+
+	x86_64 := &Snapshot{
+		Triple:       "x86_64-unknown-linux-gnu",
+		PointerWidth: 64,
+		Records: []*Record{
+			record("struct node", named(i32, "id"), named(lp64, "next")),
+			record("struct time", named(i32, "flags"), named(ll8, "ns")),
+			record("struct same", named(i32, "a"), named(i32, "b")),
+		},
+		Typedefs: []Typedef{
+			{Name: "ssize", Kind: clang.Type_Long, Size: 8, Align: 8},
+			{Name: "u32", Kind: clang.Type_UInt, Size: 4, Align: 4},
+		},
+	}
+	i386 := &Snapshot{
+		Triple:       "i386-unknown-linux-gnu",
+		PointerWidth: 32,
+		Records: []*Record{
+			record("struct node", named(i32, "id"), named(ilp32, "next")),
+			record("struct time", named(i32, "flags"), named(ll4, "ns")),
+			record("struct same", named(i32, "a"), named(i32, "b")),
+			record("struct extra", named(long32, "l")),
+		},
+		Typedefs: []Typedef{
+			{Name: "ssize", Kind: clang.Type_Long, Size: 4, Align: 4},
+			{Name: "u32", Kind: clang.Type_UInt, Size: 4, Align: 4},
+		},
+	}
+
+	type result struct {
+		typ, field string
+		reasons    []Reason
+	}
+	var got []result
+	for _, d := range Compare([]*Snapshot{x86_64, i386}) {
+		got = append(got, result{d.Type, d.Field, d.Reasons})
+	}
+
+	want := []result{
+		{"ssize", "", []Reason{Reason_LongSize}},
+		{"struct extra", "", []Reason{Reason_Missing}},
+		{"struct node", "", []Reason{Reason_PointerWidth}},
+		{"struct node", "next", []Reason{Reason_PointerWidth}},
+		{"struct time", "", []Reason{Reason_Alignment}},
+		{"struct time", "ns", []Reason{Reason_Alignment}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestConfirms(t *testing.T) {
+	tests := []struct {
+		requested string
+		got       string
+		width     int
+		want      bool
+	}{
+		{"x86_64-linux-gnu", "x86_64-unknown-linux-gnu", 64, true},
+		{"x86_64-linux-gnux32", "x86_64-unknown-linux-gnux32", 32, true},
+		{"x86_64-linux-gnux32", "x86_64-unknown-linux-gnu", 64, false},
+		{"i686-linux-gnu", "i686-unknown-linux-gnu", 32, true},
+		{"arm-none-eabi", "armv4t-none-unknown-eabi", 32, true},
+		{"armv7m-none-eabi", "thumbv7m-none-unknown-eabi", 32, true},
+		{"arm64-apple-macosx", "arm64-apple-macosx11.0.0", 64, true},
+		{"aarch64-linux-gnu", "aarch64-unknown-linux-gnu", 64, true},
+		{"riscv32-unknown-elf", "riscv32-unknown-unknown-elf", 32, true},
+		{"aarch64-linux-gnu", "x86_64-unknown-linux-gnu", 64, false},
+		{"arm-none-eabi", "x86_64-unknown-linux-gnu", 64, false},
+	}
+
+	for _, tt := range tests {
+		snap := &Snapshot{Triple: tt.got, PointerWidth: tt.width}
+		if got := confirms(snap, tt.requested); got != tt.want {
+			t.Errorf("expected %v for %s reported as %s. got=%v", tt.want, tt.requested, tt.got, got)
+		}
+	}
+}
+
+func TestCompareFields(t *testing.T) {
+	i32 := Field{Type: "int", Kind: clang.Type_Int, Size: 4, Align: 4}
+	pad := Field{Type: "int", Kind: clang.Type_Int, Size: 4, Align: 4, BitField: true, BitWidth: 3}
+
+	// The second target has an extra field in front of the unnamed bit-field, and a field the
+	// first target lacks.
+	a := &Snapshot{Triple: "a", Records: []*Record{record("struct s", named(i32, "x"), pad)}}
+	b := &Snapshot{Triple: "b", Records: []*Record{record("struct s", named(i32, "x"), named(i32, "y"), pad)}}
+
+	type result struct {
+		field   string
+		reasons []Reason
+	}
+	var got []result
+	for _, d := range Compare([]*Snapshot{a, b}) {
+		got = append(got, result{d.Field, d.Reasons})
+	}
+
+	want := []result{
+		{"", []Reason{Reason_Missing}},
+		{"(int)#0", []Reason{Reason_Offset}},
+		{"y", []Reason{Reason_Missing}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestWritePortability(t *testing.T) {
+	divs := []Divergence{
+		{Type: "struct node", Reasons: []Reason{Reason_PointerWidth}, Measures: []Measure{
+			{Triple: "x86_64", Size: 16, Align: 8, Offset: -1},
+			{Triple: "i386", Size: 8, Align: 4, Offset: -1},
+		}},
+		{Type: "struct node", Field: "next", Reasons: []Reason{Reason_PointerWidth}, Measures: []Measure{
+			{Triple: "x86_64", Size: 8, Align: 8, Offset: 64},
+			{Triple: "i386", Size: 4, Align: 4, Offset: 32},
+		}},
+	}
+
+	var b strings.Builder
+	if err := WritePortability(&b, divs); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "struct node: pointer width\n" +
+		"\tx86_64                           size 16, align 8\n" +
+		"\ti386                             size 8, align 4\n" +
+		"\t.next: pointer width\n" +
+		"\t\tx86_64                           offset 8, size 8, align 8\n" +
+		"\t\ti386                             offset 4, size 4, align 4\n"
+	if got := b.String(); got != want {
+		t.Errorf("WritePortability =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	cmd := compdb.Command{Directory: dir, Filename: "testdata/portable.h", Args: []string{"cc", "-x", "c", "-c", "testdata/portable.h"}}
+	snaps, err := Targets(idx, cmd, []string{"x86_64-unknown-linux-gnu", "i686-unknown-linux-gnu"}, 0, Collector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || snaps[0].PointerWidth != 64 || snaps[1].PointerWidth != 32 {
+		t.Fatalf("expected snapshots with 64 and 32 bit pointers. got=%+v", snaps)
+	}
+
+	type result struct {
+		field   string
+		reasons []Reason
+		offsets []int64
+	}
+	var got []result
+	for _, d := range Compare(snaps) {
+		if d.Type != "struct node" {
+			t.Errorf("unexpected divergence of %s", d.Type)
+
+			continue
+		}
+		r := result{field: d.Field, reasons: d.Reasons}
+		for _, m := range d.Measures {
+			r.offsets = append(r.offsets, m.Offset)
+		}
+		got = append(got, r)
+	}
+
+	want := []result{
+		{"", []Reason{Reason_PointerWidth, Reason_LongSize}, []int64{-1, -1}},
+		{"size", []Reason{Reason_LongSize}, []int64{64, 32}},
+		{"next", []Reason{Reason_PointerWidth}, []int64{128, 64}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected divergences\n%v\ngot\n%v", want, got)
+	}
+}
@@ -0,0 +1,192 @@
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Report summarizes the layout of a record and what could be improved.
+type Report struct {
+	*Record
+	Holes []Hole `json:",omitempty"`
+	// Padding and Wasted are in bits.
+	Padding int64
+	Wasted  int64
+	// Crossings are the indexes of the fields straddling a cache line boundary.
+	Crossings []int `json:",omitempty"`
+	// Suggested is the order of the fields minimizing the size of the record, it is empty if
+	// the record can not be made smaller. SuggestedSize is the size in bytes of that order.
+	Suggested     []Field `json:",omitempty"`
+	SuggestedSize int64
+	CacheLine     int64
+}
+
+// Analyze returns the report of the record for cache lines of the given size in bytes.
+func Analyze(r *Record, cacheLine int64) Report {
+	rep := Report{
+		Record:    r,
+		Holes:     r.Holes(),
+		Padding:   r.Padding(),
+		Wasted:    r.Wasted(),
+		Crossings: r.CacheLineCrossings(cacheLine),
+		CacheLine: cacheLine,
+	}
+
+	order, size := r.Reorder()
+	rep.SuggestedSize = size
+	if size < r.Size {
+		rep.Suggested = order
+	}
+
+	return rep
+}
+
+// Savings returns the number of bytes saved by the suggested order.
+func (rep Report) Savings() int64 {
+	return rep.Size - rep.SuggestedSize
+}
+
+// WriteJSON writes the reports as JSON.
+func WriteJSON(w io.Writer, reports []Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(reports)
+}
+
+// WriteText writes the layout of the report in the style of pahole: every field with its
+// offset and size, followed by the holes, cache line boundaries and a summary.
+func WriteText(w io.Writer, rep Report) error {
+	var b strings.Builder
+
+	name := rep.Name
+	if keyword := kindName(rep.Kind); !strings.HasPrefix(name, keyword+" ") {
+		name = keyword + " " + name
+	}
+	fmt.Fprintf(&b, "%s {\n", name)
+	if rep.Location.IsValid() {
+		fmt.Fprintf(&b, "\t/* %s */\n", rep.Location)
+	}
+	if rep.Prefix > 0 {
+		fmt.Fprintf(&b, "\t/* base classes and vtable pointer: %s */\n", bits(rep.Prefix))
+	}
+
+	holes := map[int]Hole{}
+	for _, h := range rep.Holes {
+		holes[h.After] = h
+	}
+	crossing := map[int]bool{}
+	for _, i := range rep.Crossings {
+		crossing[i] = true
+	}
+
+	line := int64(0)
+	for i, f := range rep.Fields {
+		if rep.CacheLine > 0 && !rep.IsUnion() {
+			for next := (line + 1) * rep.CacheLine; f.Offset/8 >= next; next += rep.CacheLine {
+				line++
+				fmt.Fprintf(&b, "\t/* --- cacheline %d boundary (%d bytes) --- */\n", line, next)
+			}
+		}
+
+		fmt.Fprintf(&b, "\t%-40s /* %8s %5d */\n", declaration(f)+";", offset(f), f.Size)
+		if crossing[i] {
+			fmt.Fprintf(&b, "\t/* XXX crosses cacheline %d boundary */\n", line+1)
+		}
+		if h, ok := holes[i]; ok {
+			fmt.Fprintf(&b, "\n\t/* XXX %s hole, try to pack */\n\n", bits(h.Size))
+		}
+	}
+
+	var members, holeBits int64
+	for _, f := range rep.Fields {
+		members += f.Bits()
+	}
+	for _, h := range rep.Holes {
+		holeBits += h.Size
+	}
+
+	fmt.Fprintf(&b, "\n\t/* size: %d, align: %d, members: %d */\n", rep.Size, rep.Align, len(rep.Fields))
+	fmt.Fprintf(&b, "\t/* sum members: %s, holes: %d, sum holes: %s */\n", bits(members), len(rep.Holes), bits(holeBits))
+	if rep.Suffix > 0 {
+		fmt.Fprintf(&b, "\t/* virtual base classes: %s */\n", bits(rep.Suffix))
+	}
+	if rep.Padding > 0 {
+		fmt.Fprintf(&b, "\t/* padding: %s */\n", bits(rep.Padding))
+	}
+	if len(rep.Suggested) > 0 {
+		names := make([]string, len(rep.Suggested))
+		for i, f := range rep.Suggested {
+			names[i] = fieldName(f)
+		}
+		fmt.Fprintf(&b, "\t/* suggested order saves %d bytes (size %d): %s */\n", rep.Savings(), rep.SuggestedSize,
+			strings.Join(names, ", "))
+	}
+	b.WriteString("};\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func declaration(f Field) string {
+	d := f.Type
+	if f.Name != "" {
+		d += " " + f.Name
+	}
+	if f.BitField {
+		d += fmt.Sprintf(":%d", f.BitWidth)
+	}
+
+	return d
+}
+
+func fieldName(f Field) string {
+	if f.Name != "" {
+		return f.Name
+	}
+
+	return "(" + f.Type + ")"
+}
+
+// offset returns the byte offset of the field, with the bit offset for bit-fields.
+func offset(f Field) string {
+	if f.BitField || f.Offset%8 != 0 {
+		return fmt.Sprintf("%d:%d", f.Offset/8, f.Offset%8)
+	}
+
+	return fmt.Sprint(f.Offset / 8)
+}
+
+// bits formats a number of bits in bytes and bits.
+func bits(n int64) string {
+	var parts []string
+	if bytes := n / 8; bytes == 1 {
+		parts = append(parts, "1 byte")
+	} else if bytes > 1 || n == 0 {
+		parts = append(parts, fmt.Sprintf("%d bytes", bytes))
+	}
+	if rest := n % 8; rest == 1 {
+		parts = append(parts, "1 bit")
+	} else if rest > 1 {
+		parts = append(parts, fmt.Sprintf("%d bits", rest))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// kindName returns the keyword of the record kind.
+func kindName(kind clang.CursorKind) string {
+	switch kind {
+	case clang.Cursor_UnionDecl:
+		return "union"
+	case clang.Cursor_ClassDecl:
+		return "class"
+	}
+
+	return "struct"
+}
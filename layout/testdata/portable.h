@@ -0,0 +1,5 @@
+struct node {
+	int id;
+	long size;
+	struct node *next;
+};
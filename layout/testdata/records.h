@@ -0,0 +1,23 @@
+struct padded {
+	char c;
+	int i;
+	char d;
+};
+
+struct flags {
+	unsigned a : 3;
+	unsigned b : 5;
+	int x;
+};
+
+struct tagged {
+	int kind;
+	union {
+		int i;
+		double d;
+	};
+	struct {
+		char x;
+		char y;
+	};
+};
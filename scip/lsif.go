@@ -0,0 +1,226 @@
+package scip
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// lsifVersion is the version of the LSIF format written by WriteLSIF.
+const lsifVersion = "0.4.3"
+
+type lsifPosition struct {
+	Line      uint32 `json:"line"`
+	Character uint32 `json:"character"`
+}
+
+// lsifWriter writes LSIF vertices and edges as JSON lines.
+type lsifWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	id  int
+	err error
+}
+
+func (lw *lsifWriter) emit(v map[string]interface{}) int {
+	lw.id++
+	v["id"] = lw.id
+	if lw.err == nil {
+		lw.err = lw.enc.Encode(v)
+	}
+
+	return lw.id
+}
+
+func (lw *lsifWriter) vertex(label string, v map[string]interface{}) int {
+	if v == nil {
+		v = map[string]interface{}{}
+	}
+	v["type"] = "vertex"
+	v["label"] = label
+
+	return lw.emit(v)
+}
+
+func (lw *lsifWriter) edge(label string, out int, in interface{}, extra map[string]interface{}) {
+	v := map[string]interface{}{
+		"type":  "edge",
+		"label": label,
+		"outV":  out,
+	}
+	if ins, ok := in.([]int); ok {
+		v["inVs"] = ins
+	} else {
+		v["inV"] = in
+	}
+	for k, x := range extra {
+		v[k] = x
+	}
+	lw.emit(v)
+}
+
+// lsifSymbol collects the ranges of a symbol per document.
+type lsifSymbol struct {
+	definitions map[int][]int
+	references  map[int][]int
+	docs        []int
+}
+
+func (ls *lsifSymbol) add(m map[int][]int, doc, r int) {
+	if _, ok := ls.definitions[doc]; !ok {
+		if _, ok := ls.references[doc]; !ok {
+			ls.docs = append(ls.docs, doc)
+		}
+	}
+	m[doc] = append(m[doc], r)
+}
+
+// WriteLSIF writes the index in the LSIF format used by older tools. Positions are UTF-16
+// offsets, so the indexed files are read to convert the columns. Documents which cannot be read
+// are left out of the index and reported in Skipped.
+func (ix *Indexer) WriteLSIF(w io.Writer) error {
+	docs, _ := ix.documents()
+	ix.Skipped = nil
+
+	bw := bufio.NewWriter(w)
+	lw := &lsifWriter{w: bw, enc: json.NewEncoder(bw)}
+
+	lw.vertex("metaData", map[string]interface{}{
+		"version":          lsifVersion,
+		"projectRoot":      ix.projectRoot(),
+		"positionEncoding": "utf-16",
+		"toolInfo": map[string]interface{}{
+			"name":    ix.Tool,
+			"version": ix.Version,
+			"args":    ix.Arguments,
+		},
+	})
+	project := lw.vertex("project", map[string]interface{}{"kind": "cpp"})
+
+	symbols := map[string]*lsifSymbol{}
+	var order []string
+	var docIDs []int
+
+	for _, d := range docs {
+		text, err := os.ReadFile(d.path)
+		if err != nil {
+			ix.Skipped = append(ix.Skipped, err)
+
+			continue
+		}
+		src := clang.NewSourceText(d.path, string(text))
+
+		doc := lw.vertex("document", map[string]interface{}{
+			"uri":        fileURI(d.path),
+			"languageId": language(d.path),
+		})
+		docIDs = append(docIDs, doc)
+
+		var ranges []int
+		for _, o := range d.occurrences {
+			sl, sc := src.UTF16Position(o.Span.Start)
+			el, ec := src.UTF16Position(o.Span.End)
+			r := lw.vertex("range", map[string]interface{}{
+				"start": lsifPosition{sl, sc},
+				"end":   lsifPosition{el, ec},
+			})
+			ranges = append(ranges, r)
+
+			s, ok := symbols[o.USR]
+			if !ok {
+				s = &lsifSymbol{definitions: map[int][]int{}, references: map[int][]int{}}
+				symbols[o.USR] = s
+				order = append(order, o.USR)
+			}
+			if o.Roles&(clang.SymbolRole_Definition|clang.SymbolRole_Declaration) != 0 {
+				s.add(s.definitions, doc, r)
+			} else {
+				s.add(s.references, doc, r)
+			}
+		}
+		if len(ranges) > 0 {
+			lw.edge("contains", doc, ranges, nil)
+		}
+	}
+
+	for _, usr := range order {
+		ix.writeLSIFSymbol(lw, usr, symbols[usr])
+	}
+
+	if len(docIDs) > 0 {
+		lw.edge("contains", project, docIDs, nil)
+	}
+
+	if lw.err != nil {
+		return lw.err
+	}
+
+	return bw.Flush()
+}
+
+func (ix *Indexer) writeLSIFSymbol(lw *lsifWriter, usr string, ls *lsifSymbol) {
+	s := ix.unit.Symbols[usr]
+
+	set := lw.vertex("resultSet", nil)
+	for _, doc := range ls.docs {
+		for _, r := range ls.definitions[doc] {
+			lw.edge("next", r, set, nil)
+		}
+		for _, r := range ls.references[doc] {
+			lw.edge("next", r, set, nil)
+		}
+	}
+
+	if len(ls.definitions) > 0 {
+		def := lw.vertex("definitionResult", nil)
+		lw.edge("textDocument/definition", set, def, nil)
+		for _, doc := range ls.docs {
+			if rs := ls.definitions[doc]; len(rs) > 0 {
+				lw.edge("item", def, rs, map[string]interface{}{"document": doc})
+			}
+		}
+	}
+
+	refs := lw.vertex("referenceResult", nil)
+	lw.edge("textDocument/references", set, refs, nil)
+	for _, doc := range ls.docs {
+		if rs := ls.definitions[doc]; len(rs) > 0 {
+			lw.edge("item", refs, rs, map[string]interface{}{"document": doc, "property": "definitions"})
+		}
+		if rs := ls.references[doc]; len(rs) > 0 {
+			lw.edge("item", refs, rs, map[string]interface{}{"document": doc, "property": "references"})
+		}
+	}
+
+	if s == nil {
+		return
+	}
+
+	hover := lw.vertex("hoverResult", map[string]interface{}{
+		"result": map[string]interface{}{
+			"contents": map[string]string{
+				"kind":  "markdown",
+				"value": strings.Join(hoverText(s, "cpp"), "\n\n"),
+			},
+		},
+	})
+	lw.edge("textDocument/hover", set, hover, nil)
+
+	kind := "export"
+	switch {
+	case s.Local:
+		kind = "local"
+	case len(ls.definitions) == 0:
+		kind = "import"
+	}
+	moniker := lw.vertex("moniker", map[string]interface{}{
+		"scheme":     "clang-usr",
+		"identifier": usr,
+		"kind":       kind,
+	})
+	lw.edge("moniker", set, moniker, nil)
+}
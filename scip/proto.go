@@ -0,0 +1,82 @@
+package scip
+
+import (
+	"encoding/binary"
+)
+
+// protoBuffer encodes protocol buffer messages. Only the wire types used by the SCIP schema
+// are supported.
+type protoBuffer struct {
+	b []byte
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (p *protoBuffer) tag(field int, wire int) {
+	p.b = appendUvarint(p.b, uint64(field)<<3|uint64(wire))
+}
+
+func (p *protoBuffer) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+
+	p.tag(field, wireVarint)
+	p.b = appendUvarint(p.b, v)
+}
+
+func (p *protoBuffer) bool(field int, v bool) {
+	if v {
+		p.varint(field, 1)
+	}
+}
+
+func (p *protoBuffer) bytes(field int, v []byte) {
+	p.tag(field, wireBytes)
+	p.b = appendUvarint(p.b, uint64(len(v)))
+	p.b = append(p.b, v...)
+}
+
+func (p *protoBuffer) string(field int, v string) {
+	if v == "" {
+		return
+	}
+
+	p.tag(field, wireBytes)
+	p.b = appendUvarint(p.b, uint64(len(v)))
+	p.b = append(p.b, v...)
+}
+
+func (p *protoBuffer) strings(field int, vs []string) {
+	for _, v := range vs {
+		p.tag(field, wireBytes)
+		p.b = appendUvarint(p.b, uint64(len(v)))
+		p.b = append(p.b, v...)
+	}
+}
+
+// packed encodes a packed repeated int32 field.
+func (p *protoBuffer) packed(field int, vs []int32) {
+	var b []byte
+	for _, v := range vs {
+		b = appendUvarint(b, uint64(int64(v)))
+	}
+	p.bytes(field, b)
+}
+
+// message encodes the embedded message written by f.
+func (p *protoBuffer) message(field int, f func(m *protoBuffer)) {
+	var m protoBuffer
+	f(&m)
+	p.bytes(field, m.b)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+
+	return append(b, buf[:n]...)
+}
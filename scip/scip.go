@@ -0,0 +1,295 @@
+// Package scip exports the cross references of C and C++ code in the SCIP and LSIF formats
+// consumed by code search and code review tools.
+//
+// The symbols and occurrences are collected with the xref package, so an index is built by
+// extracting every translation unit of a project and adding the results to an Indexer.
+package scip
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/xref"
+)
+
+// Symbol roles of SCIP occurrences.
+const (
+	roleDefinition        = 0x1
+	roleWriteAccess       = 0x4
+	roleReadAccess        = 0x8
+	roleGenerated         = 0x10
+	roleForwardDefinition = 0x40
+)
+
+// Indexer collects the cross references of a project.
+type Indexer struct {
+	// Root is the project root. Only files below it become documents of the index, symbols
+	// from other files are exported as external symbols.
+	Root string
+	// Tool and Version describe the tool which created the index.
+	Tool    string
+	Version string
+	// Arguments are the arguments the tool was run with.
+	Arguments []string
+	// Skipped holds the errors of the documents the last WriteLSIF could not read.
+	Skipped []error
+
+	unit *xref.Unit
+}
+
+// NewIndexer returns an indexer for the project at root.
+func NewIndexer(root string) *Indexer {
+	return &Indexer{
+		Root: filepath.Clean(root),
+		Tool: "go-clang",
+		unit: xref.NewUnit(),
+	}
+}
+
+// Add adds the cross references of a translation unit.
+func (ix *Indexer) Add(u *xref.Unit) {
+	ix.unit.Merge(u)
+}
+
+// document holds the occurrences of a single file below the root.
+type document struct {
+	path        string
+	rel         string
+	occurrences []xref.Occurrence
+	// defined are the USRs of the symbols declared in the document in order of appearance.
+	defined []string
+}
+
+// documents groups the occurrences by file. Documents and their occurrences are sorted by
+// path and position.
+func (ix *Indexer) documents() ([]*document, map[string]bool) {
+	byPath := map[string]*document{}
+	declared := map[string]bool{}
+
+	for _, o := range ix.unit.Occurrences {
+		path := o.Span.Start.File
+		d, ok := byPath[path]
+		if !ok {
+			rel, err := filepath.Rel(ix.Root, path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			d = &document{path: path, rel: filepath.ToSlash(rel)}
+			byPath[path] = d
+		}
+		d.occurrences = append(d.occurrences, o)
+	}
+
+	docs := make([]*document, 0, len(byPath))
+	for _, d := range byPath {
+		sort.SliceStable(d.occurrences, func(i, j int) bool {
+			return d.occurrences[i].Span.Start.Offset < d.occurrences[j].Span.Start.Offset
+		})
+
+		seen := map[string]bool{}
+		for _, o := range d.occurrences {
+			if o.Roles&(clang.SymbolRole_Definition|clang.SymbolRole_Declaration) != 0 && !seen[o.USR] {
+				seen[o.USR] = true
+				declared[o.USR] = true
+				d.defined = append(d.defined, o.USR)
+			}
+		}
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].rel < docs[j].rel
+	})
+
+	return docs, declared
+}
+
+func (ix *Indexer) projectRoot() string {
+	return fileURI(ix.Root)
+}
+
+func fileURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+
+	return u.String()
+}
+
+func language(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".c":
+		return "c"
+	case ".m":
+		return "objective-c"
+	case ".mm":
+		return "objective-cpp"
+	}
+
+	return "cpp"
+}
+
+// roles converts libclang symbol roles into SCIP symbol roles.
+func roles(r clang.SymbolRole) int32 {
+	var roles int32
+	if r&clang.SymbolRole_Definition != 0 {
+		roles |= roleDefinition
+	} else if r&clang.SymbolRole_Declaration != 0 {
+		roles |= roleForwardDefinition
+	}
+	if r&clang.SymbolRole_Write != 0 {
+		roles |= roleWriteAccess
+	}
+	if r&clang.SymbolRole_Read != 0 {
+		roles |= roleReadAccess
+	}
+	if r&clang.SymbolRole_Implicit != 0 {
+		roles |= roleGenerated
+	}
+
+	return roles
+}
+
+// hoverText returns the documentation of a symbol as Markdown: its declaration followed by its
+// brief comment.
+func hoverText(s *xref.Symbol, lang string) []string {
+	docs := []string{fmt.Sprintf("```%s\n%s\n```", lang, signature(s))}
+	if s.Doc != "" {
+		docs = append(docs, s.Doc)
+	}
+
+	return docs
+}
+
+func signature(s *xref.Symbol) string {
+	switch s.Kind {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_FunctionTemplate, clang.Cursor_ConversionFunction:
+		if i := strings.IndexByte(s.Type, '('); i > 0 {
+			return strings.TrimSpace(s.Type[:i]) + " " + s.DisplayName + s.Type[strings.LastIndexByte(s.Type, ')')+1:]
+		}
+	case clang.Cursor_VarDecl, clang.Cursor_ParmDecl, clang.Cursor_FieldDecl:
+		return s.Type + " " + s.Name
+	case clang.Cursor_StructDecl:
+		return "struct " + s.Name
+	case clang.Cursor_UnionDecl:
+		return "union " + s.Name
+	case clang.Cursor_ClassDecl, clang.Cursor_ClassTemplate:
+		return "class " + s.DisplayName
+	case clang.Cursor_EnumDecl:
+		return "enum " + s.Name
+	case clang.Cursor_Namespace:
+		return "namespace " + s.Name
+	case clang.Cursor_MacroDefinition:
+		return "#define " + s.Name
+	case clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		return "typedef " + s.Name
+	}
+
+	return s.DisplayName
+}
+
+// WriteSCIP writes the index in the SCIP protobuf format. Positions are UTF-8 byte offsets.
+func (ix *Indexer) WriteSCIP(w io.Writer) error {
+	docs, declared := ix.documents()
+	names := newSymbolNames(ix.unit.Symbols)
+
+	var index protoBuffer
+
+	// Metadata
+	index.message(1, func(m *protoBuffer) {
+		m.message(2, func(t *protoBuffer) {
+			t.string(1, ix.Tool)
+			t.string(2, ix.Version)
+			t.strings(3, ix.Arguments)
+		})
+		m.string(3, ix.projectRoot())
+		m.varint(4, 1) // UTF8
+	})
+
+	for _, d := range docs {
+		locals := map[string]string{}
+		name := func(usr string) string {
+			s, ok := ix.unit.Symbols[usr]
+			if !ok || !s.Local {
+				return names.global(usr)
+			}
+			if l, ok := locals[usr]; ok {
+				return l
+			}
+			l := fmt.Sprintf("local %d", len(locals))
+			locals[usr] = l
+
+			return l
+		}
+
+		lang := language(d.path)
+		index.message(2, func(m *protoBuffer) {
+			m.string(1, d.rel)
+			for _, o := range d.occurrences {
+				m.message(2, func(occ *protoBuffer) {
+					occ.packed(1, scipRange(o.Span))
+					occ.string(2, name(o.USR))
+					occ.varint(3, uint64(roles(o.Roles)))
+				})
+			}
+			for _, usr := range d.defined {
+				if s, ok := ix.unit.Symbols[usr]; ok {
+					m.message(3, func(info *protoBuffer) {
+						ix.writeSymbolInformation(info, s, name, names, lang)
+					})
+				}
+			}
+			m.string(4, lang)
+			m.varint(6, 1) // UTF8CodeUnitOffsetFromLineStart
+		})
+	}
+
+	// External symbols are referenced but not declared in any document.
+	usrs := make([]string, 0, len(ix.unit.Symbols))
+	for usr, s := range ix.unit.Symbols {
+		if !declared[usr] && !s.Local {
+			usrs = append(usrs, usr)
+		}
+	}
+	sort.Strings(usrs)
+	for _, usr := range usrs {
+		s := ix.unit.Symbols[usr]
+		index.message(3, func(info *protoBuffer) {
+			ix.writeSymbolInformation(info, s, names.global, names, "cpp")
+		})
+	}
+
+	_, err := w.Write(index.b)
+
+	return err
+}
+
+func (ix *Indexer) writeSymbolInformation(m *protoBuffer, s *xref.Symbol, name func(string) string, names *symbolNames, lang string) {
+	m.string(1, name(s.USR))
+	m.strings(3, hoverText(s, lang))
+	for _, r := range s.Relations {
+		m.message(4, func(rel *protoBuffer) {
+			rel.string(1, names.global(r.USR))
+			rel.bool(2, r.Kind == xref.Relation_Override)
+			rel.bool(3, true)
+		})
+	}
+	m.string(6, s.Name)
+	if s.Parent != "" && !s.Local {
+		m.string(8, names.global(s.Parent))
+	}
+}
+
+// scipRange returns the 0-based range of a span. Single line ranges have three elements.
+func scipRange(s clang.Span) []int32 {
+	sl, sc := int32(s.Start.Line)-1, int32(s.Start.Column)-1
+	el, ec := int32(s.End.Line)-1, int32(s.End.Column)-1
+	if sl == el {
+		return []int32{sl, sc, ec}
+	}
+
+	return []int32{sl, sc, el, ec}
+}
@@ -0,0 +1,174 @@
+package scip
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/xref"
+)
+
+func TestProtoBuffer(t *testing.T) {
+	var p protoBuffer
+	p.varint(1, 150)
+	p.string(2, "testing")
+	p.packed(4, []int32{3, 270})
+	p.message(5, func(m *protoBuffer) {
+		m.bool(1, true)
+	})
+
+	want := []byte{
+		0x08, 0x96, 0x01,
+		0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		0x22, 0x03, 0x03, 0x8e, 0x02,
+		0x2a, 0x02, 0x08, 0x01,
+	}
+	if !bytes.Equal(p.b, want) {
+		t.Errorf("expected % x. got=% x", want, p.b)
+	}
+}
+
+func TestSymbolNames(t *testing.T) {
+	symbols := map[string]*xref.Symbol{
+		"c:@N@ns":                   {Name: "ns", Kind: clang.Cursor_Namespace},
+		"c:@N@ns@S@Vec":             {Name: "Vec", Kind: clang.Cursor_StructDecl, Parent: "c:@N@ns"},
+		"c:@N@ns@S@Vec@F@push#I#":   {Name: "push", Kind: clang.Cursor_CXXMethod, Parent: "c:@N@ns@S@Vec"},
+		"c:@N@ns@S@Vec@FI@size":     {Name: "size", Kind: clang.Cursor_FieldDecl, Parent: "c:@N@ns@S@Vec"},
+		"c:@N@ns@S@Vec@F@operator+": {Name: "operator+", Kind: clang.Cursor_CXXMethod, Parent: "c:@N@ns@S@Vec"},
+		"c:a.c@M@MAX":               {Name: "MAX", Kind: clang.Cursor_MacroDefinition},
+	}
+	names := newSymbolNames(symbols)
+
+	table := map[string]string{
+		"c:@N@ns@S@Vec":             "cxx . . $ ns/Vec#",
+		"c:@N@ns@S@Vec@FI@size":     "cxx . . $ ns/Vec#size.",
+		"c:@N@ns@S@Vec@F@push#I#":   "cxx . . $ ns/Vec#push(" + hash("c:@N@ns@S@Vec@F@push#I#") + ").",
+		"c:@N@ns@S@Vec@F@operator+": "cxx . . $ ns/Vec#operator+(" + hash("c:@N@ns@S@Vec@F@operator+") + ").",
+		"c:a.c@M@MAX":               "cxx . . $ MAX!",
+	}
+	for usr, want := range table {
+		if got := names.global(usr); got != want {
+			t.Errorf("%s: expected %q. got=%q", usr, want, got)
+		}
+	}
+
+	if got := escapeName("operator()"); got != "`operator()`" {
+		t.Errorf("unexpected escaped name %q", got)
+	}
+}
+
+func testIndexer(t *testing.T) *Indexer {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.c")
+	if err := os.WriteFile(file, []byte("int x;\nint f(void) { return x; }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pos := func(line, column, offset uint32) clang.Position {
+		return clang.Position{File: file, Line: line, Column: column, Offset: offset}
+	}
+
+	u := xref.NewUnit()
+	u.Symbols["c:@x"] = &xref.Symbol{USR: "c:@x", Name: "x", DisplayName: "x", Kind: clang.Cursor_VarDecl, Type: "int", Doc: "The x."}
+	u.Symbols["c:@F@f"] = &xref.Symbol{USR: "c:@F@f", Name: "f", DisplayName: "f()", Kind: clang.Cursor_FunctionDecl, Type: "int (void)"}
+	u.Symbols["c:@F@printf"] = &xref.Symbol{USR: "c:@F@printf", Name: "printf", Kind: clang.Cursor_FunctionDecl}
+	u.Occurrences = []xref.Occurrence{
+		{USR: "c:@x", Span: clang.Span{Start: pos(1, 5, 4), End: pos(1, 6, 5)}, Roles: clang.SymbolRole_Definition},
+		{USR: "c:@F@f", Span: clang.Span{Start: pos(2, 5, 11), End: pos(2, 6, 12)}, Roles: clang.SymbolRole_Definition},
+		{USR: "c:@x", Span: clang.Span{Start: pos(2, 27, 33), End: pos(2, 28, 34)}, Roles: clang.SymbolRole_Reference | clang.SymbolRole_Read, Container: "c:@F@f"},
+	}
+
+	ix := NewIndexer(dir)
+	ix.Add(u)
+
+	return ix
+}
+
+func TestWriteSCIP(t *testing.T) {
+	ix := testIndexer(t)
+
+	var buf bytes.Buffer
+	if err := ix.WriteSCIP(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"a.c", "cxx . . $ x.", "cxx . . $ f(" + hash("c:@F@f") + ").", "The x.", "```c\nint x\n```", "cxx . . $ printf("} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("expected the index to contain %q", want)
+		}
+	}
+
+	if got := scipRange(clang.Span{Start: clang.Position{Line: 2, Column: 27}, End: clang.Position{Line: 2, Column: 28}}); len(got) != 3 || got[0] != 1 || got[1] != 26 || got[2] != 27 {
+		t.Errorf("unexpected range %v", got)
+	}
+	if got := roles(clang.SymbolRole_Reference | clang.SymbolRole_Read | clang.SymbolRole_Write); got != roleReadAccess|roleWriteAccess {
+		t.Errorf("unexpected roles %x", got)
+	}
+}
+
+func TestWriteLSIF(t *testing.T) {
+	ix := testIndexer(t)
+
+	var buf bytes.Buffer
+	if err := ix.WriteLSIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]int{}
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var v struct {
+			Type  string `json:"type"`
+			Label string `json:"label"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &v); err != nil {
+			t.Fatalf("invalid line %q: %v", sc.Text(), err)
+		}
+		labels[v.Type+" "+v.Label]++
+	}
+
+	want := map[string]int{
+		"vertex metaData":         1,
+		"vertex project":          1,
+		"vertex document":         1,
+		"vertex range":            3,
+		"vertex resultSet":        2,
+		"vertex definitionResult": 2,
+		"vertex referenceResult":  2,
+		"vertex hoverResult":      2,
+		"vertex moniker":          2,
+		"edge next":               3,
+		"edge contains":           2,
+	}
+	for label, n := range want {
+		if labels[label] != n {
+			t.Errorf("expected %d %s. got=%d", n, label, labels[label])
+		}
+	}
+}
+
+func TestWriteLSIFSkipsUnreadableDocuments(t *testing.T) {
+	ix := testIndexer(t)
+	if err := os.Remove(filepath.Join(ix.Root, "a.c")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ix.WriteLSIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ix.Skipped) != 1 || !os.IsNotExist(ix.Skipped[0]) {
+		t.Errorf("expected a.c to be skipped. got=%v", ix.Skipped)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"label":"document"`)) {
+		t.Error("expected no document vertex")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"label":"metaData"`)) {
+		t.Error("expected the metaData vertex")
+	}
+}
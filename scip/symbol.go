@@ -0,0 +1,105 @@
+package scip
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/xref"
+)
+
+// symbolPrefix is the scheme and the empty package of all global symbols.
+const symbolPrefix = "cxx . . $ "
+
+// symbolNames assigns SCIP symbol names to USRs. Global symbols are named after their
+// semantic parents, e.g. "cxx . . $ ns/Vector#push_back(3f2a19c0)." for a method.
+type symbolNames struct {
+	symbols map[string]*xref.Symbol
+	names   map[string]string
+}
+
+func newSymbolNames(symbols map[string]*xref.Symbol) *symbolNames {
+	return &symbolNames{
+		symbols: symbols,
+		names:   map[string]string{},
+	}
+}
+
+// global returns the name of a global symbol.
+func (sn *symbolNames) global(usr string) string {
+	if name, ok := sn.names[usr]; ok {
+		return name
+	}
+
+	name := symbolPrefix + sn.descriptors(usr, 0)
+	sn.names[usr] = name
+
+	return name
+}
+
+func (sn *symbolNames) descriptors(usr string, depth int) string {
+	s, ok := sn.symbols[usr]
+	if !ok {
+		return escapeName("$"+hash(usr)) + "."
+	}
+
+	var parent string
+	if s.Parent != "" && depth < 64 {
+		parent = sn.descriptors(s.Parent, depth+1)
+	}
+
+	name := s.Name
+	if name == "" {
+		name = "$" + hash(usr)
+	}
+	name = escapeName(name)
+
+	switch s.Kind {
+	case clang.Cursor_Namespace:
+		return parent + name + "/"
+	case clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl, clang.Cursor_EnumDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization, clang.Cursor_TypedefDecl,
+		clang.Cursor_TypeAliasDecl, clang.Cursor_TypeAliasTemplateDecl, clang.Cursor_ObjCInterfaceDecl,
+		clang.Cursor_ObjCProtocolDecl:
+		return parent + name + "#"
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_Constructor, clang.Cursor_Destructor,
+		clang.Cursor_ConversionFunction, clang.Cursor_FunctionTemplate, clang.Cursor_ObjCInstanceMethodDecl,
+		clang.Cursor_ObjCClassMethodDecl:
+		// Overloads share their name, the hash of the USR tells them apart.
+		return parent + name + "(" + hash(usr) + ")."
+	case clang.Cursor_MacroDefinition:
+		return name + "!"
+	case clang.Cursor_TemplateTypeParameter, clang.Cursor_NonTypeTemplateParameter, clang.Cursor_TemplateTemplateParameter:
+		return parent + "[" + name + "]"
+	case clang.Cursor_ParmDecl:
+		return parent + "(" + name + ")"
+	}
+
+	return parent + name + "."
+}
+
+// hash returns a short stable hash of s.
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:4])
+}
+
+// escapeName quotes names which contain other characters than those allowed in simple
+// identifiers of the SCIP symbol grammar.
+func escapeName(name string) string {
+	simple := true
+	for _, r := range name {
+		if !(r == '_' || r == '+' || r == '-' || r == '$' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			simple = false
+
+			break
+		}
+	}
+	if simple && name != "" {
+		return name
+	}
+
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
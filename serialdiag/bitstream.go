@@ -0,0 +1,453 @@
+package serialdiag
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Standard abbreviation IDs of the LLVM bitstream container format.
+const (
+	abbrevEndBlock      = 0
+	abbrevEnterSubblock = 1
+	abbrevDefine        = 2
+	abbrevUnabbrevRec   = 3
+	abbrevFirstUser     = 4
+)
+
+// Record codes of the BLOCKINFO block.
+const (
+	blockInfoBlockID = 0
+
+	blockInfoSetBID        = 1
+	blockInfoBlockName     = 2
+	blockInfoSetRecordName = 3
+)
+
+// opKind is the encoding of a single abbreviation operand.
+type opKind uint8
+
+const (
+	opLiteral opKind = 0
+	opFixed   opKind = 1
+	opVBR     opKind = 2
+	opArray   opKind = 3
+	opChar6   opKind = 4
+	opBlob    opKind = 5
+)
+
+// abbrevOp is an operand of an abbreviation. value is the literal value for opLiteral and the
+// bit width for opFixed and opVBR.
+type abbrevOp struct {
+	kind  opKind
+	value uint64
+}
+
+type abbrev []abbrevOp
+
+func literal(v uint64) abbrevOp { return abbrevOp{opLiteral, v} }
+func fixed(w uint64) abbrevOp   { return abbrevOp{opFixed, w} }
+func vbr(w uint64) abbrevOp     { return abbrevOp{opVBR, w} }
+func blob() abbrevOp            { return abbrevOp{kind: opBlob} }
+
+const char6Alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._"
+
+var errUnexpectedEOF = errors.New("unexpected end of bitstream")
+
+// bitReader reads a bitstream whose bits are packed least significant bit first.
+type bitReader struct {
+	data []byte
+	// pos is the position in bits.
+	pos uint64
+}
+
+func (r *bitReader) atEnd() bool {
+	return r.pos >= uint64(len(r.data))*8
+}
+
+func (r *bitReader) read(width uint) (uint64, error) {
+	if width == 0 {
+		return 0, nil
+	}
+	if width > 64 {
+		return 0, fmt.Errorf("invalid field width %d", width)
+	}
+	if r.pos+uint64(width) > uint64(len(r.data))*8 {
+		return 0, errUnexpectedEOF
+	}
+
+	var v uint64
+	for i := uint(0); i < width; {
+		byteIdx := r.pos / 8
+		bitOff := uint(r.pos % 8)
+
+		n := 8 - bitOff
+		if n > width-i {
+			n = width - i
+		}
+
+		bits := (uint64(r.data[byteIdx]) >> bitOff) & (1<<n - 1)
+		v |= bits << i
+
+		i += n
+		r.pos += uint64(n)
+	}
+
+	return v, nil
+}
+
+func (r *bitReader) readVBR(width uint) (uint64, error) {
+	if width < 2 || width > 32 {
+		return 0, fmt.Errorf("invalid vbr width %d", width)
+	}
+
+	hi := uint64(1) << (width - 1)
+
+	var v uint64
+	for shift := uint(0); ; shift += width - 1 {
+		if shift > 63 {
+			return 0, errors.New("vbr value overflows 64 bits")
+		}
+
+		chunk, err := r.read(width)
+		if err != nil {
+			return 0, err
+		}
+
+		v |= (chunk & (hi - 1)) << shift
+		if chunk&hi == 0 {
+			return v, nil
+		}
+	}
+}
+
+func (r *bitReader) align32() {
+	r.pos = (r.pos + 31) &^ 31
+}
+
+func (r *bitReader) skip(bits uint64) error {
+	if r.pos+bits > uint64(len(r.data))*8 {
+		return errUnexpectedEOF
+	}
+	r.pos += bits
+
+	return nil
+}
+
+// readAbbrev reads the body of a DEFINE_ABBREV entry.
+func (r *bitReader) readAbbrev() (abbrev, error) {
+	n, err := r.readVBR(5)
+	if err != nil {
+		return nil, err
+	}
+
+	var a abbrev
+	for i := uint64(0); i < n; i++ {
+		isLiteral, err := r.read(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if isLiteral == 1 {
+			v, err := r.readVBR(8)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, literal(v))
+
+			continue
+		}
+
+		enc, err := r.read(3)
+		if err != nil {
+			return nil, err
+		}
+
+		op := abbrevOp{kind: opKind(enc)}
+		switch op.kind {
+		case opFixed, opVBR:
+			if op.value, err = r.readVBR(5); err != nil {
+				return nil, err
+			}
+		case opArray, opChar6, opBlob:
+		default:
+			return nil, fmt.Errorf("invalid abbreviation operand encoding %d", enc)
+		}
+
+		a = append(a, op)
+	}
+
+	return a, nil
+}
+
+func (r *bitReader) readScalar(op abbrevOp) (uint64, error) {
+	switch op.kind {
+	case opLiteral:
+		return op.value, nil
+	case opFixed:
+		return r.read(uint(op.value))
+	case opVBR:
+		return r.readVBR(uint(op.value))
+	case opChar6:
+		v, err := r.read(6)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(char6Alphabet[v]), nil
+	}
+
+	return 0, fmt.Errorf("invalid scalar operand encoding %d", op.kind)
+}
+
+// readRecord reads a record with the given abbreviation and returns its code, its operands
+// and its blob, if any.
+func (r *bitReader) readRecord(a abbrev) (code uint64, vals []uint64, blobData []byte, err error) {
+	var fields []uint64
+
+	for i := 0; i < len(a); i++ {
+		op := a[i]
+
+		switch op.kind {
+		case opArray:
+			if i != len(a)-2 {
+				return 0, nil, nil, errors.New("array operand is not the second to last operand")
+			}
+
+			n, err := r.readVBR(6)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+
+			for j := uint64(0); j < n; j++ {
+				v, err := r.readScalar(a[i+1])
+				if err != nil {
+					return 0, nil, nil, err
+				}
+				fields = append(fields, v)
+			}
+
+			i++
+		case opBlob:
+			if i != len(a)-1 {
+				return 0, nil, nil, errors.New("blob operand is not the last operand")
+			}
+
+			n, err := r.readVBR(6)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+
+			r.align32()
+			start := r.pos / 8
+			if err := r.skip(n * 8); err != nil {
+				return 0, nil, nil, err
+			}
+			blobData = r.data[start : start+n]
+			r.align32()
+		default:
+			v, err := r.readScalar(op)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			fields = append(fields, v)
+		}
+	}
+
+	if len(fields) == 0 {
+		return 0, nil, nil, errors.New("record without code")
+	}
+
+	return fields[0], fields[1:], blobData, nil
+}
+
+// readUnabbrevRecord reads the body of an UNABBREV_RECORD entry.
+func (r *bitReader) readUnabbrevRecord() (code uint64, vals []uint64, err error) {
+	if code, err = r.readVBR(6); err != nil {
+		return 0, nil, err
+	}
+
+	n, err := r.readVBR(6)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	vals = make([]uint64, n)
+	for i := range vals {
+		if vals[i], err = r.readVBR(6); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return code, vals, nil
+}
+
+// bitWriter writes a bitstream whose bits are packed least significant bit first.
+type bitWriter struct {
+	buf   []byte
+	cur   uint64
+	nbits uint
+
+	width  uint
+	blocks []openBlock
+	// err is the first record which could not be written.
+	err error
+}
+
+type openBlock struct {
+	width  uint
+	lenPos int
+}
+
+func (w *bitWriter) emit(v uint64, width uint) {
+	for width > 0 {
+		n := 64 - w.nbits
+		if n > width {
+			n = width
+		}
+
+		w.cur |= (v & (1<<n - 1)) << w.nbits
+		w.nbits += n
+		v >>= n
+		width -= n
+
+		for w.nbits >= 8 {
+			w.buf = append(w.buf, byte(w.cur))
+			w.cur >>= 8
+			w.nbits -= 8
+		}
+	}
+}
+
+func (w *bitWriter) emitVBR(v uint64, width uint) {
+	hi := uint64(1) << (width - 1)
+
+	for v >= hi {
+		w.emit(v&(hi-1)|hi, width)
+		v >>= width - 1
+	}
+
+	w.emit(v, width)
+}
+
+func (w *bitWriter) align32() {
+	if pad := (32 - (uint(len(w.buf))*8+w.nbits)%32) % 32; pad > 0 {
+		w.emit(0, pad)
+	}
+}
+
+func (w *bitWriter) enterBlock(id uint64, width uint) {
+	w.emit(abbrevEnterSubblock, w.width)
+	w.emitVBR(id, 8)
+	w.emitVBR(uint64(width), 4)
+	w.align32()
+
+	w.blocks = append(w.blocks, openBlock{width: w.width, lenPos: len(w.buf)})
+	w.emit(0, 32)
+	w.width = width
+}
+
+func (w *bitWriter) exitBlock() {
+	w.emit(abbrevEndBlock, w.width)
+	w.align32()
+
+	b := w.blocks[len(w.blocks)-1]
+	w.blocks = w.blocks[:len(w.blocks)-1]
+
+	binary.LittleEndian.PutUint32(w.buf[b.lenPos:], uint32((len(w.buf)-b.lenPos-4)/4))
+	w.width = b.width
+}
+
+func (w *bitWriter) defineAbbrev(a abbrev) {
+	w.emit(abbrevDefine, w.width)
+	w.emitVBR(uint64(len(a)), 5)
+
+	for _, op := range a {
+		if op.kind == opLiteral {
+			w.emit(1, 1)
+			w.emitVBR(op.value, 8)
+
+			continue
+		}
+
+		w.emit(0, 1)
+		w.emit(uint64(op.kind), 3)
+		if op.kind == opFixed || op.kind == opVBR {
+			w.emitVBR(op.value, 5)
+		}
+	}
+}
+
+func (w *bitWriter) unabbrevRecord(code uint64, vals ...uint64) {
+	w.emit(abbrevUnabbrevRec, w.width)
+	w.emitVBR(code, 6)
+	w.emitVBR(uint64(len(vals)), 6)
+
+	for _, v := range vals {
+		w.emitVBR(v, 6)
+	}
+}
+
+// record writes a record with the abbreviation a, which has the ID id. vals holds the values of
+// all scalar operands, including the record code.
+//
+// Values which do not fit into their fixed width field are not truncated. The record is
+// dropped and the error is kept in w.err instead, as truncated values would corrupt the
+// stream.
+func (w *bitWriter) record(id uint64, a abbrev, vals []uint64, blobData []byte) {
+	if w.err != nil {
+		return
+	}
+	if err := fits(a, vals); err != nil {
+		w.err = err
+
+		return
+	}
+
+	w.emit(id, w.width)
+
+	for _, op := range a {
+		switch op.kind {
+		case opLiteral:
+			vals = vals[1:]
+		case opFixed:
+			w.emit(vals[0], uint(op.value))
+			vals = vals[1:]
+		case opVBR:
+			w.emitVBR(vals[0], uint(op.value))
+			vals = vals[1:]
+		case opBlob:
+			w.emitVBR(uint64(len(blobData)), 6)
+			w.align32()
+			for _, b := range blobData {
+				w.emit(uint64(b), 8)
+			}
+			w.align32()
+		}
+	}
+}
+
+// fits reports an error if a value of vals does not fit into the fixed width field of a.
+func fits(a abbrev, vals []uint64) error {
+	code := vals[0]
+	for _, op := range a {
+		if op.kind == opBlob {
+			continue
+		}
+		if op.kind == opFixed && op.value < 64 && vals[0] >= 1<<op.value {
+			return fmt.Errorf("value %d of record %d does not fit into %d bits", vals[0], code, op.value)
+		}
+		vals = vals[1:]
+	}
+
+	return nil
+}
+
+func (w *bitWriter) bytes() []byte {
+	buf := w.buf
+	if w.nbits > 0 {
+		buf = append(buf, byte(w.cur))
+	}
+
+	return buf
+}
@@ -0,0 +1,329 @@
+package serialdiag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// decoder holds the state while a serialized diagnostics bitstream is read.
+type decoder struct {
+	r bitReader
+
+	// blockInfo holds the abbreviations defined in the BLOCKINFO block per block ID.
+	blockInfo map[uint64][]abbrev
+
+	set        *Set
+	files      map[uint64]string
+	categories map[uint64]string
+	flags      map[uint64]string
+}
+
+// Decode decodes a serialized diagnostics bitstream.
+func Decode(data []byte) (*Set, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return nil, errors.New("not a serialized diagnostics file")
+	}
+
+	d := &decoder{
+		r:          bitReader{data: data, pos: uint64(len(magic)) * 8},
+		blockInfo:  map[uint64][]abbrev{},
+		set:        &Set{Files: map[string]FileInfo{}},
+		files:      map[uint64]string{},
+		categories: map[uint64]string{},
+		flags:      map[uint64]string{},
+	}
+
+	for !d.r.atEnd() {
+		// trailing padding which is too short to hold another entry
+		if uint64(len(data))*8-d.r.pos < 32 {
+			break
+		}
+
+		id, err := d.r.read(2)
+		if err != nil {
+			return nil, err
+		}
+		if id != abbrevEnterSubblock {
+			return nil, fmt.Errorf("unexpected top-level abbreviation %d", id)
+		}
+
+		if err := d.readSubblock(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.set, nil
+}
+
+// readSubblock reads an ENTER_SUBBLOCK entry and the block it introduces. parent is the
+// diagnostic of the enclosing diagnostic block, if any.
+func (d *decoder) readSubblock(parent *Diagnostic) error {
+	blockID, err := d.r.readVBR(8)
+	if err != nil {
+		return err
+	}
+
+	width, err := d.r.readVBR(4)
+	if err != nil {
+		return err
+	}
+
+	d.r.align32()
+
+	words, err := d.r.read(32)
+	if err != nil {
+		return err
+	}
+
+	switch blockID {
+	case blockInfoBlockID:
+		return d.readBlockInfo(uint(width))
+	case blockMeta, blockDiag:
+		return d.readBlock(blockID, uint(width), parent)
+	}
+
+	// unknown blocks are skipped
+	return d.r.skip(words * 32)
+}
+
+func (d *decoder) readBlockInfo(width uint) error {
+	var cur *uint64
+
+	for {
+		id, err := d.r.read(width)
+		if err != nil {
+			return err
+		}
+
+		switch id {
+		case abbrevEndBlock:
+			d.r.align32()
+
+			return nil
+		case abbrevEnterSubblock:
+			if err := d.readSubblock(nil); err != nil {
+				return err
+			}
+		case abbrevDefine:
+			a, err := d.r.readAbbrev()
+			if err != nil {
+				return err
+			}
+			if cur == nil {
+				return errors.New("abbreviation in BLOCKINFO block before SETBID")
+			}
+
+			d.blockInfo[*cur] = append(d.blockInfo[*cur], a)
+		case abbrevUnabbrevRec:
+			code, vals, err := d.r.readUnabbrevRecord()
+			if err != nil {
+				return err
+			}
+
+			if code == blockInfoSetBID {
+				if len(vals) < 1 {
+					return errors.New("invalid SETBID record")
+				}
+
+				bid := vals[0]
+				cur = &bid
+			}
+		default:
+			return fmt.Errorf("unexpected abbreviation %d in BLOCKINFO block", id)
+		}
+	}
+}
+
+func (d *decoder) readBlock(blockID uint64, width uint, parent *Diagnostic) error {
+	var local []abbrev
+
+	// diag is the diagnostic of this block, it is set by its RECORD_DIAG record.
+	var diag *Diagnostic
+
+	for {
+		id, err := d.r.read(width)
+		if err != nil {
+			return err
+		}
+
+		var (
+			code     uint64
+			vals     []uint64
+			blobData []byte
+		)
+
+		switch id {
+		case abbrevEndBlock:
+			d.r.align32()
+
+			return nil
+		case abbrevEnterSubblock:
+			if err := d.readSubblock(diag); err != nil {
+				return err
+			}
+
+			continue
+		case abbrevDefine:
+			a, err := d.r.readAbbrev()
+			if err != nil {
+				return err
+			}
+			local = append(local, a)
+
+			continue
+		case abbrevUnabbrevRec:
+			if code, vals, err = d.r.readUnabbrevRecord(); err != nil {
+				return err
+			}
+		default:
+			abbrevs := append(append([]abbrev(nil), d.blockInfo[blockID]...), local...)
+
+			i := id - abbrevFirstUser
+			if i >= uint64(len(abbrevs)) {
+				return fmt.Errorf("undefined abbreviation %d in block %d", id, blockID)
+			}
+
+			if code, vals, blobData, err = d.r.readRecord(abbrevs[i]); err != nil {
+				return err
+			}
+		}
+
+		if blockID == blockMeta {
+			if code == recordVersion && len(vals) > 0 {
+				d.set.Version = uint32(vals[0])
+			}
+
+			continue
+		}
+
+		if code == recordDiag {
+			diag = &Diagnostic{}
+			if parent != nil {
+				parent.Notes = append(parent.Notes, diag)
+			} else {
+				d.set.Diagnostics = append(d.set.Diagnostics, diag)
+			}
+		}
+
+		if err := d.record(diag, code, vals, blobData); err != nil {
+			return err
+		}
+	}
+}
+
+// text returns the text of a record, which is either stored in the blob or, for unabbreviated
+// records, as one character per operand after the n fixed operands.
+func text(vals []uint64, n int, blobData []byte) string {
+	if blobData != nil || len(vals) <= n {
+		return string(blobData)
+	}
+
+	b := make([]byte, len(vals)-n)
+	for i, v := range vals[n:] {
+		b[i] = byte(v)
+	}
+
+	return string(b)
+}
+
+// record handles a record of a diagnostic block.
+func (d *decoder) record(diag *Diagnostic, code uint64, vals []uint64, blobData []byte) error {
+	switch code {
+	case recordFilename:
+		if len(vals) < 4 {
+			return errors.New("invalid filename record")
+		}
+
+		name := text(vals, 4, blobData)
+		d.files[vals[0]] = name
+		d.set.Files[name] = FileInfo{Size: uint32(vals[1]), ModTime: uint32(vals[2])}
+	case recordCategory:
+		if len(vals) < 2 {
+			return errors.New("invalid category record")
+		}
+
+		d.categories[vals[0]] = text(vals, 2, blobData)
+	case recordDiagFlag:
+		if len(vals) < 2 {
+			return errors.New("invalid diagnostic flag record")
+		}
+
+		d.flags[vals[0]] = text(vals, 2, blobData)
+	case recordDiag:
+		if len(vals) < 8 {
+			return errors.New("invalid diagnostic record")
+		}
+
+		loc, err := d.location(vals[1:5])
+		if err != nil {
+			return err
+		}
+
+		diag.Level = Level(vals[0])
+		diag.Location = loc
+		diag.CategoryID = uint32(vals[5])
+		diag.Category = d.categories[vals[5]]
+		diag.Flag = d.flags[vals[6]]
+		diag.Message = text(vals, 8, blobData)
+	case recordSourceRange:
+		if diag == nil || len(vals) < 8 {
+			return errors.New("invalid source range record")
+		}
+
+		r, err := d.sourceRange(vals)
+		if err != nil {
+			return err
+		}
+
+		diag.Ranges = append(diag.Ranges, r)
+	case recordFixIt:
+		if diag == nil || len(vals) < 9 {
+			return errors.New("invalid fix-it record")
+		}
+
+		r, err := d.sourceRange(vals)
+		if err != nil {
+			return err
+		}
+
+		diag.FixIts = append(diag.FixIts, FixIt{Range: r, Text: text(vals, 9, blobData)})
+	}
+
+	return nil
+}
+
+// location decodes the four operands of a source location. Files are always defined before
+// they are referenced.
+func (d *decoder) location(vals []uint64) (Location, error) {
+	if vals[0] == 0 {
+		return Location{}, nil
+	}
+
+	name, ok := d.files[vals[0]]
+	if !ok {
+		return Location{}, fmt.Errorf("undefined file ID %d", vals[0])
+	}
+
+	return Location{
+		File:   name,
+		Line:   uint32(vals[1]),
+		Column: uint32(vals[2]),
+		Offset: uint32(vals[3]),
+	}, nil
+}
+
+// sourceRange decodes the eight operands of a source range.
+func (d *decoder) sourceRange(vals []uint64) (Range, error) {
+	start, err := d.location(vals[0:4])
+	if err != nil {
+		return Range{}, err
+	}
+
+	end, err := d.location(vals[4:8])
+	if err != nil {
+		return Range{}, err
+	}
+
+	return Range{Start: start, End: end}, nil
+}
@@ -0,0 +1,244 @@
+package serialdiag
+
+import (
+	"io"
+)
+
+// Abbreviations of the diagnostic block, in the order clang defines them in the BLOCKINFO block.
+var (
+	versionAbbrev = abbrev{literal(recordVersion), fixed(32)}
+
+	diagAbbrev = abbrev{
+		literal(recordDiag),
+		fixed(3),                                   // level
+		fixed(10), fixed(32), fixed(32), fixed(32), // location
+		fixed(10), // category
+		fixed(10), // flag
+		fixed(16), // message size
+		blob(),
+	}
+	categoryAbbrev = abbrev{literal(recordCategory), fixed(16), fixed(8), blob()}
+	rangeAbbrev    = abbrev{
+		literal(recordSourceRange),
+		fixed(10), fixed(32), fixed(32), fixed(32),
+		fixed(10), fixed(32), fixed(32), fixed(32),
+	}
+	flagAbbrev     = abbrev{literal(recordDiagFlag), fixed(10), fixed(16), blob()}
+	filenameAbbrev = abbrev{literal(recordFilename), fixed(10), fixed(32), fixed(32), fixed(16), blob()}
+	fixItAbbrev    = abbrev{
+		literal(recordFixIt),
+		fixed(10), fixed(32), fixed(32), fixed(32),
+		fixed(10), fixed(32), fixed(32), fixed(32),
+		fixed(16),
+		blob(),
+	}
+)
+
+// Abbreviation IDs of the records in the meta and diagnostic blocks.
+const (
+	versionAbbrevID = abbrevFirstUser
+
+	diagAbbrevID     = abbrevFirstUser
+	categoryAbbrevID = abbrevFirstUser + 1
+	rangeAbbrevID    = abbrevFirstUser + 2
+	flagAbbrevID     = abbrevFirstUser + 3
+	filenameAbbrevID = abbrevFirstUser + 4
+	fixItAbbrevID    = abbrevFirstUser + 5
+)
+
+var recordNames = []struct {
+	id   uint64
+	name string
+}{
+	{recordDiag, "DiagInfo"},
+	{recordSourceRange, "SrcRange"},
+	{recordCategory, "CatName"},
+	{recordDiagFlag, "DiagFlag"},
+	{recordFilename, "FileName"},
+	{recordFixIt, "FixIt"},
+}
+
+// encoder holds the state while a set is written.
+type encoder struct {
+	w bitWriter
+
+	files      map[string]uint64
+	categories map[uint32]bool
+	// catNames assigns IDs to categories which only have a name.
+	catNames map[string]uint32
+	nextCat  uint32
+	flags    map[string]uint64
+	set      *Set
+}
+
+// Encode writes the set in the serialized diagnostics format to w. The layout of the stream
+// matches the one written by clang, so that it can be read by libclang's LoadDiagnostics. Like
+// clang, it limits the IDs of files, categories and warning flags to 1023, category names to
+// 255 bytes and all other texts to 65535 bytes. Nothing is written if the set exceeds them.
+func Encode(w io.Writer, s *Set) error {
+	e := &encoder{
+		w:          bitWriter{width: 2},
+		files:      map[string]uint64{},
+		categories: map[uint32]bool{},
+		catNames:   map[string]uint32{},
+		flags:      map[string]uint64{},
+		set:        s,
+	}
+
+	for _, b := range magic {
+		e.w.emit(uint64(b), 8)
+	}
+
+	e.blockInfo()
+
+	e.w.enterBlock(blockMeta, 3)
+	e.w.record(versionAbbrevID, versionAbbrev, []uint64{recordVersion, Version}, nil)
+	e.w.exitBlock()
+
+	e.nextCat = maxCategory(s.Diagnostics) + 1
+	for _, d := range s.Diagnostics {
+		e.diagnostic(d)
+	}
+
+	if e.w.err != nil {
+		return e.w.err
+	}
+
+	_, err := w.Write(e.w.bytes())
+
+	return err
+}
+
+func maxCategory(diags []*Diagnostic) uint32 {
+	var max uint32
+	for _, d := range diags {
+		if d.CategoryID > max {
+			max = d.CategoryID
+		}
+		if m := maxCategory(d.Notes); m > max {
+			max = m
+		}
+	}
+
+	return max
+}
+
+func (e *encoder) blockInfo() {
+	e.w.enterBlock(blockInfoBlockID, 2)
+
+	e.w.unabbrevRecord(blockInfoSetBID, blockMeta)
+	e.w.unabbrevRecord(blockInfoBlockName, chars("Meta")...)
+	e.w.unabbrevRecord(blockInfoSetRecordName, append([]uint64{recordVersion}, chars("Version")...)...)
+	e.w.defineAbbrev(versionAbbrev)
+
+	e.w.unabbrevRecord(blockInfoSetBID, blockDiag)
+	e.w.unabbrevRecord(blockInfoBlockName, chars("Diag")...)
+	for _, r := range recordNames {
+		e.w.unabbrevRecord(blockInfoSetRecordName, append([]uint64{r.id}, chars(r.name)...)...)
+	}
+	for _, a := range []abbrev{diagAbbrev, categoryAbbrev, rangeAbbrev, flagAbbrev, filenameAbbrev, fixItAbbrev} {
+		e.w.defineAbbrev(a)
+	}
+
+	e.w.exitBlock()
+}
+
+func chars(s string) []uint64 {
+	v := make([]uint64, len(s))
+	for i := range s {
+		v[i] = uint64(s[i])
+	}
+
+	return v
+}
+
+// diagnostic writes the block of d including the nested blocks of its notes.
+func (e *encoder) diagnostic(d *Diagnostic) {
+	e.w.enterBlock(blockDiag, 4)
+
+	loc := e.location(d.Location)
+	category := e.category(d)
+	flag := e.flag(d.Flag)
+
+	vals := append([]uint64{recordDiag, uint64(d.Level)}, loc...)
+	vals = append(vals, category, flag, uint64(len(d.Message)))
+	e.w.record(diagAbbrevID, diagAbbrev, vals, []byte(d.Message))
+
+	for _, r := range d.Ranges {
+		e.w.record(rangeAbbrevID, rangeAbbrev, append([]uint64{recordSourceRange}, e.sourceRange(r)...), nil)
+	}
+
+	for _, f := range d.FixIts {
+		vals := append([]uint64{recordFixIt}, e.sourceRange(f.Range)...)
+		vals = append(vals, uint64(len(f.Text)))
+		e.w.record(fixItAbbrevID, fixItAbbrev, vals, []byte(f.Text))
+	}
+
+	for _, n := range d.Notes {
+		e.diagnostic(n)
+	}
+
+	e.w.exitBlock()
+}
+
+// location returns the operands of l, emitting the record of its file on first use.
+func (e *encoder) location(l Location) []uint64 {
+	if !l.IsValid() {
+		return []uint64{0, 0, 0, 0}
+	}
+
+	id, ok := e.files[l.File]
+	if !ok {
+		id = uint64(len(e.files) + 1)
+		e.files[l.File] = id
+
+		fi := e.set.Files[l.File]
+		vals := []uint64{recordFilename, id, uint64(fi.Size), uint64(fi.ModTime), uint64(len(l.File))}
+		e.w.record(filenameAbbrevID, filenameAbbrev, vals, []byte(l.File))
+	}
+
+	return []uint64{id, uint64(l.Line), uint64(l.Column), uint64(l.Offset)}
+}
+
+func (e *encoder) sourceRange(r Range) []uint64 {
+	return append(e.location(r.Start), e.location(r.End)...)
+}
+
+// category returns the category ID of d, emitting the category record on first use.
+func (e *encoder) category(d *Diagnostic) uint64 {
+	if d.CategoryID == 0 && d.Category == "" {
+		return 0
+	}
+
+	id := d.CategoryID
+	if id == 0 {
+		if id = e.catNames[d.Category]; id == 0 {
+			id = e.nextCat
+			e.nextCat++
+			e.catNames[d.Category] = id
+		}
+	}
+
+	if !e.categories[id] {
+		e.categories[id] = true
+		e.w.record(categoryAbbrevID, categoryAbbrev, []uint64{recordCategory, uint64(id), uint64(len(d.Category))}, []byte(d.Category))
+	}
+
+	return uint64(id)
+}
+
+// flag returns the ID of the warning flag, emitting the flag record on first use.
+func (e *encoder) flag(name string) uint64 {
+	if name == "" {
+		return 0
+	}
+
+	id, ok := e.flags[name]
+	if !ok {
+		id = uint64(len(e.flags) + 1)
+		e.flags[name] = id
+		e.w.record(flagAbbrevID, flagAbbrev, []uint64{recordDiagFlag, id, uint64(len(name))}, []byte(name))
+	}
+
+	return id
+}
@@ -0,0 +1,198 @@
+// Package serialdiag reads and writes clang serialized diagnostics files, as they are written
+// by "clang --serialize-diagnostics", without loading libclang.
+//
+// Serialized diagnostics are stored in the LLVM bitstream container format. A file starts with
+// the magic "DIAG", followed by a BLOCKINFO block defining the abbreviations, a meta block with
+// the format version and one block per diagnostic. Notes are stored as nested blocks of the
+// diagnostic they belong to.
+package serialdiag
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// Version is the version of the serialized diagnostics format written by Encode.
+const Version = 2
+
+// Block and record IDs of the serialized diagnostics format.
+const (
+	blockMeta = 8
+	blockDiag = 9
+
+	recordVersion     = 1
+	recordDiag        = 2
+	recordSourceRange = 3
+	recordDiagFlag    = 4
+	recordCategory    = 5
+	recordFilename    = 6
+	recordFixIt       = 7
+)
+
+var magic = []byte("DIAG")
+
+// Level is the severity of a diagnostic as it is stored in serialized diagnostics.
+type Level uint32
+
+const (
+	Level_Ignored Level = iota
+	Level_Note
+	Level_Warning
+	Level_Error
+	Level_Fatal
+	Level_Remark
+)
+
+func (l Level) Spelling() string {
+	switch l {
+	case Level_Ignored:
+		return "ignored"
+	case Level_Note:
+		return "note"
+	case Level_Warning:
+		return "warning"
+	case Level_Error:
+		return "error"
+	case Level_Fatal:
+		return "fatal error"
+	case Level_Remark:
+		return "remark"
+	}
+
+	return fmt.Sprintf("Level unknown %d", int(l))
+}
+
+func (l Level) String() string {
+	return l.Spelling()
+}
+
+// Location is a position in a source file. Line and Column are 1-based, Offset is the byte
+// offset from the start of the file. The zero Location is an invalid location.
+type Location struct {
+	File   string
+	Line   uint32
+	Column uint32
+	Offset uint32
+}
+
+// IsValid reports whether the location refers to a file.
+func (l Location) IsValid() bool {
+	return l.File != ""
+}
+
+func (l Location) String() string {
+	if !l.IsValid() {
+		return "<invalid>"
+	}
+
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// Range is a range of source code.
+type Range struct {
+	Start Location
+	End   Location
+}
+
+// FixIt is a replacement of a source range which fixes a diagnostic.
+type FixIt struct {
+	Range Range
+	Text  string
+}
+
+// FileInfo holds the metadata of a source file which is stored alongside its name.
+type FileInfo struct {
+	Size uint32
+	// ModTime is the modification time in seconds since the Unix epoch.
+	ModTime uint32
+}
+
+// Diagnostic is a single serialized diagnostic.
+type Diagnostic struct {
+	Level    Level
+	Location Location
+	// CategoryID is the clang diagnostic category, 0 means no category.
+	CategoryID uint32
+	// Category is the name of the category, e.g. "Semantic Issue".
+	Category string
+	// Flag is the name of the warning option without the "-W" prefix, e.g. "unused-variable".
+	Flag    string
+	Message string
+	Ranges  []Range
+	FixIts  []FixIt
+	// Notes are the child diagnostics attached to this diagnostic.
+	Notes []*Diagnostic
+}
+
+// Option returns the warning option of the diagnostic, e.g. "-Wunused-variable", or an empty
+// string if the diagnostic has no option.
+func (d *Diagnostic) Option() string {
+	if d.Flag == "" {
+		return ""
+	}
+
+	return "-W" + d.Flag
+}
+
+func (d *Diagnostic) String() string {
+	s := fmt.Sprintf("%s: %s: %s", d.Location, d.Level, d.Message)
+	if d.Flag != "" {
+		s += " [" + d.Option() + "]"
+	}
+
+	return s
+}
+
+// Set is the content of a serialized diagnostics file.
+type Set struct {
+	// Version is the format version the set was read with.
+	Version uint32
+	// Files holds the metadata of the files referenced by the diagnostics.
+	Files map[string]FileInfo
+	// Diagnostics are the top-level diagnostics in the order they were emitted.
+	Diagnostics []*Diagnostic
+}
+
+// ReadFile reads the serialized diagnostics file filename.
+func ReadFile(filename string) (*Set, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return s, nil
+}
+
+// WriteFile writes the set as serialized diagnostics file filename.
+func WriteFile(filename string, s *Set) error {
+	var buf bytes.Buffer
+	if err := Encode(&buf, s); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, buf.Bytes(), 0o666)
+}
+
+// Merge returns a set containing the diagnostics of all given sets, e.g. of several compiler
+// invocations. Diagnostics are not copied, the merged set shares them with the given sets.
+func Merge(sets ...*Set) *Set {
+	m := &Set{
+		Version: Version,
+		Files:   map[string]FileInfo{},
+	}
+
+	for _, s := range sets {
+		for name, fi := range s.Files {
+			m.Files[name] = fi
+		}
+		m.Diagnostics = append(m.Diagnostics, s.Diagnostics...)
+	}
+
+	return m
+}
@@ -0,0 +1,256 @@
+package serialdiag
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSet() *Set {
+	loc := func(line, column uint32) Location {
+		return Location{File: "/src/main.c", Line: line, Column: column, Offset: line*10 + column}
+	}
+
+	return &Set{
+		Version: Version,
+		Files: map[string]FileInfo{
+			"/src/main.c": {Size: 120, ModTime: 1700000000},
+			"/src/util.h": {},
+		},
+		Diagnostics: []*Diagnostic{
+			{
+				Level:      Level_Warning,
+				Location:   loc(3, 7),
+				CategoryID: 2,
+				Category:   "Semantic Issue",
+				Flag:       "unused-variable",
+				Message:    "unused variable 'x'",
+				Ranges:     []Range{{Start: loc(3, 7), End: loc(3, 8)}},
+			},
+			{
+				Level:      Level_Error,
+				Location:   loc(5, 1),
+				CategoryID: 4,
+				Category:   "Parse Issue",
+				Message:    "expected ';' after expression",
+				FixIts:     []FixIt{{Range: Range{Start: loc(5, 1), End: loc(5, 1)}, Text: ";"}},
+				Notes: []*Diagnostic{
+					{
+						Level:    Level_Note,
+						Location: Location{File: "/src/util.h", Line: 1, Column: 9, Offset: 8},
+						Message:  "expanded from macro 'CALL'",
+					},
+				},
+			},
+			{
+				Level:   Level_Fatal,
+				Message: "too many errors emitted, stopping now",
+			},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := testSet()
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("DIAG")) {
+		t.Fatalf("expected DIAG magic. got=%q", buf.Bytes()[:4])
+	}
+	if buf.Len()%4 != 0 {
+		t.Errorf("expected stream to be 32 bit aligned. got=%d bytes", buf.Len())
+	}
+
+	got, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v. got=%+v", want, got)
+	}
+}
+
+func TestLongText(t *testing.T) {
+	want := testSet()
+	want.Diagnostics[0].Message = strings.Repeat("x", 1<<16-1)
+	want.Diagnostics[1].FixIts[0].Text = strings.Repeat(";", 1<<16-1)
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("expected texts of 65535 bytes to round-trip")
+	}
+
+	tooLong := []func(s *Set){
+		func(s *Set) { s.Diagnostics[0].Message = strings.Repeat("x", 1<<16) },
+		func(s *Set) { s.Diagnostics[1].Category = strings.Repeat("c", 256) },
+		func(s *Set) { s.Diagnostics[0].CategoryID = 1024 },
+	}
+	for i, change := range tooLong {
+		s := testSet()
+		change(s)
+
+		buf.Reset()
+		if err := Encode(&buf, s); err == nil {
+			t.Errorf("%d: expected an error for a value exceeding its field", i)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%d: expected nothing to be written. got=%d bytes", i, buf.Len())
+		}
+	}
+}
+
+func TestReadWriteFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "main.dia")
+
+	a := testSet()
+	b := &Set{
+		Version: Version,
+		Files:   map[string]FileInfo{"/src/other.c": {Size: 1}},
+		Diagnostics: []*Diagnostic{
+			{Level: Level_Remark, Location: Location{File: "/src/other.c", Line: 1, Column: 1}, Category: "Custom", Message: "remark"},
+		},
+	}
+
+	if err := WriteFile(filename, Merge(a, b)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Diagnostics) != 4 || len(got.Files) != 3 {
+		t.Fatalf("expected 4 diagnostics in 3 files. got=%d in %d", len(got.Diagnostics), len(got.Files))
+	}
+
+	r := got.Diagnostics[3]
+	if r.Category != "Custom" || r.CategoryID != 5 {
+		t.Errorf("expected category Custom to be assigned the ID 5. got=%q %d", r.Category, r.CategoryID)
+	}
+	if r.Location.File != "/src/other.c" {
+		t.Errorf("expected file /src/other.c. got=%q", r.Location.File)
+	}
+}
+
+// TestDecodeClang decodes testdata/warnings.dia, the serialized diagnostics of
+// testdata/warnings.c in the layout clang writes them: the BLOCKINFO block repeats SETBID before
+// the first abbreviation of a block, and files are stored without size and modification time.
+func TestDecodeClang(t *testing.T) {
+	got, err := ReadFile("testdata/warnings.dia")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := func(line, column, offset uint32) Location {
+		return Location{File: "warnings.c", Line: line, Column: column, Offset: offset}
+	}
+	want := &Set{
+		Version: 2,
+		Files:   map[string]FileInfo{"warnings.c": {}},
+		Diagnostics: []*Diagnostic{
+			{
+				Level:      Level_Error,
+				Location:   loc(4, 5, 53),
+				CategoryID: 2,
+				Category:   "Semantic Issue",
+				Message:    "redefinition of 'twice'",
+				Notes: []*Diagnostic{
+					{
+						Level:      Level_Note,
+						Location:   loc(3, 5, 18),
+						CategoryID: 2,
+						Category:   "Semantic Issue",
+						Message:    "previous definition is here",
+					},
+				},
+			},
+			{
+				Level:      Level_Error,
+				Location:   loc(8, 20, 135),
+				CategoryID: 4,
+				Category:   "Parse Issue",
+				Message:    "expected ';' after return statement",
+				FixIts:     []FixIt{{Range: Range{Start: loc(8, 20, 135), End: loc(8, 20, 135)}, Text: ";"}},
+			},
+			{
+				Level:      Level_Warning,
+				Location:   loc(7, 7, 108),
+				CategoryID: 2,
+				Category:   "Semantic Issue",
+				Flag:       "unused-variable",
+				Message:    "unused variable 'unused'",
+				Ranges:     []Range{{Start: loc(7, 3, 104), End: loc(7, 13, 114)}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v. got=%+v", want, got)
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(again, got) {
+		t.Errorf("expected re-encoded set %+v. got=%+v", got, again)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	if _, err := Decode([]byte("BC\xc0\xde")); err == nil {
+		t.Error("expected error for wrong magic")
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, testSet()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Decode(buf.Bytes()[:buf.Len()-12]); err == nil {
+		t.Error("expected error for truncated stream")
+	}
+}
+
+func TestBitstream(t *testing.T) {
+	var w bitWriter
+	w.emit(5, 3)
+	w.emitVBR(1000, 6)
+	w.emit(0xdeadbeef, 32)
+	w.emitVBR(1<<40, 8)
+	w.align32()
+
+	r := bitReader{data: w.bytes()}
+	if v, _ := r.read(3); v != 5 {
+		t.Errorf("expected 5. got=%d", v)
+	}
+	if v, _ := r.readVBR(6); v != 1000 {
+		t.Errorf("expected 1000. got=%d", v)
+	}
+	if v, _ := r.read(32); v != 0xdeadbeef {
+		t.Errorf("expected 0xdeadbeef. got=%#x", v)
+	}
+	if v, _ := r.readVBR(8); v != 1<<40 {
+		t.Errorf("expected 1<<40. got=%d", v)
+	}
+}
@@ -0,0 +1,9 @@
+int g(void);
+
+int twice(int x) { return x * 2; }
+int twice(int x) { return x + x; }
+
+int main(void) {
+  int unused;
+  return twice(g())
+}
@@ -0,0 +1,160 @@
+package symbols
+
+import (
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Extractor extracts the symbols of translation units.
+type Extractor struct {
+	// SkipSystemHeaders skips the declarations in system headers.
+	SkipSystemHeaders bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Extract returns the symbols declared by the translation unit. Parameters and variables
+// local to functions are left out.
+func (e Extractor) Extract(tu clang.TranslationUnit) []Symbol {
+	w := walker{
+		Extractor: e,
+		index:     map[string]int{},
+		paths:     compdb.Paths{Directory: e.Directory},
+	}
+	w.children(tu.TranslationUnitCursor())
+
+	return w.symbols
+}
+
+type walker struct {
+	Extractor
+
+	symbols []Symbol
+	// index maps USRs to their index in symbols.
+	index map[string]int
+	paths compdb.Paths
+}
+
+func (w *walker) children(parent clang.Cursor) {
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		if w.SkipSystemHeaders && c.Location().IsInSystemHeader() {
+			return clang.ChildVisit_Continue
+		}
+
+		kind := c.Kind()
+		if !indexed(kind) {
+			if kind == clang.Cursor_LinkageSpec || kind == clang.Cursor_UnexposedDecl {
+				w.children(c)
+			}
+
+			return clang.ChildVisit_Continue
+		}
+
+		w.add(c)
+		if isScope(kind) {
+			w.children(c)
+		}
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) add(c clang.Cursor) {
+	usr := c.USR()
+	name := c.Spelling()
+	if usr == "" || name == "" {
+		return
+	}
+
+	definition := c.IsCursorDefinition() || c.Kind() == clang.Cursor_MacroDefinition
+	if i, ok := w.index[usr]; ok {
+		if !definition || w.symbols[i].Definition {
+			return
+		}
+	}
+
+	s := Symbol{
+		USR:        usr,
+		Name:       name,
+		Container:  container(c),
+		Kind:       c.Kind(),
+		Signature:  signature(c),
+		Location:   w.paths.Position(c.Location().Position()),
+		Definition: definition,
+	}
+
+	if i, ok := w.index[usr]; ok {
+		w.symbols[i] = s
+
+		return
+	}
+	w.index[usr] = len(w.symbols)
+	w.symbols = append(w.symbols, s)
+}
+
+// container returns the qualified name of the scope of c. Enumerators of unscoped enumerations
+// are found in the scope enclosing the enumeration.
+func container(c clang.Cursor) string {
+	var names []string
+	for p := c.SemanticParent(); !p.IsNull() && p.Kind() != clang.Cursor_TranslationUnit; p = p.SemanticParent() {
+		if !isScope(p.Kind()) || p.Kind() == clang.Cursor_EnumDecl && !p.EnumDecl_IsScoped() {
+			continue
+		}
+
+		name := p.Spelling()
+		if name == "" || p.IsAnonymous() {
+			name = "(anonymous)"
+		}
+		names = append(names, name)
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return strings.Join(names, "::")
+}
+
+func isScope(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_UnionDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization, clang.Cursor_EnumDecl:
+		return true
+	}
+
+	return false
+}
+
+func signature(c clang.Cursor) string {
+	switch c.Kind() {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_FunctionTemplate,
+		clang.Cursor_ConversionFunction:
+		return c.ResultType().Spelling() + " " + c.DisplayName()
+	case clang.Cursor_VarDecl, clang.Cursor_FieldDecl, clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl:
+		t := c.Type().Spelling()
+		if c.Kind() == clang.Cursor_TypedefDecl || c.Kind() == clang.Cursor_TypeAliasDecl {
+			t = c.TypedefDeclUnderlyingType().Spelling()
+		}
+
+		return t + " " + c.Spelling()
+	}
+
+	return c.DisplayName()
+}
+
+// indexed reports whether declarations of kind are symbols.
+func indexed(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_StructDecl, clang.Cursor_ClassDecl, clang.Cursor_UnionDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization, clang.Cursor_EnumDecl,
+		clang.Cursor_EnumConstantDecl, clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_Constructor,
+		clang.Cursor_Destructor, clang.Cursor_ConversionFunction, clang.Cursor_FunctionTemplate,
+		clang.Cursor_FieldDecl, clang.Cursor_VarDecl, clang.Cursor_TypedefDecl, clang.Cursor_TypeAliasDecl,
+		clang.Cursor_MacroDefinition, clang.Cursor_NamespaceAlias, clang.Cursor_ConceptDecl:
+		return true
+	}
+
+	return false
+}
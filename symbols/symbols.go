@@ -0,0 +1,218 @@
+// Package symbols provides an in-memory index of the symbols declared by translation units
+// with fuzzy, qualified-name and kind-filtered search, as it is needed for jump-to-symbol.
+//
+// A query is a name, optionally qualified with the names of its containers, e.g.
+// "ns::Cls::meth". Every part is matched fuzzily, so "cmeth" finds "Cls::meth" and
+// "Cl::mth" finds it as well. Qualifiers may skip scopes, a query starting with "::" has to
+// match from the global scope.
+package symbols
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/completion"
+)
+
+// Symbol is a named declaration.
+type Symbol struct {
+	USR  string
+	Name string
+	// Container is the qualified name of the enclosing namespace or record, e.g. "ns::Cls". It
+	// is empty at global scope.
+	Container string
+	Kind      clang.CursorKind
+	// Signature is the type and display name of functions and variables, e.g.
+	// "int meth(int, char)", and the display name of other symbols.
+	Signature string
+	// Location is the position of the name in the definition, or in the first declaration if
+	// there is no definition.
+	Location   clang.Position
+	Definition bool
+}
+
+// Qualified returns the name qualified with its container, e.g. "ns::Cls::meth".
+func (s *Symbol) Qualified() string {
+	if s.Container == "" {
+		return s.Name
+	}
+
+	return s.Container + "::" + s.Name
+}
+
+// Index is an index of symbols identified by their USR.
+type Index struct {
+	symbols []*Symbol
+	byUSR   map[string]*Symbol
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{
+		byUSR: map[string]*Symbol{},
+	}
+}
+
+// Len returns the number of symbols in the index.
+func (ix *Index) Len() int {
+	return len(ix.symbols)
+}
+
+// Add adds symbols to the index. A symbol already in the index is replaced if the new one is
+// its definition.
+func (ix *Index) Add(symbols ...Symbol) {
+	for _, s := range symbols {
+		if old, ok := ix.byUSR[s.USR]; ok {
+			if s.Definition && !old.Definition {
+				*old = s
+			}
+
+			continue
+		}
+
+		cp := s
+		ix.symbols = append(ix.symbols, &cp)
+		ix.byUSR[s.USR] = &cp
+	}
+}
+
+// Lookup returns the symbol with the given USR or nil.
+func (ix *Index) Lookup(usr string) *Symbol {
+	return ix.byUSR[usr]
+}
+
+// Query is a search of the index.
+type Query struct {
+	// Pattern is the possibly qualified name, e.g. "ns::Cls::meth". The empty pattern matches
+	// every symbol.
+	Pattern string
+	// Kinds restricts the search to symbols of these kinds, if set.
+	Kinds []clang.CursorKind
+	// Limit is the maximal number of hits, if positive.
+	Limit int
+}
+
+// Hit is a symbol found by a search.
+type Hit struct {
+	*Symbol
+	// Score is the quality of the match in the range (0, 1].
+	Score float64
+}
+
+// Search returns the symbols matching the query, the best matches first.
+func (ix *Index) Search(q Query) []Hit {
+	pattern := strings.TrimSpace(q.Pattern)
+	anchored := strings.HasPrefix(pattern, "::")
+	parts := strings.Split(strings.TrimPrefix(pattern, "::"), "::")
+
+	name := completion.NewMatcher(parts[len(parts)-1])
+	flat := completion.NewMatcher(strings.Join(parts, ""))
+	qualifiers := make([]*completion.Matcher, len(parts)-1)
+	for i, p := range parts[:len(parts)-1] {
+		qualifiers[i] = completion.NewMatcher(p)
+	}
+
+	kinds := map[clang.CursorKind]bool{}
+	for _, k := range q.Kinds {
+		kinds[k] = true
+	}
+
+	var hits []Hit
+	for _, s := range ix.symbols {
+		if len(kinds) > 0 && !kinds[s.Kind] {
+			continue
+		}
+
+		score, ok := match(s, name, qualifiers, anchored)
+		if !ok && len(qualifiers) == 0 && !anchored {
+			// A pattern without qualifiers like "cmeth" may span the container.
+			if score, ok = flat.Score(strings.ReplaceAll(s.Qualified(), "::", "")); ok {
+				score *= 0.8
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if s.Definition {
+			score *= 1.05
+		}
+		if score > 1 {
+			score = 1
+		}
+
+		hits = append(hits, Hit{Symbol: s, Score: score})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+
+		qi, qj := hits[i].Qualified(), hits[j].Qualified()
+		if len(qi) != len(qj) {
+			return len(qi) < len(qj)
+		}
+
+		return qi < qj
+	})
+
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+
+	return hits
+}
+
+// match matches the name of s against name and its container against the qualifiers, which
+// have to match scopes of the container in order.
+func match(s *Symbol, name *completion.Matcher, qualifiers []*completion.Matcher, anchored bool) (float64, bool) {
+	score, ok := name.Score(s.Name)
+	if !ok {
+		return 0, false
+	}
+	// Prefer exact names to longer names starting with the pattern.
+	if name.Pattern() != "" {
+		if strings.EqualFold(name.Pattern(), s.Name) {
+			score = 1
+		} else {
+			score *= 0.85
+		}
+	}
+
+	var scopes []string
+	if s.Container != "" {
+		scopes = strings.Split(s.Container, "::")
+	}
+
+	if anchored && len(scopes) != len(qualifiers) {
+		return 0, false
+	}
+
+	// Match the qualifiers from the innermost scope outwards, preferring the innermost
+	// matching scope.
+	j := len(scopes) - 1
+	for i := len(qualifiers) - 1; i >= 0; i-- {
+		found := false
+		for ; j >= 0; j-- {
+			if qs, ok := qualifiers[i].Score(scopes[j]); ok {
+				score *= qs
+				found = true
+				j--
+
+				break
+			}
+			if anchored {
+				return 0, false
+			}
+			// Skipped scopes make the match less specific.
+			score *= 0.9
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	return score, true
+}
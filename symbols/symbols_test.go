@@ -0,0 +1,146 @@
+package symbols
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func testIndex() *Index {
+	sym := func(usr, container, name string, kind clang.CursorKind, definition bool) Symbol {
+		return Symbol{
+			USR:        usr,
+			Name:       name,
+			Container:  container,
+			Kind:       kind,
+			Signature:  name,
+			Location:   clang.Position{File: "/p/" + usr + ".h", Line: 1, Column: 1},
+			Definition: definition,
+		}
+	}
+
+	ix := NewIndex()
+	ix.Add(
+		sym("ns", "", "ns", clang.Cursor_Namespace, true),
+		sym("cls", "ns", "Cls", clang.Cursor_ClassDecl, true),
+		sym("meth", "ns::Cls", "meth", clang.Cursor_CXXMethod, false),
+		sym("method", "ns::Cls", "method", clang.Cursor_CXXMethod, true),
+		sym("other", "ns::Other", "meth", clang.Cursor_CXXMethod, true),
+		sym("inner", "ns::Cls::Inner", "meth", clang.Cursor_CXXMethod, true),
+		sym("global", "", "meth", clang.Cursor_FunctionDecl, true),
+		sym("max", "", "MAX", clang.Cursor_MacroDefinition, true),
+	)
+	// The definition replaces the declaration.
+	def := sym("meth", "ns::Cls", "meth", clang.Cursor_CXXMethod, true)
+	def.Location.File = "/p/cls.cc"
+	ix.Add(def, sym("global", "", "meth", clang.Cursor_FunctionDecl, false))
+
+	return ix
+}
+
+func usrs(hits []Hit) []string {
+	var us []string
+	for _, h := range hits {
+		us = append(us, h.USR)
+	}
+
+	return us
+}
+
+func TestSearch(t *testing.T) {
+	ix := testIndex()
+
+	if n := ix.Len(); n != 8 {
+		t.Errorf("expected 8 symbols. got=%d", n)
+	}
+	if s := ix.Lookup("meth"); s.Location.File != "/p/cls.cc" || !s.Definition || s.Qualified() != "ns::Cls::meth" {
+		t.Errorf("expected the definition of meth. got=%+v", s)
+	}
+	if s := ix.Lookup("global"); !s.Definition {
+		t.Errorf("expected the definition to be kept. got=%+v", s)
+	}
+
+	table := []struct {
+		query Query
+		want  []string
+	}{
+		{Query{Pattern: "ns::Cls::meth"}, []string{"meth", "inner", "method"}},
+		{Query{Pattern: "Cls::meth"}, []string{"meth", "inner", "method"}},
+		{Query{Pattern: "ns::meth"}, []string{"meth", "other", "inner", "method"}},
+		{Query{Pattern: "::meth"}, []string{"global"}},
+		{Query{Pattern: "::ns::Cls::meth"}, []string{"meth", "method"}},
+		{Query{Pattern: "Oth::meth"}, []string{"other"}},
+		{Query{Pattern: "cmeth"}, []string{"meth", "method", "inner"}},
+		{Query{Pattern: "meth", Kinds: []clang.CursorKind{clang.Cursor_FunctionDecl}}, []string{"global"}},
+		{Query{Pattern: "meth", Limit: 2}, []string{"global", "meth"}},
+		{Query{Pattern: "MAX"}, []string{"max"}},
+		{Query{Pattern: "xyz"}, nil},
+	}
+
+	for _, tt := range table {
+		if got := usrs(ix.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: expected %v. got=%v", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := clang.NewIndex(0, 0)
+	defer idx.Dispose()
+
+	tu := idx.ParseTranslationUnit("testdata/widget.cc", nil, nil, uint32(clang.TranslationUnit_DetailedPreprocessingRecord))
+	if !tu.IsValid() {
+		t.Fatal("tu is invalid")
+	}
+	defer tu.Dispose()
+
+	byName := map[string]Symbol{}
+	for _, s := range (Extractor{Directory: dir}).Extract(tu) {
+		byName[s.Qualified()] = s
+	}
+
+	source := filepath.Join(dir, "testdata", "widget.cc")
+	tests := []struct {
+		qualified  string
+		kind       clang.CursorKind
+		signature  string
+		line       uint32
+		definition bool
+	}{
+		{"LIMIT", clang.Cursor_MacroDefinition, "LIMIT", 1, true},
+		{"app::Color", clang.Cursor_EnumDecl, "Color", 4, true},
+		{"app::Red", clang.Cursor_EnumConstantDecl, "Red", 4, true},
+		{"app::Widget", clang.Cursor_StructDecl, "Widget", 5, true},
+		{"app::Widget::size", clang.Cursor_FieldDecl, "int size", 6, true},
+		{"app::Widget::grow", clang.Cursor_CXXMethod, "int grow(int)", 9, true},
+	}
+
+	for _, tt := range tests {
+		s, ok := byName[tt.qualified]
+		if !ok {
+			t.Errorf("expected symbol %s. got=%v", tt.qualified, byName)
+
+			continue
+		}
+		if s.Kind != tt.kind || s.Signature != tt.signature || s.Definition != tt.definition {
+			t.Errorf("expected %s to be %s %q with definition=%v. got=%+v", tt.qualified, tt.kind, tt.signature, tt.definition, s)
+		}
+		if s.Location.File != source || s.Location.Line != tt.line {
+			t.Errorf("expected %s at %s:%d. got=%v", tt.qualified, source, tt.line, s.Location)
+		}
+	}
+
+	for _, local := range []string{"app::Widget::grow::by", "app::Widget::grow::local", "local", "by"} {
+		if _, ok := byName[local]; ok {
+			t.Errorf("expected no symbol for the local %s", local)
+		}
+	}
+}
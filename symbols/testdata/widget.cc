@@ -0,0 +1,10 @@
+#define LIMIT 8
+
+namespace app {
+enum Color { Red, Blue };
+struct Widget {
+  int size;
+  int grow(int by);
+};
+int Widget::grow(int by) { int local = by; return size += local; }
+} // namespace app
@@ -0,0 +1,148 @@
+package xref
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+	"github.com/go-clang/clang-v15/incremental"
+)
+
+// analysis identifies the format of the shards. Databases of other versions are rebuilt.
+const analysis = "xref/1"
+
+// FileState identifies the content of a file a translation unit was indexed with.
+type FileState = incremental.FileState
+
+// record is the content of a shard.
+type record struct {
+	Symbols     []*Symbol
+	Occurrences []Occurrence
+	Includes    []Include
+}
+
+// DB is a cross reference database stored in a directory. Each translation unit is stored in
+// its own shard by an incremental.Driver, so only changed translation units have to be indexed
+// again.
+//
+// A DB must not be used concurrently.
+type DB struct {
+	driver *incremental.Driver
+	// Extractor is used to extract the translation units.
+	Extractor Extractor
+
+	index *index
+}
+
+// UpdateStats reports what an update did.
+type UpdateStats struct {
+	Indexed   int
+	Unchanged int
+	Removed   int
+	// Duplicates counts the commands which were ignored because an earlier command compiles the
+	// same source file.
+	Duplicates int
+	// Errors holds the translation units which could not be parsed.
+	Errors []error
+}
+
+// Open opens the database in dir. The directory is created if it does not exist.
+func Open(dir string) (*DB, error) {
+	d, err := incremental.Open(dir, analysis)
+	if err != nil {
+		return nil, err
+	}
+	d.Options = uint32(clang.TranslationUnit_DetailedPreprocessingRecord | clang.TranslationUnit_KeepGoing)
+
+	return &DB{
+		driver:    d,
+		Extractor: Extractor{SkipSystemHeaders: true},
+	}, nil
+}
+
+// Sources returns the source files of the indexed translation units.
+func (db *DB) Sources() []string {
+	return db.driver.Sources()
+}
+
+// Files returns the files the translation unit of source was indexed with.
+func (db *DB) Files(source string) []FileState {
+	return db.driver.Files(source)
+}
+
+// Update indexes the translation units of cmds which changed since they were indexed, and
+// removes the translation units which are no longer part of cmds. A translation unit changed
+// if its arguments or the content of one of its files changed.
+func (db *DB) Update(idx clang.Index, cmds []compdb.Command) (UpdateStats, error) {
+	rs, err := db.driver.Run(idx, cmds, db.shard)
+
+	stats := UpdateStats{
+		Indexed:    rs.Analyzed,
+		Unchanged:  rs.Reused,
+		Removed:    rs.Removed,
+		Duplicates: rs.Duplicates,
+		Errors:     rs.Errors,
+	}
+	if stats.Indexed > 0 || stats.Removed > 0 {
+		db.index = nil
+	}
+
+	return stats, err
+}
+
+// shard extracts the translation unit of cmd and encodes it as a shard.
+func (db *DB) shard(cmd compdb.Command, tu clang.TranslationUnit) ([]byte, error) {
+	ex := db.Extractor
+	ex.Directory = cmd.Directory
+
+	return encodeShard(ex.Extract(tu))
+}
+
+// encodeShard encodes u with its symbols sorted by USR, so unchanged units give the same shard.
+func encodeShard(u *Unit) ([]byte, error) {
+	rec := record{
+		Occurrences: u.Occurrences,
+		Includes:    u.Includes,
+	}
+	for _, s := range u.Symbols {
+		rec.Symbols = append(rec.Symbols, s)
+	}
+	sort.Slice(rec.Symbols, func(i, j int) bool {
+		return rec.Symbols[i].USR < rec.Symbols[j].USR
+	})
+
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(rec)
+
+	return b.Bytes(), err
+}
+
+// load merges all shards into a single unit.
+func (db *DB) load() (*Unit, error) {
+	u := NewUnit()
+
+	for _, source := range db.Sources() {
+		b, err := db.driver.Result(source)
+		if err != nil {
+			return nil, err
+		}
+
+		var rec record
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&rec); err != nil {
+			return nil, fmt.Errorf("read shard of %s: %w", source, err)
+		}
+
+		shard := NewUnit()
+		for _, s := range rec.Symbols {
+			shard.Symbols[s.USR] = s
+		}
+		shard.Occurrences = rec.Occurrences
+		shard.Includes = rec.Includes
+		u.Merge(shard)
+	}
+
+	return u, nil
+}
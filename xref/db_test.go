@@ -0,0 +1,140 @@
+package xref
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// addShard stores u as the translation unit of source without parsing.
+func addShard(t *testing.T, db *DB, source string, u *Unit) {
+	t.Helper()
+
+	b, err := encodeShard(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.driver.Store(compdb.Command{Filename: source}, nil, b); err != nil {
+		t.Fatal(err)
+	}
+	db.index = nil
+}
+
+func TestDBQueries(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewUnit()
+	a.Symbols["c:@S@A"] = &Symbol{USR: "c:@S@A", Name: "A", Kind: clang.Cursor_ClassDecl}
+	a.Symbols["c:@S@A@F@f#"] = &Symbol{USR: "c:@S@A@F@f#", Name: "f", Kind: clang.Cursor_CXXMethod, Parent: "c:@S@A"}
+	a.Symbols["c:@S@B"] = &Symbol{USR: "c:@S@B", Name: "B", Kind: clang.Cursor_ClassDecl,
+		Relations: []Relation{{Kind: Relation_Base, USR: "c:@S@A", Access: clang.AccessSpecifier_Public}}}
+	a.Symbols["c:@S@B@F@f#"] = &Symbol{USR: "c:@S@B@F@f#", Name: "f", Kind: clang.Cursor_CXXMethod, Parent: "c:@S@B",
+		Relations: []Relation{{Kind: Relation_Override, USR: "c:@S@A@F@f#"}}}
+	a.Symbols["c:@F@main"] = &Symbol{USR: "c:@F@main", Name: "main", Kind: clang.Cursor_FunctionDecl}
+	a.Occurrences = []Occurrence{
+		{USR: "c:@S@A@F@f#", Span: span("/p/a.h", 2, 15, 30, 1), Roles: clang.SymbolRole_Declaration},
+		{USR: "c:@S@B@F@f#", Span: span("/p/a.h", 5, 7, 70, 1), Roles: clang.SymbolRole_Definition},
+		{USR: "c:@F@main", Span: span("/p/a.cc", 3, 5, 30, 4), Roles: clang.SymbolRole_Definition},
+		{USR: "c:@S@A@F@f#", Span: span("/p/a.cc", 4, 6, 45, 1), Roles: clang.SymbolRole_Reference | clang.SymbolRole_Call | clang.SymbolRole_Dynamic, Container: "c:@F@main"},
+	}
+	a.Includes = []Include{{File: "/p/a.cc", Line: 1, Included: "/p/a.h"}}
+	addShard(t, db, "/p/a.cc", a)
+
+	b := NewUnit()
+	b.Symbols["c:@S@A@F@f#"] = a.Symbols["c:@S@A@F@f#"]
+	b.Symbols["c:@F@g"] = &Symbol{USR: "c:@F@g", Name: "g", Kind: clang.Cursor_FunctionDecl}
+	b.Occurrences = []Occurrence{
+		a.Occurrences[0],
+		{USR: "c:@S@A@F@f#", Span: span("/p/b.cc", 9, 2, 99, 1), Roles: clang.SymbolRole_Reference | clang.SymbolRole_Call, Container: "c:@F@g"},
+	}
+	b.Includes = []Include{{File: "/p/b.cc", Line: 1, Included: "/p/a.h"}}
+	addShard(t, db, "/p/b.cc", b)
+
+	if err := db.driver.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queries must work on a reopened database.
+	db, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := db.Sources(); !reflect.DeepEqual(got, []string{"/p/a.cc", "/p/b.cc"}) {
+		t.Errorf("unexpected sources %v", got)
+	}
+
+	named, err := db.SymbolsNamed("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(named) != 2 || named[0].USR != "c:@S@A@F@f#" || named[1].USR != "c:@S@B@F@f#" {
+		t.Errorf("unexpected symbols named f %+v", named)
+	}
+
+	decls, _ := db.Declarations("c:@S@A@F@f#")
+	if len(decls) != 1 {
+		t.Errorf("expected the declaration in the header once. got=%+v", decls)
+	}
+	defs, _ := db.Definitions("c:@S@B@F@f#")
+	if len(defs) != 1 || defs[0].Span.Start.File != "/p/a.h" {
+		t.Errorf("unexpected definitions %+v", defs)
+	}
+
+	refs, _ := db.References("c:@S@A@F@f#", 0)
+	if len(refs) != 2 {
+		t.Errorf("expected 2 references. got=%+v", refs)
+	}
+	refs, _ = db.References("c:@S@A@F@f#", clang.SymbolRole_Dynamic)
+	if len(refs) != 1 || refs[0].Span.Start.File != "/p/a.cc" {
+		t.Errorf("expected 1 dynamic reference. got=%+v", refs)
+	}
+
+	callers, _ := db.Callers("c:@S@A@F@f#")
+	if len(callers) != 2 || callers[0].Caller != "c:@F@main" || callers[1].Caller != "c:@F@g" {
+		t.Errorf("unexpected callers %+v", callers)
+	}
+	callees, _ := db.Callees("c:@F@g")
+	if len(callees) != 1 || callees[0].Callee != "c:@S@A@F@f#" {
+		t.Errorf("unexpected callees %+v", callees)
+	}
+
+	if bases, _ := db.Bases("c:@S@B"); len(bases) != 1 || bases[0].USR != "c:@S@A" {
+		t.Errorf("unexpected bases %+v", bases)
+	}
+	if derived, _ := db.Derived("c:@S@A"); !reflect.DeepEqual(derived, []string{"c:@S@B"}) {
+		t.Errorf("unexpected derived records %v", derived)
+	}
+	if overrides, _ := db.Overrides("c:@S@B@F@f#"); !reflect.DeepEqual(overrides, []string{"c:@S@A@F@f#"}) {
+		t.Errorf("unexpected overridden methods %v", overrides)
+	}
+	if by, _ := db.OverriddenBy("c:@S@A@F@f#"); !reflect.DeepEqual(by, []string{"c:@S@B@F@f#"}) {
+		t.Errorf("unexpected overriding methods %v", by)
+	}
+
+	if incs, _ := db.IncludedBy("/p/a.h"); len(incs) != 2 {
+		t.Errorf("expected a.h to be included twice. got=%+v", incs)
+	}
+	if incs, _ := db.Includes("/p/b.cc"); len(incs) != 1 || incs[0].Included != "/p/a.h" {
+		t.Errorf("unexpected includes of b.cc %+v", incs)
+	}
+
+	// Translation units which are no longer compiled are removed.
+	stats, err := db.Update(clang.Index{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Removed != 2 || len(db.Sources()) != 0 {
+		t.Errorf("expected all units to be removed. got=%+v", stats)
+	}
+	if s, _ := db.Symbol("c:@F@main"); s != nil {
+		t.Errorf("expected no symbols after removal. got=%+v", s)
+	}
+}
@@ -0,0 +1,413 @@
+package xref
+
+import (
+	"github.com/go-clang/clang-v15/clang"
+	"github.com/go-clang/clang-v15/compdb"
+)
+
+// Extractor extracts units from translation units.
+type Extractor struct {
+	// SkipSystemHeaders skips the declarations in system headers. Symbols declared there are
+	// still described if they are referenced.
+	SkipSystemHeaders bool
+	// SkipFile is called for every file with declarations. Returning true skips the
+	// declarations in the file.
+	SkipFile func(name string) bool
+	// Directory is the working directory of the compiler, see compdb.Paths.
+	Directory string
+}
+
+// Extract returns the symbols and occurrences of the translation unit.
+func Extract(tu clang.TranslationUnit) *Unit {
+	return Extractor{}.Extract(tu)
+}
+
+// Extract returns the symbols and occurrences of the translation unit.
+func (e Extractor) Extract(tu clang.TranslationUnit) *Unit {
+	w := walker{
+		Extractor: e,
+		tu:        tu,
+		unit:      NewUnit(),
+		skipped:   map[string]bool{},
+		paths:     compdb.Paths{Directory: e.Directory},
+	}
+	w.children(tu.TranslationUnitCursor(), access{})
+	w.includes()
+
+	return w.unit
+}
+
+// access are the roles an expression inherits from its parent, e.g. the left hand side of an
+// assignment is written.
+type access struct {
+	roles clang.SymbolRole
+	// callee is the USR of the called function for clang.SymbolRole_Call.
+	callee string
+}
+
+type walker struct {
+	Extractor
+
+	tu         clang.TranslationUnit
+	unit       *Unit
+	containers []string
+	skipped    map[string]bool
+	paths      compdb.Paths
+}
+
+// includes records the inclusion directives of the translation unit.
+func (w *walker) includes() {
+	w.tu.Inclusions(func(included clang.File, stack []clang.SourceLocation) {
+		if len(stack) == 0 {
+			return
+		}
+
+		loc := stack[0]
+		if w.SkipSystemHeaders && loc.IsInSystemHeader() {
+			return
+		}
+
+		file, line, _, _ := loc.ExpansionLocation()
+		if w.SkipFile != nil && w.SkipFile(file.Name()) {
+			return
+		}
+
+		w.unit.Includes = append(w.unit.Includes, Include{
+			File:     w.paths.Abs(file.Name()),
+			Line:     line,
+			Included: w.paths.Abs(included.Name()),
+		})
+	})
+}
+
+func (w *walker) skip(c clang.Cursor) bool {
+	loc := c.Location()
+	if w.SkipSystemHeaders && loc.IsInSystemHeader() {
+		return true
+	}
+	if w.SkipFile == nil {
+		return false
+	}
+
+	file, _, _, _ := loc.ExpansionLocation()
+	name := file.Name()
+	skip, ok := w.skipped[name]
+	if !ok {
+		skip = w.SkipFile(name)
+		w.skipped[name] = skip
+	}
+
+	return skip
+}
+
+func (w *walker) children(parent clang.Cursor, first access) {
+	i := 0
+	parent.Visit(func(c, _ clang.Cursor) clang.ChildVisitResult {
+		a := access{}
+		if i == 0 {
+			a = first
+		}
+		i++
+
+		w.visit(c, a)
+
+		return clang.ChildVisit_Continue
+	})
+}
+
+func (w *walker) visit(c clang.Cursor, a access) {
+	if w.skip(c) {
+		return
+	}
+
+	kind := c.Kind()
+	switch {
+	case kind == clang.Cursor_CXXBaseSpecifier:
+		w.base(c)
+	case kind.IsDeclaration(), kind == clang.Cursor_MacroDefinition:
+		w.declaration(c)
+	case kind.IsReference(), kind == clang.Cursor_DeclRefExpr, kind == clang.Cursor_MemberRefExpr,
+		kind == clang.Cursor_MacroExpansion:
+		w.reference(c, a)
+	}
+
+	first := access{}
+	switch kind {
+	case clang.Cursor_ParenExpr, clang.Cursor_UnexposedExpr:
+		first = a
+	case clang.Cursor_CallExpr:
+		if callee := c.Referenced(); !callee.IsNull() {
+			first.roles = clang.SymbolRole_Call
+			if c.IsDynamicCall() {
+				first.roles |= clang.SymbolRole_Dynamic
+			}
+			first.callee = callee.USR()
+		}
+	case clang.Cursor_BinaryOperator:
+		if w.binaryOperator(c) == "=" {
+			first.roles = clang.SymbolRole_Write
+		}
+	case clang.Cursor_CompoundAssignOperator:
+		first.roles = clang.SymbolRole_Read | clang.SymbolRole_Write
+	case clang.Cursor_UnaryOperator:
+		switch w.unaryOperator(c) {
+		case "++", "--":
+			first.roles = clang.SymbolRole_Read | clang.SymbolRole_Write
+		case "&":
+			first.roles = clang.SymbolRole_AddressOf
+		}
+	}
+
+	container := isContainer(kind) && c.USR() != ""
+	if container {
+		w.containers = append(w.containers, c.USR())
+	}
+
+	w.children(c, first)
+
+	if container {
+		w.containers = w.containers[:len(w.containers)-1]
+	}
+}
+
+func (w *walker) container() string {
+	if n := len(w.containers); n > 0 {
+		return w.containers[n-1]
+	}
+
+	return ""
+}
+
+func (w *walker) declaration(c clang.Cursor) {
+	usr := c.USR()
+	if usr == "" {
+		return
+	}
+
+	s := w.symbol(c)
+
+	var roles clang.SymbolRole = clang.SymbolRole_Declaration
+	if c.IsCursorDefinition() || c.Kind() == clang.Cursor_MacroDefinition {
+		roles = clang.SymbolRole_Definition
+	}
+	w.occurrence(usr, nameSpan(c, c.SpellingNameRange(0, 0)), roles)
+
+	overridden := c.OverriddenCursors()
+	for _, o := range overridden {
+		ou := o.USR()
+		w.symbol(o)
+		if ou != "" && !s.HasRelation(Relation_Override, ou) {
+			s.Relations = append(s.Relations, Relation{Kind: Relation_Override, USR: ou})
+		}
+	}
+	if len(overridden) > 0 {
+		clang.Dispose(overridden)
+	}
+}
+
+// base records a base class of the record containing the base specifier.
+func (w *walker) base(c clang.Cursor) {
+	s, ok := w.unit.Symbols[w.container()]
+	decl := c.Type().Declaration()
+	if !ok || decl.IsNull() || decl.USR() == "" {
+		return
+	}
+
+	kind := Relation_Base
+	if c.IsVirtualBase() {
+		kind = Relation_VirtualBase
+	}
+
+	base := w.symbol(decl)
+	if !s.HasRelation(kind, base.USR) {
+		s.Relations = append(s.Relations, Relation{Kind: kind, USR: base.USR, Access: c.AccessSpecifier()})
+	}
+}
+
+func (w *walker) reference(c clang.Cursor, a access) {
+	ref := c.Referenced()
+	if ref.IsNull() {
+		return
+	}
+	usr := ref.USR()
+	if usr == "" {
+		return
+	}
+	w.symbol(ref)
+
+	var roles clang.SymbolRole = clang.SymbolRole_Reference
+	switch {
+	case a.roles&clang.SymbolRole_Call != 0:
+		if usr == a.callee {
+			roles |= a.roles
+		}
+	case a.roles != 0:
+		roles |= a.roles
+	case (c.Kind() == clang.Cursor_DeclRefExpr || c.Kind() == clang.Cursor_MemberRefExpr) && isVariable(ref.Kind()):
+		roles |= clang.SymbolRole_Read
+	}
+
+	var r clang.SourceRange
+	switch c.Kind() {
+	case clang.Cursor_DeclRefExpr, clang.Cursor_MemberRefExpr:
+		r = c.ReferenceNameRange(0, 0)
+	case clang.Cursor_MacroExpansion:
+		r = clang.NewNullRange()
+	default:
+		r = c.Extent()
+	}
+	w.occurrence(usr, nameSpan(c, r), roles)
+}
+
+func (w *walker) occurrence(usr string, span clang.Span, roles clang.SymbolRole) {
+	if !span.IsValid() {
+		return
+	}
+	span.Start.File = w.paths.Abs(span.Start.File)
+	span.End.File = span.Start.File
+
+	w.unit.Occurrences = append(w.unit.Occurrences, Occurrence{
+		USR:       usr,
+		Span:      span,
+		Roles:     roles,
+		Container: w.container(),
+	})
+}
+
+// symbol returns the symbol of the declaration c and adds it to the unit if it is new.
+func (w *walker) symbol(c clang.Cursor) *Symbol {
+	usr := c.USR()
+	if s, ok := w.unit.Symbols[usr]; ok {
+		if s.Doc == "" {
+			s.Doc = c.BriefCommentText()
+		}
+
+		return s
+	}
+
+	s := &Symbol{
+		USR:         usr,
+		Name:        c.Spelling(),
+		DisplayName: c.DisplayName(),
+		Kind:        c.Kind(),
+		Type:        c.Type().Spelling(),
+		Doc:         c.BriefCommentText(),
+	}
+
+	parent := c.SemanticParent()
+	if !parent.IsNull() && parent.Kind() != clang.Cursor_TranslationUnit {
+		s.Parent = parent.USR()
+	}
+	switch s.Kind {
+	case clang.Cursor_TemplateTypeParameter, clang.Cursor_NonTypeTemplateParameter, clang.Cursor_TemplateTemplateParameter:
+		s.Local = true
+	case clang.Cursor_ParmDecl, clang.Cursor_VarDecl:
+		s.Local = !parent.IsNull() && isFunction(parent.Kind())
+	}
+
+	w.unit.Symbols[usr] = s
+
+	return s
+}
+
+// nameSpan returns the span of the name of c. If r is null, the span starts at the location of
+// the cursor and has the length of its spelling.
+func nameSpan(c clang.Cursor, r clang.SourceRange) clang.Span {
+	if !r.IsNull() {
+		if s := r.Span(); s.IsValid() && s.Start.File == s.End.File && s.Start.Offset < s.End.Offset {
+			return s
+		}
+	}
+
+	start := c.Location().Position()
+	if !start.IsValid() {
+		return clang.Span{}
+	}
+
+	end := start
+	n := uint32(len(c.Spelling()))
+	end.Column += n
+	end.Offset += n
+
+	return clang.Span{Start: start, End: end}
+}
+
+// binaryOperator returns the spelling of the operator between the operands of c.
+func (w *walker) binaryOperator(c clang.Cursor) string {
+	var operands []clang.Cursor
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		operands = append(operands, child)
+
+		return clang.ChildVisit_Continue
+	})
+	if len(operands) != 2 {
+		return ""
+	}
+
+	return w.firstToken(operands[0].Extent().End(), operands[1].Extent().Start())
+}
+
+// unaryOperator returns the spelling of the prefix or postfix operator of c.
+func (w *walker) unaryOperator(c clang.Cursor) string {
+	var operand clang.Cursor
+	c.Visit(func(child, _ clang.Cursor) clang.ChildVisitResult {
+		operand = child
+
+		return clang.ChildVisit_Break
+	})
+	if operand.IsNull() {
+		return ""
+	}
+
+	if op := w.firstToken(c.Extent().Start(), operand.Extent().Start()); op != "" {
+		return op
+	}
+
+	return w.firstToken(operand.Extent().End(), c.Extent().End())
+}
+
+func (w *walker) firstToken(start, end clang.SourceLocation) string {
+	if start.Equal(end) {
+		return ""
+	}
+
+	tokens := w.tu.Tokenize(start.Range(end))
+	defer w.tu.DisposeTokens(tokens)
+
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	return w.tu.TokenSpelling(tokens[0])
+}
+
+func isFunction(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_FunctionDecl, clang.Cursor_CXXMethod, clang.Cursor_Constructor, clang.Cursor_Destructor,
+		clang.Cursor_ConversionFunction, clang.Cursor_FunctionTemplate, clang.Cursor_ObjCInstanceMethodDecl,
+		clang.Cursor_ObjCClassMethodDecl, clang.Cursor_LambdaExpr:
+		return true
+	}
+
+	return false
+}
+
+func isContainer(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_Namespace, clang.Cursor_StructDecl, clang.Cursor_UnionDecl, clang.Cursor_ClassDecl,
+		clang.Cursor_ClassTemplate, clang.Cursor_ClassTemplatePartialSpecialization, clang.Cursor_EnumDecl,
+		clang.Cursor_ObjCInterfaceDecl, clang.Cursor_ObjCImplementationDecl, clang.Cursor_ObjCProtocolDecl:
+		return true
+	}
+
+	return isFunction(kind)
+}
+
+func isVariable(kind clang.CursorKind) bool {
+	switch kind {
+	case clang.Cursor_VarDecl, clang.Cursor_ParmDecl, clang.Cursor_FieldDecl, clang.Cursor_ObjCIvarDecl:
+		return true
+	}
+
+	return false
+}
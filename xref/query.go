@@ -0,0 +1,296 @@
+package xref
+
+import (
+	"sort"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Call is a call of a function or method.
+type Call struct {
+	// Caller is the USR of the calling function. It is empty for calls at file scope, e.g. in
+	// initializers of global variables.
+	Caller string
+	Callee string
+	// Site is the occurrence of the callee at the call.
+	Site Occurrence
+}
+
+// index holds the merged shards of a DB together with lookup tables.
+type index struct {
+	unit        *Unit
+	byName      map[string][]string
+	occurrences map[string][]Occurrence
+	derived     map[RelationKind]map[string][]string
+	calls       []Call
+	includedBy  map[string][]Include
+}
+
+func newIndex(u *Unit) *index {
+	x := &index{
+		unit:        u,
+		byName:      map[string][]string{},
+		occurrences: map[string][]Occurrence{},
+		derived:     map[RelationKind]map[string][]string{},
+		includedBy:  map[string][]Include{},
+	}
+
+	for usr, s := range u.Symbols {
+		x.byName[s.Name] = append(x.byName[s.Name], usr)
+
+		for _, r := range s.Relations {
+			m := x.derived[r.Kind]
+			if m == nil {
+				m = map[string][]string{}
+				x.derived[r.Kind] = m
+			}
+			m[r.USR] = append(m[r.USR], usr)
+		}
+	}
+	for _, usrs := range x.byName {
+		sort.Strings(usrs)
+	}
+	for _, m := range x.derived {
+		for _, usrs := range m {
+			sort.Strings(usrs)
+		}
+	}
+
+	for _, o := range u.Occurrences {
+		x.occurrences[o.USR] = append(x.occurrences[o.USR], o)
+
+		if o.Is(clang.SymbolRole_Call) {
+			x.calls = append(x.calls, Call{
+				Caller: o.Container,
+				Callee: o.USR,
+				Site:   o,
+			})
+		}
+	}
+
+	for _, inc := range u.Includes {
+		x.includedBy[inc.Included] = append(x.includedBy[inc.Included], inc)
+	}
+
+	return x
+}
+
+func (db *DB) lookup() (*index, error) {
+	if db.index == nil {
+		u, err := db.load()
+		if err != nil {
+			return nil, err
+		}
+		db.index = newIndex(u)
+	}
+
+	return db.index, nil
+}
+
+// Unit returns all translation units of the database merged into one unit.
+func (db *DB) Unit() (*Unit, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	return x.unit, nil
+}
+
+// Symbol returns the symbol with the given USR or nil.
+func (db *DB) Symbol(usr string) (*Symbol, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	return x.unit.Symbols[usr], nil
+}
+
+// SymbolsNamed returns the symbols with the given unqualified name.
+func (db *DB) SymbolsNamed(name string) ([]*Symbol, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []*Symbol
+	for _, usr := range x.byName[name] {
+		symbols = append(symbols, x.unit.Symbols[usr])
+	}
+
+	return symbols, nil
+}
+
+// Occurrences returns the occurrences of usr having all of the given roles. A zero roles
+// returns all occurrences.
+func (db *DB) Occurrences(usr string, roles clang.SymbolRole) ([]Occurrence, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []Occurrence
+	for _, o := range x.occurrences[usr] {
+		if o.Is(roles) {
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// Declarations returns the declarations of usr, including its definitions.
+func (db *DB) Declarations(usr string) ([]Occurrence, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []Occurrence
+	for _, o := range x.occurrences[usr] {
+		if o.Roles&(clang.SymbolRole_Declaration|clang.SymbolRole_Definition) != 0 {
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// Definitions returns the definitions of usr.
+func (db *DB) Definitions(usr string) ([]Occurrence, error) {
+	return db.Occurrences(usr, clang.SymbolRole_Definition)
+}
+
+// References returns the references to usr having all of the given roles, e.g.
+// clang.SymbolRole_Write for writes of a variable.
+func (db *DB) References(usr string, roles clang.SymbolRole) ([]Occurrence, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []Occurrence
+	for _, o := range x.occurrences[usr] {
+		if o.Is(clang.SymbolRole_Reference | roles) {
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// Callers returns the calls of usr.
+func (db *DB) Callers(usr string) ([]Call, error) {
+	return db.calls(func(c Call) bool { return c.Callee == usr })
+}
+
+// Callees returns the calls made by the function usr.
+func (db *DB) Callees(usr string) ([]Call, error) {
+	return db.calls(func(c Call) bool { return c.Caller == usr })
+}
+
+func (db *DB) calls(match func(c Call) bool) ([]Call, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []Call
+	for _, c := range x.calls {
+		if match(c) {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls, nil
+}
+
+// Bases returns the direct base classes of the record usr, virtual or not.
+func (db *DB) Bases(usr string) ([]Relation, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var bases []Relation
+	if s := x.unit.Symbols[usr]; s != nil {
+		for _, r := range s.Relations {
+			if r.Kind == Relation_Base || r.Kind == Relation_VirtualBase {
+				bases = append(bases, r)
+			}
+		}
+	}
+
+	return bases, nil
+}
+
+// Derived returns the USRs of the records directly derived from the record usr.
+func (db *DB) Derived(usr string) ([]string, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	derived := append([]string(nil), x.derived[Relation_Base][usr]...)
+	derived = append(derived, x.derived[Relation_VirtualBase][usr]...)
+	sort.Strings(derived)
+
+	return derived, nil
+}
+
+// Overrides returns the USRs of the methods the method usr directly overrides.
+func (db *DB) Overrides(usr string) ([]string, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var overridden []string
+	if s := x.unit.Symbols[usr]; s != nil {
+		for _, r := range s.Relations {
+			if r.Kind == Relation_Override {
+				overridden = append(overridden, r.USR)
+			}
+		}
+	}
+
+	return overridden, nil
+}
+
+// OverriddenBy returns the USRs of the methods directly overriding the method usr.
+func (db *DB) OverriddenBy(usr string) ([]string, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string(nil), x.derived[Relation_Override][usr]...), nil
+}
+
+// Includes returns the inclusion directives in file.
+func (db *DB) Includes(file string) ([]Include, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	var includes []Include
+	for _, inc := range x.unit.Includes {
+		if inc.File == file {
+			includes = append(includes, inc)
+		}
+	}
+
+	return includes, nil
+}
+
+// IncludedBy returns the inclusion directives including file.
+func (db *DB) IncludedBy(file string) ([]Include, error) {
+	x, err := db.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Include(nil), x.includedBy[file]...), nil
+}
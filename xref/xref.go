@@ -0,0 +1,196 @@
+// Package xref extracts the symbols of translation units together with their occurrences and
+// the relations between them, such as base classes and overridden methods. Symbols are
+// identified by their USR, so the results of different translation units can be merged.
+package xref
+
+import (
+	"fmt"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+// Occurrence is a declaration of or a reference to a symbol.
+type Occurrence struct {
+	USR string
+	// Span is the span of the name of the symbol.
+	Span  clang.Span
+	Roles clang.SymbolRole
+	// Container is the USR of the innermost function, record or namespace the occurrence is in.
+	// It is empty at file scope.
+	Container string
+}
+
+// Is reports whether the occurrence has all of the given roles.
+func (o Occurrence) Is(roles clang.SymbolRole) bool {
+	return o.Roles&roles == roles
+}
+
+// RelationKind is the kind of a relation between two symbols.
+type RelationKind uint32
+
+const (
+	// Relation_Base relates a record to one of its base classes.
+	Relation_Base RelationKind = iota + 1
+	// Relation_VirtualBase relates a record to one of its virtual base classes.
+	Relation_VirtualBase
+	// Relation_Override relates a method to a method it overrides.
+	Relation_Override
+)
+
+func (rk RelationKind) Spelling() string {
+	switch rk {
+	case Relation_Base:
+		return "Relation=Base"
+	case Relation_VirtualBase:
+		return "Relation=VirtualBase"
+	case Relation_Override:
+		return "Relation=Override"
+	}
+
+	return fmt.Sprintf("RelationKind unknown %d", int(rk))
+}
+
+func (rk RelationKind) String() string {
+	return rk.Spelling()
+}
+
+// Relation relates a symbol to another symbol.
+type Relation struct {
+	Kind RelationKind
+	USR  string
+	// Access is the access specifier of a base class.
+	Access clang.AccessSpecifier
+}
+
+// Symbol describes a symbol independently of its occurrences.
+type Symbol struct {
+	USR         string
+	Name        string
+	DisplayName string
+	Kind        clang.CursorKind
+	// Type is the spelling of the type of the symbol, e.g. "int (int, char *)" for functions.
+	Type string
+	// Parent is the USR of the semantic parent, it is empty at translation unit scope.
+	Parent string
+	// Doc is the brief documentation comment.
+	Doc string
+	// Local is set for parameters and variables declared in functions. They can not be
+	// referenced outside of the function.
+	Local     bool
+	Relations []Relation
+}
+
+// HasRelation reports whether the symbol has a relation of the given kind to usr.
+func (s *Symbol) HasRelation(kind RelationKind, usr string) bool {
+	for _, r := range s.Relations {
+		if r.Kind == kind && r.USR == usr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Include is an inclusion directive.
+type Include struct {
+	// File and Line are the location of the directive.
+	File string
+	Line uint32
+	// Included is the name of the included file.
+	Included string
+}
+
+// Unit holds the symbols, occurrences and includes of a translation unit.
+type Unit struct {
+	Symbols map[string]*Symbol
+	// Occurrences are in the order of the AST.
+	Occurrences []Occurrence
+	Includes    []Include
+
+	seen         map[occurrenceKey]bool
+	seenIncludes map[Include]bool
+}
+
+type occurrenceKey struct {
+	usr    string
+	file   string
+	offset uint32
+	roles  clang.SymbolRole
+}
+
+func keyOf(o Occurrence) occurrenceKey {
+	return occurrenceKey{o.USR, o.Span.Start.File, o.Span.Start.Offset, o.Roles}
+}
+
+// NewUnit returns an empty unit.
+func NewUnit() *Unit {
+	return &Unit{
+		Symbols: map[string]*Symbol{},
+	}
+}
+
+// Files returns the names of all files with occurrences in the order they first appear.
+func (u *Unit) Files() []string {
+	seen := map[string]bool{}
+
+	var files []string
+	for _, o := range u.Occurrences {
+		if f := o.Span.Start.File; !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
+
+// Merge adds the symbols and occurrences of other to u. Symbol information already present in
+// u is completed but not replaced. Occurrences in headers which were already added by another
+// translation unit are skipped, as are duplicate includes.
+func (u *Unit) Merge(other *Unit) {
+	for usr, s := range other.Symbols {
+		mine, ok := u.Symbols[usr]
+		if !ok {
+			c := *s
+			c.Relations = append([]Relation(nil), s.Relations...)
+			u.Symbols[usr] = &c
+
+			continue
+		}
+
+		if mine.Doc == "" {
+			mine.Doc = s.Doc
+		}
+		for _, r := range s.Relations {
+			if !mine.HasRelation(r.Kind, r.USR) {
+				mine.Relations = append(mine.Relations, r)
+			}
+		}
+	}
+
+	if u.seen == nil {
+		u.seen = make(map[occurrenceKey]bool, len(u.Occurrences))
+		for _, o := range u.Occurrences {
+			u.seen[keyOf(o)] = true
+		}
+	}
+	for _, o := range other.Occurrences {
+		if k := keyOf(o); !u.seen[k] {
+			u.seen[k] = true
+			u.Occurrences = append(u.Occurrences, o)
+		}
+	}
+
+	if u.seenIncludes == nil {
+		u.seenIncludes = make(map[Include]bool, len(u.Includes))
+		for _, inc := range u.Includes {
+			u.seenIncludes[inc] = true
+		}
+	}
+	for _, inc := range other.Includes {
+		if !u.seenIncludes[inc] {
+			u.seenIncludes[inc] = true
+			u.Includes = append(u.Includes, inc)
+		}
+	}
+}
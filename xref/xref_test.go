@@ -0,0 +1,63 @@
+package xref
+
+import (
+	"testing"
+
+	"github.com/go-clang/clang-v15/clang"
+)
+
+func span(file string, line, column, offset, length uint32) clang.Span {
+	start := clang.Position{File: file, Line: line, Column: column, Offset: offset}
+	end := start
+	end.Column += length
+	end.Offset += length
+
+	return clang.Span{Start: start, End: end}
+}
+
+func TestMerge(t *testing.T) {
+	header := Occurrence{USR: "c:@F@f", Span: span("/p/a.h", 1, 5, 4, 1), Roles: clang.SymbolRole_Declaration}
+
+	a := NewUnit()
+	a.Symbols["c:@F@f"] = &Symbol{USR: "c:@F@f", Name: "f", Kind: clang.Cursor_FunctionDecl}
+	a.Symbols["c:@S@B"] = &Symbol{USR: "c:@S@B", Name: "B", Kind: clang.Cursor_StructDecl}
+	a.Occurrences = []Occurrence{
+		header,
+		{USR: "c:@F@f", Span: span("/p/a.c", 3, 1, 20, 1), Roles: clang.SymbolRole_Reference | clang.SymbolRole_Call},
+	}
+
+	b := NewUnit()
+	b.Symbols["c:@F@f"] = &Symbol{USR: "c:@F@f", Name: "f", Kind: clang.Cursor_FunctionDecl, Doc: "Does f."}
+	b.Symbols["c:@S@B"] = &Symbol{USR: "c:@S@B", Name: "B", Kind: clang.Cursor_StructDecl,
+		Relations: []Relation{{Kind: Relation_Base, USR: "c:@S@A"}}}
+	b.Occurrences = []Occurrence{
+		header,
+		{USR: "c:@F@f", Span: span("/p/b.c", 7, 3, 80, 1), Roles: clang.SymbolRole_Reference | clang.SymbolRole_Call},
+	}
+
+	u := NewUnit()
+	u.Merge(a)
+	u.Merge(b)
+	u.Merge(b)
+
+	if n := len(u.Occurrences); n != 3 {
+		t.Errorf("expected 3 occurrences. got=%d: %+v", n, u.Occurrences)
+	}
+	if doc := u.Symbols["c:@F@f"].Doc; doc != "Does f." {
+		t.Errorf("expected the documentation to be completed. got=%q", doc)
+	}
+	if rels := u.Symbols["c:@S@B"].Relations; len(rels) != 1 || !u.Symbols["c:@S@B"].HasRelation(Relation_Base, "c:@S@A") {
+		t.Errorf("expected a single base relation. got=%+v", rels)
+	}
+	if a.Symbols["c:@S@B"].Relations != nil {
+		t.Errorf("expected the merged unit to be unchanged")
+	}
+
+	if got, want := u.Files(), []string{"/p/a.h", "/p/a.c", "/p/b.c"}; len(got) != len(want) || got[0] != want[0] || got[2] != want[2] {
+		t.Errorf("expected files %v. got=%v", want, got)
+	}
+
+	if !u.Occurrences[1].Is(clang.SymbolRole_Call) || u.Occurrences[1].Is(clang.SymbolRole_Write) {
+		t.Errorf("unexpected roles %v", u.Occurrences[1].Roles)
+	}
+}